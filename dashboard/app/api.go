@@ -67,6 +67,9 @@ var apiHandlers = map[string]APIHandler{
 	"manager_stats":         nsHandler(apiManagerStats),
 	"commit_poll":           nsHandler(apiCommitPoll),
 	"upload_commits":        nsHandler(apiUploadCommits),
+	"fix_commits":           nsHandler(apiFixCommits),
+	"missing_backports":     nsHandler(apiMissingBackports),
+	"upload_backports":      nsHandler(apiUploadMissingBackports),
 	"bug_list":              nsHandler(apiBugList),
 	"load_bug":              nsHandler(apiLoadBug),
 	"update_report":         nsHandler(apiUpdateReport),
@@ -988,6 +991,12 @@ func saveCrash(c context.Context, ns string, req *dashapi.Crash, bug *Bug, bugKe
 			GuiltyFiles: req.GuiltyFiles,
 		},
 	}
+	for _, com := range req.IncompleteFix {
+		if crash.IncompleteFixBug == "" {
+			crash.IncompleteFixBug = com.BugID
+		}
+		crash.IncompleteFixCommits = append(crash.IncompleteFixCommits, com.Title)
+	}
 	var err error
 	if crash.Log, err = putText(c, ns, textCrashLog, req.Log); err != nil {
 		return err
@@ -1040,12 +1049,17 @@ func purgeOldCrashes(c context.Context, bug *Bug, bugKey *db.Key) {
 	var toDelete []*db.Key
 	latestOnManager := make(map[string]bool)
 	uniqueTitle := make(map[string]bool)
-	deleted, reproCount, noreproCount := 0, 0, 0
+	deleted, reproCount, noreproCount, incompleteFixCount := 0, 0, 0, 0
 	for _, crash := range crashes {
 		if !crash.Reported.IsZero() {
 			log.Errorf(c, "purging reported crash?")
 			continue
 		}
+		// Preserve some evidence of incomplete fixes.
+		if crash.IncompleteFixBug != "" && incompleteFixCount < maxCrashes() {
+			incompleteFixCount++
+			continue
+		}
 		// Preserve latest crash on each manager.
 		if !latestOnManager[crash.Manager] {
 			latestOnManager[crash.Manager] = true
@@ -1237,11 +1251,34 @@ func apiManagerStats(c context.Context, ns string, payload io.Reader) (interface
 		stats.TotalExecs += int64(req.Execs)
 		stats.TriagedCoverage = max(stats.TriagedCoverage, int64(req.TriagedCoverage))
 		stats.TriagedPCs = max(stats.TriagedPCs, int64(req.TriagedPCs))
+		updateSubsystemStats(stats, mgr.CurrentBuild, req)
 		return nil
 	})
 	return nil, err
 }
 
+func updateSubsystemStats(stats *ManagerStats, build string, req *dashapi.ManagerStatsReq) {
+	if len(req.SubsystemCover) != 0 && stats.SubsystemsBuild != build {
+		// The coverage of different builds is not comparable.
+		stats.SubsystemsBuild = build
+		for i := range stats.Subsystems {
+			stats.Subsystems[i].MaxPCs = 0
+			stats.Subsystems[i].TotalPCs = 0
+		}
+	}
+	for _, item := range req.SubsystemCover {
+		s := stats.subsystem(item.Name)
+		s.MaxPCs = max(s.MaxPCs, int64(item.PCs))
+		s.TotalPCs = int64(item.TotalPCs)
+	}
+	for name, crashes := range req.SubsystemCrashes {
+		stats.subsystem(name).Crashes += int64(crashes)
+	}
+	sort.Slice(stats.Subsystems, func(i, j int) bool {
+		return stats.Subsystems[i].Name < stats.Subsystems[j].Name
+	})
+}
+
 func apiUpdateReport(c context.Context, ns string, payload io.Reader) (interface{}, error) {
 	req := new(dashapi.UpdateReportReq)
 	if err := json.NewDecoder(payload).Decode(req); err != nil {
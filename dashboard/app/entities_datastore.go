@@ -53,6 +53,17 @@ type ManagerStats struct {
 	// These are only recorded once right after corpus is triaged.
 	TriagedCoverage int64
 	TriagedPCs      int64
+	// Per-subsystem coverage and crashes.
+	// The coverage values are reset once the manager switches to a new build.
+	Subsystems      []ManagerSubsystemStats `datastore:",noindex"`
+	SubsystemsBuild string                  `datastore:",noindex"`
+}
+
+type ManagerSubsystemStats struct {
+	Name     string
+	MaxPCs   int64
+	TotalPCs int64
+	Crashes  int64
 }
 
 type Asset struct {
@@ -371,6 +382,9 @@ type Crash struct {
 	ReportLen       int64
 	Assets          []Asset   // crash-related assets
 	AssetsLastCheck time.Time // the last time we checked the assets for deprecation
+	// Set if the crash happened on a build that contains the fix of a bug with the same title.
+	IncompleteFixBug     string   // as in the /bug?id= links
+	IncompleteFixCommits []string `datastore:",noindex"`
 }
 
 type CrashReportElements struct {
@@ -471,6 +485,27 @@ type DiscussionMessage struct {
 	Time     time.Time `datastore:",noindex"`
 }
 
+// TreeBackports holds the fixing commits that syz-ci has not found in a (stable) tree.
+type TreeBackports struct {
+	Namespace string
+	Repo      string
+	Branch    string
+	Commit    string // the commit the list was computed for
+	Updated   time.Time
+	Missing   []TreeBackportCommit `datastore:",noindex"`
+}
+
+type TreeBackportCommit struct {
+	BugID    string
+	BugTitle string
+	Title    string
+	Hash     string
+}
+
+func treeBackportsKey(c context.Context, ns, repo, branch string) *db.Key {
+	return db.NewKey(c, "TreeBackports", fmt.Sprintf("%v-%v-%v", ns, repo, branch), 0, nil)
+}
+
 // ReportingState holds dynamic info associated with reporting.
 type ReportingState struct {
 	Entries []ReportingStateEntry
@@ -791,6 +826,16 @@ func updateManager(c context.Context, ns, name string, fn func(mgr *Manager, sta
 	return runInTransaction(c, tx, nil)
 }
 
+func (stats *ManagerStats) subsystem(name string) *ManagerSubsystemStats {
+	for i := range stats.Subsystems {
+		if stats.Subsystems[i].Name == name {
+			return &stats.Subsystems[i]
+		}
+	}
+	stats.Subsystems = append(stats.Subsystems, ManagerSubsystemStats{Name: name})
+	return &stats.Subsystems[len(stats.Subsystems)-1]
+}
+
 func loadAllManagers(c context.Context, ns string) ([]*Manager, []*db.Key, error) {
 	var managers []*Manager
 	query := db.NewQuery("Manager")
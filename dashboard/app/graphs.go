@@ -9,8 +9,10 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	db "google.golang.org/appengine/v2/datastore"
@@ -49,6 +51,26 @@ type uiManagersPage struct {
 	Graph    *uiGraph
 }
 
+type uiSubsystemHealthPage struct {
+	Header     *uiHeader
+	Managers   *uiCheckbox
+	Subsystems *uiMultiInput
+	Months     *uiSlider
+	Graph      *uiGraph
+	Health     []*uiSubsystemHealth
+}
+
+type uiSubsystemHealth struct {
+	Manager   string
+	Subsystem string
+	PrevPCs   int64 // on the previous build
+	PCs       int64 // on the current build
+	TotalPCs  int64
+	Crashes   int64 // over the displayed period
+	Dropped   bool
+	Cause     string // what has changed between the builds
+}
+
 type uiCrashesPage struct {
 	Header      *uiHeader
 	Graph       *uiGraph
@@ -811,3 +833,218 @@ func createCrashesGraph(c context.Context, ns string, regexps []string, days int
 	}
 	return graph, nil
 }
+
+func handleGraphSubsystems(c context.Context, w http.ResponseWriter, r *http.Request) error {
+	hdr, err := commonHeader(c, r, w, "")
+	if err != nil {
+		return err
+	}
+	r.ParseForm()
+
+	allManagers, err := managerList(c, hdr.Namespace)
+	if err != nil {
+		return err
+	}
+	managers, err := createCheckBox(r, "Instances", allManagers)
+	if err != nil {
+		return err
+	}
+	data := &uiSubsystemHealthPage{
+		Header:     hdr,
+		Managers:   managers,
+		Subsystems: createMultiInput(r, "subsystem", "Subsystems"),
+		Months:     createSlider(r, "Months", 1, 36),
+	}
+	days := data.Months.Val * 30
+	since := timeNow(c).Add(-time.Duration(days) * 24 * time.Hour)
+	perManager := make(map[string][]*ManagerStats)
+	for _, mgr := range data.Managers.vals {
+		stats, err := loadManagerStats(c, hdr.Namespace, mgr, since)
+		if err != nil {
+			return err
+		}
+		perManager[mgr] = stats
+		health, err := subsystemHealth(c, hdr.Namespace, mgr, stats)
+		if err != nil {
+			return err
+		}
+		data.Health = append(data.Health, health...)
+	}
+	selected := data.Subsystems.Vals
+	if len(selected) == 0 {
+		// By default, show the subsystems that need attention.
+		seen := make(map[string]bool)
+		for _, item := range data.Health {
+			if item.Dropped && !seen[item.Subsystem] {
+				seen[item.Subsystem] = true
+				selected = append(selected, item.Subsystem)
+			}
+		}
+	}
+	if len(selected) != 0 {
+		data.Graph = createSubsystemsGraph(c, data.Managers.vals, selected, perManager, days)
+	}
+	return serveTemplate(w, "graph_subsystems.html", data)
+}
+
+func loadManagerStats(c context.Context, ns, mgr string, since time.Time) ([]*ManagerStats, error) {
+	var stats []*ManagerStats
+	_, err := db.NewQuery("ManagerStats").
+		Ancestor(mgrKey(c, ns, mgr)).
+		Filter("Date>=", timeDate(since)).
+		GetAll(c, &stats)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Date < stats[j].Date
+	})
+	return stats, nil
+}
+
+// The relative decrease of covered PCs between two consecutive builds that we consider worth attention.
+const subsystemCoverDrop = 0.1
+
+// subsystemHealth compares the per-subsystem coverage on the last manager build to that on the previous one.
+// The stats are expected to be sorted by date and to cover only the displayed period.
+func subsystemHealth(c context.Context, ns, mgr string, stats []*ManagerStats) (
+	[]*uiSubsystemHealth, error) {
+	var builds []string
+	perBuild := make(map[string]map[string]ManagerSubsystemStats)
+	crashes := make(map[string]int64)
+	for _, stat := range stats {
+		for _, item := range stat.Subsystems {
+			crashes[item.Name] += item.Crashes
+		}
+		if stat.SubsystemsBuild == "" {
+			continue
+		}
+		if len(builds) == 0 || builds[len(builds)-1] != stat.SubsystemsBuild {
+			builds = append(builds, stat.SubsystemsBuild)
+			perBuild[stat.SubsystemsBuild] = make(map[string]ManagerSubsystemStats)
+		}
+		buildStats := perBuild[stat.SubsystemsBuild]
+		for _, item := range stat.Subsystems {
+			prev := buildStats[item.Name]
+			item.MaxPCs = max(item.MaxPCs, prev.MaxPCs)
+			if item.TotalPCs == 0 {
+				item.TotalPCs = prev.TotalPCs
+			}
+			buildStats[item.Name] = item
+		}
+	}
+	var cur, prev map[string]ManagerSubsystemStats
+	cause := ""
+	if len(builds) > 0 {
+		cur = perBuild[builds[len(builds)-1]]
+	}
+	if len(builds) > 1 {
+		prev = perBuild[builds[len(builds)-2]]
+		var err error
+		cause, err = buildChangeCause(c, ns, builds[len(builds)-2], builds[len(builds)-1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	names := make(map[string]bool)
+	for name := range cur {
+		names[name] = true
+	}
+	for name, crashes := range crashes {
+		if crashes != 0 {
+			names[name] = true
+		}
+	}
+	var ret []*uiSubsystemHealth
+	for name := range names {
+		item := &uiSubsystemHealth{
+			Manager:   mgr,
+			Subsystem: name,
+			PrevPCs:   prev[name].MaxPCs,
+			PCs:       cur[name].MaxPCs,
+			TotalPCs:  cur[name].TotalPCs,
+			Crashes:   crashes[name],
+		}
+		if item.PrevPCs != 0 && float64(item.PCs) < float64(item.PrevPCs)*(1-subsystemCoverDrop) {
+			item.Dropped = true
+			item.Cause = cause
+		}
+		ret = append(ret, item)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Dropped != ret[j].Dropped {
+			return ret[i].Dropped
+		}
+		return ret[i].Subsystem < ret[j].Subsystem
+	})
+	return ret, nil
+}
+
+// buildChangeCause describes what changed between two manager builds.
+// Old builds may have been deleted, in such case the cause is unknown.
+func buildChangeCause(c context.Context, ns, prevID, curID string) (string, error) {
+	prev, cur := new(Build), new(Build)
+	for _, item := range []struct {
+		id    string
+		build *Build
+	}{{prevID, prev}, {curID, cur}} {
+		if err := db.Get(c, buildKey(c, ns, item.id), item.build); err != nil {
+			if err == db.ErrNoSuchEntity {
+				return "unknown", nil
+			}
+			return "", fmt.Errorf("failed to get build %v/%v: %w", ns, item.id, err)
+		}
+	}
+	var causes []string
+	if prev.KernelCommit != cur.KernelCommit {
+		causes = append(causes, "kernel")
+	}
+	if prev.SyzkallerCommit != cur.SyzkallerCommit {
+		causes = append(causes, "descriptions")
+	}
+	if len(causes) == 0 {
+		return "build config", nil
+	}
+	return strings.Join(causes, ", "), nil
+}
+
+func createSubsystemsGraph(c context.Context, managers, subsystems []string,
+	perManager map[string][]*ManagerStats, days int) *uiGraph {
+	graph := &uiGraph{}
+	for _, mgr := range managers {
+		for _, name := range subsystems {
+			graph.Headers = append(graph.Headers, uiGraphHeader{Name: mgr + "-" + name})
+		}
+	}
+	now := timeNow(c)
+	const day = 24 * time.Hour
+	for date := 0; date <= days; date++ {
+		col := uiGraphColumn{Hint: now.Add(time.Duration(date-days) * day).Format("02-01-2006")}
+		for range managers {
+			for range subsystems {
+				col.Vals = append(col.Vals, uiGraphValue{IsNull: true, Hint: "-"})
+			}
+		}
+		graph.Columns = append(graph.Columns, col)
+	}
+	for mgrIndex, mgr := range managers {
+		for _, stat := range perManager[mgr] {
+			dayIndex := days - int(now.Sub(dateTime(stat.Date))/day)
+			if dayIndex < 0 || dayIndex > days {
+				continue
+			}
+			for _, item := range stat.Subsystems {
+				nameIndex := slices.Index(subsystems, item.Name)
+				if nameIndex < 0 || item.TotalPCs == 0 {
+					continue
+				}
+				val := float32(item.MaxPCs) * 100 / float32(item.TotalPCs)
+				graph.Columns[dayIndex].Vals[mgrIndex*len(subsystems)+nameIndex] = uiGraphValue{
+					Val:  val,
+					Hint: fmt.Sprintf("%.2f%% (%v/%v)", val, item.MaxPCs, item.TotalPCs),
+				}
+			}
+		}
+	}
+	return graph
+}
@@ -4,10 +4,12 @@
 package main
 
 import (
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestManagersGraphs(t *testing.T) {
@@ -145,3 +147,86 @@ func TestManagersGraph_FuzzingMetric_BadRequest_OnMalformedInput(t *testing.T) {
 	_, err := c.AuthGET(AccessAdmin, "/test2/graph/fuzzing?Metrics=MaxCorpus'%2F*%22ZYLQ%22*%2F+AND+'0'%3D'0&Months=27")
 	c.expectBadReqest(err)
 }
+
+func TestSubsystemsGraph(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	build1 := testBuild(1)
+	c.client2.UploadBuild(build1)
+	c.expectOK(c.client2.UploadManagerStats(&dashapi.ManagerStatsReq{
+		Name: build1.Manager,
+		SubsystemCover: []dashapi.SubsystemCover{
+			{Name: "net", PCs: 1000, TotalPCs: 5000},
+			{Name: "mm", PCs: 300, TotalPCs: 1000},
+		},
+	}))
+	c.advanceTime(25 * time.Hour)
+	c.expectOK(c.client2.UploadManagerStats(&dashapi.ManagerStatsReq{
+		Name: build1.Manager,
+		SubsystemCover: []dashapi.SubsystemCover{
+			{Name: "net", PCs: 1200, TotalPCs: 5000},
+			{Name: "mm", PCs: 310, TotalPCs: 1000},
+		},
+		SubsystemCrashes: map[string]uint64{"net": 2},
+	}))
+
+	// The new build only changes the kernel.
+	build2 := testBuild(1)
+	build2.ID = "build1-new"
+	build2.KernelCommit = strings.Repeat("f", 40)
+	c.client2.UploadBuild(build2)
+	c.advanceTime(25 * time.Hour)
+	c.expectOK(c.client2.UploadManagerStats(&dashapi.ManagerStatsReq{
+		Name: build1.Manager,
+		SubsystemCover: []dashapi.SubsystemCover{
+			{Name: "net", PCs: 600, TotalPCs: 5100},
+			{Name: "mm", PCs: 305, TotalPCs: 1000},
+		},
+		SubsystemCrashes: map[string]uint64{"net": 1, "fs": 3},
+	}))
+
+	stats, err := loadManagerStats(c.ctx, "test2", build1.Manager, c.mockedTime.Add(-30*24*time.Hour))
+	c.expectOK(err)
+	assert.Len(t, stats, 3)
+	health, err := subsystemHealth(c.ctx, "test2", build1.Manager, stats)
+	c.expectOK(err)
+	assert.Equal(t, []*uiSubsystemHealth{
+		{
+			Manager:   build1.Manager,
+			Subsystem: "net",
+			PrevPCs:   1200,
+			PCs:       600,
+			TotalPCs:  5100,
+			Crashes:   3,
+			Dropped:   true,
+			Cause:     "kernel",
+		},
+		{
+			Manager:   build1.Manager,
+			Subsystem: "fs",
+			Crashes:   3,
+		},
+		{
+			Manager:   build1.Manager,
+			Subsystem: "mm",
+			PrevPCs:   310,
+			PCs:       305,
+			TotalPCs:  1000,
+		},
+	}, health)
+
+	// Only the stats from the selected period are loaded.
+	stats, err = loadManagerStats(c.ctx, "test2", build1.Manager, c.mockedTime)
+	c.expectOK(err)
+	assert.Len(t, stats, 1)
+
+	// Deleted builds must not break the page.
+	cause, err := buildChangeCause(c.ctx, "test2", "deleted-build", build2.ID)
+	c.expectOK(err)
+	assert.Equal(t, "unknown", cause)
+
+	reply, err := c.AuthGET(AccessAdmin, "/test2/graph/subsystems")
+	c.expectOK(err)
+	assert.Contains(t, string(reply), "after kernel change")
+}
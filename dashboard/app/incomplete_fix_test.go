@@ -0,0 +1,61 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncompleteFix(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	build1 := testBuild(1)
+	c.client.UploadBuild(build1)
+	crash1 := testCrash(build1, 1)
+	c.client.ReportCrash(crash1)
+	rep := c.client.pollBug()
+	reply, _ := c.client.ReportingUpdate(&dashapi.BugUpdate{
+		ID:         rep.ID,
+		Status:     dashapi.BugStatusOpen,
+		FixCommits: []string{"foo: fix the crash"},
+	})
+	c.expectEQ(reply.OK, true)
+
+	// The fix is not yet present in the manager builds.
+	fixes, err := c.client.FixCommits(&dashapi.FixCommitsReq{Manager: build1.Manager})
+	c.expectOK(err)
+	c.expectEQ(len(fixes.List), 0)
+
+	build2 := testBuild(2)
+	build2.Manager = build1.Manager
+	build2.Commits = []string{"foo: fix the crash"}
+	c.client.UploadBuild(build2)
+
+	fixes, err = c.client.FixCommits(&dashapi.FixCommitsReq{Manager: build1.Manager})
+	c.expectOK(err)
+	c.expectEQ(len(fixes.List), 1)
+	c.expectEQ(fixes.List[0].CrashTitle, crash1.Title)
+	c.expectEQ(fixes.List[0].Title, "foo: fix the crash")
+
+	// The crash still happens on the build with the fix.
+	crash2 := testCrash(build2, 1)
+	crash2.IncompleteFix = fixes.List
+	c.client.ReportCrash(crash2)
+	rep2 := c.client.pollBug()
+	c.expectEQ(rep2.Title, crash1.Title+" (2)")
+
+	fixedBug, _, _ := c.loadBug(rep.ID)
+	page, err := c.AuthGET(AccessAdmin, "/bug?id="+fixedBug.keyHash(c.ctx))
+	c.expectOK(err)
+	assert.Contains(t, string(page), "Crashes on builds with the fix (1)")
+
+	newBug, _, _ := c.loadBug(rep2.ID)
+	page, err = c.AuthGET(AccessAdmin, "/bug?id="+newBug.keyHash(c.ctx))
+	c.expectOK(err)
+	assert.Contains(t, string(page), "after fix")
+}
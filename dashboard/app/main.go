@@ -63,6 +63,7 @@ func initHTTPHandlers() {
 		http.Handle("/"+ns+"/graph/bugs", handlerWrapper(handleKernelHealthGraph))
 		http.Handle("/"+ns+"/graph/lifetimes", handlerWrapper(handleGraphLifetimes))
 		http.Handle("/"+ns+"/graph/fuzzing", handlerWrapper(handleGraphFuzzing))
+		http.Handle("/"+ns+"/graph/subsystems", handlerWrapper(handleGraphSubsystems))
 		http.Handle("/"+ns+"/graph/crashes", handlerWrapper(handleGraphCrashes))
 		http.Handle("/"+ns+"/graph/found-bugs", handlerWrapper(handleFoundBugsGraph))
 		http.Handle("/"+ns+"/graph/coverage", handlerWrapper(handleCoverageGraph))
@@ -309,6 +310,7 @@ const (
 	sectionDiscussionList = "discussion_list"
 	sectionTestResults    = "test_results"
 	sectionReproAttempts  = "repro_attempts"
+	sectionCrashList      = "crash_list"
 )
 
 type uiCollapsible struct {
@@ -407,6 +409,9 @@ type uiCrash struct {
 	ReproLogLink    string
 	MachineInfoLink string
 	Assets          []*uiAsset
+	BugLink         string // only set if the crash belongs to another bug
+	// Set if the crash happened on a build that contained the fix of a bug with the same title.
+	IncompleteFixLink string
 	*uiBuild
 }
 
@@ -1190,6 +1195,18 @@ func handleBug(c context.Context, w http.ResponseWriter, r *http.Request) error
 			},
 		})
 	}
+	afterFix, err := loadIncompleteFixCrashes(c, accessLevel, bug)
+	if err != nil {
+		return err
+	}
+	if len(afterFix) > 0 {
+		sections = append(sections, &uiCollapsible{
+			Title: fmt.Sprintf("Crashes on builds with the fix (%d)", len(afterFix)),
+			Show:  true,
+			Type:  sectionCrashList,
+			Value: &uiCrashTable{Crashes: afterFix},
+		})
+	}
 	if accessLevel == AccessAdmin && len(bug.ReproAttempts) > 0 {
 		reproAttempts := getReproAttempts(bug)
 		sections = append(sections, &uiCollapsible{
@@ -2070,6 +2087,52 @@ func makeUIAssets(c context.Context, build *Build, crash *Crash, forReport bool)
 	return uiAssets
 }
 
+// loadIncompleteFixCrashes returns the crashes that syz-manager has attributed to an incomplete fix of the bug.
+func loadIncompleteFixCrashes(c context.Context, accessLevel AccessLevel, bug *Bug) ([]*uiCrash, error) {
+	if len(bug.Commits) == 0 {
+		return nil, nil
+	}
+	bugID := bug.keyHash(c)
+	var crashes []*Crash
+	keys, err := db.NewQuery("Crash").
+		Filter("IncompleteFixBug=", bugID).
+		Limit(maxCrashes()).
+		GetAll(c, &crashes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query crashes: %w", err)
+	}
+	bugs := make(map[string]*Bug)
+	var ret []*uiCrash
+	for i, crash := range crashes {
+		parentKey := keys[i].Parent()
+		parent := bugs[parentKey.StringID()]
+		if parent == nil {
+			parent = new(Bug)
+			if err := db.Get(c, parentKey, parent); err != nil {
+				return nil, fmt.Errorf("failed to get bug: %w", err)
+			}
+			bugs[parentKey.StringID()] = parent
+		}
+		if accessLevel < parent.sanitizeAccess(c, accessLevel) {
+			continue
+		}
+		build, err := loadBuild(c, bug.Namespace, crash.BuildID)
+		if err != nil {
+			return nil, err
+		}
+		ui := makeUICrash(c, crash, build)
+		ui.IncompleteFixLink = ""
+		if parentKey.StringID() != bugID {
+			ui.BugLink = bugLink(parentKey.StringID())
+		}
+		ret = append(ret, ui)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Time.After(ret[j].Time)
+	})
+	return ret, nil
+}
+
 func makeUICrash(c context.Context, crash *Crash, build *Build) *uiCrash {
 	ui := &uiCrash{
 		Title:           crash.Title,
@@ -2086,6 +2149,7 @@ func makeUICrash(c context.Context, crash *Crash, build *Build) *uiCrash {
 		MachineInfoLink: textLink(textMachineInfo, crash.MachineInfo),
 		Assets:          makeUIAssets(c, build, crash, true),
 	}
+	ui.IncompleteFixLink = bugLink(crash.IncompleteFixBug)
 	if build != nil {
 		ui.uiBuild = makeUIBuild(c, build, true)
 	}
@@ -0,0 +1,143 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	db "google.golang.org/appengine/v2/datastore"
+)
+
+// The missing backports of the stable trees are computed by syz-ci: it queries the fixing commits
+// via fix_commits, looks them up in its checkout of the tree (see vcs.MissingFixCommits)
+// and uploads the ones it has not found. Other clients can do the same for any commit.
+// syz-manager also queries fix_commits to detect the crashes that still happen after the fix.
+
+func apiFixCommits(c context.Context, ns string, payload io.Reader) (interface{}, error) {
+	req := new(dashapi.FixCommitsReq)
+	if err := json.NewDecoder(payload).Decode(req); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal request: %w", err)
+	}
+	statuses := []int{BugStatusFixed}
+	if req.Manager != "" {
+		statuses = append(statuses, BugStatusOpen)
+	}
+	resp := &dashapi.FixCommitsResp{}
+	for _, status := range statuses {
+		// Filter by the fix time in the query, otherwise we would load all the fixed bugs on each call.
+		sinceField := "Closed>="
+		if status == BugStatusOpen {
+			sinceField = "FixTime>="
+		}
+		bugs, keys, err := loadAllBugs(c, func(query *db.Query) *db.Query {
+			return query.Filter("Namespace=", ns).
+				Filter("Status=", status).
+				Filter(sinceField, req.Since)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i, bug := range bugs {
+			if !bugFixPresent(bug, req) {
+				continue
+			}
+			resp.List = append(resp.List, bugFixCommits(bug, keys[i])...)
+		}
+	}
+	return resp, nil
+}
+
+func bugFixPresent(bug *Bug, req *dashapi.FixCommitsReq) bool {
+	if len(bug.Commits) == 0 {
+		return false
+	}
+	if bug.Status == BugStatusFixed {
+		// The fix is present on all managers.
+		return !bug.Closed.Before(req.Since)
+	}
+	return !bug.FixTime.Before(req.Since) && stringInList(bug.PatchedOn, req.Manager)
+}
+
+func bugFixCommits(bug *Bug, key *db.Key) []dashapi.FixCommit {
+	var ret []dashapi.FixCommit
+	for j, title := range bug.Commits {
+		com := dashapi.FixCommit{
+			BugID:      key.StringID(),
+			BugTitle:   bug.displayTitle(),
+			CrashTitle: bug.Title,
+			Title:      title,
+		}
+		if j < len(bug.CommitInfo) {
+			com.Hash = bug.CommitInfo[j].Hash
+		}
+		ret = append(ret, com)
+	}
+	return ret
+}
+
+func apiMissingBackports(c context.Context, ns string, payload io.Reader) (interface{}, error) {
+	req := new(dashapi.MissingBackportsReq)
+	if err := json.NewDecoder(payload).Decode(req); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal request: %w", err)
+	}
+	info := new(TreeBackports)
+	err := db.Get(c, treeBackportsKey(c, ns, req.Repo, req.Branch), info)
+	if err == db.ErrNoSuchEntity {
+		return nil, fmt.Errorf("%w: missing backports of %v/%v are not known",
+			ErrClientBadRequest, req.Repo, req.Branch)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to query missing backports: %w", err)
+	}
+	if req.Commit != "" && req.Commit != info.Commit {
+		return nil, fmt.Errorf("%w: missing backports of %v/%v are known for %v, not for %v"+
+			" (use fix_commits to check other commits)",
+			ErrClientBadRequest, req.Repo, req.Branch, info.Commit, req.Commit)
+	}
+	resp := &dashapi.MissingBackportsResp{
+		Commit:  info.Commit,
+		Updated: info.Updated,
+	}
+	for _, com := range info.Missing {
+		resp.Missing = append(resp.Missing, dashapi.FixCommit{
+			BugID:    com.BugID,
+			BugTitle: com.BugTitle,
+			Title:    com.Title,
+			Hash:     com.Hash,
+		})
+	}
+	return resp, nil
+}
+
+func apiUploadMissingBackports(c context.Context, ns string, payload io.Reader) (interface{}, error) {
+	req := new(dashapi.UploadMissingBackportsReq)
+	if err := json.NewDecoder(payload).Decode(req); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal request: %w", err)
+	}
+	if req.Repo == "" || req.Commit == "" {
+		return nil, fmt.Errorf("%w: repo and commit must be set", ErrClientBadRequest)
+	}
+	info := &TreeBackports{
+		Namespace: ns,
+		Repo:      req.Repo,
+		Branch:    req.Branch,
+		Commit:    req.Commit,
+		Updated:   timeNow(c),
+	}
+	for _, com := range req.Missing {
+		info.Missing = append(info.Missing, TreeBackportCommit{
+			BugID:    com.BugID,
+			BugTitle: com.BugTitle,
+			Title:    com.Title,
+			Hash:     com.Hash,
+		})
+	}
+	if _, err := db.Put(c, treeBackportsKey(c, ns, req.Repo, req.Branch), info); err != nil {
+		return nil, fmt.Errorf("failed to save missing backports: %w", err)
+	}
+	return nil, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+)
+
+func TestMissingBackports(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	build1 := testBuild(1)
+	c.client.UploadBuild(build1)
+	crash1 := testCrash(build1, 1)
+	c.client.ReportCrash(crash1)
+	rep := c.client.pollBug()
+	reply, _ := c.client.ReportingUpdate(&dashapi.BugUpdate{
+		ID:         rep.ID,
+		Status:     dashapi.BugStatusOpen,
+		FixCommits: []string{"foo: fix the crash"},
+	})
+	c.expectEQ(reply.OK, true)
+
+	// The bug is not fixed yet.
+	fixes, err := c.client.FixCommits(&dashapi.FixCommitsReq{})
+	c.expectOK(err)
+	c.expectEQ(len(fixes.List), 0)
+
+	build2 := testBuild(2)
+	build2.Manager = build1.Manager
+	build2.Commits = []string{"foo: fix the crash"}
+	c.client.UploadBuild(build2)
+	c.expectOK(c.client.UploadCommits([]dashapi.Commit{
+		{Hash: "hash1", Title: "foo: fix the crash"},
+	}))
+
+	fixes, err = c.client.FixCommits(&dashapi.FixCommitsReq{})
+	c.expectOK(err)
+	c.expectEQ(len(fixes.List), 1)
+	c.expectEQ(fixes.List[0].Title, "foo: fix the crash")
+	c.expectEQ(fixes.List[0].Hash, "hash1")
+	c.expectEQ(fixes.List[0].BugTitle, crash1.Title)
+
+	// Nothing has been computed for the tree yet.
+	_, err = c.client.MissingBackports(&dashapi.MissingBackportsReq{
+		Repo:   "git://stable.git",
+		Branch: "linux-6.1.y",
+	})
+	c.expectBadReqest(err)
+
+	c.expectOK(c.client.UploadMissingBackports(&dashapi.UploadMissingBackportsReq{
+		Repo:    "git://stable.git",
+		Branch:  "linux-6.1.y",
+		Commit:  "stable-commit",
+		Missing: fixes.List,
+	}))
+	resp, err := c.client.MissingBackports(&dashapi.MissingBackportsReq{
+		Repo:   "git://stable.git",
+		Branch: "linux-6.1.y",
+		Commit: "stable-commit",
+	})
+	c.expectOK(err)
+	c.expectEQ(resp.Commit, "stable-commit")
+	c.expectEQ(resp.Missing, fixes.List)
+
+	// The list is not known for other commits.
+	_, err = c.client.MissingBackports(&dashapi.MissingBackportsReq{
+		Repo:   "git://stable.git",
+		Branch: "linux-6.1.y",
+		Commit: "another-commit",
+	})
+	c.expectBadReqest(err)
+}
@@ -311,6 +311,68 @@ func (dash *Dashboard) UploadCommits(commits []Commit) error {
 	return dash.Query("upload_commits", &CommitPollResultReq{commits}, nil)
 }
 
+// FixCommit is a fixing commit of a fixed bug.
+type FixCommit struct {
+	BugID      string // as in the /bug?id= links
+	BugTitle   string
+	CrashTitle string // the title of the crashes the bug was created for
+	Title      string
+	Hash       string // may be empty if the commit has not been seen in the main repo yet
+}
+
+type FixCommitsReq struct {
+	// Only the bugs fixed after this moment are considered.
+	Since time.Time
+	// If set, only the fixing commits that are known to be present in the manager builds are returned.
+	// These also include the fixes of the bugs that are not yet fixed on all managers.
+	Manager string
+}
+
+type FixCommitsResp struct {
+	List []FixCommit
+}
+
+// FixCommits returns the fixing commits of the fixed bugs of the namespace.
+func (dash *Dashboard) FixCommits(req *FixCommitsReq) (*FixCommitsResp, error) {
+	resp := new(FixCommitsResp)
+	err := dash.Query("fix_commits", req, resp)
+	return resp, err
+}
+
+type MissingBackportsReq struct {
+	Repo   string
+	Branch string
+	// If set, the result must have been computed exactly for this commit.
+	Commit string
+}
+
+type MissingBackportsResp struct {
+	Commit  string // the commit the list was computed for
+	Updated time.Time
+	Missing []FixCommit
+}
+
+// MissingBackports returns the fixing commits that are not present in the tree.
+// The lists are periodically computed by syz-ci for the stable trees it's configured to check,
+// so only the last checked commit of the tree is known. To check an arbitrary commit,
+// query FixCommits and pass them to vcs.MissingFixCommits with the commit checked out.
+func (dash *Dashboard) MissingBackports(req *MissingBackportsReq) (*MissingBackportsResp, error) {
+	resp := new(MissingBackportsResp)
+	err := dash.Query("missing_backports", req, resp)
+	return resp, err
+}
+
+type UploadMissingBackportsReq struct {
+	Repo    string
+	Branch  string
+	Commit  string
+	Missing []FixCommit
+}
+
+func (dash *Dashboard) UploadMissingBackports(req *UploadMissingBackportsReq) error {
+	return dash.Query("upload_backports", req, nil)
+}
+
 type CrashFlags int64
 
 const (
@@ -338,6 +400,9 @@ type Crash struct {
 	ReproC        []byte
 	ReproLog      []byte
 	OriginalTitle string // Title before we began bug reproduction.
+	// The fixing commits of the bugs with the same title that are present in the build.
+	// Set if the manager believes that the fix was incomplete.
+	IncompleteFix []FixCommit
 }
 
 type ReportCrashResp struct {
@@ -784,10 +849,18 @@ type ManagerStatsReq struct {
 	Crashes           uint64
 	SuppressedCrashes uint64
 	Execs             uint64
+	SubsystemCrashes  map[string]uint64
 
 	// Non-zero only when set.
 	TriagedCoverage uint64
 	TriagedPCs      uint64
+	SubsystemCover  []SubsystemCover
+}
+
+type SubsystemCover struct {
+	Name     string
+	PCs      uint64
+	TotalPCs uint64
 }
 
 func (dash *Dashboard) UploadManagerStats(req *ManagerStatsReq) error {
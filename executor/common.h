@@ -478,6 +478,53 @@ static uint16 csum_inet_digest(struct csum_inet* csum)
 {
 	return ~csum->acc;
 }
+
+// Reflected CRC32 with the given polynomial:
+// 0xedb88320 for CRC32 (IEEE 802.3) and 0x82f63b78 for CRC32C (Castagnoli).
+static uint32 csum_crc32(const uint8* data, size_t length, uint32 poly)
+{
+	uint32 crc = 0xffffffff;
+	for (size_t i = 0; i < length; i++) {
+		crc ^= data[i];
+		for (int bit = 0; bit < 8; bit++)
+			crc = (crc >> 1) ^ (poly & -(crc & 1));
+	}
+	return ~crc;
+}
+
+static uint32 csum_adler32(const uint8* data, size_t length)
+{
+	uint32 a = 1, b = 0;
+	for (size_t i = 0; i < length; i++) {
+		a = (a + data[i]) % 65521;
+		b = (b + a) % 65521;
+	}
+	return (b << 16) | a;
+}
+
+static uint16 csum_fletcher16(const uint8* data, size_t length)
+{
+	uint32 a = 0, b = 0;
+	for (size_t i = 0; i < length; i++) {
+		a = (a + data[i]) % 255;
+		b = (b + a) % 255;
+	}
+	return (b << 8) | a;
+}
+
+// Fletcher-32 over little-endian 16-bit words, odd length is padded with a zero byte.
+static uint32 csum_fletcher32(const uint8* data, size_t length)
+{
+	uint32 a = 0, b = 0;
+	for (size_t i = 0; i < length; i += 2) {
+		uint32 word = data[i];
+		if (i + 1 < length)
+			word |= (uint32)data[i + 1] << 8;
+		a = (a + word) % 65535;
+		b = (b + a) % 65535;
+	}
+	return (b << 16) | a;
+}
 #endif
 
 #if GOOS_freebsd || GOOS_darwin || GOOS_netbsd
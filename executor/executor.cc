@@ -330,6 +330,12 @@ static uint8* input_data;
 
 // Checksum kinds.
 static const uint64 arg_csum_inet = 0;
+static const uint64 arg_csum_crc32 = 1;
+static const uint64 arg_csum_crc32c = 2;
+static const uint64 arg_csum_adler32 = 3;
+static const uint64 arg_csum_fletcher16 = 4;
+static const uint64 arg_csum_fletcher32 = 5;
+static const uint64 arg_csum_inet_sum = 6;
 
 // Checksum chunk kinds.
 static const uint64 arg_csum_chunk_data = 0;
@@ -957,11 +963,14 @@ void execute_one()
 			}
 			case arg_csum: {
 				debug_verbose("checksum found at %p\n", addr);
-				uint64 size = read_input(&input_pos);
+				uint64 meta = read_input(&input_pos);
+				uint64 size = meta & 0xff;
+				uint64 bf = meta >> 8;
 				char* csum_addr = addr;
 				uint64 csum_kind = read_input(&input_pos);
 				switch (csum_kind) {
-				case arg_csum_inet: {
+				case arg_csum_inet:
+				case arg_csum_inet_sum: {
 					if (size != 2)
 						failmsg("bag inet checksum size", "size=%llu", size);
 					debug_verbose("calculating checksum for %p\n", csum_addr);
@@ -992,11 +1001,50 @@ void execute_one()
 							failmsg("bad checksum chunk kind", "kind=%llu", chunk_kind);
 						}
 					}
-					uint16 csum_value = csum_inet_digest(&csum);
+					// The sum variant is stored without the final complement.
+					uint16 csum_value = csum_kind == arg_csum_inet ? csum_inet_digest(&csum) : (uint16)csum.acc;
 					debug_verbose("writing inet checksum %hx to %p\n", csum_value, csum_addr);
 					copyin(csum_addr, csum_value, 2, binary_format_native, 0, 0);
 					break;
 				}
+				case arg_csum_crc32:
+				case arg_csum_crc32c:
+				case arg_csum_adler32:
+				case arg_csum_fletcher16:
+				case arg_csum_fletcher32: {
+					uint64 chunks_num = read_input(&input_pos);
+					if (chunks_num != 1)
+						failmsg("bad checksum chunks number", "kind=%llu chunks=%llu", csum_kind, chunks_num);
+					uint64 chunk_kind = read_input(&input_pos);
+					uint64 chunk_value = read_input(&input_pos);
+					uint64 chunk_size = read_input(&input_pos);
+					if (chunk_kind != arg_csum_chunk_data)
+						failmsg("bad checksum chunk kind", "kind=%llu", chunk_kind);
+					const uint8* data = (const uint8*)(chunk_value + SYZ_DATA_OFFSET);
+					debug_verbose("calculating checksum kind %llu for %p over %p/%llu\n",
+						      csum_kind, csum_addr, data, chunk_size);
+					uint64 csum_value = 0;
+					switch (csum_kind) {
+					case arg_csum_crc32:
+						NONFAILING(csum_value = csum_crc32(data, chunk_size, 0xedb88320));
+						break;
+					case arg_csum_crc32c:
+						NONFAILING(csum_value = csum_crc32(data, chunk_size, 0x82f63b78));
+						break;
+					case arg_csum_adler32:
+						NONFAILING(csum_value = csum_adler32(data, chunk_size));
+						break;
+					case arg_csum_fletcher16:
+						NONFAILING(csum_value = csum_fletcher16(data, chunk_size));
+						break;
+					case arg_csum_fletcher32:
+						NONFAILING(csum_value = csum_fletcher32(data, chunk_size));
+						break;
+					}
+					debug_verbose("writing checksum %llx to %p\n", csum_value, csum_addr);
+					copyin(csum_addr, csum_value, size, bf, 0, 0);
+					break;
+				}
 				default:
 					failmsg("bad checksum kind", "kind=%llu", csum_kind);
 				}
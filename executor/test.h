@@ -172,6 +172,33 @@ static int test_csum_inet()
 	return 0;
 }
 
+static int test_csum_plain()
+{
+	const uint8* digits = (const uint8*)"123456789";
+	const uint8* abcdef = (const uint8*)"abcdef";
+	struct {
+		const char* name;
+		uint32 got;
+		uint32 want;
+	} tests[] = {
+	    {"crc32", csum_crc32(digits, 9, 0xedb88320), 0xcbf43926},
+	    {"crc32c", csum_crc32(digits, 9, 0x82f63b78), 0xe3069283},
+	    {"crc32 empty", csum_crc32(digits, 0, 0xedb88320), 0},
+	    {"adler32", csum_adler32((const uint8*)"Wikipedia", 9), 0x11e60398},
+	    {"fletcher16", csum_fletcher16(abcdef, 5), 0xc8f0},
+	    {"fletcher16 even", csum_fletcher16(abcdef, 6), 0x2057},
+	    {"fletcher32", csum_fletcher32(abcdef, 5), 0xf04fc729},
+	    {"fletcher32 even", csum_fletcher32(abcdef, 6), 0x56502d2a},
+	};
+	for (unsigned i = 0; i < ARRAY_SIZE(tests); i++) {
+		if (tests[i].got != tests[i].want) {
+			fprintf(stderr, "bad %s checksum, want: %x, got: %x\n", tests[i].name, tests[i].want, tests[i].got);
+			return 1;
+		}
+	}
+	return 0;
+}
+
 static int rand_int_range(int start, int end)
 {
 	return rand() % (end + 1 - start) + start;
@@ -372,6 +399,7 @@ static struct {
     {"test_copyin", test_copyin},
     {"test_csum_inet", test_csum_inet},
     {"test_csum_inet_acc", test_csum_inet_acc},
+    {"test_csum_plain", test_csum_plain},
 #if GOOS_linux && (GOARCH_amd64 || GOARCH_ppc64 || GOARCH_ppc64le || GOARCH_arm64)
     {"test_kvm", test_kvm},
 #endif
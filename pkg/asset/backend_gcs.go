@@ -62,27 +62,49 @@ func (csb *cloudStorageBackend) upload(req *uploadRequest) (*uploadResponse, err
 	// Best-effort check only. In the worst case we'll just overwite the file.
 	// The alternative would be to add an If-precondition, but it'd require
 	// complicated error-during-write handling.
-	exists, err := csb.client.FileExists(path)
-	if err != nil {
-		return nil, err
-	}
-	if exists {
-		return nil, &FileExistsError{req.savePath}
+	if !req.overwrite {
+		exists, err := csb.client.FileExists(path)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, &FileExistsError{req.savePath}
+		}
 	}
 	w, err := csb.client.FileWriterExt(path, req.contentType, req.contentEncoding)
 	csb.tracer.Log("gcs upload: obtained a writer for %s, error %s", path, err)
 	if err != nil {
 		return nil, err
 	}
-	return &uploadResponse{
+	writer := &wrappedWriteCloser{
 		writer: &writeErrorLogger{
 			writeCloser: w,
 			tracer:      csb.tracer,
 		},
-		path: req.savePath,
+		closeCallback: func() error {
+			if req.publish {
+				return csb.client.Publish(path)
+			}
+			return nil
+		},
+		abortCallback: func(err error) {
+			// The GCS writer commits the object on Close, so the only option is to delete it.
+			if err := csb.client.DeleteFile(path); err != nil && err != gcs.ErrFileNotFound {
+				csb.tracer.Log("gcs upload: failed to delete %s: %s", path, err)
+			}
+		},
+	}
+	return &uploadResponse{
+		writer: writer,
+		path:   req.savePath,
 	}, nil
 }
 
+func (csb *cloudStorageBackend) Close() error {
+	csb.client.Close()
+	return nil
+}
+
 func (csb *cloudStorageBackend) downloadURL(path string, publicURL bool) (string, error) {
 	return csb.client.GetDownloadURL(fmt.Sprintf("%s/%s", csb.bucket, path), publicURL), nil
 }
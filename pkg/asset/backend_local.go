@@ -0,0 +1,140 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package asset
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/debugtracer"
+	"github.com/google/syzkaller/pkg/gcs"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// localStorageBackend keeps assets in a local (or network-mounted) directory.
+// Download URLs are file:// URLs, unless downloadPrefix is set, in which case
+// it's assumed that the directory is served by some HTTP server at that address.
+type localStorageBackend struct {
+	dir            string
+	downloadPrefix string
+	tracer         debugtracer.DebugTracer
+}
+
+func makeLocalStorageBackend(dir, downloadPrefix string, tracer debugtracer.DebugTracer) (*localStorageBackend, error) {
+	dir = osutil.Abs(dir)
+	if err := osutil.MkdirAll(dir); err != nil {
+		return nil, fmt.Errorf("failed to create %v: %w", dir, err)
+	}
+	tracer.Log("created local backend for dir '%s'", dir)
+	if downloadPrefix == "" {
+		downloadPrefix = (&url.URL{Scheme: "file", Path: dir}).String()
+	}
+	return &localStorageBackend{
+		dir:            dir,
+		downloadPrefix: strings.TrimSuffix(downloadPrefix, "/") + "/",
+		tracer:         tracer,
+	}, nil
+}
+
+func (lsb *localStorageBackend) filePath(path string) (string, error) {
+	file := filepath.Join(lsb.dir, filepath.FromSlash(path))
+	if !strings.HasPrefix(file, lsb.dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes the storage dir", path)
+	}
+	return file, nil
+}
+
+func (lsb *localStorageBackend) upload(req *uploadRequest) (*uploadResponse, error) {
+	file, err := lsb.filePath(req.savePath)
+	if err != nil {
+		return nil, err
+	}
+	if !req.overwrite && osutil.IsExist(file) {
+		return nil, &FileExistsError{req.savePath}
+	}
+	if err := osutil.MkdirAll(filepath.Dir(file)); err != nil {
+		return nil, err
+	}
+	// Write to a temp file first, so that readers never observe partially written files.
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".tmp*")
+	if err != nil {
+		return nil, err
+	}
+	// CreateTemp creates files only readable by the owner, but the directory may be served to others.
+	if err := tmp.Chmod(osutil.DefaultFilePerm); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+	lsb.tracer.Log("local upload: writing %s", file)
+	return &uploadResponse{
+		path: req.savePath,
+		writer: &wrappedWriteCloser{
+			writer: tmp,
+			closeCallback: func() error {
+				return osutil.Rename(tmp.Name(), file)
+			},
+			abortCallback: func(err error) {
+				os.Remove(tmp.Name())
+			},
+		},
+	}, nil
+}
+
+func (lsb *localStorageBackend) downloadURL(path string, publicURL bool) (string, error) {
+	return lsb.downloadPrefix + path, nil
+}
+
+func (lsb *localStorageBackend) getPath(url string) (string, error) {
+	if !strings.HasPrefix(url, lsb.downloadPrefix) {
+		return "", ErrUnknownBucket
+	}
+	return strings.TrimPrefix(url, lsb.downloadPrefix), nil
+}
+
+// tmpFileRe matches the names of the files that are still being uploaded.
+var tmpFileRe = regexp.MustCompile(`\.tmp[0-9]+$`)
+
+func (lsb *localStorageBackend) list() ([]*gcs.Object, error) {
+	ret := []*gcs.Object{}
+	err := filepath.WalkDir(lsb.dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || tmpFileRe.MatchString(d.Name()) {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(lsb.dir, file)
+		if err != nil {
+			return err
+		}
+		ret = append(ret, &gcs.Object{
+			Path:      filepath.ToSlash(rel),
+			CreatedAt: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %v: %w", lsb.dir, err)
+	}
+	return ret, nil
+}
+
+func (lsb *localStorageBackend) remove(path string) error {
+	file, err := lsb.filePath(path)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(file)
+	if os.IsNotExist(err) {
+		return ErrAssetDoesNotExist
+	}
+	return err
+}
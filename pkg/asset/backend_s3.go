@@ -0,0 +1,357 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package asset
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/debugtracer"
+	"github.com/google/syzkaller/pkg/gcs"
+)
+
+// s3StorageBackend stores assets in an S3-compatible object store (AWS S3, MinIO, Ceph RGW, etc).
+// Objects are addressed in the path style: endpoint/bucket/key.
+type s3StorageBackend struct {
+	client    *http.Client
+	endpoint  string // e.g. "http://minio.local:9000"
+	bucket    string
+	prefix    string // key prefix inside the bucket, empty or ends with "/"
+	region    string
+	accessKey string
+	secretKey string
+	tracer    debugtracer.DebugTracer
+	now       func() time.Time
+}
+
+// makeS3StorageBackendFromEnv creates a backend for the "bucket/prefix" location.
+// The endpoint and the credentials are taken from the standard AWS environment variables:
+// AWS_ENDPOINT_URL, AWS_REGION (or AWS_DEFAULT_REGION), AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY.
+func makeS3StorageBackendFromEnv(location string, tracer debugtracer.DebugTracer) (*s3StorageBackend, error) {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := os.Getenv("AWS_ENDPOINT_URL")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%v.amazonaws.com", region)
+	}
+	return makeS3StorageBackend(endpoint, location, region,
+		os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"), tracer)
+}
+
+func makeS3StorageBackend(endpoint, location, region, accessKey, secretKey string,
+	tracer debugtracer.DebugTracer) (*s3StorageBackend, error) {
+	bucket, prefix, _ := strings.Cut(strings.Trim(location, "/"), "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("no bucket in s3 location %q", location)
+	}
+	if prefix != "" {
+		prefix += "/"
+	}
+	if accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("s3 credentials are not specified")
+	}
+	tracer.Log("created s3 backend for %s, bucket '%s', prefix '%s'", endpoint, bucket, prefix)
+	return &s3StorageBackend{
+		client:    &http.Client{Timeout: time.Hour},
+		endpoint:  strings.TrimSuffix(endpoint, "/"),
+		bucket:    bucket,
+		prefix:    prefix,
+		region:    region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		tracer:    tracer,
+		now:       time.Now,
+	}, nil
+}
+
+// objectURL returns the URL of the object, the key is escaped segment by segment.
+func (sb *s3StorageBackend) objectURL(path string) string {
+	return fmt.Sprintf("%s/%s/%s", sb.endpoint, sb.bucket, awsEscape(sb.prefix+path, false))
+}
+
+func (sb *s3StorageBackend) upload(req *uploadRequest) (*uploadResponse, error) {
+	if !req.overwrite {
+		// Best-effort check only, same as for GCS.
+		exists, err := sb.exists(req.savePath)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, &FileExistsError{req.savePath}
+		}
+	}
+	// S3 PUT needs to know the content length in advance, so spool the data to a temp file.
+	tmp, err := os.CreateTemp("", "syz-s3-upload")
+	if err != nil {
+		return nil, err
+	}
+	return &uploadResponse{
+		path: req.savePath,
+		writer: &wrappedWriteCloser{
+			writer: tmp,
+			closeCallback: func() error {
+				defer os.Remove(tmp.Name())
+				return sb.put(req, tmp.Name())
+			},
+			abortCallback: func(err error) {
+				os.Remove(tmp.Name())
+			},
+		},
+	}, nil
+}
+
+func (sb *s3StorageBackend) put(req *uploadRequest, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequest(http.MethodPut, sb.objectURL(req.savePath), f)
+	if err != nil {
+		return err
+	}
+	httpReq.ContentLength = stat.Size()
+	if req.contentType != "" {
+		httpReq.Header.Set("Content-Type", req.contentType)
+	}
+	if req.contentEncoding != "" {
+		httpReq.Header.Set("Content-Encoding", req.contentEncoding)
+	}
+	if req.publish {
+		httpReq.Header.Set("X-Amz-Acl", "public-read")
+	}
+	resp, err := sb.do(httpReq)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	sb.tracer.Log("s3 upload: put %s (%v bytes)", req.savePath, stat.Size())
+	return nil
+}
+
+func (sb *s3StorageBackend) exists(path string) (bool, error) {
+	httpReq, err := http.NewRequest(http.MethodHead, sb.objectURL(path), nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := sb.do(httpReq)
+	var statusErr *s3StatusError
+	if errors.As(err, &statusErr) && statusErr.code == http.StatusNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	return true, nil
+}
+
+func (sb *s3StorageBackend) downloadURL(path string, publicURL bool) (string, error) {
+	return sb.objectURL(path), nil
+}
+
+func (sb *s3StorageBackend) getPath(downloadURL string) (string, error) {
+	prefix := sb.objectURL("")
+	if strings.HasPrefix(downloadURL, prefix) {
+		return url.PathUnescape(strings.TrimPrefix(downloadURL, prefix))
+	}
+	if strings.HasPrefix(downloadURL, sb.endpoint+"/") {
+		return "", ErrUnknownBucket
+	}
+	return "", fmt.Errorf("not allowed host: %s", downloadURL)
+}
+
+type s3ListResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (sb *s3StorageBackend) list() ([]*gcs.Object, error) {
+	ret := []*gcs.Object{}
+	token := ""
+	for {
+		query := url.Values{
+			"list-type": {"2"},
+			"prefix":    {sb.prefix},
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		httpReq, err := http.NewRequest(http.MethodGet,
+			fmt.Sprintf("%s/%s?%s", sb.endpoint, sb.bucket, query.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := sb.do(httpReq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		var res s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&res)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse list response: %w", err)
+		}
+		for _, obj := range res.Contents {
+			ret = append(ret, &gcs.Object{
+				Path:      strings.TrimPrefix(obj.Key, sb.prefix),
+				CreatedAt: obj.LastModified,
+			})
+		}
+		if !res.IsTruncated || res.NextContinuationToken == "" {
+			break
+		}
+		token = res.NextContinuationToken
+	}
+	return ret, nil
+}
+
+func (sb *s3StorageBackend) remove(path string) error {
+	// S3 DELETE succeeds for missing objects, so check it explicitly.
+	exists, err := sb.exists(path)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrAssetDoesNotExist
+	}
+	httpReq, err := http.NewRequest(http.MethodDelete, sb.objectURL(path), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := sb.do(httpReq)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+type s3StatusError struct {
+	code int
+	body string
+}
+
+func (e *s3StatusError) Error() string {
+	return fmt.Sprintf("s3 request failed with status %v: %s", e.code, e.body)
+}
+
+func (sb *s3StorageBackend) do(req *http.Request) (*http.Response, error) {
+	signS3Request(req, unsignedPayload, sb.accessKey, sb.secretKey, sb.region, sb.now())
+	resp, err := sb.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		resp.Body.Close()
+		return nil, &s3StatusError{resp.StatusCode, string(body)}
+	}
+	return resp, nil
+}
+
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// signS3Request signs the request with AWS Signature Version 4.
+// All headers present in req.Header are signed along with the host header.
+// See https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html.
+func signS3Request(req *http.Request, payloadHash, accessKey, secretKey, region string, now time.Time) {
+	const service = "s3"
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, vals := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(vals, ","))
+	}
+	var names []string
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	canonicalHeaders := new(strings.Builder)
+	for _, name := range names {
+		fmt.Fprintf(canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	var queryParts []string
+	for key, vals := range req.URL.Query() {
+		for _, val := range vals {
+			queryParts = append(queryParts, awsEscape(key, true)+"="+awsEscape(val, true))
+		}
+	}
+	sort.Strings(queryParts)
+	path := req.URL.Path
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		awsEscape(path, false),
+		strings.Join(queryParts, "&"),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", date, region, service)
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := fmt.Sprintf("AWS4-HMAC-SHA256\n%s\n%s\n%x", amzDate, scope, canonicalHash)
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// awsEscape implements URI encoding as required by AWS signatures:
+// everything except the unreserved characters is percent-encoded.
+func awsEscape(s string, encodeSlash bool) string {
+	var sb strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			sb.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			sb.WriteByte(c)
+		default:
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
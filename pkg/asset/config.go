@@ -16,9 +16,16 @@ type Config struct {
 	Debug bool `json:"debug"`
 	// Where to upload artifacts.
 	// If "gs://bucket/" is specified, assets will be stored in the corresponding GCS bucket.
+	// If "s3://bucket/" is specified, assets will be stored in an S3-compatible object store.
+	// The endpoint and credentials are taken from the AWS_ENDPOINT_URL, AWS_REGION,
+	// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables.
+	// If "file:///path/" is specified, assets will be stored in the local directory.
 	// If "dummy://" is specified, assets will not be actually stored anywhere. May be helpful
 	// for debugging.
 	UploadTo string `json:"upload_to"`
+	// For "file://" storage: the URL the directory is served at (e.g. "https://host/assets/").
+	// If not set, download URLs are file:// URLs.
+	DownloadURL string `json:"download_url"`
 	// Perform asset deprecation from this instance. If several syz-ci's share a common stoage,
 	// it make sense to enable derprecation only on one of them.
 	DoDeprecation bool `json:"do_deprecation"`
@@ -59,7 +66,7 @@ func (c *Config) Validate() error {
 	if c.UploadTo == "" && len(c.Assets) != 0 {
 		return fmt.Errorf("assets are specified, but upload_to is empty")
 	}
-	allowedFormats := []string{"gs://", "dummy://"}
+	allowedFormats := []string{"gs://", "s3://", "file://", "dummy://"}
 	if c.UploadTo != "" {
 		any := false
 		for _, prefix := range allowedFormats {
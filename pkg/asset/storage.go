@@ -45,17 +45,9 @@ func StorageFromConfig(cfg *Config, dash Dashboard) (*Storage, error) {
 			TraceWriter: os.Stdout,
 		}
 	}
-	var backend StorageBackend
-	if strings.HasPrefix(cfg.UploadTo, "gs://") {
-		var err error
-		backend, err = makeCloudStorageBackend(strings.TrimPrefix(cfg.UploadTo, "gs://"), tracer)
-		if err != nil {
-			return nil, fmt.Errorf("the call to MakeCloudStorageBackend failed: %w", err)
-		}
-	} else if strings.HasPrefix(cfg.UploadTo, "dummy://") {
-		backend = makeDummyStorageBackend()
-	} else {
-		return nil, fmt.Errorf("unknown UploadTo during StorageFromConfig(): %#v", cfg.UploadTo)
+	backend, err := makeBackend(cfg.UploadTo, cfg.DownloadURL, tracer)
+	if err != nil {
+		return nil, err
 	}
 	return &Storage{
 		cfg:     cfg,
@@ -143,7 +135,7 @@ func (storage *Storage) uploadFileStream(reader io.Reader, assetType dashapi.Ass
 		written, err := io.Copy(res.writer, reader)
 		if err != nil {
 			more := ""
-			closeErr := res.writer.Close()
+			closeErr := abortWriter(res.writer, err)
 			var exiterr *exec.ExitError
 			if errors.As(closeErr, &exiterr) {
 				more = fmt.Sprintf(", process state '%s'", exiterr.ProcessState)
@@ -290,11 +282,35 @@ func (storage *Storage) DeprecateAssets() (DeprecateStats, error) {
 	return stats, nil
 }
 
+func makeBackend(uploadTo, downloadURL string, tracer debugtracer.DebugTracer) (StorageBackend, error) {
+	switch {
+	case strings.HasPrefix(uploadTo, "gs://"):
+		backend, err := makeCloudStorageBackend(strings.TrimPrefix(uploadTo, "gs://"), tracer)
+		if err != nil {
+			return nil, fmt.Errorf("the call to MakeCloudStorageBackend failed: %w", err)
+		}
+		return backend, nil
+	case strings.HasPrefix(uploadTo, "s3://"):
+		return makeS3StorageBackendFromEnv(strings.TrimPrefix(uploadTo, "s3://"), tracer)
+	case strings.HasPrefix(uploadTo, "file://"):
+		return makeLocalStorageBackend(strings.TrimPrefix(uploadTo, "file://"), downloadURL, tracer)
+	case strings.HasPrefix(uploadTo, "http://"), strings.HasPrefix(uploadTo, "https://"):
+		return makeHTTPPutBackend(uploadTo), nil
+	case strings.HasPrefix(uploadTo, "dummy://"):
+		return makeDummyStorageBackend(), nil
+	}
+	return nil, fmt.Errorf("unknown upload destination: %#v", uploadTo)
+}
+
 type uploadRequest struct {
 	savePath          string
 	contentEncoding   string
 	contentType       string
 	preserveExtension bool
+	// Overwrite the existing file instead of returning FileExistsError.
+	overwrite bool
+	// Make the uploaded file publicly readable (if the backend supports it).
+	publish bool
 }
 
 type uploadResponse struct {
@@ -332,6 +348,9 @@ func xzCompressor(req *uploadRequest,
 		writer: &wrappedWriteCloser{
 			writer:        xzWriter,
 			closeCallback: resp.writer.Close,
+			abortCallback: func(err error) {
+				abortWriter(resp.writer, err)
+			},
 		},
 	}, nil
 }
@@ -340,28 +359,51 @@ const gzipCompressionRatio = 4
 
 // This struct allows to attach a callback on the Close() method invocation of
 // an existing io.WriteCloser. Also, it can convert an io.Writer to an io.WriteCloser.
+// If a write has failed or the upload was aborted, closeCallback is not invoked
+// (so that truncated objects are not committed), abortCallback is invoked instead.
 type wrappedWriteCloser struct {
 	writer        io.Writer
 	closeCallback func() error
+	abortCallback func(err error)
+	err           error
 }
 
 func (wwc *wrappedWriteCloser) Write(p []byte) (int, error) {
-	return wwc.writer.Write(p)
+	n, err := wwc.writer.Write(p)
+	if err != nil && wwc.err == nil {
+		wwc.err = err
+	}
+	return n, err
 }
 
 func (wwc *wrappedWriteCloser) Close() error {
 	var err error
-	closer, ok := wwc.writer.(io.Closer)
-	if ok {
+	if closer, ok := wwc.writer.(interface{ CloseWithError(error) error }); ok && wwc.err != nil {
+		err = closer.CloseWithError(wwc.err)
+	} else if closer, ok := wwc.writer.(io.Closer); ok {
 		err = closer.Close()
 	}
-	err2 := wwc.closeCallback()
-	if err != nil {
-		return err
-	} else if err2 != nil {
-		return err2
+	if wwc.err == nil {
+		wwc.err = err
+	}
+	if wwc.err != nil {
+		if wwc.abortCallback != nil {
+			wwc.abortCallback(wwc.err)
+		}
+		return wwc.err
 	}
-	return nil
+	return wwc.closeCallback()
+}
+
+// abortWriter closes the writer without committing the written data.
+func abortWriter(w io.WriteCloser, err error) error {
+	if wwc, ok := w.(*wrappedWriteCloser); ok {
+		if wwc.err == nil {
+			wwc.err = err
+		}
+		return wwc.Close()
+	}
+	return w.Close()
 }
 
 func gzipCompressor(req *uploadRequest,
@@ -386,6 +428,9 @@ func gzipCompressor(req *uploadRequest,
 			closeCallback: func() error {
 				return resp.writer.Close()
 			},
+			abortCallback: func(err error) {
+				abortWriter(resp.writer, err)
+			},
 		},
 	}, nil
 }
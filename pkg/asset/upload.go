@@ -0,0 +1,130 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package asset
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/debugtracer"
+	"github.com/google/syzkaller/pkg/gcs"
+	"github.com/google/syzkaller/pkg/log"
+)
+
+// UploadFile stores the contents of reader as dstPath/name, overwriting the existing file.
+// dstPath may use any of the storage backends (gs://, s3://, file://) or be an http:// or
+// https:// URL, in which case the file is sent with HTTP PUT.
+// This is used for artifacts that are not tracked by the dashboard (coverage reports, corpus, etc).
+func UploadFile(dstPath, name string, reader io.Reader, publish bool) error {
+	URL, err := url.Parse(dstPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse upload path: %w", err)
+	}
+	if URL.Scheme == "" {
+		// Unqualified paths were historically treated as GCS paths.
+		dstPath = "gs://" + dstPath
+	}
+	dstPath = strings.TrimSuffix(dstPath, "/")
+	log.Logf(0, "uploading %v to %v", name, dstPath)
+	backend, err := makeBackend(dstPath, "", &debugtracer.NullTracer{})
+	if err != nil {
+		return err
+	}
+	if closer, ok := backend.(io.Closer); ok {
+		defer closer.Close()
+	}
+	resp, err := backend.upload(&uploadRequest{
+		savePath:  name,
+		overwrite: true,
+		publish:   publish,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(resp.writer, reader); err != nil {
+		abortWriter(resp.writer, err)
+		return fmt.Errorf("failed to upload %v: %w", name, err)
+	}
+	return resp.writer.Close()
+}
+
+// httpPutBackend uploads files with HTTP PUT requests.
+// The server is not expected to support listing and deletion, so it can't be used for assets.
+type httpPutBackend struct {
+	baseURL string
+}
+
+func makeHTTPPutBackend(baseURL string) *httpPutBackend {
+	return &httpPutBackend{baseURL: baseURL}
+}
+
+var errNotSupported = errors.New("the operation is not supported by the storage backend")
+
+func (hb *httpPutBackend) upload(req *uploadRequest) (*uploadResponse, error) {
+	URL, err := url.Parse(hb.baseURL)
+	if err != nil {
+		return nil, err
+	}
+	URL.Path = path.Join(URL.Path, req.savePath)
+	pr, pw := io.Pipe()
+	httpReq, err := http.NewRequest(http.MethodPut, URL.String(), pr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP PUT request: %w", err)
+	}
+	if req.contentType != "" {
+		httpReq.Header.Set("Content-Type", req.contentType)
+	}
+	if req.contentEncoding != "" {
+		httpReq.Header.Set("Content-Encoding", req.contentEncoding)
+	}
+	done := make(chan error, 1)
+	go func() {
+		resp, err := http.DefaultClient.Do(httpReq)
+		if err != nil {
+			err = fmt.Errorf("failed to perform HTTP PUT request: %w", err)
+		} else {
+			resp.Body.Close()
+			if resp.StatusCode < 200 || resp.StatusCode > 299 {
+				err = fmt.Errorf("HTTP PUT failed with status code: %v", resp.StatusCode)
+			}
+		}
+		// Unblock the writer if the request has failed before consuming the whole body.
+		pr.CloseWithError(err)
+		done <- err
+	}()
+	return &uploadResponse{
+		path: req.savePath,
+		writer: &wrappedWriteCloser{
+			writer: pw,
+			closeCallback: func() error {
+				return <-done
+			},
+			abortCallback: func(err error) {
+				// The pipe is closed with the error, so the request fails without completing the body.
+				<-done
+			},
+		},
+	}, nil
+}
+
+func (hb *httpPutBackend) downloadURL(path string, publicURL bool) (string, error) {
+	return "", errNotSupported
+}
+
+func (hb *httpPutBackend) getPath(url string) (string, error) {
+	return "", errNotSupported
+}
+
+func (hb *httpPutBackend) list() ([]*gcs.Object, error) {
+	return nil, errNotSupported
+}
+
+func (hb *httpPutBackend) remove(path string) error {
+	return errNotSupported
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package asset
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/debugtracer"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadFileLocal(t *testing.T) {
+	dir := t.TempDir()
+	for _, contents := range []string{"first", "second"} {
+		// Existing files must be overwritten.
+		err := UploadFile("file://"+dir+"/cover/", "manager.html", strings.NewReader(contents), false)
+		assert.NoError(t, err)
+		data, err := os.ReadFile(filepath.Join(dir, "cover", "manager.html"))
+		assert.NoError(t, err)
+		assert.Equal(t, contents, string(data))
+	}
+}
+
+func TestUploadFileLocalReadError(t *testing.T) {
+	dir := t.TempDir()
+	reader := io.MultiReader(strings.NewReader("truncated"), iotest.ErrReader(errors.New("read failed")))
+	err := UploadFile("file://"+dir, "manager.html", reader, false)
+	assert.ErrorContains(t, err, "read failed")
+	// Neither the truncated file, nor the temp file must be left behind.
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestLocalStorageUpload(t *testing.T) {
+	dir := t.TempDir()
+	be, err := makeLocalStorageBackend(dir, "", &debugtracer.TestTracer{T: t})
+	assert.NoError(t, err)
+	resp, err := be.upload(&uploadRequest{savePath: "tag/file.xz"})
+	assert.NoError(t, err)
+	_, err = resp.writer.Write([]byte("data"))
+	assert.NoError(t, err)
+	// The file being uploaded must not be listed.
+	list, err := be.list()
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+	assert.NoError(t, resp.writer.Close())
+
+	list, err = be.list()
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, "tag/file.xz", list[0].Path)
+	// Uploaded files must be readable by others, e.g. by the HTTP server serving the dir.
+	info, err := os.Stat(filepath.Join(dir, "tag", "file.xz"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(osutil.DefaultFilePerm), info.Mode().Perm())
+}
+
+func TestUploadFileHTTPPut(t *testing.T) {
+	var gotPath, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		gotPath, gotBody = r.URL.Path, string(data)
+	}))
+	defer srv.Close()
+	err := UploadFile(srv.URL+"/upload", "corpus.db", strings.NewReader("corpus"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, "/upload/corpus.db", gotPath)
+	assert.Equal(t, "corpus", gotBody)
+
+	err = UploadFile(srv.URL+"/upload", "corpus.db", strings.NewReader("corpus"), false)
+	assert.NoError(t, err)
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	err = UploadFile(srv.URL+"/upload", "corpus.db", strings.NewReader("corpus"), false)
+	assert.ErrorContains(t, err, "status code: 403")
+}
+
+func TestLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	be, err := makeLocalStorageBackend(dir, "https://assets.local/", &debugtracer.TestTracer{T: t})
+	assert.NoError(t, err)
+	storage := &Storage{
+		cfg:     &Config{UploadTo: "file://" + dir},
+		backend: be,
+		dash:    newDashMock(),
+		tracer:  &debugtracer.TestTracer{T: t},
+	}
+	build := &dashapi.Build{ID: "1234", KernelCommit: "abcdef2134"}
+	keep, err := storage.UploadBuildAsset(strings.NewReader("image"), "disk.raw",
+		dashapi.BootableDisk, build, &ExtraUploadArg{UniqueTag: "tag1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://assets.local/tag1/disk-abcdef21.raw.xz", keep.DownloadURL)
+	_, err = storage.UploadBuildAsset(strings.NewReader("vmlinux"), "vmlinux",
+		dashapi.KernelObject, build, &ExtraUploadArg{UniqueTag: "tag2"})
+	assert.NoError(t, err)
+	// With SkipIfExists, the existing asset's URL is returned.
+	again, err := storage.UploadBuildAsset(strings.NewReader("image"), "disk.raw",
+		dashapi.BootableDisk, build, &ExtraUploadArg{UniqueTag: "tag1", SkipIfExists: true})
+	assert.NoError(t, err)
+	assert.Equal(t, keep.DownloadURL, again.DownloadURL)
+
+	assert.NoError(t, storage.ReportBuildAssets(build, keep))
+	old := time.Now().Add(-2 * deletionEmbargo)
+	list, err := be.list()
+	assert.NoError(t, err)
+	for _, obj := range list {
+		file := filepath.Join(dir, filepath.FromSlash(obj.Path))
+		assert.NoError(t, os.Chtimes(file, old, old))
+	}
+	stats, err := storage.DeprecateAssets()
+	assert.NoError(t, err)
+	assert.Equal(t, DeprecateStats{Needed: 1, Existing: 2, Deleted: 1}, stats)
+	assert.FileExists(t, filepath.Join(dir, "tag1", "disk-abcdef21.raw.xz"))
+	assert.NoFileExists(t, filepath.Join(dir, "tag2", "vmlinux-abcdef21.xz"))
+
+	_, err = be.getPath("https://other.local/tag1/file")
+	assert.ErrorIs(t, err, ErrUnknownBucket)
+	_, err = be.filePath("../escape")
+	assert.Error(t, err)
+}
@@ -193,6 +193,19 @@ func (n *TypeDef) Info() (Pos, string, string) {
 	return n.Pos, "type", n.Name.Name
 }
 
+// Protocol describes valid orders of calls on a resource as a state machine.
+type Protocol struct {
+	Pos         Pos
+	Name        *Ident
+	Resource    *Ident
+	Transitions []*Transition
+	Comments    []*Comment
+}
+
+func (n *Protocol) Info() (Pos, string, string) {
+	return n.Pos, "protocol", n.Name.Name
+}
+
 // Not top-level AST nodes.
 
 type Ident struct {
@@ -304,3 +317,16 @@ type Field struct {
 func (n *Field) Info() (Pos, string, string) {
 	return n.Pos, "arg/field", n.Name.Name
 }
+
+type Transition struct {
+	Pos      Pos
+	Call     *Ident
+	From     *Ident
+	To       *Ident
+	NewBlock bool // separated from previous transitions by a new line
+	Comments []*Comment
+}
+
+func (n *Transition) Info() (Pos, string, string) {
+	return n.Pos, "transition", n.Call.Name
+}
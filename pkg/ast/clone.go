@@ -111,6 +111,31 @@ func (n *Struct) Clone() Node {
 	}
 }
 
+func (n *Protocol) Clone() Node {
+	var transitions []*Transition
+	for _, t := range n.Transitions {
+		transitions = append(transitions, t.Clone().(*Transition))
+	}
+	return &Protocol{
+		Pos:         n.Pos,
+		Name:        n.Name.Clone().(*Ident),
+		Resource:    n.Resource.Clone().(*Ident),
+		Transitions: transitions,
+		Comments:    cloneComments(n.Comments),
+	}
+}
+
+func (n *Transition) Clone() Node {
+	return &Transition{
+		Pos:      n.Pos,
+		Call:     n.Call.Clone().(*Ident),
+		From:     n.From.Clone().(*Ident),
+		To:       n.To.Clone().(*Ident),
+		NewBlock: n.NewBlock,
+		Comments: cloneComments(n.Comments),
+	}
+}
+
 func (n *IntFlags) Clone() Node {
 	return &IntFlags{
 		Pos:    n.Pos,
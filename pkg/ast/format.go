@@ -167,6 +167,38 @@ func (n *Struct) serialize(w io.Writer) {
 	fmt.Fprintf(w, "\n")
 }
 
+func (n *Protocol) serialize(w io.Writer) {
+	fmt.Fprintf(w, "protocol %v[%v] {\n", n.Name.Name, n.Resource.Name)
+	// Align states of all transitions to the same columns.
+	const tabWidth = 8
+	callTabs, fromTabs := 0, 0
+	for _, t := range n.Transitions {
+		callTabs = max(callTabs, (len(t.Call.Name)+tabWidth)/tabWidth)
+		fromTabs = max(fromTabs, (len(t.From.Name)+tabWidth)/tabWidth)
+	}
+	for _, t := range n.Transitions {
+		if t.NewBlock {
+			fmt.Fprintf(w, "\n")
+		}
+		for _, com := range t.Comments {
+			com.serialize(w)
+		}
+		fmt.Fprintf(w, "\t%v\t", t.Call.Name)
+		for tabs := len(t.Call.Name)/tabWidth + 1; tabs < callTabs; tabs++ {
+			fmt.Fprintf(w, "\t")
+		}
+		fmt.Fprintf(w, "%v\t", t.From.Name)
+		for tabs := len(t.From.Name)/tabWidth + 1; tabs < fromTabs; tabs++ {
+			fmt.Fprintf(w, "\t")
+		}
+		fmt.Fprintf(w, "%v\n", t.To.Name)
+	}
+	for _, com := range n.Comments {
+		com.serialize(w)
+	}
+	fmt.Fprintf(w, "}\n")
+}
+
 func (n *IntFlags) serialize(w io.Writer) {
 	fmt.Fprintf(w, "%v = ", n.Name.Name)
 	for i, v := range n.Values {
@@ -133,6 +133,10 @@ func (p *parser) parseTop() Node {
 			return p.parseMeta()
 		case "type":
 			return p.parseTypeDef()
+		case "protocol":
+			if p.tok == tokIdent {
+				return p.parseProtocol(name.Pos)
+			}
 		}
 		switch p.tok {
 		case tokLParen:
@@ -402,6 +406,41 @@ func (p *parser) parseStruct(name *Ident) *Struct {
 	return str
 }
 
+func (p *parser) parseProtocol(pos Pos) *Protocol {
+	proto := &Protocol{
+		Pos:  pos,
+		Name: p.parseIdent(),
+	}
+	p.consume(tokLBrack)
+	proto.Resource = p.parseIdent()
+	p.consume(tokRBrack)
+	p.consume(tokLBrace)
+	p.consume(tokNewLine)
+	for {
+		newBlock := false
+		for p.tok == tokNewLine {
+			newBlock = true
+			p.next()
+		}
+		comments := p.parseCommentBlock()
+		if p.tryConsume(tokRBrace) {
+			proto.Comments = comments
+			break
+		}
+		call := p.parseIdent()
+		proto.Transitions = append(proto.Transitions, &Transition{
+			Pos:      call.Pos,
+			Call:     call,
+			From:     p.parseIdent(),
+			To:       p.parseIdent(),
+			NewBlock: newBlock,
+			Comments: comments,
+		})
+		p.consume(tokNewLine)
+	}
+	return proto
+}
+
 func (p *parser) parseCommentBlock() []*Comment {
 	var comments []*Comment
 	for p.tok == tokComment {
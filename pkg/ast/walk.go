@@ -100,6 +100,26 @@ func (n *Struct) walk(cb func(Node)) {
 	}
 }
 
+func (n *Protocol) walk(cb func(Node)) {
+	cb(n.Name)
+	cb(n.Resource)
+	for _, t := range n.Transitions {
+		cb(t)
+	}
+	for _, c := range n.Comments {
+		cb(c)
+	}
+}
+
+func (n *Transition) walk(cb func(Node)) {
+	cb(n.Call)
+	cb(n.From)
+	cb(n.To)
+	for _, c := range n.Comments {
+		cb(c)
+	}
+}
+
 func (n *IntFlags) walk(cb func(Node)) {
 	cb(n.Name)
 	for _, v := range n.Values {
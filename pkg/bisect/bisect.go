@@ -158,33 +158,9 @@ func Run(cfg *Config) (*Result, error) {
 }
 
 func runImpl(cfg *Config, repo vcs.Repo, inst instance.Env) (*Result, error) {
-	bisecter, ok := repo.(vcs.Bisecter)
-	if !ok {
-		return nil, fmt.Errorf("bisection is not implemented for %v", cfg.Manager.TargetOS)
-	}
-	minimizer, ok := repo.(vcs.ConfigMinimizer)
-	if !ok && len(cfg.Kernel.BaselineConfig) != 0 {
-		return nil, fmt.Errorf("config minimization is not implemented for %v", cfg.Manager.TargetOS)
-	}
-	env := &env{
-		cfg:        cfg,
-		repo:       repo,
-		bisecter:   bisecter,
-		minimizer:  minimizer,
-		inst:       inst,
-		startTime:  time.Now(),
-		confidence: 1.0,
-		buildCfg: instance.BuildKernelConfig{
-			CompilerBin:  cfg.DefaultCompiler,
-			MakeBin:      cfg.Make,
-			LinkerBin:    cfg.Linker,
-			CcacheBin:    cfg.Ccache,
-			UserspaceDir: cfg.Kernel.Userspace,
-			CmdlineFile:  cfg.Kernel.Cmdline,
-			SysctlFile:   cfg.Kernel.Sysctl,
-			KernelConfig: cfg.Kernel.Config,
-			BuildCPUs:    cfg.BuildCPUs,
-		},
+	env, err := newEnv(cfg, repo, inst)
+	if err != nil {
+		return nil, err
 	}
 	head, err := repo.Commit(vcs.HEAD)
 	if err != nil {
@@ -247,6 +223,37 @@ func runImpl(cfg *Config, repo vcs.Repo, inst instance.Env) (*Result, error) {
 	return res, nil
 }
 
+func newEnv(cfg *Config, repo vcs.Repo, inst instance.Env) (*env, error) {
+	bisecter, ok := repo.(vcs.Bisecter)
+	if !ok {
+		return nil, fmt.Errorf("bisection is not implemented for %v", cfg.Manager.TargetOS)
+	}
+	minimizer, ok := repo.(vcs.ConfigMinimizer)
+	if !ok && len(cfg.Kernel.BaselineConfig) != 0 {
+		return nil, fmt.Errorf("config minimization is not implemented for %v", cfg.Manager.TargetOS)
+	}
+	return &env{
+		cfg:        cfg,
+		repo:       repo,
+		bisecter:   bisecter,
+		minimizer:  minimizer,
+		inst:       inst,
+		startTime:  time.Now(),
+		confidence: 1.0,
+		buildCfg: instance.BuildKernelConfig{
+			CompilerBin:  cfg.DefaultCompiler,
+			MakeBin:      cfg.Make,
+			LinkerBin:    cfg.Linker,
+			CcacheBin:    cfg.Ccache,
+			UserspaceDir: cfg.Kernel.Userspace,
+			CmdlineFile:  cfg.Kernel.Cmdline,
+			SysctlFile:   cfg.Kernel.Sysctl,
+			KernelConfig: cfg.Kernel.Config,
+			BuildCPUs:    cfg.BuildCPUs,
+		},
+	}, nil
+}
+
 func (env *env) bisect() (*Result, error) {
 	err := env.bisecter.PrepareBisect()
 	if err != nil {
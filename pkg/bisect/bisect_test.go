@@ -51,6 +51,13 @@ func (env *testEnv) BuildKernel(buildCfg *instance.BuildKernelConfig) (string, b
 	if env.config == "baseline-fails" {
 		return "", details, fmt.Errorf("failure")
 	}
+	if commit >= env.test.buildErrStart && commit <= env.test.buildErrEnd {
+		return "", details, &build.KernelError{Report: []byte(fmt.Sprintf("net/core/dev.c:%v:5: error: 'foo' undeclared",
+			commit))}
+	}
+	if commit >= env.test.otherBuildErrStart && commit <= env.test.otherBuildErrEnd {
+		return "", details, &build.KernelError{Report: []byte("fs/inode.c:10:5: error: 'bar' undeclared")}
+	}
 	return "", details, nil
 }
 
@@ -313,7 +320,12 @@ type BisectionTest struct {
 	brokenEnd         int
 	infraErrStart     int
 	infraErrEnd       int
-	reportType        crash.Type
+	// Ranges of commits where the build fails (for build failure bisection).
+	buildErrStart      int
+	buildErrEnd        int
+	otherBuildErrStart int
+	otherBuildErrEnd   int
+	reportType         crash.Type
 	// Range of commits that result in the same kernel binary signature.
 	sameBinaryStart int
 	sameBinaryEnd   int
@@ -1069,3 +1081,85 @@ func TestPickReleaseTags(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildFailureBisection(t *testing.T) {
+	baseDir := createTestRepo(t)
+	tests := []struct {
+		name    string
+		test    BisectionTest
+		good    string
+		commits []string
+		err     bool
+	}{
+		{
+			name:    "conclusive",
+			test:    BisectionTest{buildErrStart: 703, buildErrEnd: 999},
+			good:    "600",
+			commits: []string{"703"},
+		},
+		{
+			name: "skip-other-failures",
+			test: BisectionTest{
+				buildErrStart:      703,
+				buildErrEnd:        999,
+				otherBuildErrStart: 701,
+				otherBuildErrEnd:   702,
+			},
+			good: "600",
+			// Commits with other build failures are skipped, so the result is inconclusive.
+			commits: []string{"701", "702", "703"},
+		},
+		{
+			name: "builds-on-head",
+			test: BisectionTest{buildErrStart: 100, buildErrEnd: 200},
+			good: "600",
+			err:  true,
+		},
+		{
+			name: "fails-on-good",
+			test: BisectionTest{buildErrStart: 400, buildErrEnd: 999},
+			good: "600",
+			err:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := vcs.NewRepo(targets.TestOS, targets.TestArch64, baseDir, vcs.OptPrecious)
+			assert.NoError(t, err)
+			r.SwitchCommit("master")
+			head, err := r.Commit(vcs.HEAD)
+			assert.NoError(t, err)
+			good, err := r.GetCommitByTitle(test.good)
+			assert.NoError(t, err)
+			cfg := &Config{
+				Trace: &debugtracer.TestTracer{T: t},
+				Manager: &mgrconfig.Config{
+					Derived: mgrconfig.Derived{
+						TargetOS:     targets.TestOS,
+						TargetVMArch: targets.TestArch64,
+					},
+					Type:      "qemu",
+					KernelSrc: baseDir,
+				},
+				Kernel: KernelConfig{
+					Repo:   baseDir,
+					Branch: "master",
+					Commit: head.Hash,
+					Config: []byte("original config"),
+				},
+			}
+			inst := &testEnv{t: t, r: r, test: test.test}
+			res, err := runBuildFailureImpl(cfg, r, inst, good.Hash)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			var titles []string
+			for _, com := range res.Commits {
+				titles = append(titles, com.Title)
+			}
+			assert.ElementsMatch(t, test.commits, titles)
+		})
+	}
+}
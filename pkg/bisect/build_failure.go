@@ -0,0 +1,106 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package bisect
+
+import (
+	"fmt"
+
+	"github.com/google/syzkaller/pkg/build"
+	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/vcs"
+)
+
+// RunBuildFailure bisects the commit that broke the kernel build between the good commit
+// (where the kernel builds) and cfg.Kernel.Commit (where the build fails).
+// A commit is considered bad if the build fails with the same failure (see build.Failure.Signature),
+// and it's skipped if the build fails with a different failure.
+// Repro, Syzkaller and BaselineConfig parts of cfg are not used.
+func RunBuildFailure(cfg *Config, good string) (*Result, error) {
+	if err := checkConfig(cfg); err != nil {
+		return nil, err
+	}
+	repo, err := vcs.NewRepo(cfg.Manager.TargetOS, cfg.Manager.Type, cfg.Manager.KernelSrc)
+	if err != nil {
+		return nil, err
+	}
+	inst, err := instance.NewEnv(cfg.Manager, cfg.BuildSemaphore, cfg.TestSemaphore)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = repo.CheckoutBranch(cfg.Kernel.Repo, cfg.Kernel.Branch); err != nil {
+		return nil, &build.InfraError{Title: fmt.Sprintf("%v", err)}
+	}
+	return runBuildFailureImpl(cfg, repo, inst, good)
+}
+
+func runBuildFailureImpl(cfg *Config, repo vcs.Repo, inst instance.Env, good string) (*Result, error) {
+	cfg.Kernel.BaselineConfig = nil
+	env, err := newEnv(cfg, repo, inst)
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Commit(vcs.HEAD)
+	if err != nil {
+		return nil, err
+	}
+	defer env.repo.SwitchCommit(head.Hash)
+	env.head = head
+	env.kernelConfig = cfg.Kernel.Config
+	if err := env.bisecter.PrepareBisect(); err != nil {
+		return nil, err
+	}
+	env.logf("bisecting build failure between %v and %v", good, cfg.Kernel.Commit)
+	bad, err := env.repo.SwitchCommit(cfg.Kernel.Commit)
+	if err != nil {
+		return nil, err
+	}
+	_, _, err = env.build()
+	if err == nil {
+		return nil, fmt.Errorf("the build failure wasn't reproduced on the original commit")
+	}
+	failure := build.ParseFailure(err)
+	signature := failure.Signature()
+	env.logf("build failure: %v", failure.Title())
+	goodCom, err := env.repo.SwitchCommit(good)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := env.build(); err != nil {
+		return nil, fmt.Errorf("the kernel does not build on the good commit %v: %w", good, err)
+	}
+	pred := func() (vcs.BisectResult, error) {
+		_, _, err := env.build()
+		if err == nil {
+			return vcs.BisectGood, nil
+		}
+		other := build.ParseFailure(err)
+		if other.Signature() == signature {
+			return vcs.BisectBad, nil
+		}
+		env.logf("different build failure: %v", other.Title())
+		return vcs.BisectSkip, nil
+	}
+	commits, err := env.bisecter.Bisect(bad.Hash, goodCom.Hash, cfg.Trace, pred)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{
+		Commits:    commits,
+		Config:     env.kernelConfig,
+		Confidence: 1.0,
+	}
+	if len(commits) == 1 {
+		env.logf("first bad commit: %v %v", commits[0].Hash, commits[0].Title)
+		res.IsRelease, err = env.bisecter.IsRelease(commits[0].Hash)
+		if err != nil {
+			env.logf("failed to detect release: %v", err)
+		}
+	} else {
+		env.logf("bisection is inconclusive, the first bad commit could be any of:")
+		for _, com := range commits {
+			env.logf("%v", com.Hash)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,396 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package btf parses BPF Type Format (BTF) debug info of the Linux kernel.
+// BTF is available in vmlinux/module .BTF ELF sections and in /sys/kernel/btf/* files.
+// See https://docs.kernel.org/bpf/btf.html for the format description.
+package btf
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+)
+
+type Kind int
+
+const (
+	KindVoid Kind = iota
+	KindInt
+	KindPtr
+	KindArray
+	KindStruct
+	KindUnion
+	KindEnum
+	KindFwd
+	KindTypedef
+	KindVolatile
+	KindConst
+	KindRestrict
+	KindFunc
+	KindFuncProto
+	KindVar
+	KindDatasec
+	KindFloat
+	KindDeclTag
+	KindTypeTag
+	KindEnum64
+)
+
+type Type struct {
+	ID   int
+	Kind Kind
+	Name string
+	// Size in bytes for ints, structs, unions, enums and floats (see Spec.SizeOf for other kinds).
+	Size uint64
+	// Referenced type for pointers, arrays, typedefs, modifiers, funcs and vars.
+	Elem *Type
+	// Number of elements for arrays.
+	NumElems uint64
+	// Fields for structs and unions.
+	Members []Member
+	// Values for enums.
+	Values []EnumValue
+
+	elemID int
+}
+
+type Member struct {
+	Name string
+	Type *Type
+	// Offset from the beginning of the struct in bits.
+	BitOffset uint64
+	// Non-zero for bitfields.
+	BitfieldSize uint64
+
+	typeID int
+}
+
+type EnumValue struct {
+	Name string
+	// Negative values of signed enums are sign-extended.
+	Value uint64
+}
+
+// Spec is a parsed BTF blob. Module BTF is split BTF that references types of the base vmlinux BTF.
+type Spec struct {
+	base    *Spec
+	first   int     // id of types[0]
+	types   []*Type // types[0] is void for non-split BTF
+	strings []byte
+	// Pointer size is not recorded in BTF explicitly, we infer it from "long".
+	ptrSize uint64
+}
+
+var ErrNoBTF = errors.New("no BTF info")
+
+const (
+	btfMagic     = 0xeb9f
+	btfHeaderLen = 24
+)
+
+// Load parses BTF from an ELF file (vmlinux or a kernel module) or a raw BTF file (e.g. /sys/kernel/btf/vmlinux).
+// The base spec is required for modules.
+func Load(file string, base *Spec) (*Spec, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, []byte(elf.ELFMAG)) {
+		ef, err := elf.NewFile(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", file, err)
+		}
+		sec := ef.Section(".BTF")
+		if sec == nil {
+			return nil, fmt.Errorf("%v: %w", file, ErrNoBTF)
+		}
+		if data, err = sec.Data(); err != nil {
+			return nil, fmt.Errorf("%v: %w", file, err)
+		}
+	}
+	spec, err := Parse(data, base)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", file, err)
+	}
+	return spec, nil
+}
+
+// Parse parses a raw BTF blob.
+func Parse(data []byte, base *Spec) (*Spec, error) {
+	if len(data) < btfHeaderLen {
+		return nil, ErrNoBTF
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	switch {
+	case binary.LittleEndian.Uint16(data) == btfMagic:
+	case binary.BigEndian.Uint16(data) == btfMagic:
+		order = binary.BigEndian
+	default:
+		return nil, ErrNoBTF
+	}
+	hdrLen := order.Uint32(data[4:])
+	typeOff, typeLen := order.Uint32(data[8:]), order.Uint32(data[12:])
+	strOff, strLen := order.Uint32(data[16:]), order.Uint32(data[20:])
+	if uint64(hdrLen)+uint64(typeOff)+uint64(typeLen) > uint64(len(data)) ||
+		uint64(hdrLen)+uint64(strOff)+uint64(strLen) > uint64(len(data)) {
+		return nil, fmt.Errorf("truncated BTF")
+	}
+	data = data[hdrLen:]
+	spec := &Spec{
+		base:    base,
+		strings: data[strOff : strOff+strLen],
+	}
+	if base == nil {
+		spec.types = []*Type{{Kind: KindVoid}}
+	} else {
+		spec.first = base.first + len(base.types)
+	}
+	p := &parser{spec: spec, order: order, data: data[typeOff : typeOff+typeLen]}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	if err := spec.resolve(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+type parser struct {
+	spec  *Spec
+	order binary.ByteOrder
+	data  []byte
+	pos   int
+	err   error
+}
+
+func (p *parser) u32() uint32 {
+	if p.pos+4 > len(p.data) {
+		p.err = fmt.Errorf("truncated BTF type section")
+		p.pos = len(p.data)
+		return 0
+	}
+	v := p.order.Uint32(p.data[p.pos:])
+	p.pos += 4
+	return v
+}
+
+func (p *parser) str(off uint32) string {
+	s, err := p.spec.str(off)
+	if err != nil && p.err == nil {
+		p.err = err
+	}
+	return s
+}
+
+func (p *parser) parse() error {
+	id := p.spec.first + len(p.spec.types)
+	for ; p.pos < len(p.data) && p.err == nil; id++ {
+		nameOff, info, sizeOrType := p.u32(), p.u32(), p.u32()
+		typ := &Type{
+			ID:   id,
+			Kind: Kind((info >> 24) & 0x1f),
+			Name: p.str(nameOff),
+		}
+		vlen := int(info & 0xffff)
+		kindFlag := info>>31 != 0
+		switch typ.Kind {
+		case KindInt:
+			typ.Size = uint64(sizeOrType)
+			p.u32()
+		case KindPtr, KindTypedef, KindVolatile, KindConst, KindRestrict, KindFunc, KindTypeTag:
+			typ.elemID = int(sizeOrType)
+		case KindArray:
+			typ.elemID = int(p.u32())
+			p.u32() // index type
+			typ.NumElems = uint64(p.u32())
+		case KindStruct, KindUnion:
+			typ.Size = uint64(sizeOrType)
+			for i := 0; i < vlen; i++ {
+				m := Member{Name: p.str(p.u32()), typeID: int(p.u32())}
+				off := p.u32()
+				if kindFlag {
+					m.BitOffset, m.BitfieldSize = uint64(off&0xffffff), uint64(off>>24)
+				} else {
+					m.BitOffset = uint64(off)
+				}
+				typ.Members = append(typ.Members, m)
+			}
+		case KindEnum:
+			typ.Size = uint64(sizeOrType)
+			for i := 0; i < vlen; i++ {
+				name, val := p.str(p.u32()), p.u32()
+				v := uint64(val)
+				if kindFlag {
+					v = uint64(int64(int32(val)))
+				}
+				typ.Values = append(typ.Values, EnumValue{name, v})
+			}
+		case KindEnum64:
+			typ.Size = uint64(sizeOrType)
+			for i := 0; i < vlen; i++ {
+				name, lo, hi := p.str(p.u32()), p.u32(), p.u32()
+				typ.Values = append(typ.Values, EnumValue{name, uint64(hi)<<32 | uint64(lo)})
+			}
+		case KindFwd:
+		case KindFuncProto:
+			p.pos += 8 * vlen
+		case KindVar:
+			typ.elemID = int(sizeOrType)
+			p.u32() // linkage
+		case KindDatasec:
+			typ.Size = uint64(sizeOrType)
+			p.pos += 12 * vlen
+		case KindFloat:
+			typ.Size = uint64(sizeOrType)
+		case KindDeclTag:
+			typ.elemID = int(sizeOrType)
+			p.u32() // component index
+		default:
+			return fmt.Errorf("type %v: unknown BTF kind %v", id, typ.Kind)
+		}
+		if p.pos > len(p.data) {
+			p.err = fmt.Errorf("truncated BTF type section")
+		}
+		p.spec.types = append(p.spec.types, typ)
+	}
+	return p.err
+}
+
+func (spec *Spec) str(off uint32) (string, error) {
+	if spec.base != nil {
+		baseLen := uint32(len(spec.base.strings))
+		if off < baseLen {
+			return spec.base.str(off)
+		}
+		off -= baseLen
+	}
+	if off >= uint32(len(spec.strings)) {
+		return "", fmt.Errorf("bad BTF string offset %v", off)
+	}
+	end := bytes.IndexByte(spec.strings[off:], 0)
+	if end == -1 {
+		return "", fmt.Errorf("unterminated BTF string at %v", off)
+	}
+	return string(spec.strings[off : off+uint32(end)]), nil
+}
+
+func (spec *Spec) resolve() error {
+	var err error
+	lookup := func(id int) *Type {
+		typ := spec.Type(id)
+		if typ == nil && err == nil {
+			err = fmt.Errorf("bad BTF type id %v", id)
+		}
+		return typ
+	}
+	for _, typ := range spec.types {
+		if typ.elemID != 0 {
+			typ.Elem = lookup(typ.elemID)
+		}
+		for i := range typ.Members {
+			typ.Members[i].Type = lookup(typ.Members[i].typeID)
+		}
+		if typ.Kind == KindInt && typ.Name == "long int" {
+			spec.ptrSize = typ.Size
+		}
+	}
+	if spec.ptrSize == 0 {
+		spec.ptrSize = 8
+		if spec.base != nil {
+			spec.ptrSize = spec.base.ptrSize
+		}
+	}
+	return err
+}
+
+// Type returns the type by id, including types of the base spec.
+func (spec *Spec) Type(id int) *Type {
+	if id < spec.first {
+		return spec.base.Type(id)
+	}
+	if id < 0 || id-spec.first >= len(spec.types) {
+		return nil
+	}
+	return spec.types[id-spec.first]
+}
+
+// Types returns all types of the spec (excluding the base spec types).
+func (spec *Spec) Types() []*Type {
+	return spec.types
+}
+
+// Enums returns values of all enumerators of all enums (including the base spec).
+// Enumerators that are defined several times with different values are ambiguous,
+// they are not returned in values, but are returned in ambiguous.
+func (spec *Spec) Enums() (values map[string]uint64, ambiguous map[string]bool) {
+	values = make(map[string]uint64)
+	ambiguous = make(map[string]bool)
+	spec.collectEnums(values, ambiguous)
+	for name := range ambiguous {
+		delete(values, name)
+	}
+	return values, ambiguous
+}
+
+func (spec *Spec) collectEnums(res map[string]uint64, conflicts map[string]bool) {
+	if spec.base != nil {
+		spec.base.collectEnums(res, conflicts)
+	}
+	for _, typ := range spec.types {
+		for _, v := range typ.Values {
+			if old, ok := res[v.Name]; ok && old != v.Value {
+				conflicts[v.Name] = true
+			}
+			res[v.Name] = v.Value
+		}
+	}
+}
+
+// Struct returns the struct or union definition with the given name, or nil.
+func (spec *Spec) Struct(name string) *Type {
+	for _, typ := range spec.types {
+		if (typ.Kind == KindStruct || typ.Kind == KindUnion) && typ.Name == name {
+			return typ
+		}
+	}
+	if spec.base != nil {
+		return spec.base.Struct(name)
+	}
+	return nil
+}
+
+// SizeOf returns the size of the type in bytes.
+func (spec *Spec) SizeOf(typ *Type) (uint64, error) {
+	for i := 0; ; i++ {
+		if i > 100 {
+			return 0, fmt.Errorf("type %v: too deep type chain", typ.ID)
+		}
+		switch typ.Kind {
+		case KindInt, KindStruct, KindUnion, KindEnum, KindEnum64, KindFloat, KindDatasec:
+			return typ.Size, nil
+		case KindPtr:
+			return spec.ptrSize, nil
+		case KindArray:
+			size, err := spec.SizeOf(typ.Elem)
+			return size * typ.NumElems, err
+		case KindTypedef, KindVolatile, KindConst, KindRestrict, KindTypeTag, KindVar:
+			typ = typ.Elem
+		default:
+			return 0, fmt.Errorf("type %v: %v has no size", typ.ID, typ.Kind)
+		}
+	}
+}
+
+func (kind Kind) String() string {
+	names := [...]string{"void", "int", "ptr", "array", "struct", "union", "enum", "fwd", "typedef",
+		"volatile", "const", "restrict", "func", "func_proto", "var", "datasec", "float", "decl_tag",
+		"type_tag", "enum64"}
+	if int(kind) < len(names) {
+		return names[kind]
+	}
+	return fmt.Sprintf("kind(%d)", int(kind))
+}
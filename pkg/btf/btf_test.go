@@ -0,0 +1,167 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package btf
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// builder creates raw BTF blobs for tests.
+type builder struct {
+	types   []uint32
+	strings []byte
+	strBase int
+}
+
+func newBuilder() *builder {
+	return &builder{strings: []byte{0}}
+}
+
+func (b *builder) str(s string) uint32 {
+	if s == "" {
+		return 0
+	}
+	off := len(b.strings) + b.strBase
+	b.strings = append(append(b.strings, s...), 0)
+	return uint32(off)
+}
+
+func (b *builder) typ(name string, kind Kind, vlen int, kindFlag bool, sizeOrType uint32, extra ...uint32) {
+	info := uint32(kind)<<24 | uint32(vlen)
+	if kindFlag {
+		info |= 1 << 31
+	}
+	b.types = append(b.types, b.str(name), info, sizeOrType)
+	b.types = append(b.types, extra...)
+}
+
+func (b *builder) data() []byte {
+	typeLen := 4 * len(b.types)
+	hdr := []uint32{0, btfHeaderLen, 0, uint32(typeLen), uint32(typeLen), uint32(len(b.strings))}
+	buf := binary.LittleEndian.AppendUint16(nil, btfMagic)
+	buf = append(buf, 1, 0)
+	for _, v := range hdr[1:] {
+		buf = binary.LittleEndian.AppendUint32(buf, v)
+	}
+	for _, v := range b.types {
+		buf = binary.LittleEndian.AppendUint32(buf, v)
+	}
+	return append(buf, b.strings...)
+}
+
+func TestParse(t *testing.T) {
+	b := newBuilder()
+	b.typ("int", KindInt, 0, false, 4, 1<<24|32)      // 1
+	b.typ("long int", KindInt, 0, false, 8, 1<<24|64) // 2
+	b.typ("", KindPtr, 0, false, 0)                   // 3: void*
+	b.typ("u32", KindTypedef, 0, false, 1)            // 4
+	b.typ("", KindArray, 0, false, 0, 4, 1, 3)        // 5: u32[3]
+	b.typ("foo", KindStruct, 5, true, 32,             // 6
+		b.str("a"), 1, 0,
+		b.str("b"), 1, 3<<24|32,
+		b.str("c"), 1, 5<<24|35,
+		b.str("p"), 3, 64,
+		b.str("arr"), 5, 128)
+	b.typ("", KindEnum, 2, true, 4, b.str("E_NEG"), 0xffffffff, b.str("E_ONE"), 1) // 7
+	b.typ("e64", KindEnum64, 1, false, 8, b.str("E_BIG"), 2, 1)                    // 8
+	b.typ("", KindConst, 0, false, 6)                                              // 9
+	b.typ("", KindFuncProto, 1, false, 1, 0, 1)                                    // 10
+	b.typ("func", KindFunc, 0, false, 10)                                          // 11
+	spec, err := Parse(b.data(), nil)
+	require.NoError(t, err)
+	require.Len(t, spec.Types(), 12)
+
+	foo := spec.Struct("foo")
+	require.NotNil(t, foo)
+	assert.Equal(t, 6, foo.ID)
+	assert.Equal(t, uint64(32), foo.Size)
+	var members []string
+	for _, m := range foo.Members {
+		size, err := spec.SizeOf(m.Type)
+		require.NoError(t, err)
+		members = append(members, m.Name)
+		switch m.Name {
+		case "a":
+			assert.Equal(t, uint64(0), m.BitOffset)
+			assert.Equal(t, uint64(4), size)
+		case "b":
+			assert.Equal(t, uint64(32), m.BitOffset)
+			assert.Equal(t, uint64(3), m.BitfieldSize)
+		case "c":
+			assert.Equal(t, uint64(35), m.BitOffset)
+			assert.Equal(t, uint64(5), m.BitfieldSize)
+		case "p":
+			assert.Equal(t, uint64(8), size)
+		case "arr":
+			assert.Equal(t, uint64(12), size)
+			assert.Equal(t, "u32", m.Type.Elem.Name)
+		}
+	}
+	assert.Equal(t, []string{"a", "b", "c", "p", "arr"}, members)
+	size, err := spec.SizeOf(spec.Type(9))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(32), size)
+	_, err = spec.SizeOf(spec.Type(11))
+	assert.Error(t, err)
+	assert.Nil(t, spec.Struct("u32"))
+
+	enums, ambiguous := spec.Enums()
+	assert.Equal(t, map[string]uint64{
+		"E_NEG": ^uint64(0),
+		"E_ONE": 1,
+		"E_BIG": 1<<32 | 2,
+	}, enums)
+	assert.Empty(t, ambiguous)
+}
+
+func TestParseSplit(t *testing.T) {
+	base := newBuilder()
+	base.typ("int", KindInt, 0, false, 4, 1<<24|32)                                // 1
+	base.typ("", KindEnum, 2, false, 4, base.str("BASE"), 1, base.str("OTHER"), 1) // 2
+	baseSpec, err := Parse(base.data(), nil)
+	require.NoError(t, err)
+
+	mod := newBuilder()
+	mod.strBase = len(base.strings)
+	mod.typ("bar", KindStruct, 1, false, 4, mod.str("x"), 1, 0) // 3
+	mod.typ("", KindEnum, 1, false, 4, mod.str("MOD"), 2)       // 4
+	mod.typ("", KindPtr, 0, false, 3)                           // 5
+	// The same enumerator with the same value is fine, with a different value it's ambiguous.
+	mod.typ("", KindEnum, 2, false, 4, mod.str("BASE"), 1, mod.str("DUP"), 3)  // 6
+	mod.typ("", KindEnum, 2, false, 4, mod.str("DUP"), 4, mod.str("OTHER"), 5) // 7
+	spec, err := Parse(mod.data(), baseSpec)
+	require.NoError(t, err)
+	bar := spec.Struct("bar")
+	require.NotNil(t, bar)
+	assert.Equal(t, 3, bar.ID)
+	assert.Equal(t, "int", bar.Members[0].Type.Name)
+	assert.Equal(t, bar, spec.Type(5).Elem)
+	assert.Nil(t, baseSpec.Struct("bar"))
+	enums, ambiguous := spec.Enums()
+	assert.Equal(t, map[string]uint64{"BASE": 1, "MOD": 2}, enums)
+	assert.Equal(t, map[string]bool{"DUP": true, "OTHER": true}, ambiguous)
+	enums, ambiguous = baseSpec.Enums()
+	assert.Equal(t, map[string]uint64{"BASE": 1, "OTHER": 1}, enums)
+	assert.Empty(t, ambiguous)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse([]byte("not a BTF blob at all!!!"), nil)
+	assert.ErrorIs(t, err, ErrNoBTF)
+
+	b := newBuilder()
+	b.typ("int", KindInt, 0, false, 4, 1<<24|32)
+	data := b.data()
+	_, err = Parse(data[:len(data)-8], nil)
+	assert.Error(t, err)
+
+	b = newBuilder()
+	b.typ("", KindPtr, 0, false, 42)
+	_, err = Parse(b.data(), nil)
+	assert.Error(t, err)
+}
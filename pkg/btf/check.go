@@ -0,0 +1,99 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package btf
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/syzkaller/prog"
+)
+
+// CheckLayouts compares sizes and field offsets of compiled syzlang structs/unions
+// with the kernel structs/unions that have the same name. Template instantiations are not checked.
+// Returns descriptions of the mismatches.
+func (spec *Spec) CheckLayouts(types []prog.Type) []string {
+	var res []string
+	for _, typ := range types {
+		var fields []prog.Field
+		isUnion := false
+		switch t := typ.(type) {
+		case *prog.StructType:
+			fields = t.Fields
+		case *prog.UnionType:
+			isUnion = true
+		default:
+			continue
+		}
+		if typ.TemplateName() != typ.Name() {
+			continue
+		}
+		kernel := spec.Struct(typ.Name())
+		if kernel == nil {
+			continue
+		}
+		if (kernel.Kind == KindUnion) != isUnion {
+			// Either a different type with the same name, or intentionally described differently.
+			continue
+		}
+		if !typ.Varlen() && typ.Size() != kernel.Size {
+			res = append(res, fmt.Sprintf("%v: size %v, kernel size %v", typ.Name(), typ.Size(), kernel.Size))
+		}
+		res = append(res, spec.checkFields(types, typ.Name(), fields, kernel)...)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func (spec *Spec) checkFields(types []prog.Type, name string, fields []prog.Field, kernel *Type) []string {
+	offsets := make(map[string]uint64)
+	spec.memberOffsets(kernel, 0, offsets)
+	var res []string
+	offset := uint64(0)
+	for _, f := range fields {
+		typ := f.Type
+		if ref, ok := typ.(prog.Ref); ok {
+			typ = types[ref]
+		}
+		if prog.IsPad(typ) {
+			offset += typ.Size()
+			continue
+		}
+		if typ.IsBitfield() {
+			// Bitfields are grouped differently in syzlang, check only the group offset.
+			offset += typ.Size()
+			continue
+		}
+		if kernelOffset, ok := offsets[f.Name]; ok && kernelOffset != offset {
+			res = append(res, fmt.Sprintf("%v.%v: offset %v, kernel offset %v",
+				name, f.Name, offset, kernelOffset))
+		}
+		if typ.Varlen() {
+			break
+		}
+		offset += typ.Size()
+	}
+	return res
+}
+
+// memberOffsets collects byte offsets of non-bitfield members, members of anonymous structs/unions are flattened.
+func (spec *Spec) memberOffsets(typ *Type, base uint64, offsets map[string]uint64) {
+	for _, m := range typ.Members {
+		offset := base + m.BitOffset/8
+		if m.Name == "" {
+			elem := m.Type
+			for elem != nil && elem.Kind != KindStruct && elem.Kind != KindUnion && elem.Elem != nil {
+				elem = elem.Elem
+			}
+			if elem != nil && (elem.Kind == KindStruct || elem.Kind == KindUnion) {
+				spec.memberOffsets(elem, offset, offsets)
+			}
+			continue
+		}
+		if m.BitfieldSize != 0 || m.BitOffset%8 != 0 {
+			continue
+		}
+		offsets[m.Name] = offset
+	}
+}
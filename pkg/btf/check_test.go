@@ -0,0 +1,90 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package btf
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/ast"
+	"github.com/google/syzkaller/pkg/compiler"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckLayouts(t *testing.T) {
+	const desc = `
+btf_call(a ptr[in, btf_ok], b ptr[in, btf_size], c ptr[in, btf_offset], d ptr[in, btf_anon], e ptr[in, btf_tmpl[int8]])
+
+btf_ok {
+	a	int8
+	b	int32
+	c	int16:3
+	d	int16:13
+}
+
+btf_size {
+	a	int32
+}
+
+btf_offset {
+	a	int8
+	b	int16
+	c	int32
+} [packed]
+
+btf_anon {
+	a	int32
+	b	int32
+	c	int64
+}
+
+type btf_tmpl[T] {
+	a	T
+}
+`
+	eh := func(pos ast.Pos, msg string) {
+		t.Errorf("%v: %v", pos, msg)
+	}
+	top := ast.Parse([]byte(desc), "test.txt", eh)
+	require.NotNil(t, top)
+	target := targets.List[targets.TestOS][targets.TestArch64]
+	p := compiler.Compile(top, map[string]uint64{"SYS_btf_call": 1}, target, eh)
+	require.NotNil(t, p)
+
+	b := newBuilder()
+	b.typ("char", KindInt, 0, false, 1, 8)   // 1
+	b.typ("short", KindInt, 0, false, 2, 16) // 2
+	b.typ("int", KindInt, 0, false, 4, 32)   // 3
+	b.typ("long", KindInt, 0, false, 8, 64)  // 4
+	b.typ("btf_ok", KindStruct, 4, true, 12, // 5
+		b.str("a"), 1, 0,
+		b.str("b"), 3, 32,
+		b.str("c"), 2, 3<<24|64,
+		b.str("d"), 2, 13<<24|67)
+	b.typ("btf_size", KindStruct, 1, false, 8, // 6
+		b.str("a"), 4, 0)
+	b.typ("btf_offset", KindStruct, 3, false, 8, // 7
+		b.str("a"), 1, 0,
+		b.str("b"), 2, 16,
+		b.str("c"), 3, 32)
+	b.typ("", KindUnion, 2, false, 4, // 8
+		b.str("b"), 3, 0,
+		b.str("b2"), 3, 0)
+	b.typ("btf_anon", KindStruct, 3, false, 16, // 9
+		b.str("a"), 3, 0,
+		0, 8, 32,
+		b.str("c"), 4, 64)
+	b.typ("btf_tmpl", KindStruct, 1, false, 8, // 10
+		b.str("a"), 4, 0)
+	spec, err := Parse(b.data(), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"btf_offset.b: offset 1, kernel offset 2",
+		"btf_offset.c: offset 3, kernel offset 4",
+		"btf_offset: size 7, kernel size 8",
+		"btf_size: size 4, kernel size 8",
+	}, spec.CheckLayouts(p.Types))
+}
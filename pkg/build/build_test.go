@@ -592,3 +592,66 @@ make: *** [Makefile:1231: vmlinux_o] Error 2
 		"",
 	},
 }
+
+func TestParseFailure(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Failure
+	}{
+		{
+			err: &KernelError{
+				Report: []byte("net/core/dev.c:123:5: error: 'foo' undeclared (first use in this function)\n" +
+					"net/core/dev.c:130:5: error: 'bar' undeclared"),
+				guiltyFile: "net/core/dev.c",
+			},
+			want: Failure{
+				Kind:    FailureCompiler,
+				File:    "net/core/dev.c",
+				Line:    123,
+				Message: "'foo' undeclared (first use in this function)",
+			},
+		},
+		{
+			err: &KernelError{
+				Report:     []byte("ld: net/core/dev.o: in function `foo': undefined reference to `bar'"),
+				guiltyFile: "net/core/dev.c",
+			},
+			want: Failure{
+				Kind:    FailureLinker,
+				File:    "net/core/dev.c",
+				Message: "ld: net/core/dev.o: in function `foo': undefined reference to `bar'",
+			},
+		},
+		{
+			err: &KernelError{
+				Report:     []byte("drivers/net/Kconfig:12: syntax error"),
+				guiltyFile: "drivers/net/Kconfig",
+			},
+			want: Failure{
+				Kind:    FailureKconfig,
+				File:    "drivers/net/Kconfig",
+				Line:    12,
+				Message: "syntax error",
+			},
+		},
+		{
+			err:  &InfraError{Title: "Killed"},
+			want: Failure{Kind: FailureOOM, Message: "Killed"},
+		},
+		{
+			err:  &osutil.VerboseError{Title: "timedout after 1h0m0s [\"make\"]"},
+			want: Failure{Kind: FailureTimeout, Message: "timedout after 1h0m0s [\"make\"]"},
+		},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			assert.Equal(t, &test.want, ParseFailure(test.err))
+		})
+	}
+	// The same error on another line must have the same signature.
+	f1 := ParseFailure(&KernelError{Report: []byte("a.c:10:5: error: 'foo' undeclared")})
+	f2 := ParseFailure(&KernelError{Report: []byte("a.c:20:5: error: 'foo' undeclared")})
+	f3 := ParseFailure(&KernelError{Report: []byte("a.c:20:5: error: 'bar' undeclared")})
+	assert.Equal(t, f1.Signature(), f2.Signature())
+	assert.NotEqual(t, f1.Signature(), f3.Signature())
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package build
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// FailureKind is a coarse classification of build failures.
+type FailureKind string
+
+const (
+	FailureCompiler FailureKind = "compiler"
+	FailureLinker   FailureKind = "linker"
+	FailureKconfig  FailureKind = "kconfig"
+	FailureTimeout  FailureKind = "timeout"
+	FailureOOM      FailureKind = "oom"
+	FailureOther    FailureKind = "other"
+)
+
+// Failure is a structured description of a build failure.
+type Failure struct {
+	Kind FailureKind
+	// File and Line point to the first diagnostic, if it could be extracted.
+	File    string
+	Line    int
+	Message string
+}
+
+// ParseFailure returns a structured description of an error returned by Image.
+func ParseFailure(err error) *Failure {
+	var kernelErr *KernelError
+	var infraErr *InfraError
+	var verboseErr *osutil.VerboseError
+	switch {
+	case errors.As(err, &kernelErr):
+		return parseFailureReport(kernelErr.Report, kernelErr.guiltyFile)
+	case errors.As(err, &infraErr):
+		kind := FailureOther
+		if infraErr.Title == "Killed" {
+			kind = FailureOOM
+		}
+		return &Failure{Kind: kind, Message: infraErr.Title}
+	case errors.As(err, &verboseErr):
+		kind := FailureOther
+		if strings.HasPrefix(verboseErr.Title, "timedout") {
+			kind = FailureTimeout
+		}
+		return &Failure{Kind: kind, Message: firstLine(verboseErr.Title)}
+	default:
+		return &Failure{Kind: FailureOther, Message: firstLine(err.Error())}
+	}
+}
+
+// Title returns a short human-readable description of the failure.
+func (f *Failure) Title() string {
+	if f.File == "" {
+		return fmt.Sprintf("%v error: %v", f.Kind, f.Message)
+	}
+	return fmt.Sprintf("%v error in %v: %v", f.Kind, f.File, f.Message)
+}
+
+// Signature identifies the failure for deduplication. It does not depend on line numbers
+// and other volatile details, so the same failure on different commits and managers
+// has the same signature.
+func (f *Failure) Signature() string {
+	msg := failureNumberRe.ReplaceAllString(f.Message, "N")
+	return hash.String([]byte(fmt.Sprintf("%v|%v|%v", f.Kind, f.File, msg)))
+}
+
+var (
+	diagnosticRe    = regexp.MustCompile(`^([a-zA-Z0-9_\-/.]+):([0-9]+):(?:[0-9]+:)?\s*(?:(?:fatal )?error:\s*)?(.*)$`)
+	linkerRe        = regexp.MustCompile(`undefined reference to|multiple definition of|unresolved symbol|final link failed|^(?:ld|ld\.lld|[a-z0-9_\-]+-ld): `)
+	failureNumberRe = regexp.MustCompile(`\b(0x[0-9a-fA-F]+|[0-9]+)\b`)
+)
+
+func parseFailureReport(report []byte, file string) *Failure {
+	line := firstLine(string(report))
+	f := &Failure{
+		Kind:    FailureCompiler,
+		File:    file,
+		Message: line,
+	}
+	if match := diagnosticRe.FindStringSubmatch(line); match != nil {
+		f.File = strings.TrimPrefix(match[1], "./")
+		f.Line, _ = strconv.Atoi(match[2])
+		f.Message = match[3]
+	}
+	base := filepath.Base(f.File)
+	switch {
+	case strings.HasPrefix(base, "Kconfig") || strings.HasPrefix(f.File, "scripts/kconfig/") ||
+		bytes.Contains(report, []byte("syncconfig")) || bytes.Contains(report, []byte("oldconfig")):
+		f.Kind = FailureKconfig
+	case linkerRe.MatchString(line):
+		f.Kind = FailureLinker
+	}
+	return f
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if pos := strings.IndexByte(s, '\n'); pos != -1 {
+		s = s[:pos]
+	}
+	return s
+}
@@ -20,6 +20,7 @@ func (comp *compiler) typecheck() {
 	comp.checkComments()
 	comp.checkDirectives()
 	comp.checkNames()
+	comp.checkProtocols()
 	comp.checkFlags()
 	comp.checkFields()
 	comp.checkTypedefs()
@@ -157,6 +158,62 @@ func (comp *compiler) checkNames() {
 	}
 }
 
+func (comp *compiler) checkProtocols() {
+	calls := make(map[string]bool)
+	for _, decl := range comp.desc.Nodes {
+		if n, ok := decl.(*ast.Call); ok {
+			calls[n.Name.Name] = true
+		}
+	}
+	protocols := make(map[string]*ast.Protocol)
+	for _, decl := range comp.desc.Nodes {
+		n, ok := decl.(*ast.Protocol)
+		if !ok {
+			continue
+		}
+		name := n.Name.Name
+		if prev := protocols[name]; prev != nil {
+			comp.error(n.Pos, "protocol %v redeclared, previously declared at %v", name, prev.Pos)
+			continue
+		}
+		protocols[name] = n
+		if comp.resources[n.Resource.Name] == nil {
+			comp.error(n.Resource.Pos, "protocol %v uses unknown resource %v", name, n.Resource.Name)
+		}
+		if len(n.Transitions) == 0 {
+			comp.error(n.Pos, "protocol %v does not have transitions", name)
+			continue
+		}
+		reachable := map[string]bool{prog.ProtocolInitState: true}
+		transitions := make(map[[2]string]*ast.Transition)
+		hasInit := false
+		for _, t := range n.Transitions {
+			reachable[t.To.Name] = true
+			hasInit = hasInit || t.From.Name == prog.ProtocolInitState
+			if !calls[t.Call.Name] {
+				comp.error(t.Call.Pos, "protocol %v uses unknown call %v", name, t.Call.Name)
+			}
+			if t.To.Name == prog.ProtocolInitState {
+				comp.error(t.To.Pos, "protocol %v transitions to %v state", name, prog.ProtocolInitState)
+			}
+			key := [2]string{t.Call.Name, t.From.Name}
+			if prev := transitions[key]; prev != nil {
+				comp.error(t.Pos, "protocol %v: transition %v from state %v redeclared, previously declared at %v",
+					name, t.Call.Name, t.From.Name, prev.Pos)
+			}
+			transitions[key] = t
+		}
+		if !hasInit {
+			comp.error(n.Pos, "protocol %v does not have transitions from %v state", name, prog.ProtocolInitState)
+		}
+		for _, t := range n.Transitions {
+			if !reachable[t.From.Name] {
+				comp.error(t.From.Pos, "protocol %v: state %v is unreachable", name, t.From.Name)
+			}
+		}
+	}
+}
+
 func (comp *compiler) checkFlags() {
 	checkFlagsGeneric[*ast.IntFlags, *ast.Int](comp, comp.intFlags)
 	checkFlagsGeneric[*ast.StrFlags, *ast.String](comp, comp.strFlags)
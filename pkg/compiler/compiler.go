@@ -36,6 +36,7 @@ type Prog struct {
 	Resources []*prog.ResourceDesc
 	Syscalls  []*prog.Syscall
 	Types     []prog.Type
+	Protocols []prog.ProtocolDesc
 	// Set of unsupported syscalls/flags.
 	Unsupported map[string]bool
 	// Returned if consts was nil.
@@ -101,11 +102,14 @@ func Compile(desc *ast.Description, consts map[string]uint64, target *targets.Ta
 	}
 	syscalls := comp.genSyscalls()
 	comp.layoutTypes(syscalls)
+	resources := comp.genResources()
+	protocols := comp.genProtocols(syscalls, resources)
 	types := comp.generateTypes(syscalls)
 	prg := &Prog{
-		Resources:   comp.genResources(),
+		Resources:   resources,
 		Syscalls:    syscalls,
 		Types:       types,
+		Protocols:   protocols,
 		Unsupported: comp.unsupported,
 	}
 	if comp.errors != 0 {
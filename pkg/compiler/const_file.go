@@ -94,6 +94,32 @@ func (cf *ConstFile) Arch(arch string) map[string]uint64 {
 	return m
 }
 
+// Undeclared returns consts that are not defined for the arch (as opposed to consts that are not known at all).
+func (cf *ConstFile) Undeclared(arch string) map[string]bool {
+	if cf == nil || !cf.arches[arch] {
+		return nil
+	}
+	m := make(map[string]bool)
+	for name, cv := range cf.m {
+		if _, ok := cv.vals[arch]; !ok {
+			m[name] = true
+		}
+	}
+	return m
+}
+
+func (cf *ConstFile) Arches() []string {
+	if cf == nil {
+		return nil
+	}
+	var arches []string
+	for arch := range cf.arches {
+		arches = append(arches, arch)
+	}
+	sort.Strings(arches)
+	return arches
+}
+
 func (cf *ConstFile) ExistsAny(constName string) bool {
 	return len(cf.m[constName].vals) > 0
 }
@@ -223,6 +249,7 @@ func (cf *ConstFile) deserializeFile(data []byte, file, arch string, eh ast.Erro
 		name, val := strings.TrimSpace(line[:eq]), strings.TrimSpace(line[eq+1:])
 		if arch != "" {
 			// Old format.
+			cf.arches[arch] = true
 			if !cf.parseOldConst(arch, name, val, errf) {
 				return false
 			}
@@ -234,6 +261,7 @@ func (cf *ConstFile) deserializeFile(data []byte, file, arch string, eh ast.Erro
 			}
 			for _, arch := range strings.Split(val, ",") {
 				arches = append(arches, strings.TrimSpace(arch))
+				cf.arches[strings.TrimSpace(arch)] = true
 			}
 			continue
 		}
@@ -261,6 +289,11 @@ func (cf *ConstFile) parseConst(arches []string, name, line string, weak bool, e
 			dflt = make(map[string]uint64)
 			valStr := strings.TrimSpace(fields[0])
 			if valStr == undefined {
+				for _, arch := range arches {
+					if err := cf.addConst(arch, name, 0, false, weak); err != nil {
+						return errf("%v", err)
+					}
+				}
 				continue
 			}
 			val, err := strconv.ParseUint(valStr, 0, 64)
@@ -101,8 +101,10 @@ CONST5_SOME_UNDEFINED2 = 100, arch3:???
 		}
 		file.Close()
 		cf1 := DeserializeConstFile(file.Name(), nil)
+		assert.Equal(t, []string{"arch1", "arch2", "arch3"}, cf1.Arches())
 		for name, arch := range arches {
 			assert.Equal(t, cf1.Arch(name), arch.consts)
+			assert.Equal(t, cf1.Undeclared(name), arch.undefined)
 		}
 	}
 	{
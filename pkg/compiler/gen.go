@@ -30,6 +30,82 @@ func (comp *compiler) genResources() []*prog.ResourceDesc {
 	return resources
 }
 
+// genProtocols generates protocol descriptions. It needs to be called before generateTypes
+// since it walks call types. Transitions of unsupported calls are dropped.
+func (comp *compiler) genProtocols(syscalls []*prog.Syscall, resources []*prog.ResourceDesc) []prog.ProtocolDesc {
+	calls := make(map[string]*prog.Syscall)
+	for _, c := range syscalls {
+		calls[c.Name] = c
+	}
+	generated := make(map[string]bool)
+	for _, res := range resources {
+		generated[res.Name] = true
+	}
+	var protocols []prog.ProtocolDesc
+	for _, decl := range comp.desc.Nodes {
+		n, ok := decl.(*ast.Protocol)
+		if !ok || !generated[n.Resource.Name] {
+			continue
+		}
+		proto := prog.ProtocolDesc{
+			Name:     n.Name.Name,
+			Resource: n.Resource.Name,
+		}
+		for _, t := range n.Transitions {
+			meta := calls[t.Call.Name]
+			if meta == nil {
+				continue
+			}
+			init := t.From.Name == prog.ProtocolInitState
+			if !comp.callUsesResource(meta, n.Resource.Name, init) {
+				what := "use"
+				if init {
+					what = "create"
+				}
+				comp.error(t.Call.Pos, "protocol %v: call %v does not %v resource %v",
+					proto.Name, meta.Name, what, n.Resource.Name)
+				continue
+			}
+			proto.Transitions = append(proto.Transitions, prog.ProtocolTransition{
+				Call: meta.Name,
+				From: t.From.Name,
+				To:   t.To.Name,
+			})
+		}
+		if len(proto.Transitions) != 0 {
+			protocols = append(protocols, proto)
+		}
+	}
+	sort.Slice(protocols, func(i, j int) bool {
+		return protocols[i].Name < protocols[j].Name
+	})
+	return protocols
+}
+
+// callUsesResource returns true if the call creates (if out is set) or uses an instance
+// of the resource res (or of a related more generic/specialized resource).
+func (comp *compiler) callUsesResource(meta *prog.Syscall, res string, out bool) bool {
+	found := false
+	prog.ForeachCallType(meta, func(typ prog.Type, ctx *prog.TypeCtx) {
+		r, ok := typ.(*prog.ResourceType)
+		if !ok || found || out && ctx.Dir == prog.DirIn || !out && ctx.Dir == prog.DirOut {
+			return
+		}
+		found = comp.isRelatedResource(r.TypeName, res) || comp.isRelatedResource(res, r.TypeName)
+	})
+	return found
+}
+
+// isRelatedResource returns true if base is a base resource of res (or the same resource).
+func (comp *compiler) isRelatedResource(res, base string) bool {
+	for n := comp.resources[res]; n != nil; n = comp.resources[n.Base.Ident] {
+		if n.Name.Name == base {
+			return true
+		}
+	}
+	return false
+}
+
 func (comp *compiler) genResource(n *ast.Resource) *prog.ResourceDesc {
 	res := &prog.ResourceDesc{
 		Name: n.Name.Name,
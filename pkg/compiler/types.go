@@ -543,9 +543,14 @@ var typeCsum = &typeDesc{
 		{Name: "proto", Type: typeArgInt},
 	},
 	Check: func(comp *compiler, t *ast.Type, args []*ast.Type, base prog.IntTypeCommon) {
-		if len(args) > 2 && genCsumKind(args[1]) != prog.CsumPseudo {
+		kind := genCsumKind(args[1])
+		if len(args) > 2 && kind != prog.CsumPseudo && kind != prog.CsumPseudoSum {
 			comp.error(args[2].Pos, "only pseudo csum can have proto")
 		}
+		if size := csumKindSize[kind]; size != 0 && base.TypeSize != size {
+			comp.error(args[1].Pos, "%v csum must have %v-byte base type, got %v bytes",
+				args[1].Ident, size, base.TypeSize)
+		}
 		if len(args[0].Colon) != 0 {
 			comp.error(args[0].Colon[0].Pos, "path expressions are not implemented for csum")
 		}
@@ -566,8 +571,20 @@ var typeCsum = &typeDesc{
 }
 
 var typeArgCsumType = &typeArg{
-	Kind:  kindIdent,
-	Names: []string{"inet", "pseudo"},
+	Kind: kindIdent,
+	Names: []string{"inet", "pseudo", "inet_sum", "pseudo_sum", "crc32", "crc32c", "adler32",
+		"fletcher16", "fletcher32"},
+}
+
+// csumKindSize is the size of the checksum value for csum kinds that require a particular base type size.
+var csumKindSize = map[prog.CsumKind]uint64{
+	prog.CsumInetSum:    2,
+	prog.CsumPseudoSum:  2,
+	prog.CsumCRC32:      4,
+	prog.CsumCRC32C:     4,
+	prog.CsumAdler32:    4,
+	prog.CsumFletcher16: 2,
+	prog.CsumFletcher32: 4,
 }
 
 func genCsumKind(t *ast.Type) prog.CsumKind {
@@ -576,6 +593,20 @@ func genCsumKind(t *ast.Type) prog.CsumKind {
 		return prog.CsumInet
 	case "pseudo":
 		return prog.CsumPseudo
+	case "inet_sum":
+		return prog.CsumInetSum
+	case "pseudo_sum":
+		return prog.CsumPseudoSum
+	case "crc32":
+		return prog.CsumCRC32
+	case "crc32c":
+		return prog.CsumCRC32C
+	case "adler32":
+		return prog.CsumAdler32
+	case "fletcher16":
+		return prog.CsumFletcher16
+	case "fletcher32":
+		return prog.CsumFletcher32
 	default:
 		panic(fmt.Sprintf("unknown csum kind %q", t.Ident))
 	}
@@ -1209,3 +1240,18 @@ func init() {
 		panic(fmt.Sprintf("failed to parse builtins: %v: %v", pos, msg))
 	})
 }
+
+// BuiltinTypeNames returns the names of the builtin types and type templates (e.g. int32, ptr, bool8).
+func BuiltinTypeNames() []string {
+	var names []string
+	for name := range builtinTypes {
+		names = append(names, name)
+	}
+	for _, node := range builtinDescs.Nodes {
+		if def, ok := node.(*ast.TypeDef); ok {
+			names = append(names, def.Name.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -115,13 +115,16 @@ type NewInput struct {
 	Signal   signal.Signal
 	Cover    []uint64
 	RawCover []uint64
+	// Trace of the mutation that produced the program (before minimization), if it was recorded.
+	MutationTrace *prog.MutationTrace
 }
 
 type NewItemEvent struct {
-	Sig      string
-	Exists   bool
-	ProgData []byte
-	NewCover []uint64
+	Sig           string
+	Exists        bool
+	ProgData      []byte
+	NewCover      []uint64
+	MutationTrace *prog.MutationTrace
 }
 
 func (corpus *Corpus) Save(inp NewInput) {
@@ -179,10 +182,11 @@ func (corpus *Corpus) Save(inp NewInput) {
 		select {
 		case <-corpus.ctx.Done():
 		case corpus.updates <- NewItemEvent{
-			Sig:      sig,
-			Exists:   exists,
-			ProgData: progData,
-			NewCover: newCover,
+			Sig:           sig,
+			Exists:        exists,
+			ProgData:      progData,
+			NewCover:      newCover,
+			MutationTrace: inp.MutationTrace,
 		}:
 		}
 	}
@@ -23,11 +23,13 @@ func TestCorpusOperation(t *testing.T) {
 	// First program is saved.
 	rs := rand.NewSource(0)
 	inp1 := generateInput(target, rs, 5)
+	inp1.MutationTrace = &prog.MutationTrace{NCalls: 5}
 	go corpus.Save(inp1)
 	event := <-ch
 	progData := inp1.Prog.Serialize()
 	assert.Equal(t, progData, event.ProgData)
 	assert.Equal(t, false, event.Exists)
+	assert.Equal(t, inp1.MutationTrace, event.MutationTrace)
 
 	// Second program is saved for every its call.
 	inp2 := generateInput(target, rs, 5)
@@ -0,0 +1,245 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package corpus
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/syzkaller/prog"
+)
+
+type DescElemKind string
+
+const (
+	DescUnionOption  DescElemKind = "union"
+	DescFlagValue    DescElemKind = "flags"
+	DescOptField     DescElemKind = "field"
+	DescResourceKind DescElemKind = "resource"
+)
+
+// DescElem is an alternative in syscall descriptions that is chosen during program generation:
+// a union option, a flag value, an optional pointer field, or a kind of the resource passed to a call.
+type DescElem struct {
+	Kind   DescElemKind
+	Type   string // name of the union/flags/struct/resource type, or the syscall name for syscall args
+	Option string // union option, flag value, field name or the passed resource
+}
+
+// DescElemCover says how useful a description element was for the corpus.
+type DescElemCover struct {
+	DescElem
+	// Number of corpus programs that use the element in any call to the syscall.
+	Selected int
+	// Number of corpus programs that use the element in the call they were added for.
+	Inputs int
+	// Amount of signal that was produced only by inputs that use the element.
+	Signal int
+}
+
+// DescriptionCover attributes signal of corpus programs to description elements of the syscalls.
+// An element is effective if some signal was produced only by calls that use the element.
+// Only syscalls that were the reason to add at least one corpus program are returned,
+// but for them all elements are returned including the never selected ones.
+func (corpus *Corpus) DescriptionCover(target *prog.Target) map[string][]DescElemCover {
+	type callState struct {
+		elems    map[DescElem]*DescElemCover
+		signal   map[uint64]int              // number of inputs that produced each signal element
+		attrib   map[DescElem]map[uint64]int // same, but only for inputs that use the element
+		selected map[DescElem]*prog.Prog     // the last program that used the element
+	}
+	calls := make(map[*prog.Syscall]*callState)
+	getCall := func(meta *prog.Syscall) *callState {
+		cs := calls[meta]
+		if cs == nil {
+			cs = &callState{
+				elems:    make(map[DescElem]*DescElemCover),
+				signal:   make(map[uint64]int),
+				attrib:   make(map[DescElem]map[uint64]int),
+				selected: make(map[DescElem]*prog.Prog),
+			}
+			for _, elem := range SyscallDescElems(target, meta) {
+				cs.elems[elem] = &DescElemCover{DescElem: elem}
+			}
+			calls[meta] = cs
+		}
+		return cs
+	}
+	for _, item := range corpus.Items() {
+		for i, c := range item.Prog.Calls {
+			cs := getCall(c.Meta)
+			elems := CallDescElems(c)
+			for _, elem := range elems {
+				ec := cs.elems[elem]
+				if ec == nil {
+					ec = &DescElemCover{DescElem: elem}
+					cs.elems[elem] = ec
+				}
+				if cs.selected[elem] != item.Prog {
+					ec.Selected++
+					cs.selected[elem] = item.Prog
+				}
+			}
+			if i != item.Call {
+				continue
+			}
+			raw := item.Signal.ToRaw()
+			for _, sig := range raw {
+				cs.signal[sig]++
+			}
+			for _, elem := range elems {
+				cs.elems[elem].Inputs++
+				attrib := cs.attrib[elem]
+				if attrib == nil {
+					attrib = make(map[uint64]int)
+					cs.attrib[elem] = attrib
+				}
+				for _, sig := range raw {
+					attrib[sig]++
+				}
+			}
+		}
+	}
+	res := make(map[string][]DescElemCover)
+	for meta, cs := range calls {
+		if len(cs.signal) == 0 {
+			continue
+		}
+		var elems []DescElemCover
+		for elem, ec := range cs.elems {
+			for sig, n := range cs.attrib[elem] {
+				if n == cs.signal[sig] {
+					ec.Signal++
+				}
+			}
+			elems = append(elems, *ec)
+		}
+		sort.Slice(elems, func(i, j int) bool {
+			return elems[i].DescElem.less(elems[j].DescElem)
+		})
+		res[meta.Name] = elems
+	}
+	return res
+}
+
+func (elem DescElem) less(other DescElem) bool {
+	if elem.Kind != other.Kind {
+		return elem.Kind < other.Kind
+	}
+	if elem.Type != other.Type {
+		return elem.Type < other.Type
+	}
+	return elem.Option < other.Option
+}
+
+// SyscallDescElems returns all description elements that can be used in calls to the syscall.
+func SyscallDescElems(target *prog.Target, meta *prog.Syscall) []DescElem {
+	dedup := make(map[DescElem]bool)
+	var elems []DescElem
+	add := func(elem DescElem) {
+		if !dedup[elem] {
+			dedup[elem] = true
+			elems = append(elems, elem)
+		}
+	}
+	addFields := func(typ string, fields []prog.Field) {
+		for _, f := range fields {
+			if isOptPointer(f.Type) {
+				add(DescElem{DescOptField, typ, f.Name})
+			}
+		}
+	}
+	addFields(meta.Name, meta.Args)
+	prog.ForeachCallType(meta, func(t prog.Type, ctx *prog.TypeCtx) {
+		switch typ := t.(type) {
+		case *prog.UnionType:
+			for _, f := range typ.Fields {
+				add(DescElem{DescUnionOption, typ.Name(), f.Name})
+			}
+		case *prog.FlagsType:
+			for _, v := range typ.Vals {
+				add(DescElem{DescFlagValue, typ.Name(), flagValue(v)})
+			}
+		case *prog.StructType:
+			addFields(typ.Name(), typ.Fields)
+		case *prog.ResourceType:
+			if ctx.Dir == prog.DirOut {
+				break
+			}
+			for _, res := range target.Resources {
+				if isResourceKind(res, typ.Desc) {
+					add(DescElem{DescResourceKind, typ.Desc.Name, res.Name})
+				}
+			}
+		}
+	})
+	return elems
+}
+
+// CallDescElems returns description elements used in the call.
+func CallDescElems(c *prog.Call) []DescElem {
+	dedup := make(map[DescElem]bool)
+	var elems []DescElem
+	add := func(elem DescElem) {
+		if !dedup[elem] {
+			dedup[elem] = true
+			elems = append(elems, elem)
+		}
+	}
+	addFields := func(typ string, fields []prog.Field, args []prog.Arg) {
+		for i, f := range fields {
+			if ptr, ok := args[i].(*prog.PointerArg); ok && isOptPointer(f.Type) && !ptr.IsSpecial() {
+				add(DescElem{DescOptField, typ, f.Name})
+			}
+		}
+	}
+	addFields(c.Meta.Name, c.Meta.Args, c.Args)
+	prog.ForeachArg(c, func(arg prog.Arg, ctx *prog.ArgCtx) {
+		switch typ := arg.Type().(type) {
+		case *prog.UnionType:
+			a := arg.(*prog.UnionArg)
+			add(DescElem{DescUnionOption, typ.Name(), typ.Fields[a.Index].Name})
+		case *prog.FlagsType:
+			val := arg.(*prog.ConstArg).Val
+			for _, v := range typ.Vals {
+				if typ.BitMask && v != 0 && val&v == v || !typ.BitMask && val == v {
+					add(DescElem{DescFlagValue, typ.Name(), flagValue(v)})
+				}
+			}
+		case *prog.StructType:
+			addFields(typ.Name(), typ.Fields, arg.(*prog.GroupArg).Inner)
+		case *prog.ResourceType:
+			a := arg.(*prog.ResultArg)
+			if a.Dir() != prog.DirOut && a.Res != nil {
+				add(DescElem{DescResourceKind, typ.Desc.Name, a.Res.Type().(*prog.ResourceType).Desc.Name})
+			}
+		}
+	})
+	return elems
+}
+
+func isOptPointer(typ prog.Type) bool {
+	switch typ.(type) {
+	case *prog.PtrType, *prog.VmaType:
+		return typ.Optional()
+	}
+	return false
+}
+
+// isResourceKind returns true if res is the same resource as base or a more specialized one.
+func isResourceKind(res, base *prog.ResourceDesc) bool {
+	if len(res.Kind) < len(base.Kind) {
+		return false
+	}
+	for i, kind := range base.Kind {
+		if res.Kind[i] != kind {
+			return false
+		}
+	}
+	return true
+}
+
+func flagValue(v uint64) string {
+	return fmt.Sprintf("0x%x", v)
+}
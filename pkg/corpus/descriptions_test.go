@@ -0,0 +1,64 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package corpus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/signal"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescriptionCover(t *testing.T) {
+	target := getTarget(t, targets.TestOS, targets.TestArch64)
+	corpus := NewCorpus(context.Background())
+	save := func(text string, sig ...uint64) {
+		p, err := target.Deserialize([]byte(text), prog.Strict)
+		require.NoError(t, err)
+		corpus.Save(NewInput{
+			Prog:   p,
+			Call:   len(p.Calls) - 1,
+			Signal: signal.FromRaw(sig, 0),
+		})
+	}
+	save(`
+r0 = test$res2()
+test$desc_cover(0x1, 0x0, r0)
+`, 1, 2)
+	save(`
+r0 = test$res2()
+test$desc_cover(0x3, &(0x7f0000000000)=@f0=0x1, r0)
+`, 1, 3)
+	save(`
+test$desc_cover(0x1, &(0x7f0000000000)=@f0=0x1, 0xffffffffffffffff)
+`, 1)
+	// The last call is the reason to add the program, so test$desc_cover does not get any signal.
+	save(`
+test$desc_cover(0x4, 0x0, 0xffffffffffffffff)
+test$res2()
+`, 4)
+	cover := corpus.DescriptionCover(target)
+	require.Len(t, cover, 2)
+	got := make(map[DescElem]DescElemCover)
+	for _, ec := range cover["test$desc_cover"] {
+		got[ec.DescElem] = ec
+	}
+	flags := target.SyscallMap["test$desc_cover"].Args[0].Type.Name()
+	check := func(kind DescElemKind, typ, option string, selected, inputs, signal int) {
+		elem := DescElem{kind, typ, option}
+		assert.Equal(t, DescElemCover{elem, selected, inputs, signal}, got[elem], "%+v", elem)
+	}
+	check(DescFlagValue, flags, "0x1", 3, 3, 3)
+	check(DescFlagValue, flags, "0x2", 1, 1, 1)
+	check(DescFlagValue, flags, "0x4", 1, 0, 0)
+	check(DescUnionOption, "syz_union1", "f0", 2, 2, 1)
+	check(DescUnionOption, "syz_union1", "f1", 0, 0, 0)
+	check(DescOptField, "test$desc_cover", "a1", 2, 2, 1)
+	check(DescResourceKind, "fd", "fd", 2, 2, 2)
+	check(DescResourceKind, "fd", "sock", 0, 0, 0)
+}
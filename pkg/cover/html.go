@@ -517,6 +517,68 @@ func (rg *ReportGenerator) DoFileCover(w io.Writer, params HandlerParams) error
 	return writer.WriteAll(d)
 }
 
+// FileCover is per-file line coverage as reported by DoFileCover.
+type FileCover struct {
+	Module       string
+	Filename     string
+	CoveredLines int
+	TotalLines   int
+}
+
+// ReadFileCover parses the CSV report produced by DoFileCover (the /filecover manager page).
+func ReadFileCover(r io.Reader) ([]FileCover, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	cols := make(map[string]int)
+	for i, name := range header {
+		cols[name] = i
+	}
+	for _, name := range csvFilesHeader[:4] {
+		if _, ok := cols[name]; !ok {
+			return nil, fmt.Errorf("no %v column in the report", name)
+		}
+	}
+	var res []FileCover
+	for {
+		rec, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		covered, err := strconv.Atoi(rec[cols["CoveredLines"]])
+		if err != nil {
+			return nil, fmt.Errorf("bad CoveredLines value: %w", err)
+		}
+		total, err := strconv.Atoi(rec[cols["TotalLines"]])
+		if err != nil {
+			return nil, fmt.Errorf("bad TotalLines value: %w", err)
+		}
+		res = append(res, FileCover{
+			Module:       rec[cols["Module"]],
+			Filename:     rec[cols["Filename"]],
+			CoveredLines: covered,
+			TotalLines:   total,
+		})
+	}
+	return res, nil
+}
+
+// PoorlyCoveredFiles returns sorted names of the files whose line coverage is below threshold.
+func PoorlyCoveredFiles(files []FileCover, threshold float64) []string {
+	var res []string
+	for _, file := range files {
+		if file.TotalLines != 0 && float64(file.CoveredLines)/float64(file.TotalLines) < threshold {
+			res = append(res, file.Filename)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
 func groupCoverByFilePrefixes(datas []fileStats, subsystems []mgrconfig.Subsystem) map[string]map[string]string {
 	d := make(map[string]map[string]string)
 
@@ -387,7 +387,10 @@ func generateReport(t *testing.T, target *targets.Target, test *Test) (*reports,
 		return nil, err
 	}
 	assert.NoError(t, rg.DoSubsystemCover(new(bytes.Buffer), params))
-	assert.NoError(t, rg.DoFileCover(new(bytes.Buffer), params))
+	fileCover := new(bytes.Buffer)
+	assert.NoError(t, rg.DoFileCover(fileCover, params))
+	_, err = ReadFileCover(fileCover)
+	assert.NoError(t, err)
 	res := &reports{
 		csv:           new(bytes.Buffer),
 		jsonl:         new(bytes.Buffer),
@@ -502,3 +505,19 @@ func TestCoverByFilePrefixes(t *testing.T) {
 		"PCsInCoveredFuncs": "3 / 6 / 50.00%",
 	})
 }
+
+func TestReadFileCover(t *testing.T) {
+	files, err := ReadFileCover(strings.NewReader(`Module,Filename,CoveredLines,TotalLines,CoveredPCs
+,net/socket.c,10,100,12
+kvm,arch/x86/kvm/x86.c,0,50,0
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []FileCover{
+		{Filename: "net/socket.c", CoveredLines: 10, TotalLines: 100},
+		{Module: "kvm", Filename: "arch/x86/kvm/x86.c", TotalLines: 50},
+	}, files)
+	assert.Equal(t, []string{"arch/x86/kvm/x86.c"}, PoorlyCoveredFiles(files, 0.1))
+	assert.Equal(t, []string{"arch/x86/kvm/x86.c", "net/socket.c"}, PoorlyCoveredFiles(files, 0.5))
+	_, err = ReadFileCover(strings.NewReader("Module,Filename\n"))
+	assert.Error(t, err)
+}
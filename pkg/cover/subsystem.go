@@ -0,0 +1,52 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"sort"
+
+	"github.com/google/syzkaller/pkg/subsystem"
+)
+
+// SubsystemCover is the coverage of the source files that belong to a kernel subsystem.
+type SubsystemCover struct {
+	Name       string
+	CoveredPCs int
+	TotalPCs   int
+}
+
+// SubsystemCoverage groups the coverage of the programs by the subsystems of the source files.
+// Unlike DoSubsystemCover, it relies on the subsystem path rules from pkg/subsystem
+// rather than on the kernel_subsystem manager config.
+func (rg *ReportGenerator) SubsystemCoverage(progs []Prog, matcher *subsystem.PathMatcher) (
+	[]SubsystemCover, error) {
+	files, err := rg.prepareFileMap(progs, true, false)
+	if err != nil {
+		return nil, err
+	}
+	return groupCoverBySubsystem(files, matcher), nil
+}
+
+func groupCoverBySubsystem(files fileMap, matcher *subsystem.PathMatcher) []SubsystemCover {
+	perName := make(map[string]*SubsystemCover)
+	for name, file := range files {
+		for _, item := range matcher.Match(name) {
+			stats := perName[item.Name]
+			if stats == nil {
+				stats = &SubsystemCover{Name: item.Name}
+				perName[item.Name] = stats
+			}
+			stats.CoveredPCs += file.coveredPCs
+			stats.TotalPCs += file.totalPCs
+		}
+	}
+	var ret []SubsystemCover
+	for _, stats := range perName {
+		ret = append(ret, *stats)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
@@ -0,0 +1,31 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package cover
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/subsystem"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupCoverBySubsystem(t *testing.T) {
+	matcher := subsystem.MakePathMatcher([]*subsystem.Subsystem{
+		{Name: "net", PathRules: []subsystem.PathRule{{IncludeRegexp: `^net/`}}},
+		{Name: "ipv4", PathRules: []subsystem.PathRule{{IncludeRegexp: `^net/ipv4/`}}},
+		{Name: "mm", PathRules: []subsystem.PathRule{{IncludeRegexp: `^mm/`}}},
+	})
+	files := fileMap{
+		"net/socket.c":     {coveredPCs: 10, totalPCs: 100},
+		"net/ipv4/tcp.c":   {coveredPCs: 5, totalPCs: 50},
+		"mm/slub.c":        {coveredPCs: 0, totalPCs: 30},
+		"kernel/sched.c":   {coveredPCs: 7, totalPCs: 70},
+		"net/ipv4/route.c": {coveredPCs: 1, totalPCs: 10},
+	}
+	assert.Equal(t, []SubsystemCover{
+		{Name: "ipv4", CoveredPCs: 6, TotalPCs: 60},
+		{Name: "mm", CoveredPCs: 0, TotalPCs: 30},
+		{Name: "net", CoveredPCs: 16, TotalPCs: 160},
+	}, groupCoverBySubsystem(files, matcher))
+}
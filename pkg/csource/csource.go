@@ -395,8 +395,37 @@ func (ctx *context) generateCsumInet(w *bytes.Buffer, addr uint64, arg prog.Exec
 			panic(fmt.Sprintf("unknown checksum chunk kind %v", chunk.Kind))
 		}
 	}
-	fmt.Fprintf(w, "\tNONFAILING(*(uint16*)0x%x = csum_inet_digest(&csum_%d));\n",
-		addr, csumSeq)
+	if arg.Kind == prog.ExecArgCsumInetSum {
+		fmt.Fprintf(w, "\tNONFAILING(*(uint16*)0x%x = csum_%d.acc);\n", addr, csumSeq)
+	} else {
+		fmt.Fprintf(w, "\tNONFAILING(*(uint16*)0x%x = csum_inet_digest(&csum_%d));\n", addr, csumSeq)
+	}
+}
+
+func (ctx *context) generateCsum(w *bytes.Buffer, addr uint64, arg prog.ExecArgCsum) {
+	if len(arg.Chunks) != 1 || arg.Chunks[0].Kind != prog.ExecArgCsumChunkData {
+		panic(fmt.Sprintf("bad checksum chunks %+v", arg.Chunks))
+	}
+	var fn string
+	switch arg.Kind {
+	case prog.ExecArgCsumCRC32:
+		fn = "csum_crc32(%v, %v, 0xedb88320)"
+	case prog.ExecArgCsumCRC32C:
+		fn = "csum_crc32(%v, %v, 0x82f63b78)"
+	case prog.ExecArgCsumAdler32:
+		fn = "csum_adler32(%v, %v)"
+	case prog.ExecArgCsumFletcher16:
+		fn = "csum_fletcher16(%v, %v)"
+	case prog.ExecArgCsumFletcher32:
+		fn = "csum_fletcher32(%v, %v)"
+	default:
+		panic(fmt.Sprintf("unknown csum kind %v", arg.Kind))
+	}
+	val := fmt.Sprintf(fn, fmt.Sprintf("(const uint8*)0x%x", arg.Chunks[0].Value), arg.Chunks[0].Size)
+	if arg.Format == prog.FormatBigEndian {
+		val = fmt.Sprintf("htobe%v(%v)", arg.Size*8, val)
+	}
+	ctx.copyinVal(w, addr, arg.Size, val, prog.FormatNative)
 }
 
 func (ctx *context) copyin(w *bytes.Buffer, csumSeq *int, copyin prog.ExecCopyin) {
@@ -432,11 +461,11 @@ func (ctx *context) copyin(w *bytes.Buffer, csumSeq *int, copyin prog.ExecCopyin
 		}
 	case prog.ExecArgCsum:
 		switch arg.Kind {
-		case prog.ExecArgCsumInet:
+		case prog.ExecArgCsumInet, prog.ExecArgCsumInetSum:
 			*csumSeq++
 			ctx.generateCsumInet(w, copyin.Addr, arg, *csumSeq)
 		default:
-			panic(fmt.Sprintf("unknown csum kind %v", arg.Kind))
+			ctx.generateCsum(w, copyin.Addr, arg)
 		}
 	default:
 		panic(fmt.Sprintf("bad argument type: %+v", arg))
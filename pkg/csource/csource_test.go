@@ -240,6 +240,20 @@ syscall(SYS_csource7, /*flag=*/4ul);
 syscall(SYS_csource7, /*flag=BIT_0|0x4*/5ul);
 `,
 		},
+		{
+			input: `
+csource8(&AUTO={0x0, 0x0, "abcd"})
+`,
+			output: fmt.Sprintf(`
+NONFAILING(*(uint32*)0x%[1]x = htobe32(0));
+NONFAILING(*(uint16*)0x%[2]x = 0);
+NONFAILING(memcpy((void*)0x%[3]x, "\xab\xcd", 2));
+NONFAILING(*(uint16*)0x%[2]x = csum_fletcher16((const uint8*)0x%[1]x, 8));
+NONFAILING(*(uint32*)0x%[1]x = htobe32(csum_crc32((const uint8*)0x%[1]x, 8, 0x82f63b78)));
+syscall(SYS_csource8, /*buf=*/0x%[1]xul);
+`,
+				target.DataOffset+0x40, target.DataOffset+0x44, target.DataOffset+0x46),
+		},
 	}
 	for i, test := range tests {
 		t.Run(fmt.Sprint(i), func(t *testing.T) {
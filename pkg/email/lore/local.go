@@ -0,0 +1,155 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package lore
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/hash"
+)
+
+// ReadMbox splits an mbox file into individual messages.
+// Both the mboxo and the mboxrd variants are supported.
+func ReadMbox(file string) ([]EmailReader, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var ret []EmailReader
+	for _, msg := range splitMbox(data) {
+		ret = append(ret, EmailReader{
+			Read: func() ([]byte, error) {
+				return msg, nil
+			},
+		})
+	}
+	return ret, nil
+}
+
+var (
+	mboxFromRe    = regexp.MustCompile(`(?m)^From .*\n`)
+	mboxEscapedRe = regexp.MustCompile(`(?m)^>(>*From )`)
+)
+
+func splitMbox(data []byte) [][]byte {
+	var ret [][]byte
+	var start []int
+	for _, pos := range mboxFromRe.FindAllIndex(data, -1) {
+		// The separator must be either at the start of the file or after an empty line.
+		if pos[0] == 0 || pos[0] >= 2 && data[pos[0]-2] == '\n' {
+			start = append(start, pos[0], pos[1])
+		}
+	}
+	if len(start) == 0 || start[0] != 0 {
+		// It's not an mbox, but maybe a single message.
+		start = append([]int{0, 0}, start...)
+	}
+	for i := 0; i < len(start); i += 2 {
+		end := len(data)
+		if i+2 < len(start) {
+			end = start[i+2]
+		}
+		msg := bytes.TrimSpace(data[start[i+1]:end])
+		if len(msg) == 0 {
+			continue
+		}
+		ret = append(ret, mboxEscapedRe.ReplaceAll(msg, []byte("$1")))
+	}
+	return ret
+}
+
+// ReadMaildir returns the messages from the maildir that were delivered after fromTime.
+func ReadMaildir(dir string, fromTime time.Time) ([]EmailReader, error) {
+	var ret []EmailReader
+	for _, sub := range []string{"new", "cur"} {
+		entries, err := os.ReadDir(filepath.Join(dir, sub))
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil, err
+			}
+			if info.ModTime().Before(fromTime) {
+				continue
+			}
+			file := filepath.Join(dir, sub, entry.Name())
+			ret = append(ret, EmailReader{
+				Read: func() ([]byte, error) {
+					return os.ReadFile(file)
+				},
+			})
+		}
+	}
+	return ret, nil
+}
+
+// ReadPatchDir reads the files generated by git format-patch.
+// All *.patch files in the directory must belong to the same patch series.
+// Unless git format-patch was invoked with --thread, the files have neither Message-ID,
+// nor In-Reply-To headers, so we generate them to let PatchSeries() group the patches.
+// The generated Message-IDs only depend on the contents, so they are stable between calls.
+func ReadPatchDir(dir string) ([]EmailReader, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.patch"))
+	if err != nil {
+		return nil, err
+	}
+	// The file names start with the patch number, the cover letter goes first.
+	sort.Strings(files)
+	var ret []EmailReader
+	var firstID string
+	for i, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		msgs := splitMbox(data)
+		if len(msgs) != 1 {
+			return nil, fmt.Errorf("%v: expected 1 message, found %v", file, len(msgs))
+		}
+		msg := msgs[0]
+		header, _, _ := bytes.Cut(msg, []byte("\n\n"))
+		var extra []string
+		msgID := headerValue(header, "Message-ID")
+		if msgID == "" {
+			msgID = fmt.Sprintf("<%v@format-patch>", hash.String(msg))
+			extra = append(extra, "Message-ID: "+msgID)
+		}
+		if i == 0 {
+			firstID = msgID
+		} else if headerValue(header, "In-Reply-To") == "" {
+			extra = append(extra, "In-Reply-To: "+firstID)
+		}
+		if len(extra) != 0 {
+			msg = append([]byte(strings.Join(extra, "\n")+"\n"), msg...)
+		}
+		ret = append(ret, EmailReader{
+			Read: func() ([]byte, error) {
+				return msg, nil
+			},
+		})
+	}
+	return ret, nil
+}
+
+func headerValue(header []byte, name string) string {
+	prefix := strings.ToLower(name) + ":"
+	for _, line := range strings.Split(string(header), "\n") {
+		if strings.HasPrefix(strings.ToLower(line), prefix) {
+			return strings.TrimSpace(line[len(prefix):])
+		}
+	}
+	return ""
+}
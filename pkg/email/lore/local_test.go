@@ -0,0 +1,137 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package lore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/email"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMbox = `From a@user.com Sun May  7 19:54:00 2017
+Date: Sun, 7 May 2017 19:54:00 -0700
+Subject: [PATCH v2 0/2] Some series
+Message-ID: <cover>
+From: UserA <a@user.com>
+
+Cover letter
+
+From a@user.com Sun May  7 19:55:00 2017
+Date: Sun, 7 May 2017 19:55:00 -0700
+Subject: [PATCH v2 1/2] First patch
+Message-ID: <patch-1>
+In-Reply-To: <cover>
+From: UserA <a@user.com>
+
+>From the description.
+From the middle of a paragraph.
+
+From a@user.com Sun May  7 19:56:00 2017
+Date: Sun, 7 May 2017 19:56:00 -0700
+Subject: [PATCH v2 2/2] Second patch
+Message-ID: <patch-2>
+In-Reply-To: <cover>
+From: UserA <a@user.com>
+
+Patch 2
+`
+
+func TestReadMbox(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "mbox")
+	require.NoError(t, os.WriteFile(file, []byte(testMbox), 0644))
+	readers, err := ReadMbox(file)
+	require.NoError(t, err)
+	require.Len(t, readers, 3)
+	body, err := readers[1].Read()
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(string(body), "\n\nFrom the description.\nFrom the middle of a paragraph."),
+		"%q", body)
+
+	series := PatchSeries(parseReaders(t, readers))
+	require.Len(t, series, 1)
+	assert.Equal(t, "Some series", series[0].Subject)
+	assert.Equal(t, 2, series[0].Version)
+	assert.Empty(t, series[0].Corrupted)
+	require.Len(t, series[0].Patches, 2)
+	assert.Equal(t, "<patch-1>", series[0].Patches[0].MessageID)
+	assert.Equal(t, "<patch-2>", series[0].Patches[1].MessageID)
+}
+
+func TestReadMaildir(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"new", "cur", "tmp"} {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, sub), 0755))
+	}
+	msgs := splitMbox([]byte(testMbox))
+	files := []string{
+		filepath.Join(dir, "new", "1"),
+		filepath.Join(dir, "cur", "2:2,S"),
+		filepath.Join(dir, "tmp", "3"),
+	}
+	for i, file := range files {
+		require.NoError(t, os.WriteFile(file, msgs[i], 0644))
+	}
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(files[1], old, old))
+
+	readers, err := ReadMaildir(dir, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, readers, 2)
+	readers, err = ReadMaildir(dir, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Len(t, readers, 1)
+}
+
+func TestReadPatchDir(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"0001-first.patch": `From 1111111111111111111111111111111111111111 Mon Sep 17 00:00:00 2001
+From: UserA <a@user.com>
+Date: Sun, 7 May 2017 19:55:00 -0700
+Subject: [PATCH 1/2] First patch
+
+Patch 1
+`,
+		"0002-second.patch": `From 2222222222222222222222222222222222222222 Mon Sep 17 00:00:00 2001
+From: UserA <a@user.com>
+Date: Sun, 7 May 2017 19:56:00 -0700
+Subject: [PATCH 2/2] Second patch
+
+Patch 2
+`,
+		"notes.txt": "Not a patch",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	readers, err := ReadPatchDir(dir)
+	require.NoError(t, err)
+	require.Len(t, readers, 2)
+	series := PatchSeries(parseReaders(t, readers))
+	require.Len(t, series, 1)
+	assert.Equal(t, "First patch", series[0].Subject)
+	assert.Empty(t, series[0].Corrupted)
+	assert.Len(t, series[0].Patches, 2)
+
+	// The IDs must not change between the calls.
+	readers, err = ReadPatchDir(dir)
+	require.NoError(t, err)
+	series2 := PatchSeries(parseReaders(t, readers))
+	assert.Equal(t, series[0].MessageID, series2[0].MessageID)
+}
+
+func parseReaders(t *testing.T, readers []EmailReader) []*email.Email {
+	var ret []*email.Email
+	for _, reader := range readers {
+		msg, err := reader.Parse(nil, nil)
+		require.NoError(t, err)
+		ret = append(ret, msg)
+	}
+	return ret
+}
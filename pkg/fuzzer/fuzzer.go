@@ -151,6 +151,7 @@ func (fuzzer *Fuzzer) processResult(req *queue.Request, res *queue.Result, flags
 			}
 			job := &triageJob{
 				p:        req.Prog.Clone(),
+				trace:    req.MutationTrace,
 				executor: res.Executor,
 				flags:    flags,
 				queue:    queue.Append(),
@@ -210,6 +211,10 @@ type Config struct {
 	FetchRawCover  bool
 	NewInputFilter func(call string) bool
 	PatchTest      bool
+	// Record mutation traces for mutated programs (see queue.Request.MutationTrace).
+	TraceMutations bool
+	// Call-to-call priorities imported from another corpus, used in addition to the own corpus.
+	PrioPrior *prog.DynamicPrios
 }
 
 func (fuzzer *Fuzzer) triageProgCall(p *prog.Prog, info *flatrpc.CallInfo, call int, triage *map[int]*triageCall) {
@@ -368,7 +373,7 @@ func (fuzzer *Fuzzer) rand() *rand.Rand {
 }
 
 func (fuzzer *Fuzzer) updateChoiceTable(programs []*prog.Prog) {
-	newCt := fuzzer.target.BuildChoiceTable(programs, fuzzer.Config.EnabledCalls)
+	newCt := fuzzer.target.BuildChoiceTableWithPrior(programs, fuzzer.Config.EnabledCalls, fuzzer.Config.PrioPrior)
 
 	fuzzer.ctMu.Lock()
 	defer fuzzer.ctMu.Unlock()
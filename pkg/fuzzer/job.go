@@ -58,17 +58,29 @@ func mutateProgRequest(fuzzer *Fuzzer, rnd *rand.Rand) *queue.Request {
 		return nil
 	}
 	newP := p.Clone()
-	newP.Mutate(rnd,
+	trace := fuzzer.mutate(newP, rnd)
+	return &queue.Request{
+		Prog:          newP,
+		ExecOpts:      setFlags(flatrpc.ExecFlagCollectSignal),
+		Stat:          fuzzer.statExecFuzz,
+		MutationTrace: trace,
+	}
+}
+
+// mutate mutates p in place, it returns the mutation trace if Config.TraceMutations is set.
+func (fuzzer *Fuzzer) mutate(p *prog.Prog, rnd *rand.Rand) *prog.MutationTrace {
+	opts := prog.DefaultMutateOpts
+	if fuzzer.Config.TraceMutations {
+		opts.Trace = new(prog.MutationTrace)
+	}
+	p.MutateWithOpts(rnd,
 		prog.RecommendedCalls,
 		fuzzer.ChoiceTable(),
 		fuzzer.Config.NoMutateCalls,
 		fuzzer.Config.Corpus.Programs(),
+		opts,
 	)
-	return &queue.Request{
-		Prog:     newP,
-		ExecOpts: setFlags(flatrpc.ExecFlagCollectSignal),
-		Stat:     fuzzer.statExecFuzz,
-	}
+	return opts.Trace
 }
 
 // triageJob are programs for which we noticed potential new coverage during
@@ -77,6 +89,7 @@ func mutateProgRequest(fuzzer *Fuzzer, rnd *rand.Rand) *queue.Request {
 // and if yes, minimize them and add to corpus.
 type triageJob struct {
 	p        *prog.Prog
+	trace    *prog.MutationTrace // The mutation that produced p, if recorded.
 	executor queue.ExecutorID
 	flags    ProgFlags
 	fuzzer   *Fuzzer
@@ -209,11 +222,12 @@ func (job *triageJob) handleCall(call int, info *triageCall) {
 	}
 	job.fuzzer.Logf(2, "added new input for %v to the corpus: %s", callName, p)
 	input := corpus.NewInput{
-		Prog:     p,
-		Call:     call,
-		Signal:   info.stableSignal,
-		Cover:    info.cover.Serialize(),
-		RawCover: info.rawCover,
+		Prog:          p,
+		Call:          call,
+		Signal:        info.stableSignal,
+		Cover:         info.cover.Serialize(),
+		RawCover:      info.rawCover,
+		MutationTrace: job.trace,
 	}
 	job.fuzzer.Config.Corpus.Save(input)
 }
@@ -452,14 +466,12 @@ func (job *smashJob) run(fuzzer *Fuzzer) {
 	rnd := fuzzer.rand()
 	for i := 0; i < iters; i++ {
 		p := job.p.Clone()
-		p.Mutate(rnd, prog.RecommendedCalls,
-			fuzzer.ChoiceTable(),
-			fuzzer.Config.NoMutateCalls,
-			fuzzer.Config.Corpus.Programs())
+		trace := fuzzer.mutate(p, rnd)
 		result := fuzzer.execute(job.exec, &queue.Request{
-			Prog:     p,
-			ExecOpts: setFlags(flatrpc.ExecFlagCollectSignal),
-			Stat:     fuzzer.statExecSmash,
+			Prog:          p,
+			ExecOpts:      setFlags(flatrpc.ExecFlagCollectSignal),
+			Stat:          fuzzer.statExecSmash,
+			MutationTrace: trace,
 		})
 		if result.Stop() {
 			return
@@ -31,6 +31,10 @@ type Request struct {
 	BinaryFile  string     // for RequestTypeBinary
 	GlobPattern string     // for 	RequestTypeGlob
 
+	// How the program was obtained by mutation of a corpus program
+	// (set only if the fuzzer is configured to trace mutations).
+	MutationTrace *prog.MutationTrace
+
 	// Return all signal for these calls instead of new signal.
 	ReturnAllSignal []int
 	ReturnError     bool
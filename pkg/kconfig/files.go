@@ -0,0 +1,84 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package kconfig
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FileConfigs returns configs that affect compilation of the given kernel source file (relative to srcDir):
+// configs that control whether the file is built at all (obj-$(CONFIG_FOO) += file.o in the Makefile
+// of the same or a parent dir) and configs that are referenced in the file itself (#ifdef CONFIG_FOO,
+// IS_ENABLED(CONFIG_FOO), etc). The result is sorted.
+func FileConfigs(srcDir, file string) []string {
+	configs := make(map[string]bool)
+	dir, obj := filepath.Dir(file), strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))+".o"
+	for {
+		data, err := os.ReadFile(filepath.Join(srcDir, dir, "Makefile"))
+		if err == nil {
+			for _, cfg := range makefileConfigs(data, obj) {
+				configs[cfg] = true
+			}
+		}
+		if dir == "." || dir == "/" {
+			break
+		}
+		// For parent dirs we are looking for obj-$(CONFIG_FOO) += dir/.
+		obj = filepath.Base(dir) + "/"
+		dir = filepath.Dir(dir)
+	}
+	if data, err := os.ReadFile(filepath.Join(srcDir, file)); err == nil {
+		for _, match := range sourceConfigRe.FindAllSubmatch(data, -1) {
+			configs[string(match[1])] = true
+		}
+	}
+	var res []string
+	for cfg := range configs {
+		res = append(res, cfg)
+	}
+	sort.Strings(res)
+	return res
+}
+
+var (
+	sourceConfigRe   = regexp.MustCompile(`\bCONFIG_([A-Za-z0-9_]+)`)
+	makefileConfigRe = regexp.MustCompile(`^\s*[a-z0-9_-]+-\$\(CONFIG_([A-Za-z0-9_]+)\)\s*[:+]?=(.*)$`)
+	// Matches composite object definitions: foo-y += a.o b.o, foo-objs := a.o b.o.
+	makefileCompositeRe = regexp.MustCompile(`^\s*([a-z0-9_-]+)-(?:y|objs)\s*[:+]?=(.*)$`)
+)
+
+// makefileConfigs returns configs that guard obj in the Kbuild Makefile.
+// Composite objects (foo-y += obj, obj-$(CONFIG_FOO) += foo.o) are taken into account.
+func makefileConfigs(data []byte, obj string) []string {
+	// Join continuation lines.
+	data = bytes.ReplaceAll(data, []byte("\\\n"), []byte(" "))
+	lines := strings.Split(string(data), "\n")
+	objs := map[string]bool{obj: true}
+	for _, line := range lines {
+		if match := makefileCompositeRe.FindStringSubmatch(line); match != nil && containsObj(match[2], objs) {
+			objs[match[1]+".o"] = true
+		}
+	}
+	var res []string
+	for _, line := range lines {
+		if match := makefileConfigRe.FindStringSubmatch(line); match != nil && containsObj(match[2], objs) {
+			res = append(res, match[1])
+		}
+	}
+	return res
+}
+
+func containsObj(list string, objs map[string]bool) bool {
+	for _, elem := range strings.Fields(list) {
+		if objs[elem] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package kconfig
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// VariantParams controls derivation of a config variant from a base config.
+type VariantParams struct {
+	// Number of random user-visible bool/tristate options to toggle.
+	Toggles int
+	// Configs that should be enabled in the variant (e.g. configs related to poorly covered files).
+	Enable []string
+	// Configs that must not be changed (e.g. debugging configs required for fuzzing).
+	Keep []string
+}
+
+// Variant derives a new config from base by enabling params.Enable configs and randomly toggling
+// params.Toggles user-visible options. Dependencies are resolved approximately: enabling a config
+// also enables all configs it depends on, and disabling a config also disables all enabled configs
+// that depend on it. The result is supposed to be passed through olddefconfig before use.
+// Returns the new config and the sorted list of configs that were changed.
+func (kconf *KConfig) Variant(base *ConfigFile, params VariantParams, rnd *rand.Rand) (*ConfigFile, []string) {
+	cf := base.Clone()
+	keep := make(map[string]bool)
+	for _, name := range params.Keep {
+		keep[name] = true
+	}
+	changed := make(map[string]bool)
+	for _, name := range params.Enable {
+		kconf.enable(cf, name, keep, changed)
+	}
+	candidates := kconf.toggleCandidates()
+	for i := 0; i < params.Toggles && len(candidates) != 0; i++ {
+		name := candidates[rnd.Intn(len(candidates))]
+		if keep[name] || changed[name] {
+			continue
+		}
+		if cf.Value(name) == No {
+			kconf.enable(cf, name, keep, changed)
+		} else {
+			kconf.disable(cf, name, keep, changed)
+		}
+	}
+	var res []string
+	for name := range changed {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return cf, res
+}
+
+// toggleCandidates returns sorted names of all bool/tristate configs that have a prompt,
+// i.e. can be changed by the user.
+func (kconf *KConfig) toggleCandidates() []string {
+	var res []string
+	for name, m := range kconf.Configs {
+		if isToggle(m) && m.Prompt() != "" {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+func isToggle(m *Menu) bool {
+	return m.Type == TypeBool || m.Type == TypeTristate
+}
+
+// enable enables the config and all its dependencies. It's a no-op if that would require
+// changing any of the keep configs.
+func (kconf *KConfig) enable(cf *ConfigFile, name string, keep, changed map[string]bool) {
+	m := kconf.Configs[name]
+	if m == nil || !isToggle(m) || cf.Value(name) != No {
+		return
+	}
+	toEnable := []string{name}
+	for dep := range m.DependsOn() {
+		depMenu := kconf.Configs[dep]
+		if depMenu == nil || !isToggle(depMenu) || cf.Value(dep) != No {
+			continue
+		}
+		toEnable = append(toEnable, dep)
+	}
+	for _, cfg := range toEnable {
+		if keep[cfg] {
+			return
+		}
+	}
+	for _, cfg := range toEnable {
+		cf.Set(cfg, Yes)
+		changed[cfg] = true
+	}
+}
+
+// disable disables the config and all enabled configs that depend on it. It's a no-op if that would
+// require changing any of the keep configs.
+func (kconf *KConfig) disable(cf *ConfigFile, name string, keep, changed map[string]bool) {
+	m := kconf.Configs[name]
+	if m == nil || !isToggle(m) || cf.Value(name) == No {
+		return
+	}
+	toDisable := []string{name}
+	for _, cfg := range cf.Configs {
+		if cfg.Value == No || cfg.Name == name {
+			continue
+		}
+		if dep := kconf.Configs[cfg.Name]; dep != nil && dep.DependsOn()[name] {
+			toDisable = append(toDisable, cfg.Name)
+		}
+	}
+	for _, cfg := range toDisable {
+		if keep[cfg] {
+			return
+		}
+	}
+	for _, cfg := range toDisable {
+		cf.Unset(cfg)
+		changed[cfg] = true
+	}
+}
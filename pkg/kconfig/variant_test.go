@@ -0,0 +1,123 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package kconfig
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+)
+
+const variantKConfig = `
+mainmenu "test"
+
+config NET
+	bool "Networking support"
+
+config HAMRADIO
+	bool "Amateur Radio support"
+	depends on NET
+
+config AX25
+	tristate "Amateur Radio AX.25 Level 2 protocol"
+	depends on HAMRADIO
+
+config ROSE
+	tristate "Amateur Radio X.25 PLP (Rose)"
+	depends on AX25
+
+config KASAN
+	bool "KASAN"
+
+config HIDDEN
+	bool
+`
+
+func TestVariantEnable(t *testing.T) {
+	kconf, err := ParseData(targets.Get("linux", "amd64"), []byte(variantKConfig), "Kconfig")
+	assert.NoError(t, err)
+	base, err := ParseConfigData([]byte("CONFIG_KASAN=y\n"), "base")
+	assert.NoError(t, err)
+
+	cf, changed := kconf.Variant(base, VariantParams{Enable: []string{"ROSE"}}, rand.New(rand.NewSource(0)))
+	assert.Equal(t, []string{"AX25", "HAMRADIO", "NET", "ROSE"}, changed)
+	for _, name := range changed {
+		assert.Equal(t, Yes, cf.Value(name))
+	}
+	// The base config must not be changed.
+	assert.Equal(t, No, base.Value("ROSE"))
+
+	// Can't enable ROSE w/o changing NET.
+	_, changed = kconf.Variant(base, VariantParams{
+		Enable: []string{"ROSE"},
+		Keep:   []string{"NET"},
+	}, rand.New(rand.NewSource(0)))
+	assert.Empty(t, changed)
+}
+
+func TestVariantToggle(t *testing.T) {
+	kconf, err := ParseData(targets.Get("linux", "amd64"), []byte(variantKConfig), "Kconfig")
+	assert.NoError(t, err)
+	base, err := ParseConfigData([]byte(`
+CONFIG_KASAN=y
+CONFIG_NET=y
+CONFIG_HAMRADIO=y
+CONFIG_AX25=y
+CONFIG_ROSE=y
+`), "base")
+	assert.NoError(t, err)
+	for seed := int64(0); seed < 100; seed++ {
+		cf, changed := kconf.Variant(base, VariantParams{
+			Toggles: 2,
+			Keep:    []string{"KASAN"},
+		}, rand.New(rand.NewSource(seed)))
+		assert.Equal(t, Yes, cf.Value("KASAN"))
+		assert.NotContains(t, changed, "HIDDEN")
+		// Disabling a config must disable everything that depends on it.
+		if cf.Value("NET") == No {
+			assert.Equal(t, No, cf.Value("HAMRADIO"))
+			assert.Equal(t, No, cf.Value("ROSE"))
+		}
+		if cf.Value("AX25") == No {
+			assert.Equal(t, No, cf.Value("ROSE"))
+		}
+	}
+}
+
+func TestFileConfigs(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"net/Makefile": `
+obj-$(CONFIG_NET) += core/
+obj-$(CONFIG_HAMRADIO) += ax25/
+`,
+		"net/ax25/Makefile": `
+obj-$(CONFIG_AX25) += ax25.o
+ax25-y := ax25_addr.o ax25_dev.o \
+	ax25_iface.o
+ax25-$(CONFIG_AX25_DAMA_SLAVE) += ax25_ds_in.o
+obj-$(CONFIG_ROSE) += rose.o
+`,
+		"net/ax25/ax25_iface.c": `
+#ifdef CONFIG_SYSCTL
+static int foo;
+#endif
+if (IS_ENABLED(CONFIG_AX25_DAMA_MASTER))
+`,
+	}
+	for name, data := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		assert.NoError(t, os.WriteFile(file, []byte(data), 0644))
+	}
+	assert.Equal(t, []string{"AX25", "AX25_DAMA_MASTER", "HAMRADIO", "SYSCTL"},
+		FileConfigs(dir, "net/ax25/ax25_iface.c"))
+	assert.Equal(t, []string{"AX25_DAMA_SLAVE", "HAMRADIO"},
+		FileConfigs(dir, "net/ax25/ax25_ds_in.c"))
+	assert.Equal(t, []string(nil), FileConfigs(dir, "kernel/fork.c"))
+}
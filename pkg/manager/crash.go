@@ -4,6 +4,7 @@
 package manager
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -30,6 +31,7 @@ type CrashStore struct {
 const reproFileName = "repro.prog"
 const cReproFileName = "repro.cprog"
 const straceFileName = "strace.log"
+const incompleteFixFileName = "incomplete_fix.json"
 
 const MaxReproAttempts = 3
 
@@ -173,6 +175,23 @@ func (cs *CrashStore) SaveRepro(res *ReproResult, progText, cProgText []byte) er
 	return nil
 }
 
+// IncompleteFix is a fixing commit of a bug with the same title that is present in the build
+// the crash has happened on.
+type IncompleteFix struct {
+	BugTitle string
+	BugLink  string
+	Title    string
+	Hash     string
+	Tag      string // the build the crash has happened on
+}
+
+// SaveIncompleteFix records that the crashes with the title happen despite the fixes.
+func (cs *CrashStore) SaveIncompleteFix(title string, fixes []*IncompleteFix) error {
+	dir := cs.path(title)
+	osutil.MkdirAll(dir)
+	return osutil.WriteJSON(filepath.Join(dir, incompleteFixFileName), fixes)
+}
+
 type BugReport struct {
 	Title  string
 	Tag    string
@@ -218,6 +237,8 @@ type BugInfo struct {
 	StraceFile    string // relative to the workdir
 	ReproAttempts int
 	Crashes       []*CrashInfo
+	// Set if the crash still happens on the builds that contain fixes of the bugs with this title.
+	IncompleteFix []*IncompleteFix
 }
 
 func (cs *CrashStore) BugInfo(id string, full bool) (*BugInfo, error) {
@@ -254,6 +275,14 @@ func (cs *CrashStore) BugInfo(id string, full bool) (*BugInfo, error) {
 			ret.HasCRepro = true
 		} else if f == straceFileName {
 			ret.StraceFile = filepath.Join(dir, f)
+		} else if f == incompleteFixFileName {
+			data, err := os.ReadFile(filepath.Join(dir, f))
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(data, &ret.IncompleteFix); err != nil {
+				return nil, fmt.Errorf("failed to parse %v: %w", f, err)
+			}
 		} else if strings.HasPrefix(f, "repro") {
 			ret.ReproAttempts++
 		}
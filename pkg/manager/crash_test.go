@@ -113,3 +113,29 @@ func TestCrashRepro(t *testing.T) {
 	assert.Equal(t, []byte("c prog text"), report.CProg)
 	assert.Equal(t, []byte("Some report"), report.Report)
 }
+
+func TestIncompleteFix(t *testing.T) {
+	crashStore := &CrashStore{
+		BaseDir:      t.TempDir(),
+		MaxCrashLogs: 10,
+	}
+	_, err := crashStore.SaveCrash(&Crash{Report: &report.Report{
+		Title:  "Title A",
+		Output: []byte("ABCD"),
+	}})
+	assert.NoError(t, err)
+	fixes := []*IncompleteFix{
+		{
+			BugTitle: "Title A",
+			Title:    "foo: fix the crash",
+			Hash:     "1234",
+			Tag:      "build1",
+		},
+	}
+	assert.NoError(t, crashStore.SaveIncompleteFix("Title A", fixes))
+
+	list, err := crashStore.BugList()
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, fixes, list[0].IncompleteFix)
+}
@@ -88,6 +88,7 @@ func (serv *HTTPServer) Serve(ctx context.Context) error {
 	handle("/cover", serv.httpCover)
 	handle("/coverprogs", serv.httpPrograms)
 	handle("/debuginput", serv.httpDebugInput)
+	handle("/descriptions", serv.httpDescriptions)
 	handle("/file", serv.httpFile)
 	handle("/filecover", serv.httpFileCover)
 	handle("/filterpcs", serv.httpFilterPCs)
@@ -365,6 +366,8 @@ func makeUICrashType(info *BugInfo, startTime time.Time, repros map[string]bool)
 		Triaged:     triaged,
 		Strace:      info.StraceFile,
 		Crashes:     crashes,
+
+		IncompleteFix: info.IncompleteFix,
 	}
 }
 
@@ -654,6 +657,15 @@ func (serv *HTTPServer) httpPrio(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "the corpus information is not yet available", http.StatusInternalServerError)
 		return
 	}
+	if r.FormValue("json") == "1" {
+		var progs []*prog.Prog
+		for _, inp := range corpus.Items() {
+			progs = append(progs, inp.Prog)
+		}
+		w.Header().Set("Content-Type", ctApplicationJSON)
+		w.Write(serv.Cfg.Target.CalculateDynamicPriorities(progs).Serialize())
+		return
+	}
 
 	callName := r.FormValue("call")
 	call := serv.Cfg.Target.SyscallMap[callName]
@@ -682,6 +694,44 @@ func (serv *HTTPServer) httpPrio(w http.ResponseWriter, r *http.Request) {
 	executeTemplate(w, prioTemplate, data)
 }
 
+func (serv *HTTPServer) httpDescriptions(w http.ResponseWriter, r *http.Request) {
+	corpus := serv.Corpus.Load()
+	if corpus == nil {
+		http.Error(w, "the corpus information is not yet available", http.StatusInternalServerError)
+		return
+	}
+	callName := r.FormValue("call")
+	all := r.FormValue("all") != ""
+	data := &UIDescriptionsData{
+		UIPageHeader: serv.pageHeader(r, "descriptions"),
+		Call:         callName,
+		All:          all,
+	}
+	for call, elems := range corpus.DescriptionCover(serv.Cfg.Target) {
+		if callName != "" && call != callName {
+			continue
+		}
+		for _, elem := range elems {
+			if elem.Signal != 0 && !all {
+				continue
+			}
+			data.Elems = append(data.Elems, UIDescElem{
+				Call:     call,
+				Kind:     string(elem.Kind),
+				Type:     elem.Type,
+				Option:   elem.Option,
+				Selected: elem.Selected,
+				Inputs:   elem.Inputs,
+				Signal:   elem.Signal,
+			})
+		}
+	}
+	sort.SliceStable(data.Elems, func(i, j int) bool {
+		return data.Elems[i].Call < data.Elems[j].Call
+	})
+	executeTemplate(w, descriptionsTemplate, data)
+}
+
 func (serv *HTTPServer) httpFile(w http.ResponseWriter, r *http.Request) {
 	file := filepath.Clean(r.FormValue("name"))
 	if !strings.HasPrefix(file, "crashes/") && !strings.HasPrefix(file, "corpus/") {
@@ -1030,6 +1080,9 @@ type UICrashType struct {
 	Triaged     string
 	Strace      string
 	Crashes     []UICrash
+
+	// The fixes that are present in the build, yet the crash still happens.
+	IncompleteFix []*IncompleteFix
 }
 
 type UICrash struct {
@@ -1133,6 +1186,23 @@ type UIPrio struct {
 	Prio int32
 }
 
+type UIDescriptionsData struct {
+	UIPageHeader
+	Call  string
+	All   bool
+	Elems []UIDescElem
+}
+
+type UIDescElem struct {
+	Call     string
+	Kind     string
+	Type     string
+	Option   string
+	Selected int
+	Inputs   int
+	Signal   int
+}
+
 type UIFallbackCoverData struct {
 	UIPageHeader
 	Calls []UIFallbackCall
@@ -1180,6 +1250,7 @@ var (
 	crashTemplate         = createPage("crash", UICrashPage{})
 	corpusTemplate        = createPage("corpus", UICorpusPage{})
 	prioTemplate          = createPage("prio", UIPrioData{})
+	descriptionsTemplate  = createPage("descriptions", UIDescriptionsData{})
 	fallbackCoverTemplate = createPage("fallback_cover", UIFallbackCoverData{})
 	rawCoverTemplate      = createPage("raw_cover", UIRawCoverPage{})
 	jobListTemplate       = createPage("job_list", UIJobList{})
@@ -255,6 +255,17 @@ type Experimental struct {
 	// with an empty Filter, but non-empty weight.
 	// E.g. "focus_areas": [ {"filter": {"files": ["^net"]}, "weight": 10.0}, {"weight": 1.0"} ].
 	FocusAreas []FocusArea `json:"focus_areas,omitempty"`
+
+	// File with corpus-derived call-to-call priorities exported from another manager
+	// (download them from the /prio?json=1 page). They are used as an additional prior
+	// for syscall selection, which helps a fresh manager with an empty corpus.
+	PrioPrior string `json:"prio_prior,omitempty"`
+
+	// Record traces of mutations that produced new corpus inputs and save them to workdir/traces
+	// (the file name is the corpus input hash). The traces include the mutated program and the used
+	// corpus programs, so they can be replayed with syz-mutate -replay given the same enabled syscalls.
+	// Note: the new corpus input is the minimized version of the mutation result.
+	TraceMutations bool `json:"trace_mutations,omitempty"`
 }
 
 type FocusArea struct {
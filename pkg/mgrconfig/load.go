@@ -338,6 +338,12 @@ func (cfg *Config) completeBinaries() error {
 		}
 		cfg.StraceBin = osutil.Abs(cfg.StraceBin)
 	}
+	if cfg.Experimental.PrioPrior != "" {
+		if !osutil.IsExist(cfg.Experimental.PrioPrior) {
+			return fmt.Errorf("bad config param prio_prior: can't find %v", cfg.Experimental.PrioPrior)
+		}
+		cfg.Experimental.PrioPrior = osutil.Abs(cfg.Experimental.PrioPrior)
+	}
 	return nil
 }
 
@@ -11,7 +11,9 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/syzkaller/dashboard/dashapi"
 	"github.com/google/syzkaller/pkg/debugtracer"
+	"github.com/google/syzkaller/sys/targets"
 )
 
 func init() {
@@ -401,3 +403,38 @@ func TestBisect(t *testing.T) {
 		}
 	}
 }
+
+func TestMissingFixCommits(t *testing.T) {
+	t.Parallel()
+	baseDir := t.TempDir()
+	repo := MakeTestRepo(t, baseDir)
+	old := repo.CommitChange("net: an old fix")
+	repo.Git("checkout", "-b", "stable")
+	repo.Git("checkout", "-b", "upstream")
+	fix1 := repo.CommitChange("net: fix1")
+	fix2 := repo.CommitChange("mm: fix2")
+	repo.Git("checkout", "stable")
+	// The cherry-picked commit gets a different hash.
+	repo.CommitChange("net: fix1")
+
+	vcsRepo, err := NewRepo(targets.TestOS, targets.TestArch64, baseDir, OptPrecious)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing, err := MissingFixCommits(vcsRepo, []dashapi.FixCommit{
+		{BugID: "1", Title: old.Title, Hash: old.Hash},
+		{BugID: "2", Title: fix1.Title, Hash: fix1.Hash},
+		{BugID: "3", Title: fix2.Title, Hash: fix2.Hash},
+		{BugID: "4", Title: "fs: fix3"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []dashapi.FixCommit{
+		{BugID: "3", Title: fix2.Title, Hash: fix2.Hash},
+		{BugID: "4", Title: "fs: fix3"},
+	}
+	if diff := cmp.Diff(want, missing); diff != "" {
+		t.Fatal(diff)
+	}
+}
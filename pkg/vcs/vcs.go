@@ -259,6 +259,45 @@ func Patch(dir string, patch []byte) error {
 	return nil
 }
 
+// MissingFixCommits returns the fix commits (see dashapi.FixCommits) that are not reachable
+// from the checked out commit of the repo, e.g. fixes that are not backported to a stable tree.
+// The commits are first looked up by hash. Backports to stable trees are cherry-picked
+// and get new hashes, so the commits that were not found are then looked up by title.
+func MissingFixCommits(repo Repo, fixes []dashapi.FixCommit) ([]dashapi.FixCommit, error) {
+	var rest []dashapi.FixCommit
+	var titles []string
+	for _, fix := range fixes {
+		if fix.Hash != "" {
+			ok, err := repo.Contains(fix.Hash)
+			if err != nil {
+				return nil, err
+			} else if ok {
+				continue
+			}
+		}
+		rest = append(rest, fix)
+		titles = append(titles, fix.Title)
+	}
+	if len(rest) == 0 {
+		return nil, nil
+	}
+	_, missingTitles, err := repo.GetCommitsByTitles(titles)
+	if err != nil {
+		return nil, err
+	}
+	missing := make(map[string]bool)
+	for _, title := range missingTitles {
+		missing[title] = true
+	}
+	var ret []dashapi.FixCommit
+	for _, fix := range rest {
+		if missing[fix.Title] {
+			ret = append(ret, fix)
+		}
+	}
+	return ret, nil
+}
+
 // CheckRepoAddress does a best-effort approximate check of a git repo address.
 func CheckRepoAddress(repo string) bool {
 	return gitLocalRepoRe.MatchString(repo) ||
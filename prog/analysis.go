@@ -25,6 +25,10 @@ type state struct {
 	strings   map[string]bool
 	ma        *memAlloc
 	va        *vmaAlloc
+	// Current states of resources in protocols.
+	protoStates map[protocolInstance]string
+	// Resource that generation of the next call should use to follow a protocol transition.
+	protoSubject *ResultArg
 }
 
 // analyze analyzes the program p up to but not including call c.
@@ -50,6 +54,8 @@ func newState(target *Target, ct *ChoiceTable, corpus []*Prog) *state {
 		strings:   make(map[string]bool),
 		ma:        newMemAlloc(target.NumPages * target.PageSize),
 		va:        newVmaAlloc(target.NumPages),
+
+		protoStates: make(map[protocolInstance]string),
 	}
 	return s
 }
@@ -102,6 +108,9 @@ func (s *state) analyzeImpl(c *Call, resources bool) {
 			}
 		}
 	})
+	if resources {
+		s.analyzeProtocols(c)
+	}
 }
 
 type parentStack []Arg
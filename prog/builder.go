@@ -0,0 +1,346 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Build returns a builder that constructs programs from Go code
+// without writing arg trees or program text by hand, for example:
+//
+//	p, err := target.Build().
+//		Call("openat", uint64(0xffffffffffffff9c), "./file0\x00", 0x42, 0).Ret("fd").
+//		Call("write").Field("fd", ResVar("fd")).Field("buf", []byte{1, 2}).
+//		Finalize()
+//
+// Fields are denoted by dot-separated paths. The first element is the syscall argument name,
+// next elements are struct field names, union option names (which selects the option)
+// or array element indexes. Pointers are transparent: paths go through them to the pointee,
+// and setting a pointer to a non-nil value sets the pointee.
+// Values can be integers (ints, flags, consts, resources with special values),
+// strings/[]byte (input data), integers for output data (size) and vma (number of pages),
+// ResVar (reference to a resource variable), or nil (null pointer or the default value).
+// Arguments that are not set have default values, lengths are calculated automatically,
+// and pointers are allocated when the call is complete.
+func (target *Target) Build() *Builder {
+	return MakeProgGen(target)
+}
+
+// ResVar is a reference to a resource variable defined with CallBuilder.Ret or CallBuilder.Var.
+type ResVar string
+
+type CallBuilder struct {
+	pg *Builder
+	c  *Call
+}
+
+// Call appends a call to the program. Optional args set syscall arguments in order.
+func (pg *Builder) Call(name string, args ...any) *CallBuilder {
+	pg.flush()
+	cb := &CallBuilder{pg: pg}
+	if pg.err != nil {
+		return cb
+	}
+	meta := pg.target.SyscallMap[name]
+	if meta == nil {
+		pg.err = fmt.Errorf("unknown syscall %v", name)
+		return cb
+	}
+	if len(args) > len(meta.Args) {
+		pg.err = fmt.Errorf("%v: excessive syscall arguments: %v, want %v", name, len(args), len(meta.Args))
+		return cb
+	}
+	var defaults []Arg
+	for _, f := range meta.Args {
+		defaults = append(defaults, f.DefaultArg(f.Dir(DirIn)))
+	}
+	cb.c = MakeCall(meta, defaults)
+	pg.cur = cb.c
+	for i, val := range args {
+		cb.Field(meta.Args[i].Name, val)
+	}
+	return cb
+}
+
+// flush appends the call constructed with Call to the program.
+func (pg *Builder) flush() {
+	c := pg.cur
+	if c == nil || pg.err != nil {
+		return
+	}
+	pg.cur = nil
+	c.setDefaultConditions(pg.target, true)
+	ForeachArg(c, func(arg Arg, _ *ArgCtx) {
+		a, ok := arg.(*PointerArg)
+		if !ok || a.IsSpecial() {
+			return
+		}
+		if a.Res != nil {
+			a.Address = pg.Allocate(a.Res.Size(), a.Res.Type().Alignment())
+		} else {
+			a.Address = pg.AllocateVMA(a.VmaSize / pg.target.PageSize)
+		}
+	})
+	pg.Append(c)
+}
+
+// Call appends the next call to the program (see Builder.Call).
+func (cb *CallBuilder) Call(name string, args ...any) *CallBuilder {
+	return cb.pg.Call(name, args...)
+}
+
+// Finalize returns the constructed program (see Builder.Finalize).
+func (cb *CallBuilder) Finalize() (*Prog, error) {
+	return cb.pg.Finalize()
+}
+
+// Field sets the argument denoted by path to val.
+func (cb *CallBuilder) Field(path string, val any) *CallBuilder {
+	cb.do(path, func(arg Arg) error {
+		return cb.pg.set(arg, val)
+	})
+	return cb
+}
+
+// Ret names the resource returned by the call, so that subsequent calls can refer to it.
+func (cb *CallBuilder) Ret(name string) *CallBuilder {
+	cb.do("", func(Arg) error {
+		if cb.c.Ret == nil {
+			return fmt.Errorf("syscall does not return a resource")
+		}
+		return cb.pg.define(name, cb.c.Ret)
+	})
+	return cb
+}
+
+// Var names the output resource denoted by path, so that subsequent calls can refer to it.
+func (cb *CallBuilder) Var(path, name string) *CallBuilder {
+	cb.do(path, func(arg Arg) error {
+		for {
+			ptr, ok := arg.(*PointerArg)
+			if !ok || !isPtrType(ptr) {
+				break
+			}
+			arg = builderPointee(ptr)
+		}
+		res, ok := arg.(*ResultArg)
+		if !ok || res.Dir() == DirIn {
+			return fmt.Errorf("%v is not an output resource", arg.Type().Name())
+		}
+		return cb.pg.define(name, res)
+	})
+	return cb
+}
+
+// Props sets call properties.
+func (cb *CallBuilder) Props(props CallProps) *CallBuilder {
+	cb.do("", func(Arg) error {
+		cb.c.Props = props
+		return nil
+	})
+	return cb
+}
+
+func (cb *CallBuilder) do(path string, fn func(arg Arg) error) {
+	pg := cb.pg
+	if pg.err != nil {
+		return
+	}
+	if pg.cur != cb.c {
+		pg.err = fmt.Errorf("%v: the call is already complete", cb.c.Meta.Name)
+		return
+	}
+	var arg Arg
+	var err error
+	if path != "" {
+		arg, err = pg.lookup(cb.c, path)
+	}
+	if err == nil {
+		err = fn(arg)
+	}
+	if err != nil {
+		if path != "" {
+			err = fmt.Errorf("%v: %w", path, err)
+		}
+		pg.err = fmt.Errorf("call #%v %v: %w", len(pg.p.Calls), cb.c.Meta.Name, err)
+	}
+}
+
+func (pg *Builder) define(name string, res *ResultArg) error {
+	if pg.vars[name] != nil {
+		return fmt.Errorf("variable %v is redefined", name)
+	}
+	pg.vars[name] = res
+	return nil
+}
+
+func (pg *Builder) lookup(c *Call, path string) (Arg, error) {
+	elems := strings.Split(path, ".")
+	var arg Arg
+	for i, f := range c.Meta.Args {
+		if f.Name == elems[0] {
+			arg = c.Args[i]
+		}
+	}
+	if arg == nil {
+		return nil, fmt.Errorf("no argument %v", elems[0])
+	}
+	for _, elem := range elems[1:] {
+		var err error
+		if arg, err = pg.descend(arg, elem); err != nil {
+			return nil, err
+		}
+	}
+	return arg, nil
+}
+
+func (pg *Builder) descend(arg Arg, elem string) (Arg, error) {
+	switch a := arg.(type) {
+	case *PointerArg:
+		if !isPtrType(a) {
+			break
+		}
+		return pg.descend(builderPointee(a), elem)
+	case *GroupArg:
+		switch typ := a.Type().(type) {
+		case *StructType:
+			for i, f := range typ.Fields {
+				if f.Name == elem && !IsPad(f.Type) {
+					return a.Inner[i], nil
+				}
+			}
+			return nil, fmt.Errorf("%v has no field %v", typ.Name(), elem)
+		case *ArrayType:
+			idx, err := strconv.Atoi(elem)
+			if err != nil || idx < 0 {
+				return nil, fmt.Errorf("bad index %q for %v", elem, typ.Name())
+			}
+			if typ.Kind == ArrayRangeLen && uint64(idx) >= typ.RangeEnd {
+				return nil, fmt.Errorf("index %v is out of bounds for %v", idx, typ.Name())
+			}
+			for len(a.Inner) <= idx {
+				a.Inner = append(a.Inner, typ.Elem.DefaultArg(a.Dir()))
+			}
+			return a.Inner[idx], nil
+		}
+	case *UnionArg:
+		typ := a.Type().(*UnionType)
+		for i, f := range typ.Fields {
+			if f.Name != elem {
+				continue
+			}
+			if a.Index != i {
+				replaceArg(a, MakeUnionArg(typ, a.Dir(), f.DefaultArg(f.Dir(a.Dir())), i))
+			}
+			return a.Option, nil
+		}
+		return nil, fmt.Errorf("%v has no option %v", typ.Name(), elem)
+	}
+	return nil, fmt.Errorf("%v has no fields", arg.Type().Name())
+}
+
+func isPtrType(a *PointerArg) bool {
+	_, ok := a.Type().(*PtrType)
+	return ok
+}
+
+// builderPointee returns the pointee of the pointer, the pointer is made non-special if necessary.
+func builderPointee(a *PointerArg) Arg {
+	if a.Res == nil {
+		typ := a.Type().(*PtrType)
+		*a = *MakePointerArg(typ, DirIn, 0, typ.Elem.DefaultArg(typ.ElemDir))
+	}
+	return a.Res
+}
+
+func (pg *Builder) set(arg Arg, val any) error {
+	if val == nil {
+		if a, ok := arg.(*PointerArg); ok {
+			if a.Res != nil {
+				removeArg(a.Res)
+			}
+			*a = *MakeSpecialPointerArg(a.Type(), a.Dir(), 0)
+			return nil
+		}
+		replaceArg(arg, arg.Type().DefaultArg(arg.Dir()))
+		return nil
+	}
+	v, isInt := builderInt(val)
+	switch a := arg.(type) {
+	case *PointerArg:
+		if isPtrType(a) {
+			return pg.set(builderPointee(a), val)
+		}
+		if !isInt || v == 0 || v > pg.target.NumPages {
+			break
+		}
+		*a = *MakeVmaPointerArg(a.Type(), a.Dir(), 0, v*pg.target.PageSize)
+		return nil
+	case *ConstArg:
+		if !isInt {
+			break
+		}
+		a.Val = v
+		return nil
+	case *ResultArg:
+		typ := a.Type().(*ResourceType)
+		if isInt {
+			replaceResultArg(a, MakeResultArg(typ, a.Dir(), nil, v))
+			return nil
+		}
+		name, ok := val.(ResVar)
+		if !ok {
+			break
+		}
+		res := pg.vars[string(name)]
+		if res == nil {
+			return fmt.Errorf("undefined variable %v", name)
+		}
+		if !pg.target.isCompatibleResource(typ.Desc.Name, res.Type().(*ResourceType).Desc.Name) {
+			return fmt.Errorf("variable %v of type %v can't be used as %v",
+				name, res.Type().Name(), typ.Name())
+		}
+		replaceResultArg(a, MakeResultArg(typ, a.Dir(), res, 0))
+		return nil
+	case *DataArg:
+		typ := a.Type().(*BufferType)
+		if a.Dir() == DirOut {
+			if !isInt {
+				break
+			}
+			*a = *MakeOutDataArg(typ, a.Dir(), v)
+			return nil
+		}
+		var data []byte
+		switch d := val.(type) {
+		case string:
+			data = []byte(d)
+		case []byte:
+			data = d
+		default:
+			return fmt.Errorf("can't set %v to %T", typ.Name(), val)
+		}
+		if !typ.Varlen() && uint64(len(data)) != typ.Size() {
+			return fmt.Errorf("data size %v does not match %v size %v", len(data), typ.Name(), typ.Size())
+		}
+		a.SetData(data)
+		return nil
+	}
+	return fmt.Errorf("can't set %v to %v", arg.Type().Name(), val)
+}
+
+func builderInt(val any) (uint64, bool) {
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,196 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/syzkaller/pkg/image"
+)
+
+func TestBuilder(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	type Test struct {
+		build func(pg *Builder) *CallBuilder
+		prog  string
+	}
+	tests := []Test{
+		{
+			build: func(pg *Builder) *CallBuilder {
+				return pg.Call("test$res0").Ret("res").
+					Call("test$res1", ResVar("res")).Props(CallProps{FailNth: 2}).
+					Call("test$res1", 0x42)
+			},
+			prog: `r0 = test$res0()
+test$res1(r0) (fail_nth: 2)
+test$res1(0x42)
+`,
+		},
+		{
+			build: func(pg *Builder) *CallBuilder {
+				return pg.Call("test$res3").Var("a0", "res").
+					Call("test$res1").Field("a0", ResVar("res"))
+			},
+			prog: `test$res3(&(0x7f0000000000)=<r0=>0xffff)
+test$res1(r0)
+`,
+		},
+		{
+			build: func(pg *Builder) *CallBuilder {
+				return pg.Call("test$struct").Field("a0.f0", 1).
+					Call("test$union0").Field("a0.f", 2).Field("a0.u.f1.3", 3).
+					Call("test$union0").Field("a0.u.f2", 4).
+					Call("test$array0").Field("a0.f1.0.f1", 5).Field("a0.f1.1.f0", 6).
+					Call("test$blob0", []byte{1, 2, 3})
+			},
+			prog: `test$struct(&(0x7f0000000000)={0x1})
+test$union0(&(0x7f0000000040)={0x2, @f1=[0x0, 0x0, 0x0, 0x3]})
+test$union0(&(0x7f00000000c0)={0x0, @f2=0x4})
+test$array0(&(0x7f0000000140)={0x0, [@f1=0x5, @f0=0x6]})
+test$blob0(&(0x7f0000000180)="010203")
+`,
+		},
+		{
+			build: func(pg *Builder) *CallBuilder {
+				return pg.Call("test$length0").Field("a0", nil).
+					Call("test$vma0", 2, nil, 5).Field("v2", 8)
+			},
+			prog: `test$length0(0x0)
+test$vma0(&(0x7f0000000000/0x2000)=nil, 0x2000, &(0x7f0000002000/0x5000)=nil, 0x5000, &(0x7f0000007000/0x8000)=nil, 0x8000)
+`,
+		},
+	}
+	for i, test := range tests {
+		p, err := test.build(target.Build()).Finalize()
+		if err != nil {
+			t.Fatalf("#%v: %v", i, err)
+		}
+		if diff := cmp.Diff(test.prog, string(p.Serialize())); diff != "" {
+			t.Errorf("#%v: wrong program:\n%s", i, diff)
+		}
+	}
+}
+
+func TestBuilderDeserialize(t *testing.T) {
+	// Programs constructed with the builder must be the same as the deserialized ones.
+	target := initTargetTest(t, "test", "64")
+	data := []byte("syzkaller")
+	compressed := image.Compress(data)
+	type Test struct {
+		build func(pg *Builder) *CallBuilder
+		text  string
+	}
+	tests := []Test{
+		{
+			build: func(pg *Builder) *CallBuilder {
+				return pg.Call("syz_compare", []byte{0, 0, 0, 0}).Field("got.conditional", nil)
+			},
+			text: `syz_compare(&(0x7f0000000000)="00000000", 0x4, &(0x7f0000000040)=@conditional={0x0, @void, @void, @void}, AUTO)`,
+		},
+		{
+			build: func(pg *Builder) *CallBuilder {
+				return pg.Call("syz_compare_zlib").Field("data", data).Field("zdata", compressed)
+			},
+			text: fmt.Sprintf(`syz_compare_zlib(&(0x7f0000000000)="$%s", AUTO, &(0x7f0000000040)="$%s", AUTO)`,
+				image.EncodeB64(data), image.EncodeB64(compressed)),
+		},
+		{
+			build: func(pg *Builder) *CallBuilder {
+				return pg.Call("syz_inject_cover", []byte{0xab, 0xcd})
+			},
+			text: `syz_inject_cover(&(0x7f0000000000)="abcd", AUTO)`,
+		},
+	}
+	for i, test := range tests {
+		p, err := test.build(target.Build()).Finalize()
+		if err != nil {
+			t.Fatalf("#%v: %v", i, err)
+		}
+		want, err := target.Deserialize([]byte(test.text), NonStrict)
+		if err != nil {
+			t.Fatalf("#%v: %v", i, err)
+		}
+		if diff := cmp.Diff(string(want.Serialize()), string(p.Serialize())); diff != "" {
+			t.Errorf("#%v: wrong program:\n%s", i, diff)
+		}
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	type Test struct {
+		build func(pg *Builder) *CallBuilder
+		err   string
+	}
+	tests := []Test{
+		{
+			build: func(pg *Builder) *CallBuilder { return pg.Call("foo").Field("a0", 1) },
+			err:   "unknown syscall foo",
+		},
+		{
+			build: func(pg *Builder) *CallBuilder { return pg.Call("test$res1", 1, 2) },
+			err:   "test$res1: excessive syscall arguments: 2, want 1",
+		},
+		{
+			build: func(pg *Builder) *CallBuilder { return pg.Call("test$res0").Call("test$res1", ResVar("res")) },
+			err:   "call #1 test$res1: a0: undefined variable res",
+		},
+		{
+			build: func(pg *Builder) *CallBuilder { return pg.Call("test$res2").Ret("fd").Call("test$res1", ResVar("fd")) },
+			err:   "call #1 test$res1: a0: variable fd of type fd can't be used as syz_res",
+		},
+		{
+			build: func(pg *Builder) *CallBuilder { return pg.Call("test$res1").Ret("res") },
+			err:   "call #0 test$res1: syscall does not return a resource",
+		},
+		{
+			build: func(pg *Builder) *CallBuilder { return pg.Call("test$res0").Ret("res").Call("test$res0").Ret("res") },
+			err:   "call #1 test$res0: variable res is redefined",
+		},
+		{
+			build: func(pg *Builder) *CallBuilder { return pg.Call("test$res1").Var("a0", "res") },
+			err:   "call #0 test$res1: a0: syz_res is not an output resource",
+		},
+		{
+			build: func(pg *Builder) *CallBuilder { return pg.Call("test$struct").Field("a0.f2", 1) },
+			err:   "call #0 test$struct: a0.f2: syz_struct0 has no field f2",
+		},
+		{
+			build: func(pg *Builder) *CallBuilder { return pg.Call("test$union0").Field("a0.u.f3", 1) },
+			err:   "call #0 test$union0: a0.u.f3: syz_union0 has no option f3",
+		},
+		{
+			build: func(pg *Builder) *CallBuilder { return pg.Call("test$array0").Field("a0.f1.2", nil) },
+			err:   "call #0 test$array0: a0.f1.2: index 2 is out of bounds for array",
+		},
+		{
+			build: func(pg *Builder) *CallBuilder { return pg.Call("test$struct").Field("a0.f0.x", 1) },
+			err:   "call #0 test$struct: a0.f0.x: int64 has no fields",
+		},
+		{
+			build: func(pg *Builder) *CallBuilder { return pg.Call("test$struct").Field("a0.f0", "foo") },
+			err:   "call #0 test$struct: a0.f0: can't set int64 to foo",
+		},
+		{
+			build: func(pg *Builder) *CallBuilder { return pg.Call("test$blob0", 1) },
+			err:   "call #0 test$blob0: a: can't set array to int",
+		},
+		{
+			build: func(pg *Builder) *CallBuilder {
+				cb := pg.Call("test$res0")
+				pg.Call("test$res0")
+				return cb.Ret("res")
+			},
+			err: "test$res0: the call is already complete",
+		},
+	}
+	for i, test := range tests {
+		_, err := test.build(target.Build()).Finalize()
+		if err == nil || err.Error() != test.err {
+			t.Errorf("#%v: wrong error\ngot:  %v\nwant: %v", i, err, test.err)
+		}
+	}
+}
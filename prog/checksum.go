@@ -27,23 +27,25 @@ type CsumChunk struct {
 }
 
 func calcChecksumsCall(c *Call) (map[Arg]CsumInfo, map[Arg]struct{}) {
-	var inetCsumFields, pseudoCsumFields []Arg
+	var inetCsumFields, pseudoCsumFields, plainCsumFields []Arg
 
 	// Find all csum fields.
 	ForeachArg(c, func(arg Arg, _ *ArgCtx) {
 		if typ, ok := arg.Type().(*CsumType); ok {
 			switch typ.Kind {
-			case CsumInet:
+			case CsumInet, CsumInetSum:
 				inetCsumFields = append(inetCsumFields, arg)
-			case CsumPseudo:
+			case CsumPseudo, CsumPseudoSum:
 				pseudoCsumFields = append(pseudoCsumFields, arg)
+			case CsumCRC32, CsumCRC32C, CsumAdler32, CsumFletcher16, CsumFletcher32:
+				plainCsumFields = append(plainCsumFields, arg)
 			default:
 				panic(fmt.Sprintf("unknown csum kind %v", typ.Kind))
 			}
 		}
 	})
 
-	if len(inetCsumFields) == 0 && len(pseudoCsumFields) == 0 {
+	if len(inetCsumFields) == 0 && len(pseudoCsumFields) == 0 && len(plainCsumFields) == 0 {
 		return nil, nil
 	}
 
@@ -66,7 +68,16 @@ func calcChecksumsCall(c *Call) (map[Arg]CsumInfo, map[Arg]struct{}) {
 		csummedArg := findCsummedArg(arg, typ, parentsMap)
 		csumUses[csummedArg] = struct{}{}
 		chunk := CsumChunk{CsumChunkArg, csummedArg, 0, 0}
-		csumMap[arg] = CsumInfo{Kind: CsumInet, Chunks: []CsumChunk{chunk}}
+		csumMap[arg] = CsumInfo{Kind: typ.Kind, Chunks: []CsumChunk{chunk}}
+	}
+
+	// Calculate checksums that cover only the csummed field (crc32, adler32, etc).
+	for _, arg := range plainCsumFields {
+		typ, _ := arg.Type().(*CsumType)
+		csummedArg := findCsummedArg(arg, typ, parentsMap)
+		csumUses[csummedArg] = struct{}{}
+		chunk := CsumChunk{CsumChunkArg, csummedArg, 0, 0}
+		csumMap[arg] = CsumInfo{Kind: typ.Kind, Chunks: []CsumChunk{chunk}}
 	}
 
 	// No need to continue if there are no pseudo csum fields.
@@ -104,6 +115,11 @@ func calcChecksumsCall(c *Call) (map[Arg]CsumInfo, map[Arg]struct{}) {
 		} else {
 			info = composePseudoCsumIPv6(csummedArg, ipSrcAddr, ipDstAddr, protocol)
 		}
+		if typ.Kind == CsumPseudoSum {
+			// Only the pseudo header is summed, the last chunk is the packet itself.
+			info.Kind = CsumInetSum
+			info.Chunks = info.Chunks[:len(info.Chunks)-1]
+		}
 		csumMap[arg] = info
 		csumUses[csummedArg] = struct{}{}
 		csumUses[ipSrcAddr] = struct{}{}
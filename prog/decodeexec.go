@@ -63,6 +63,7 @@ type ExecArgData struct {
 type ExecArgCsum struct {
 	Size   uint64
 	Kind   uint64
+	Format BinaryFormat
 	Chunks []ExecCsumChunk
 }
 
@@ -221,9 +222,12 @@ func (dec *execDecoder) readArg() ExecArg {
 			Readable: readable,
 		}
 	case execArgCsum:
-		size := dec.read("arg/csum/size")
+		meta := dec.read("arg/csum/meta")
+		size := meta & 0xff
+		format := BinaryFormat((meta >> 8) & 0xff)
 		switch kind := dec.read("arg/csum/kind"); kind {
-		case ExecArgCsumInet:
+		case ExecArgCsumInet, ExecArgCsumCRC32, ExecArgCsumCRC32C, ExecArgCsumAdler32,
+			ExecArgCsumFletcher16, ExecArgCsumFletcher32, ExecArgCsumInetSum:
 			chunks := make([]ExecCsumChunk, dec.read("arg/csum/chunks"))
 			for i := range chunks {
 				kind := dec.read("arg/csum/chunk/kind")
@@ -241,6 +245,7 @@ func (dec *execDecoder) readArg() ExecArg {
 			return ExecArgCsum{
 				Size:   size,
 				Kind:   kind,
+				Format: format,
 				Chunks: chunks,
 			}
 		default:
@@ -0,0 +1,359 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ProgDiff is a structural difference between two programs.
+// Calls are aligned by syscall names, and arguments are compared field-by-field,
+// so addresses of pointers and numbering of resources do not affect the result:
+// references to resources are compared by the (aligned) calls that produce them.
+type ProgDiff struct {
+	Calls []CallDiff `json:"calls"`
+}
+
+type CallDiffKind int
+
+const (
+	CallSame CallDiffKind = iota
+	CallChanged
+	CallRemoved
+	CallInserted
+)
+
+var callDiffKindNames = [...]string{
+	CallSame:     "same",
+	CallChanged:  "changed",
+	CallRemoved:  "removed",
+	CallInserted: "inserted",
+}
+
+func (kind CallDiffKind) String() string {
+	return callDiffKindNames[kind]
+}
+
+func (kind CallDiffKind) MarshalText() ([]byte, error) {
+	return []byte(kind.String()), nil
+}
+
+func (kind *CallDiffKind) UnmarshalText(data []byte) error {
+	for k, name := range callDiffKindNames {
+		if name == string(data) {
+			*kind = CallDiffKind(k)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown call diff kind %q", data)
+}
+
+type CallDiff struct {
+	Kind CallDiffKind `json:"kind"`
+	Name string       `json:"name"`
+	// Indexes of the call in the old/new programs (-1 for inserted/removed calls).
+	Old    int         `json:"old"`
+	New    int         `json:"new"`
+	Fields []FieldDiff `json:"fields,omitempty"`
+}
+
+// FieldDiff describes a changed scalar value (int, data, resource, union option, call properties).
+// Path uses the same notation as Builder (syscall argument and field names, union options,
+// array indexes; pointers are transparent). Old is empty for added fields, New is empty for removed.
+type FieldDiff struct {
+	Path string `json:"path"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+// DiffProgs returns the structural difference between p0 and p1.
+func DiffProgs(p0, p1 *Prog) *ProgDiff {
+	toNew, toOld := alignProgs(p0, p1)
+	old := flattenProg(p0, func(idx int) string { return fmt.Sprint(idx) })
+	cur := flattenProg(p1, func(idx int) string {
+		if toOld[idx] >= 0 {
+			return fmt.Sprint(toOld[idx])
+		}
+		return fmt.Sprintf("new%v", idx)
+	})
+	return &ProgDiff{Calls: diffCalls(p0.Calls, p1.Calls, toNew, toOld, old, cur)}
+}
+
+// diffCalls compares aligned calls (see alignProgs) of 2 flattened programs.
+func diffCalls(calls0, calls1 []*Call, toNew, toOld []int, old, cur *flatProg) []CallDiff {
+	var res []CallDiff
+	i, j := 0, 0
+	for i < len(calls0) || j < len(calls1) {
+		switch {
+		case i < len(calls0) && toNew[i] < 0:
+			res = append(res, CallDiff{
+				Kind: CallRemoved,
+				Name: calls0[i].Meta.Name,
+				Old:  i,
+				New:  -1,
+			})
+			i++
+		case j < len(calls1) && toOld[j] < 0:
+			res = append(res, CallDiff{
+				Kind: CallInserted,
+				Name: calls1[j].Meta.Name,
+				Old:  -1,
+				New:  j,
+			})
+			j++
+		default:
+			cd := CallDiff{
+				Kind:   CallSame,
+				Name:   calls0[i].Meta.Name,
+				Old:    i,
+				New:    j,
+				Fields: diffFields(old.calls[i], cur.calls[j]),
+			}
+			if len(cd.Fields) != 0 {
+				cd.Kind = CallChanged
+			}
+			res = append(res, cd)
+			i++
+			j++
+		}
+	}
+	return res
+}
+
+// Empty returns true if the programs are structurally equal.
+func (diff *ProgDiff) Empty() bool {
+	for _, cd := range diff.Calls {
+		if cd.Kind != CallSame {
+			return false
+		}
+	}
+	return true
+}
+
+func (diff *ProgDiff) String() string {
+	buf := new(bytes.Buffer)
+	for _, cd := range diff.Calls {
+		switch cd.Kind {
+		case CallSame:
+			fmt.Fprintf(buf, "  #%v #%v %v\n", cd.Old, cd.New, cd.Name)
+		case CallChanged:
+			fmt.Fprintf(buf, "~ #%v #%v %v\n", cd.Old, cd.New, cd.Name)
+		case CallRemoved:
+			fmt.Fprintf(buf, "- #%v %v\n", cd.Old, cd.Name)
+		case CallInserted:
+			fmt.Fprintf(buf, "+ #%v %v\n", cd.New, cd.Name)
+		}
+		for _, fd := range cd.Fields {
+			switch {
+			case fd.Old == "":
+				fmt.Fprintf(buf, "\t+ %v: %v\n", fd.Path, fd.New)
+			case fd.New == "":
+				fmt.Fprintf(buf, "\t- %v: %v\n", fd.Path, fd.Old)
+			default:
+				fmt.Fprintf(buf, "\t%v: %v -> %v\n", fd.Path, fd.Old, fd.New)
+			}
+		}
+	}
+	return buf.String()
+}
+
+// alignProgs matches calls of the programs using the longest common subsequence of syscalls.
+// It returns the index of the matching call in the other program for each call (or -1).
+func alignProgs(p0, p1 *Prog) ([]int, []int) {
+	n, m := len(p0.Calls), len(p1.Calls)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if p0.Calls[i].Meta == p1.Calls[j].Meta {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	toNew, toOld := make([]int, n), make([]int, m)
+	for i := range toNew {
+		toNew[i] = -1
+	}
+	for j := range toOld {
+		toOld[j] = -1
+	}
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case p0.Calls[i].Meta == p1.Calls[j].Meta:
+			toNew[i], toOld[j] = j, i
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return toNew, toOld
+}
+
+// diffLeaf is a scalar value in a call.
+type diffLeaf struct {
+	path string
+	text string // human-readable value
+	key  string // value used for comparison
+	arg  Arg    // nil for call properties
+	// Set only for call properties.
+	props CallProps
+}
+
+type resLoc struct {
+	call int
+	path string
+}
+
+type flatProg struct {
+	target *Target
+	calls  [][]diffLeaf
+	res    map[*ResultArg]resLoc
+	// Returns the call identity used to compare resource references across programs.
+	id func(idx int) string
+}
+
+const diffPropsPath = "(props)"
+
+func flattenProg(p *Prog, id func(idx int) string) *flatProg {
+	fp := &flatProg{
+		target: p.Target,
+		res:    make(map[*ResultArg]resLoc),
+		id:     id,
+	}
+	for i, c := range p.Calls {
+		fp.calls = append(fp.calls, fp.flattenCall(i, c))
+	}
+	return fp
+}
+
+func (fp *flatProg) flattenCall(idx int, c *Call) []diffLeaf {
+	var leaves []diffLeaf
+	if c.Ret != nil {
+		fp.res[c.Ret] = resLoc{idx, "ret"}
+	}
+	for i, arg := range c.Args {
+		leaves = fp.flattenArg(leaves, idx, arg, c.Meta.Args[i].Name)
+	}
+	if props := propsString(c.Props); props != "" {
+		leaves = append(leaves, diffLeaf{path: diffPropsPath, text: props, key: props, props: c.Props})
+	}
+	return leaves
+}
+
+func (fp *flatProg) flattenArg(leaves []diffLeaf, idx int, arg Arg, path string) []diffLeaf {
+	leaf := diffLeaf{path: path, arg: arg}
+	switch a := arg.(type) {
+	case *ConstArg:
+		leaf.text = fmt.Sprintf("0x%x", a.Val)
+	case *PointerArg:
+		if a.Res != nil {
+			return fp.flattenArg(leaves, idx, a.Res, path)
+		}
+		if a.IsSpecial() {
+			leaf.text = fmt.Sprintf("0x%x", a.Address)
+			leaf.key = "special:" + leaf.text
+		} else {
+			leaf.text = fmt.Sprintf("vma/0x%x", a.VmaSize)
+		}
+	case *DataArg:
+		ctx := &serializer{target: fp.target, buf: new(bytes.Buffer)}
+		a.serialize(ctx)
+		leaf.text = ctx.buf.String()
+	case *GroupArg:
+		switch typ := a.Type().(type) {
+		case *StructType:
+			for i, inner := range a.Inner {
+				if !IsPad(typ.Fields[i].Type) {
+					leaves = fp.flattenArg(leaves, idx, inner, path+"."+typ.Fields[i].Name)
+				}
+			}
+		case *ArrayType:
+			for i, inner := range a.Inner {
+				leaves = fp.flattenArg(leaves, idx, inner, fmt.Sprintf("%v.%v", path, i))
+			}
+		}
+		return leaves
+	case *UnionArg:
+		name := a.Type().(*UnionType).Fields[a.Index].Name
+		leaf.text = "@" + name
+		leaf.key = leaf.text
+		leaves = append(leaves, leaf)
+		return fp.flattenArg(leaves, idx, a.Option, path+"."+name)
+	case *ResultArg:
+		if a.Dir() != DirIn {
+			fp.res[a] = resLoc{idx, path}
+		}
+		if a.Res == nil {
+			leaf.text = fmt.Sprintf("0x%x", a.Val)
+			break
+		}
+		loc, ok := fp.res[a.Res]
+		ops := ""
+		if a.OpDiv != 0 {
+			ops += fmt.Sprintf("/%v", a.OpDiv)
+		}
+		if a.OpAdd != 0 {
+			ops += fmt.Sprintf("+%v", a.OpAdd)
+		}
+		if !ok {
+			leaf.text = "<unknown>" + ops
+			break
+		}
+		leaf.text = fmt.Sprintf("<#%v.%v>%v", loc.call, loc.path, ops)
+		leaf.key = fmt.Sprintf("ref:%v.%v%v", fp.id(loc.call), loc.path, ops)
+	}
+	if leaf.key == "" {
+		leaf.key = leaf.text
+	}
+	return append(leaves, leaf)
+}
+
+func propsString(props CallProps) string {
+	var res []string
+	props.ForeachProp(func(_, key string, value reflect.Value) {
+		if value.IsZero() {
+			return
+		}
+		if value.Kind() == reflect.Bool {
+			res = append(res, key)
+		} else {
+			res = append(res, fmt.Sprintf("%v: %v", key, value.Interface()))
+		}
+	})
+	return strings.Join(res, ", ")
+}
+
+func diffFields(old, cur []diffLeaf) []FieldDiff {
+	curMap := make(map[string]diffLeaf)
+	for _, leaf := range cur {
+		curMap[leaf.path] = leaf
+	}
+	oldMap := make(map[string]bool)
+	var res []FieldDiff
+	for _, leaf := range old {
+		oldMap[leaf.path] = true
+		leaf1, ok := curMap[leaf.path]
+		if !ok {
+			res = append(res, FieldDiff{Path: leaf.path, Old: leaf.text})
+		} else if leaf.key != leaf1.key {
+			res = append(res, FieldDiff{Path: leaf.path, Old: leaf.text, New: leaf1.text})
+		}
+	}
+	for _, leaf := range cur {
+		if !oldMap[leaf.path] {
+			res = append(res, FieldDiff{Path: leaf.path, New: leaf.text})
+		}
+	}
+	return res
+}
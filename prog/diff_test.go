@@ -0,0 +1,291 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiffProgs(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	type Test struct {
+		p0   string
+		p1   string
+		diff string
+	}
+	tests := []Test{
+		{
+			p0: `r0 = test$res0()
+test$struct(&(0x7f0000000000)={0x1})
+test$res1(r0)
+`,
+			p1: `r1 = test$res0()
+test$struct(&(0x7f0000001000)={0x1})
+test$res1(r1)
+`,
+			diff: `  #0 #0 test$res0
+  #1 #1 test$struct
+  #2 #2 test$res1
+`,
+		},
+		{
+			p0: `r0 = test$res0()
+test$struct(&(0x7f0000000000)={0x1})
+test$res1(r0)
+`,
+			p1: `r0 = test$res0()
+test$res1(0x5)
+test$struct(&(0x7f0000000000)={0x2})
+test$res1(r0)
+test$res1(r0) (fail_nth: 2)
+`,
+			diff: `  #0 #0 test$res0
++ #1 test$res1
+~ #1 #2 test$struct
+	a0.f0: 0x1 -> 0x2
+  #2 #3 test$res1
++ #4 test$res1
+`,
+		},
+		{
+			p0: `r0 = test$res0()
+r1 = test$res0()
+test$res1(r0)
+test$union0(&(0x7f0000000000)={0x0, @f1=[0x0, 0x0, 0x0, 0x3]})
+test$blob0(&(0x7f0000000000)="0102")
+`,
+			p1: `r0 = test$res0()
+r1 = test$res0()
+test$res1(r1)
+test$union0(&(0x7f0000000000)={0x0, @f2=0x4})
+test$blob0(&(0x7f0000000000)="0103") (async)
+`,
+			diff: `  #0 #0 test$res0
+  #1 #1 test$res0
+~ #2 #2 test$res1
+	a0: <#0.ret> -> <#1.ret>
+~ #3 #3 test$union0
+	a0.u: @f1 -> @f2
+	- a0.u.f1.0: 0x0
+	- a0.u.f1.1: 0x0
+	- a0.u.f1.2: 0x0
+	- a0.u.f1.3: 0x3
+	- a0.u.f1.4: 0x0
+	- a0.u.f1.5: 0x0
+	- a0.u.f1.6: 0x0
+	- a0.u.f1.7: 0x0
+	- a0.u.f1.8: 0x0
+	- a0.u.f1.9: 0x0
+	+ a0.u.f2: 0x4
+~ #4 #4 test$blob0
+	a: "0102" -> "0103"
+	+ (props): async
+`,
+		},
+		{
+			p0: `test$res0()
+test$struct(&(0x7f0000000000)={0x1})
+`,
+			p1: `test$struct(&(0x7f0000000000)={0x1})
+test$res0()
+`,
+			diff: `- #0 test$res0
+  #1 #0 test$struct
++ #1 test$res0
+`,
+		},
+	}
+	for i, test := range tests {
+		p0, err := target.Deserialize([]byte(test.p0), NonStrict)
+		if err != nil {
+			t.Fatalf("#%v: failed to deserialize: %v", i, err)
+		}
+		p1, err := target.Deserialize([]byte(test.p1), NonStrict)
+		if err != nil {
+			t.Fatalf("#%v: failed to deserialize: %v", i, err)
+		}
+		diff := DiffProgs(p0, p1)
+		if d := cmp.Diff(test.diff, diff.String()); d != "" {
+			t.Errorf("#%v: wrong diff:\n%s", i, d)
+		}
+		empty := test.diff == DiffProgs(p0, p0).String()
+		if diff.Empty() != empty {
+			t.Errorf("#%v: Empty() = %v, want %v", i, diff.Empty(), empty)
+		}
+		if !DiffProgs(p1, p1).Empty() {
+			t.Errorf("#%v: program differs from itself", i)
+		}
+	}
+}
+
+func TestMergeProgs(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	type Test struct {
+		base string
+		a    string
+		b    string
+		res  string
+		err  string
+	}
+	tests := []Test{
+		{
+			// Independent insertions and removals.
+			base: `r0 = test$res0()
+test$struct(&(0x7f0000000000)={0x1})
+test$res1(r0)
+`,
+			a: `r0 = test$res0()
+test$res1(0x5)
+test$struct(&(0x7f0000000000)={0x1})
+test$res1(r0)
+`,
+			b: `r0 = test$res0()
+test$struct(&(0x7f0000000000)={0x1})
+`,
+			res: `test$res0()
+test$res1(0x5)
+test$struct(&(0x7f0000000000)={0x1})
+`,
+		},
+		{
+			// Changes of different fields of the same call are combined,
+			// references are relinked to the merged calls.
+			base: `r0 = test$res0()
+test$union0(&(0x7f0000000000)={0x0, @f1=[0x0, 0x0, 0x0, 0x3]})
+`,
+			a: `r0 = test$res0()
+test$union0(&(0x7f0000000000)={0x1, @f1=[0x0, 0x0, 0x0, 0x3]})
+`,
+			b: `r0 = test$res0()
+r1 = test$res0()
+test$union0(&(0x7f0000001000)={0x0, @f1=[0x0, 0x7, 0x0, 0x3]}) (fail_nth: 3)
+test$res1(r1)
+`,
+			res: `test$res0()
+r0 = test$res0()
+test$union0(&(0x7f0000000000)={0x1, @f1=[0x0, 0x7, 0x0, 0x3]}) (fail_nth: 3)
+test$res1(r0)
+`,
+		},
+		{
+			// The same change in both programs.
+			base: `test$struct(&(0x7f0000000000)={0x1})
+`,
+			a: `test$struct(&(0x7f0000000000)={0x2})
+test$res0()
+`,
+			b: `test$struct(&(0x7f0000000000)={0x2})
+test$res0()
+`,
+			res: `test$struct(&(0x7f0000000000)={0x2})
+test$res0()
+`,
+		},
+		{
+			// The same call is inserted in both programs,
+			// references to it from only one of the programs are preserved.
+			base: `test$struct(&(0x7f0000000000)={0x1})
+`,
+			a: `r0 = test$res0()
+test$struct(&(0x7f0000000000)={0x1})
+`,
+			b: `r0 = test$res0()
+test$struct(&(0x7f0000000000)={0x1})
+test$res1(r0)
+`,
+			res: `r0 = test$res0()
+test$struct(&(0x7f0000000000)={0x1})
+test$res1(r0)
+`,
+		},
+		{
+			base: `test$struct(&(0x7f0000000000)={0x1})
+test$res0()
+`,
+			a: `test$struct(&(0x7f0000000000)={0x2})
+test$res0()
+`,
+			b: `test$struct(&(0x7f0000000000)={0x3})
+`,
+			err: `merge conflicts:
+call #0 test$struct: conflicting changes of a0.f0`,
+		},
+		{
+			base: `test$union0(&(0x7f0000000000)={0x0, @f1=[0x0, 0x0, 0x0, 0x3]})
+test$struct(&(0x7f0000000000)={0x1})
+`,
+			a: `test$union0(&(0x7f0000000000)={0x1, @f1=[0x0, 0x0, 0x0, 0x3]})
+`,
+			b: `test$union0(&(0x7f0000000000)={0x0, @f2=0x4})
+test$struct(&(0x7f0000000000)={0x2})
+test$res0()
+`,
+			err: `merge conflicts:
+call #0 test$union0: conflicting structural changes
+call #1 test$struct is removed in one program and changed in another`,
+		},
+		{
+			base: `test$struct(&(0x7f0000000000)={0x1})
+`,
+			a: `test$res0()
+test$struct(&(0x7f0000000000)={0x1})
+`,
+			b: `test$res1(0x1)
+test$struct(&(0x7f0000000000)={0x1})
+`,
+			err: `merge conflicts:
+different calls are inserted before base call #0`,
+		},
+	}
+	for i, test := range tests {
+		var progs []*Prog
+		for _, text := range []string{test.base, test.a, test.b} {
+			p, err := target.Deserialize([]byte(text), NonStrict)
+			if err != nil {
+				t.Fatalf("#%v: failed to deserialize: %v", i, err)
+			}
+			progs = append(progs, p)
+		}
+		p, err := MergeProgs(progs[0], progs[1], progs[2])
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("#%v: wrong error\ngot:  %v\nwant: %v", i, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("#%v: merge failed: %v", i, err)
+		}
+		if d := cmp.Diff(test.res, string(p.Serialize())); d != "" {
+			t.Errorf("#%v: wrong program:\n%s", i, d)
+		}
+	}
+}
+
+func TestMergeProgsRandom(t *testing.T) {
+	testEachTargetRandom(t, func(t *testing.T, target *Target, rs rand.Source, iters int) {
+		ct := target.DefaultChoiceTable()
+		for i := 0; i < iters; i++ {
+			p0 := target.Generate(rs, 10, ct)
+			p1 := p0.Clone()
+			p1.Mutate(rs, 20, ct, nil, nil)
+			if diff := DiffProgs(p1, p1.Clone()); !diff.Empty() {
+				t.Fatalf("program differs from its clone:\n%s\n%v", p1.Serialize(), diff)
+			}
+			// Merging with an unchanged program must produce the changed one.
+			for _, args := range [][3]*Prog{{p0, p0, p1}, {p0, p1, p0}} {
+				p, err := MergeProgs(args[0], args[1], args[2])
+				if err != nil {
+					t.Fatalf("merge failed: %v\nbase:\n%s\nchanged:\n%s", err, p0.Serialize(), p1.Serialize())
+				}
+				if diff := DiffProgs(p1, p); !diff.Empty() {
+					t.Fatalf("wrong merge result:\n%s\nwant:\n%s\ndiff:\n%v", p.Serialize(), p1.Serialize(), diff)
+				}
+			}
+		}
+	})
+}
@@ -48,6 +48,12 @@ const (
 
 const (
 	ExecArgCsumInet = uint64(iota)
+	ExecArgCsumCRC32
+	ExecArgCsumCRC32C
+	ExecArgCsumAdler32
+	ExecArgCsumFletcher16
+	ExecArgCsumFletcher32
+	ExecArgCsumInetSum
 )
 
 const (
@@ -206,28 +212,40 @@ func (w *execContext) writeChecksums() {
 		w.write(execInstrCopyin)
 		w.write(w.args[arg].Addr)
 		w.write(execArgCsum)
-		w.write(arg.Size())
+		w.write(arg.Size() | uint64(arg.Type().Format())<<8)
 		switch info.Kind {
 		case CsumInet:
 			w.write(ExecArgCsumInet)
-			w.write(uint64(len(info.Chunks)))
-			for _, chunk := range info.Chunks {
-				switch chunk.Kind {
-				case CsumChunkArg:
-					w.write(ExecArgCsumChunkData)
-					w.write(w.args[chunk.Arg].Addr)
-					w.write(chunk.Arg.Size())
-				case CsumChunkConst:
-					w.write(ExecArgCsumChunkConst)
-					w.write(chunk.Value)
-					w.write(chunk.Size)
-				default:
-					panic(fmt.Sprintf("csum chunk has unknown kind %v", chunk.Kind))
-				}
-			}
+		case CsumCRC32:
+			w.write(ExecArgCsumCRC32)
+		case CsumCRC32C:
+			w.write(ExecArgCsumCRC32C)
+		case CsumAdler32:
+			w.write(ExecArgCsumAdler32)
+		case CsumFletcher16:
+			w.write(ExecArgCsumFletcher16)
+		case CsumFletcher32:
+			w.write(ExecArgCsumFletcher32)
+		case CsumInetSum:
+			w.write(ExecArgCsumInetSum)
 		default:
 			panic(fmt.Sprintf("csum arg has unknown kind %v", info.Kind))
 		}
+		w.write(uint64(len(info.Chunks)))
+		for _, chunk := range info.Chunks {
+			switch chunk.Kind {
+			case CsumChunkArg:
+				w.write(ExecArgCsumChunkData)
+				w.write(w.args[chunk.Arg].Addr)
+				w.write(chunk.Arg.Size())
+			case CsumChunkConst:
+				w.write(ExecArgCsumChunkConst)
+				w.write(chunk.Value)
+				w.write(chunk.Size)
+			default:
+				panic(fmt.Sprintf("csum chunk has unknown kind %v", chunk.Kind))
+			}
+		}
 	}
 }
 
@@ -574,6 +574,53 @@ func TestSerializeForExec(t *testing.T) {
 				},
 			},
 		},
+		{
+			"test$csum_ipv4_offload(&(0x7f0000000000)={{0x0, 0x1, 0x2}, {0x0, 0x0, \"ab\"}})",
+			[]any{
+				execInstrCopyin, 0, execArgConst, 2, 0x0,
+				execInstrCopyin, 2, execArgConst, 4 | 1<<8, 0x1,
+				execInstrCopyin, 6, execArgConst, 4 | 1<<8, 0x2,
+				execInstrCopyin, 10, execArgConst, 2, 0x0,
+				execInstrCopyin, 12, execArgConst, 2, 0x0,
+				execInstrCopyin, 14, execArgData, 1, []byte{0xab},
+				execInstrCopyin, 12, execArgCsum, 2, ExecArgCsumInetSum, 1,
+				ExecArgCsumChunkData, 10, 5,
+				execInstrCopyin, 10, execArgCsum, 2, ExecArgCsumInetSum, 4,
+				ExecArgCsumChunkData, 2, 4,
+				ExecArgCsumChunkData, 6, 4,
+				ExecArgCsumChunkConst, 0x0600, 2,
+				ExecArgCsumChunkConst, 0x0500, 2,
+				execInstrCopyin, 0, execArgCsum, 2, ExecArgCsumInet, 1,
+				ExecArgCsumChunkData, 0, 10,
+				callID("test$csum_ipv4_offload"), ExecNoCopyout, 1, execArgAddr64, 0,
+				execInstrEOF,
+			},
+			nil,
+		},
+		{
+			"test$csum_plain(&(0x7f0000000000)={0x0, 0x0, 0x0, 0x0, 0x0, \"ab\"})",
+			[]any{
+				execInstrCopyin, 0, execArgConst, 4, 0x0,
+				execInstrCopyin, 4, execArgConst, 4 | 1<<8, 0x0,
+				execInstrCopyin, 8, execArgConst, 4 | 1<<8, 0x0,
+				execInstrCopyin, 12, execArgConst, 2, 0x0,
+				execInstrCopyin, 14, execArgConst, 4, 0x0,
+				execInstrCopyin, 18, execArgData, 1, []byte{0xab},
+				execInstrCopyin, 14, execArgCsum, 4, ExecArgCsumFletcher32, 1,
+				ExecArgCsumChunkData, 0, 19,
+				execInstrCopyin, 12, execArgCsum, 2, ExecArgCsumFletcher16, 1,
+				ExecArgCsumChunkData, 0, 19,
+				execInstrCopyin, 8, execArgCsum, 4 | 1<<8, ExecArgCsumAdler32, 1,
+				ExecArgCsumChunkData, 0, 19,
+				execInstrCopyin, 4, execArgCsum, 4 | 1<<8, ExecArgCsumCRC32C, 1,
+				ExecArgCsumChunkData, 0, 19,
+				execInstrCopyin, 0, execArgCsum, 4, ExecArgCsumCRC32, 1,
+				ExecArgCsumChunkData, 0, 19,
+				callID("test$csum_plain"), ExecNoCopyout, 1, execArgAddr64, 0,
+				execInstrEOF,
+			},
+			nil,
+		},
 		{
 			`test() (fail_nth: 3)
 test() (fail_nth: 4)
@@ -0,0 +1,500 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// JSONProg is a JSON representation of a program intended for external tooling.
+// Unlike the text format, it contains type information for every argument.
+// The representation is stable: fields may be added, but existing fields are not changed.
+type JSONProg struct {
+	Target string      `json:"target"` // OS/arch
+	Calls  []*JSONCall `json:"calls"`
+}
+
+type JSONCall struct {
+	Name string `json:"name"`
+	// Name of the variable that holds the returned resource (if it's used by other calls).
+	Ret  string     `json:"ret,omitempty"`
+	Args []*JSONArg `json:"args"`
+	// Non-default call properties (see CallProps), e.g. {"fail_nth": 1, "async": true}.
+	Props map[string]any `json:"props,omitempty"`
+}
+
+type JSONArgKind string
+
+const (
+	JSONConst    JSONArgKind = "const"
+	JSONPointer  JSONArgKind = "pointer"
+	JSONData     JSONArgKind = "data"
+	JSONStruct   JSONArgKind = "struct"
+	JSONArray    JSONArgKind = "array"
+	JSONUnion    JSONArgKind = "union"
+	JSONResource JSONArgKind = "resource"
+)
+
+type JSONArg struct {
+	// Name of the syscall argument, struct field or union option (empty for array elements).
+	Name string      `json:"name,omitempty"`
+	Type string      `json:"type"`
+	Kind JSONArgKind `json:"kind"`
+	Dir  string      `json:"dir"` // in/out/inout
+
+	// Value of const args and of resources that don't refer to a variable.
+	Value uint64 `json:"value,omitempty"`
+
+	// Pointers: address is an offset in the data area (or the value of special pointers).
+	Address uint64   `json:"address,omitempty"`
+	VmaSize uint64   `json:"vma_size,omitempty"`
+	Special bool     `json:"special,omitempty"`
+	Any     bool     `json:"any,omitempty"` // pointee is squashed into ANY type
+	Pointee *JSONArg `json:"pointee,omitempty"`
+
+	// Data: hex-encoded contents for input data, size for output data.
+	Data string `json:"data,omitempty"`
+	Size uint64 `json:"size,omitempty"`
+
+	// Elements of structs (except for padding) and arrays.
+	Inner []*JSONArg `json:"inner,omitempty"`
+
+	// Selected option of unions.
+	Option *JSONArg `json:"option,omitempty"`
+
+	// Resources: the variable defined by this arg (if it's used later),
+	// and the variable used as the value with optional div/add operations.
+	Var   string `json:"var,omitempty"`
+	Ref   string `json:"ref,omitempty"`
+	OpDiv uint64 `json:"op_div,omitempty"`
+	OpAdd uint64 `json:"op_add,omitempty"`
+}
+
+// SerializeJSON returns the JSON representation of the program (see JSONProg).
+func (p *Prog) SerializeJSON() []byte {
+	data, err := json.MarshalIndent(p.JSON(), "", "\t")
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal program: %v", err))
+	}
+	return data
+}
+
+// JSON returns the JSON representation of the program.
+func (p *Prog) JSON() *JSONProg {
+	p.debugValidate()
+	ctx := &jsonSerializer{
+		target: p.Target,
+		vars:   make(map[*ResultArg]string),
+	}
+	jp := &JSONProg{
+		Target: p.Target.OS + "/" + p.Target.Arch,
+		Calls:  []*JSONCall{},
+	}
+	for _, c := range p.Calls {
+		jc := &JSONCall{
+			Name: c.Meta.Name,
+			Args: []*JSONArg{},
+		}
+		if c.Ret != nil && len(c.Ret.uses) != 0 {
+			jc.Ret = ctx.allocVar(c.Ret)
+		}
+		for i, arg := range c.Args {
+			jc.Args = append(jc.Args, ctx.arg(arg, c.Meta.Args[i].Name))
+		}
+		c.Props.ForeachProp(func(_, key string, value reflect.Value) {
+			if value.IsZero() {
+				return
+			}
+			if jc.Props == nil {
+				jc.Props = make(map[string]any)
+			}
+			jc.Props[key] = value.Interface()
+		})
+		jp.Calls = append(jp.Calls, jc)
+	}
+	return jp
+}
+
+type jsonSerializer struct {
+	target *Target
+	vars   map[*ResultArg]string
+}
+
+func (ctx *jsonSerializer) allocVar(arg *ResultArg) string {
+	name := fmt.Sprintf("r%v", len(ctx.vars))
+	ctx.vars[arg] = name
+	return name
+}
+
+func (ctx *jsonSerializer) arg(arg Arg, name string) *JSONArg {
+	if arg == nil {
+		return nil
+	}
+	ja := &JSONArg{
+		Name: name,
+		Type: arg.Type().Name(),
+		Dir:  arg.Dir().String(),
+	}
+	switch a := arg.(type) {
+	case *ConstArg:
+		ja.Kind = JSONConst
+		ja.Value = a.Val
+	case *PointerArg:
+		ja.Kind = JSONPointer
+		ja.Address = a.Address
+		if a.IsSpecial() {
+			ja.Special = true
+			break
+		}
+		ja.VmaSize = a.VmaSize
+		ja.Any = ctx.target.isAnyPtr(a.Type())
+		ja.Pointee = ctx.arg(a.Res, "")
+	case *DataArg:
+		ja.Kind = JSONData
+		ja.Size = a.Size()
+		if a.Dir() != DirOut {
+			ja.Data = hex.EncodeToString(a.Data())
+		}
+	case *GroupArg:
+		ja.Inner = []*JSONArg{}
+		switch typ := a.Type().(type) {
+		case *StructType:
+			ja.Kind = JSONStruct
+			for i, inner := range a.Inner {
+				if !IsPad(typ.Fields[i].Type) {
+					ja.Inner = append(ja.Inner, ctx.arg(inner, typ.Fields[i].Name))
+				}
+			}
+		case *ArrayType:
+			ja.Kind = JSONArray
+			for _, inner := range a.Inner {
+				ja.Inner = append(ja.Inner, ctx.arg(inner, ""))
+			}
+		default:
+			panic(fmt.Sprintf("unknown group type %T", typ))
+		}
+	case *UnionArg:
+		ja.Kind = JSONUnion
+		ja.Option = ctx.arg(a.Option, a.Type().(*UnionType).Fields[a.Index].Name)
+	case *ResultArg:
+		ja.Kind = JSONResource
+		if len(a.uses) != 0 {
+			ja.Var = ctx.allocVar(a)
+		}
+		if a.Res == nil {
+			ja.Value = a.Val
+			break
+		}
+		ja.Ref = ctx.vars[a.Res]
+		if ja.Ref == "" {
+			panic("no result")
+		}
+		ja.OpDiv = a.OpDiv
+		ja.OpAdd = a.OpAdd
+	default:
+		panic(fmt.Sprintf("unknown arg type %T", arg))
+	}
+	return ja
+}
+
+// DeserializeJSON parses a program in the JSON representation (see JSONProg).
+func (target *Target) DeserializeJSON(data []byte, mode DeserializeMode) (*Prog, error) {
+	jp := new(JSONProg)
+	if err := json.Unmarshal(data, jp); err != nil {
+		return nil, fmt.Errorf("failed to parse json: %w", err)
+	}
+	return target.FromJSON(jp, mode)
+}
+
+// FromJSON converts the JSON representation to a program.
+// The names, types and directions of all arguments are checked against descriptions,
+// then the program goes through the same checks and fixups as Deserialize does.
+func (target *Target) FromJSON(jp *JSONProg, mode DeserializeMode) (*Prog, error) {
+	if want := target.OS + "/" + target.Arch; jp.Target != want {
+		return nil, fmt.Errorf("program is for target %q, expected %q", jp.Target, want)
+	}
+	ctx := &jsonParser{
+		target: target,
+		buf:    new(bytes.Buffer),
+		vars:   make(map[string]string),
+	}
+	for i, jc := range jp.Calls {
+		if err := ctx.call(jc); err != nil {
+			return nil, fmt.Errorf("call #%v %v: %w", i, jc.Name, err)
+		}
+	}
+	return target.Deserialize(ctx.buf.Bytes(), mode)
+}
+
+// jsonParser converts the JSON representation to the text format.
+type jsonParser struct {
+	target *Target
+	buf    *bytes.Buffer
+	vars   map[string]string // JSON variable names to the text format names
+	inAny  int
+}
+
+func (ctx *jsonParser) call(jc *JSONCall) error {
+	meta := ctx.target.SyscallMap[jc.Name]
+	if meta == nil {
+		return fmt.Errorf("unknown syscall")
+	}
+	if len(jc.Args) > len(meta.Args) {
+		return fmt.Errorf("excessive syscall arguments: %v, want %v", len(jc.Args), len(meta.Args))
+	}
+	// The return variable is defined after all arguments, so arguments can't use it.
+	var args bytes.Buffer
+	buf := ctx.buf
+	ctx.buf = &args
+	for i, ja := range jc.Args {
+		if i != 0 {
+			ctx.buf.WriteString(", ")
+		}
+		if err := ctx.arg(ja, meta.Args[i].Type, DirIn, meta.Args[i].Name); err != nil {
+			ctx.buf = buf
+			return err
+		}
+	}
+	ctx.buf = buf
+	if jc.Ret != "" {
+		if meta.Ret == nil {
+			return fmt.Errorf("syscall does not return a resource")
+		}
+		name, err := ctx.defineVar(jc.Ret)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(ctx.buf, "%v = ", name)
+	}
+	fmt.Fprintf(ctx.buf, "%v(%s)", meta.Name, args.Bytes())
+	if len(jc.Props) != 0 {
+		// Keys are written into the program text, so only the known ones can be accepted.
+		kinds := make(map[string]reflect.Kind)
+		(&CallProps{}).ForeachProp(func(_, key string, value reflect.Value) {
+			kinds[key] = value.Kind()
+		})
+		var props []string
+		for key, val := range jc.Props {
+			kind, ok := kinds[key]
+			if !ok {
+				return fmt.Errorf("unknown call property %q", key)
+			}
+			switch v := val.(type) {
+			case bool:
+				if kind != reflect.Bool {
+					return fmt.Errorf("bad value of call property %v: %v", key, val)
+				}
+				if v {
+					props = append(props, key)
+				}
+			case float64:
+				if kind == reflect.Bool {
+					return fmt.Errorf("bad value of call property %v: %v", key, val)
+				}
+				props = append(props, fmt.Sprintf("%v: %v", key, int64(v)))
+			case int:
+				if kind == reflect.Bool {
+					return fmt.Errorf("bad value of call property %v: %v", key, val)
+				}
+				props = append(props, fmt.Sprintf("%v: %v", key, v))
+			default:
+				return fmt.Errorf("bad value of call property %v: %v", key, val)
+			}
+		}
+		sort.Strings(props)
+		fmt.Fprintf(ctx.buf, " (%v)", strings.Join(props, ", "))
+	}
+	ctx.buf.WriteString("\n")
+	return nil
+}
+
+func (ctx *jsonParser) defineVar(name string) (string, error) {
+	if ctx.vars[name] != "" {
+		return "", fmt.Errorf("variable %v is redefined", name)
+	}
+	text := fmt.Sprintf("r%v", len(ctx.vars))
+	ctx.vars[name] = text
+	return text, nil
+}
+
+func (ctx *jsonParser) arg(ja *JSONArg, typ Type, dir Dir, name string) error {
+	if ja == nil {
+		ctx.buf.WriteString("nil")
+		return nil
+	}
+	if err := ctx.argImpl(ja, typ, dir, name); err != nil {
+		if name == "" {
+			name = typ.Name()
+		}
+		return fmt.Errorf("%v: %w", name, err)
+	}
+	return nil
+}
+
+func (ctx *jsonParser) argImpl(ja *JSONArg, typ Type, dir Dir, name string) error {
+	if ja.Name != name {
+		return fmt.Errorf("unexpected name %q", ja.Name)
+	}
+	typeName := typ.Name()
+	if ja.Any {
+		// Squashed pointers have the ANY pointer type instead of the descriptions type.
+		typeName = ctx.target.getAnyPtrType(typ.Size()).Name()
+	}
+	if ja.Type != typeName {
+		return fmt.Errorf("type %q does not match descriptions type %q", ja.Type, typeName)
+	}
+	if _, ok := typ.(*PtrType); ok {
+		dir = DirIn // pointers are always in
+	}
+	if ja.Dir != dir.String() && ctx.inAny == 0 {
+		return fmt.Errorf("direction %q does not match descriptions direction %q", ja.Dir, dir)
+	}
+	if ja.Var != "" {
+		if ja.Kind != JSONResource {
+			return fmt.Errorf("variable %v doesn't refer to a resource", ja.Var)
+		}
+		varName, err := ctx.defineVar(ja.Var)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(ctx.buf, "<%v=>", varName)
+	}
+	switch ja.Kind {
+	case JSONConst:
+		fmt.Fprintf(ctx.buf, "0x%x", ja.Value)
+	case JSONResource:
+		if ja.Ref == "" {
+			fmt.Fprintf(ctx.buf, "0x%x", ja.Value)
+			break
+		}
+		ref := ctx.vars[ja.Ref]
+		if ref == "" {
+			return fmt.Errorf("undefined variable %v", ja.Ref)
+		}
+		ctx.buf.WriteString(ref)
+		if ja.OpDiv != 0 {
+			fmt.Fprintf(ctx.buf, "/%v", ja.OpDiv)
+		}
+		if ja.OpAdd != 0 {
+			fmt.Fprintf(ctx.buf, "+%v", ja.OpAdd)
+		}
+	case JSONPointer:
+		return ctx.pointer(ja, typ)
+	case JSONData:
+		t, ok := typ.(*BufferType)
+		if !ok {
+			return fmt.Errorf("data for %T type", typ)
+		}
+		if dir == DirOut {
+			fmt.Fprintf(ctx.buf, "\"\"/%v", ja.Size)
+			break
+		}
+		data, err := hex.DecodeString(ja.Data)
+		if err != nil {
+			return fmt.Errorf("bad data: %w", err)
+		}
+		if t.IsCompressed() {
+			serializeCompressedData(ctx.buf, data)
+		} else {
+			serializeData(ctx.buf, data, false)
+		}
+	case JSONStruct:
+		t, ok := typ.(*StructType)
+		if !ok {
+			return fmt.Errorf("struct for %T type", typ)
+		}
+		var fields []Field
+		for _, f := range t.Fields {
+			if !IsPad(f.Type) {
+				fields = append(fields, f)
+			}
+		}
+		if len(ja.Inner) != len(fields) {
+			return fmt.Errorf("struct has %v fields, want %v", len(ja.Inner), len(fields))
+		}
+		ctx.buf.WriteString("{")
+		for i, inner := range ja.Inner {
+			if i != 0 {
+				ctx.buf.WriteString(", ")
+			}
+			if err := ctx.arg(inner, fields[i].Type, fields[i].Dir(dir), fields[i].Name); err != nil {
+				return err
+			}
+		}
+		ctx.buf.WriteString("}")
+	case JSONArray:
+		t, ok := typ.(*ArrayType)
+		if !ok {
+			return fmt.Errorf("array for %T type", typ)
+		}
+		ctx.buf.WriteString("[")
+		for i, inner := range ja.Inner {
+			if i != 0 {
+				ctx.buf.WriteString(", ")
+			}
+			if err := ctx.arg(inner, t.Elem, dir, ""); err != nil {
+				return err
+			}
+		}
+		ctx.buf.WriteString("]")
+	case JSONUnion:
+		t, ok := typ.(*UnionType)
+		if !ok {
+			return fmt.Errorf("union for %T type", typ)
+		}
+		if ja.Option == nil {
+			return fmt.Errorf("union without option")
+		}
+		for _, f := range t.Fields {
+			if f.Name == ja.Option.Name {
+				fmt.Fprintf(ctx.buf, "@%v=", f.Name)
+				return ctx.arg(ja.Option, f.Type, f.Dir(dir), f.Name)
+			}
+		}
+		return fmt.Errorf("unknown union option %q", ja.Option.Name)
+	default:
+		return fmt.Errorf("unknown arg kind %q", ja.Kind)
+	}
+	return nil
+}
+
+func (ctx *jsonParser) pointer(ja *JSONArg, typ Type) error {
+	if ja.Special {
+		fmt.Fprintf(ctx.buf, "0x%x", ja.Address)
+		return nil
+	}
+	switch t := typ.(type) {
+	case *VmaType:
+		fmt.Fprintf(ctx.buf, "&(0x%x/0x%x)", encodingAddrBase+ja.Address, ja.VmaSize)
+		return nil
+	case *PtrType:
+		fmt.Fprintf(ctx.buf, "&(0x%x)", encodingAddrBase+ja.Address)
+		if ja.Pointee == nil {
+			return nil
+		}
+		ctx.buf.WriteString("=")
+		elem, elemDir := t.Elem, t.ElemDir
+		if ja.Any {
+			if !t.SquashableElem {
+				return fmt.Errorf("pointer can't be squashed")
+			}
+			anyPtr := ctx.target.getAnyPtrType(t.Size())
+			elem, elemDir = anyPtr.Elem, anyPtr.ElemDir
+			ctx.buf.WriteString("ANY=")
+			// Squashed args are created as in (see squashPtr), but are parsed as inout,
+			// so directions of squashed args are not checked.
+			ctx.inAny++
+			defer func() { ctx.inAny-- }()
+		}
+		return ctx.arg(ja.Pointee, elem, elemDir, "")
+	default:
+		return fmt.Errorf("pointer for %T type", typ)
+	}
+}
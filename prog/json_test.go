@@ -0,0 +1,126 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestSerializeJSONRandom(t *testing.T) {
+	testEachTargetRandom(t, func(t *testing.T, target *Target, rs rand.Source, iters int) {
+		ct := target.DefaultChoiceTable()
+		for i := 0; i < iters; i++ {
+			p0 := target.Generate(rs, 10, ct)
+			squashed := false
+			if i%3 == 0 {
+				// Exercise squashed pointers as well.
+				ptrs := p0.complexPtrs()
+				if len(ptrs) != 0 {
+					ptr := ptrs[rand.New(rs).Intn(len(ptrs))]
+					p0.Target.squashPtr(ptr.arg)
+					squashed = true
+				}
+			}
+			data0 := p0.SerializeJSON()
+			p1, err := target.DeserializeJSON(data0, Strict)
+			if err != nil {
+				t.Fatalf("failed to deserialize: %v\nprogram:\n%s\njson:\n%s", err, p0.Serialize(), data0)
+			}
+			if text0, text1 := p0.Serialize(), p1.Serialize(); !bytes.Equal(text0, text1) {
+				t.Fatalf("program changed after json round-trip:\n%s\ngot:\n%s", text0, text1)
+			}
+			// Squashed args change direction after deserialization.
+			if data1 := p1.SerializeJSON(); !squashed && !bytes.Equal(data0, data1) {
+				t.Fatalf("json changed after round-trip:\n%s\ngot:\n%s", data0, data1)
+			}
+		}
+	})
+}
+
+func TestDeserializeJSON(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	type Test struct {
+		json string
+		err  string
+	}
+	tests := []Test{
+		{
+			json: `{"target": "test/64", "calls": [{"name": "test$res0", "ret": "fd", "args": []},
+				{"name": "test$res1", "args": [{"name": "a0", "type": "syz_res", "kind": "resource", "dir": "in", "ref": "fd"}]}]}`,
+		},
+		{
+			json: `{"target": "linux/amd64", "calls": []}`,
+			err:  `program is for target "linux/amd64", expected "test/64"`,
+		},
+		{
+			json: `{"target": "test/64", "calls": [{"name": "foo"}]}`,
+			err:  `call #0 foo: unknown syscall`,
+		},
+		{
+			json: `{"target": "test/64", "calls": [{"name": "test$res1",
+				"args": [{"name": "a0", "type": "syz_res", "kind": "resource", "dir": "in", "ref": "fd"}]}]}`,
+			err: `call #0 test$res1: a0: undefined variable fd`,
+		},
+		{
+			json: `{"target": "test/64", "calls": [{"name": "test$res1",
+				"args": [{"name": "a0", "type": "int32", "kind": "const", "dir": "in"}]}]}`,
+			err: `call #0 test$res1: a0: type "int32" does not match descriptions type "syz_res"`,
+		},
+		{
+			json: `{"target": "test/64", "calls": [{"name": "test$res1",
+				"args": [{"name": "fd", "type": "syz_res", "kind": "resource", "dir": "in"}]}]}`,
+			err: `call #0 test$res1: a0: unexpected name "fd"`,
+		},
+		{
+			json: `{"target": "test/64", "calls": [{"name": "test$res1",
+				"args": [{"name": "a0", "type": "syz_res", "kind": "resource", "dir": "out"}]}]}`,
+			err: `call #0 test$res1: a0: direction "out" does not match descriptions direction "in"`,
+		},
+		{
+			json: `{"target": "test/64", "calls": [{"name": "test$res1", "ret": "r0",
+				"args": [{"name": "a0", "type": "syz_res", "kind": "resource", "dir": "in"}]}]}`,
+			err: `call #0 test$res1: syscall does not return a resource`,
+		},
+		{
+			json: `{"target": "test/64", "calls": [{"name": "test$res1",
+				"args": [{"name": "a0", "type": "syz_res", "kind": "blob", "dir": "in"}]}]}`,
+			err: `call #0 test$res1: a0: unknown arg kind "blob"`,
+		},
+		{
+			json: `{"target": "test/64", "calls": [{"name": "test$res1", "props": {"fail_nth": "x"},
+				"args": [{"name": "a0", "type": "syz_res", "kind": "resource", "dir": "in"}]}]}`,
+			err: `call #0 test$res1: bad value of call property fail_nth: x`,
+		},
+		{
+			json: `{"target": "test/64", "calls": [{"name": "test$res1", "props": {"foo": 1},
+				"args": [{"name": "a0", "type": "syz_res", "kind": "resource", "dir": "in"}]}]}`,
+			err: `call #0 test$res1: unknown call property "foo"`,
+		},
+		{
+			json: `{"target": "test/64", "calls": [{"name": "test$res1", "props": {"async)\nr9 = test$res0(": true},
+				"args": [{"name": "a0", "type": "syz_res", "kind": "resource", "dir": "in"}]}]}`,
+			err: `call #0 test$res1: unknown call property "async)\nr9 = test$res0("`,
+		},
+		{
+			json: `{"target": "test/64", "calls": [{"name": "test$res1", "props": {"async": 1},
+				"args": [{"name": "a0", "type": "syz_res", "kind": "resource", "dir": "in"}]}]}`,
+			err: `call #0 test$res1: bad value of call property async: 1`,
+		},
+	}
+	for i, test := range tests {
+		_, err := target.DeserializeJSON([]byte(test.json), Strict)
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("#%v: unexpected error: %v", i, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("#%v: wrong error\ngot:  %v\nwant: %v", i, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MergeProgs does a three-way merge of programs a and b that were both derived from base
+// (see DiffProgs for how programs are compared).
+// Calls inserted/removed in only one of the programs are inserted/removed in the result.
+// If a call is changed in both programs, changes of different fields are combined
+// as long as they don't change the structure of the call (union options, array lengths, etc).
+// All conflicting changes are returned in the error.
+func MergeProgs(base, a, b *Prog) (*Prog, error) {
+	baseToA, aToBase := alignProgs(base, a)
+	baseToB, bToBase := alignProgs(base, b)
+	ctx := &mergeCtx{
+		target: base.Target,
+		base:   flattenProg(base, func(idx int) string { return fmt.Sprint(idx) }),
+		a:      flattenProg(a, mergeID(aToBase, "a")),
+		b:      flattenProg(b, mergeID(bToBase, "b")),
+		newres: make(map[string]map[string]*ResultArg),
+	}
+	insA := mergeInsertions(aToBase, len(base.Calls))
+	insB := mergeInsertions(bToBase, len(base.Calls))
+	for i := 0; i <= len(base.Calls); i++ {
+		switch {
+		case len(insA[i]) != 0 && len(insB[i]) != 0:
+			same := ctx.sameCalls(a, insA[i], ctx.a, b, insB[i], ctx.b)
+			if !same {
+				ctx.conflictf("different calls are inserted before base call #%v", i)
+			}
+			ctx.appendCalls(a, insA[i], ctx.a)
+			if same {
+				// Resources of b's copies of the calls are the ones produced by a's copies.
+				for j, idx := range insB[i] {
+					ctx.newres[ctx.b.id(idx)] = ctx.newres[ctx.a.id(insA[i][j])]
+				}
+			}
+		case len(insA[i]) != 0:
+			ctx.appendCalls(a, insA[i], ctx.a)
+		case len(insB[i]) != 0:
+			ctx.appendCalls(b, insB[i], ctx.b)
+		}
+		if i == len(base.Calls) {
+			break
+		}
+		ai, bi := baseToA[i], baseToB[i]
+		var diffA, diffB []FieldDiff
+		if ai >= 0 {
+			diffA = diffFields(ctx.base.calls[i], ctx.a.calls[ai])
+		}
+		if bi >= 0 {
+			diffB = diffFields(ctx.base.calls[i], ctx.b.calls[bi])
+		}
+		name := base.Calls[i].Meta.Name
+		switch {
+		case ai < 0 && bi < 0:
+		case ai < 0 || bi < 0:
+			if len(diffA) != 0 || len(diffB) != 0 {
+				ctx.conflictf("call #%v %v is removed in one program and changed in another", i, name)
+			}
+		case len(diffB) == 0:
+			ctx.appendCall(a.Calls[ai], ctx.a, ai, nil)
+		case len(diffA) == 0:
+			ctx.appendCall(b.Calls[bi], ctx.b, bi, nil)
+		default:
+			patch, err := ctx.mergeFields(ctx.base.calls[i], ctx.a.calls[ai], ctx.b.calls[bi], diffA, diffB)
+			if err != nil {
+				ctx.conflictf("call #%v %v: %v", i, name, err)
+			}
+			ctx.appendCall(a.Calls[ai], ctx.a, ai, patch)
+		}
+	}
+	if len(ctx.conflicts) != 0 {
+		return nil, fmt.Errorf("merge conflicts:\n%v", strings.Join(ctx.conflicts, "\n"))
+	}
+	p := &Prog{
+		Target: base.Target,
+		Calls:  ctx.calls,
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+type mergeCtx struct {
+	target    *Target
+	base      *flatProg
+	a         *flatProg
+	b         *flatProg
+	calls     []*Call
+	conflicts []string
+	// Resources produced by the merged calls: call identity -> path -> resource.
+	newres map[string]map[string]*ResultArg
+}
+
+func (ctx *mergeCtx) conflictf(msg string, args ...any) {
+	ctx.conflicts = append(ctx.conflicts, fmt.Sprintf(msg, args...))
+}
+
+// mergeID returns call identities for a derived program: aligned calls are identified
+// by the base call index, inserted calls are unique to the program.
+func mergeID(toBase []int, prefix string) func(int) string {
+	return func(idx int) string {
+		if toBase[idx] >= 0 {
+			return fmt.Sprint(toBase[idx])
+		}
+		return fmt.Sprintf("%v%v", prefix, idx)
+	}
+}
+
+// mergeInsertions returns indexes of inserted calls grouped by the base call they precede.
+func mergeInsertions(toBase []int, baseLen int) [][]int {
+	res := make([][]int, baseLen+1)
+	var pending []int
+	for idx, baseIdx := range toBase {
+		if baseIdx < 0 {
+			pending = append(pending, idx)
+			continue
+		}
+		res[baseIdx] = pending
+		pending = nil
+	}
+	res[baseLen] = pending
+	return res
+}
+
+func (ctx *mergeCtx) sameCalls(a *Prog, idxA []int, fa *flatProg, b *Prog, idxB []int, fb *flatProg) bool {
+	if len(idxA) != len(idxB) {
+		return false
+	}
+	for i := range idxA {
+		if a.Calls[idxA[i]].Meta != b.Calls[idxB[i]].Meta ||
+			len(diffFields(fa.calls[idxA[i]], fb.calls[idxB[i]])) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (ctx *mergeCtx) appendCalls(p *Prog, idxs []int, fp *flatProg) {
+	for _, idx := range idxs {
+		ctx.appendCall(p.Calls[idx], fp, idx, nil)
+	}
+}
+
+// mergeFields returns changes of b that need to be applied to a's version of the call.
+// Only changes of values are applied, structural changes (added/removed fields,
+// changed union options or argument kinds) can't be combined with other changes.
+// Call properties are a single value that is never structural.
+func (ctx *mergeCtx) mergeFields(base, leavesA, leavesB []diffLeaf, diffA, diffB []FieldDiff) ([]diffLeaf, error) {
+	baseLeaves, leavesMapA, leavesMapB := diffLeafMap(base), diffLeafMap(leavesA), diffLeafMap(leavesB)
+	structural := func(diff []FieldDiff, leaves map[string]diffLeaf) bool {
+		for _, fd := range diff {
+			if fd.Path == diffPropsPath {
+				continue
+			}
+			if fd.Old == "" || fd.New == "" ||
+				reflect.TypeOf(baseLeaves[fd.Path].arg) != reflect.TypeOf(leaves[fd.Path].arg) {
+				return true
+			}
+			if _, ok := baseLeaves[fd.Path].arg.(*UnionArg); ok {
+				return true
+			}
+		}
+		return false
+	}
+	changedA := make(map[string]bool)
+	for _, fd := range diffA {
+		changedA[fd.Path] = true
+	}
+	var patch []diffLeaf
+	for _, fd := range diffB {
+		leaf, ok := leavesMapB[fd.Path]
+		if !ok && fd.Path == diffPropsPath {
+			// Properties are reset in b.
+			leaf = diffLeaf{path: fd.Path}
+		}
+		if !changedA[fd.Path] {
+			patch = append(patch, leaf)
+		} else if leavesMapA[fd.Path].key != leaf.key {
+			return nil, fmt.Errorf("conflicting changes of %v", fd.Path)
+		}
+	}
+	if len(patch) != 0 && (structural(diffA, leavesMapA) || structural(diffB, leavesMapB)) {
+		return nil, fmt.Errorf("conflicting structural changes")
+	}
+	return patch, nil
+}
+
+func diffLeafMap(leaves []diffLeaf) map[string]diffLeaf {
+	res := make(map[string]diffLeaf)
+	for _, leaf := range leaves {
+		res[leaf.path] = leaf
+	}
+	return res
+}
+
+// appendCall appends a copy of the call c from the program fp to the merged program,
+// and applies patch (values from another version of the call) to it.
+// Resource references are relinked to the merged calls with the same identity.
+func (ctx *mergeCtx) appendCall(c *Call, fp *flatProg, idx int, patch []diffLeaf) {
+	newargs := make(map[*ResultArg]*ResultArg)
+	dangling := new(ResultArg)
+	ForeachArg(c, func(arg Arg, _ *ArgCtx) {
+		if a, ok := arg.(*ResultArg); ok && a.Res != nil {
+			newargs[a.Res] = ctx.resolve(fp, a.Res, dangling)
+		}
+	})
+	c1 := cloneCall(c, newargs)
+	var leaves map[string]diffLeaf
+	if len(patch) != 0 {
+		leaves = diffLeafMap((&flatProg{target: ctx.target, res: make(map[*ResultArg]resLoc)}).flattenCall(0, c1))
+	}
+	for _, p := range patch {
+		if p.path == diffPropsPath {
+			c1.Props = p.props
+			continue
+		}
+		ctx.patchArg(leaves[p.path].arg, p.arg, dangling)
+	}
+	ForeachArg(c1, func(arg Arg, _ *ArgCtx) {
+		if a, ok := arg.(*ResultArg); ok && a.Res == dangling {
+			replaceResultArg(a, a.Type().DefaultArg(a.Dir()).(*ResultArg))
+		}
+	})
+	// Record produced resources (they are located at the same paths as in the original call).
+	res := make(map[string]*ResultArg)
+	flat := &flatProg{target: ctx.target, res: make(map[*ResultArg]resLoc)}
+	flat.flattenCall(0, c1)
+	for arg, loc := range flat.res {
+		res[loc.path] = arg
+	}
+	ctx.newres[fp.id(idx)] = res
+	ctx.calls = append(ctx.calls, c1)
+}
+
+func (ctx *mergeCtx) resolve(fp *flatProg, res, dangling *ResultArg) *ResultArg {
+	loc, ok := fp.res[res]
+	if !ok {
+		return dangling
+	}
+	if res1 := ctx.newres[fp.id(loc.call)][loc.path]; res1 != nil {
+		return res1
+	}
+	return dangling
+}
+
+func (ctx *mergeCtx) patchArg(arg, val Arg, dangling *ResultArg) {
+	switch a := arg.(type) {
+	case *ConstArg:
+		a.Val = val.(*ConstArg).Val
+	case *DataArg:
+		*a = *clone(val, nil).(*DataArg)
+	case *PointerArg:
+		*a = *val.(*PointerArg)
+	case *ResultArg:
+		v := val.(*ResultArg)
+		var res *ResultArg
+		if v.Res != nil {
+			res = ctx.resolve(ctx.b, v.Res, dangling)
+		}
+		a1 := MakeResultArg(a.Type(), a.Dir(), res, v.Val)
+		a1.OpDiv, a1.OpAdd = v.OpDiv, v.OpAdd
+		replaceResultArg(a, a1)
+	default:
+		panic(fmt.Sprintf("bad patched arg %#v", arg))
+	}
+}
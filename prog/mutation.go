@@ -47,6 +47,8 @@ type MutateOpts struct {
 	InsertWeight       int
 	MutateArgWeight    int
 	RemoveCallWeight   int
+	// If set, the mutation is recorded in the trace (see MutationTrace).
+	Trace *MutationTrace `json:"-"`
 }
 
 func (o MutateOpts) weight() int {
@@ -58,8 +60,25 @@ func (p *Prog) MutateWithOpts(rs rand.Source, ncalls int, ct *ChoiceTable, noMut
 	if p.isUnsafe {
 		panic("mutation of unsafe programs is not supposed to be done")
 	}
+	if opts.Trace != nil {
+		*opts.Trace = MutationTrace{
+			Prog:       string(p.Serialize()),
+			NCalls:     ncalls,
+			Opts:       opts,
+			CorpusSize: len(corpus),
+		}
+		opts.Trace.Opts.Trace = nil
+		for id := range noMutate {
+			opts.Trace.NoMutate = append(opts.Trace.NoMutate, p.Target.Syscalls[id].Name)
+		}
+		sort.Strings(opts.Trace.NoMutate)
+		rs = &traceSource{src: rs, trace: opts.Trace}
+	}
 	totalWeight := opts.weight()
 	r := newRand(p.Target, rs)
+	if hook, ok := rs.(traceHook); ok {
+		r.trace = hook
+	}
 	ncalls = max(ncalls, len(p.Calls))
 	ctx := &mutator{
 		p:        p,
@@ -69,6 +88,7 @@ func (p *Prog) MutateWithOpts(rs rand.Source, ncalls int, ct *ChoiceTable, noMut
 		noMutate: noMutate,
 		corpus:   corpus,
 		opts:     opts,
+		trace:    opts.Trace,
 	}
 	for stop, ok := false, false; !stop; stop = ok && len(p.Calls) != 0 && r.oneOf(opts.ExpectedIterations) {
 		val := r.Intn(totalWeight)
@@ -113,6 +133,7 @@ type mutator struct {
 	noMutate map[int]bool // Set of IDs of syscalls which should not be mutated.
 	corpus   []*Prog      // The entire corpus, including original program p.
 	opts     MutateOpts
+	trace    *MutationTrace // Optional trace of the mutation.
 }
 
 // This function selects a random other program p0 out of the corpus, and
@@ -123,13 +144,17 @@ func (ctx *mutator) splice() bool {
 	if len(ctx.corpus) == 0 || len(p.Calls) == 0 || len(p.Calls) >= ctx.ncalls {
 		return false
 	}
-	p0 := ctx.corpus[r.Intn(len(ctx.corpus))]
+	snap := ctx.traceBegin()
+	p0idx := r.Intn(len(ctx.corpus))
+	p0 := r.corpusProg(ctx.corpus, p0idx)
+	r.useCorpusProg(p0idx, p0)
 	p0c := p0.Clone()
 	idx := r.Intn(len(p.Calls))
 	p.Calls = append(p.Calls[:idx], append(p0c.Calls, p.Calls[idx:]...)...)
 	for i := len(p.Calls) - 1; i >= ctx.ncalls; i-- {
 		p.RemoveCall(i)
 	}
+	ctx.traceEnd(snap, MutationSplice, idx, "")
 	return true
 }
 
@@ -145,8 +170,11 @@ func (ctx *mutator) squashAny() bool {
 	if ctx.noMutate[ptr.call.Meta.ID] {
 		return false
 	}
+	snap := ctx.traceBegin()
+	squashed := false
 	if !p.Target.isAnyPtr(ptr.arg.Type()) {
 		p.Target.squashPtr(ptr.arg)
+		squashed = true
 	}
 	var blobs []*DataArg
 	var bases []*PointerArg
@@ -157,6 +185,9 @@ func (ctx *mutator) squashAny() bool {
 		}
 	})
 	if len(blobs) == 0 {
+		if squashed {
+			ctx.traceSquash(snap, ptr)
+		}
 		return false
 	}
 	// Note: we need to call analyze before we mutate the blob.
@@ -177,6 +208,7 @@ func (ctx *mutator) squashAny() bool {
 		newArg := r.allocAddr(s, base.Type(), base.Dir(), base.Res.Size(), base.Res)
 		*base = *newArg
 	}
+	ctx.traceSquash(snap, ptr)
 	return true
 }
 
@@ -192,12 +224,14 @@ func (ctx *mutator) insertCall() bool {
 	if idx < len(p.Calls) {
 		c = p.Calls[idx]
 	}
+	snap := ctx.traceBegin()
 	s := analyze(ctx.ct, ctx.corpus, p, c)
 	calls := r.generateCall(s, p, idx)
 	p.insertBefore(c, calls)
 	for len(p.Calls) > ctx.ncalls {
 		p.RemoveCall(idx)
 	}
+	ctx.traceEnd(snap, MutationInsert, idx, "")
 	return true
 }
 
@@ -208,7 +242,9 @@ func (ctx *mutator) removeCall() bool {
 		return false
 	}
 	idx := r.Intn(len(p.Calls))
+	snap := ctx.traceBegin()
 	p.RemoveCall(idx)
+	ctx.traceEnd(snap, MutationRemove, idx, "")
 	return true
 }
 
@@ -237,6 +273,11 @@ func (ctx *mutator) mutateArg() bool {
 		}
 		s := analyze(ctx.ct, ctx.corpus, p, c)
 		arg, argCtx := ma.chooseArg(r.Rand)
+		snap := ctx.traceBegin()
+		var path string
+		if snap != nil {
+			path = argPath(c, arg)
+		}
 		calls, ok1 := p.Target.mutateArg(r, s, arg, argCtx, &updateSizes)
 		if !ok1 {
 			ok = false
@@ -257,6 +298,7 @@ func (ctx *mutator) mutateArg() bool {
 		if updateSizes || fieldsPatched {
 			p.Target.assignSizesCall(c)
 		}
+		ctx.traceEnd(snap, MutationMutateArg, idx, path)
 	}
 	return true
 }
@@ -0,0 +1,437 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+)
+
+// MutationTrace records what a single MutateWithOpts invocation did to the program.
+// It is recorded if MutateOpts.Trace is set.
+// The trace is self-contained: it includes everything that is needed to replay the mutation
+// except for the set of enabled syscalls (see ReplayMutation).
+type MutationTrace struct {
+	// The program before the mutation.
+	Prog     string     `json:"prog"`
+	NCalls   int        `json:"ncalls"`
+	Opts     MutateOpts `json:"opts"`
+	NoMutate []string   `json:"no_mutate,omitempty"`
+	// Size of the corpus and the corpus programs used by the mutation
+	// (splice donors and sources of resources) by their index in the corpus.
+	CorpusSize int            `json:"corpus_size"`
+	Corpus     map[int]string `json:"corpus,omitempty"`
+	// Calls chosen with the choice table.
+	Choices []MutationChoice `json:"choices,omitempty"`
+	Steps   []MutationStep   `json:"steps"`
+	// Random values consumed by the mutation, they are used to replay the mutation.
+	Rand []uint64 `json:"rand"`
+}
+
+// MutationChoice is a call chosen with the choice table during the mutation.
+type MutationChoice struct {
+	Call string `json:"call"`
+	// Number of random values consumed by the choice.
+	Rand int `json:"rand"`
+}
+
+type MutationOp string
+
+const (
+	MutationSquash    MutationOp = "squash"
+	MutationSplice    MutationOp = "splice"
+	MutationInsert    MutationOp = "insert"
+	MutationMutateArg MutationOp = "mutate_arg"
+	MutationRemove    MutationOp = "remove"
+)
+
+// MutationStep is a single successful application of a mutation operator.
+type MutationStep struct {
+	Op MutationOp `json:"op"`
+	// Index of the target call in the program after the step
+	// (the mutated call, or the first inserted call), or before the step for removed calls.
+	Call int    `json:"call"`
+	Name string `json:"name"`
+	// Path of the mutated argument for mutate_arg and squash (see FieldDiff for the notation).
+	Path string `json:"path,omitempty"`
+	// Changed, inserted and removed calls with old/new values of the fields.
+	Calls []CallDiff `json:"calls,omitempty"`
+}
+
+func (trace *MutationTrace) Serialize() []byte {
+	data, err := json.MarshalIndent(trace, "", "\t")
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func DeserializeMutationTrace(data []byte) (*MutationTrace, error) {
+	trace := new(MutationTrace)
+	if err := json.Unmarshal(data, trace); err != nil {
+		return nil, fmt.Errorf("failed to parse mutation trace: %w", err)
+	}
+	return trace, nil
+}
+
+func (trace *MutationTrace) String() string {
+	buf := new(bytes.Buffer)
+	for i, step := range trace.Steps {
+		fmt.Fprintf(buf, "step #%v: %v call #%v %v", i, step.Op, step.Call, step.Name)
+		if step.Path != "" {
+			fmt.Fprintf(buf, " %v", step.Path)
+		}
+		fmt.Fprintf(buf, "\n")
+		diff := (&ProgDiff{Calls: step.Calls}).String()
+		for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+			if line != "" {
+				fmt.Fprintf(buf, "\t%v\n", line)
+			}
+		}
+	}
+	return buf.String()
+}
+
+// ReplayMutation repeats the mutation recorded in trace and returns the mutated program.
+// The program, the used corpus programs, calls chosen with the choice table and random values
+// are taken from the trace, ct is used only to check what calls are enabled,
+// so it must have the same enabled calls as during the recorded mutation.
+// Returns an error if the replayed mutation diverges from the recorded one.
+func (target *Target) ReplayMutation(trace *MutationTrace, ct *ChoiceTable) (p *Prog, err error) {
+	p, err = target.Deserialize([]byte(trace.Prog), NonStrict)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the mutated program: %w", err)
+	}
+	noMutate := make(map[int]bool)
+	for _, name := range trace.NoMutate {
+		meta := target.SyscallMap[name]
+		if meta == nil {
+			return nil, fmt.Errorf("unknown no_mutate syscall %v", name)
+		}
+		noMutate[meta.ID] = true
+	}
+	src := &replaySource{
+		target: target,
+		trace:  trace,
+		corpus: make(map[int]*Prog),
+	}
+	for idx, data := range trace.Corpus {
+		if idx < 0 || idx >= trace.CorpusSize {
+			return nil, fmt.Errorf("corpus program #%v is out of bounds of corpus of size %v",
+				idx, trace.CorpusSize)
+		}
+		if src.corpus[idx], err = target.Deserialize([]byte(data), NonStrict); err != nil {
+			return nil, fmt.Errorf("failed to parse corpus program #%v: %w", idx, err)
+		}
+	}
+	replay := new(MutationTrace)
+	opts := trace.Opts
+	opts.Trace = replay
+	defer func() {
+		if v := recover(); v != nil {
+			rerr, ok := v.(replayError)
+			if !ok {
+				panic(v)
+			}
+			p, err = nil, fmt.Errorf("mutation diverged: %v after %v steps", rerr, len(replay.Steps))
+		}
+	}()
+	// All accesses to the corpus go through the replay source.
+	corpus := make([]*Prog, trace.CorpusSize)
+	p.MutateWithOpts(src, trace.NCalls, ct, noMutate, corpus, opts)
+	for i := 0; i < len(trace.Steps) || i < len(replay.Steps); i++ {
+		if i >= len(trace.Steps) || i >= len(replay.Steps) {
+			return nil, fmt.Errorf("mutation diverged: got %v steps, recorded %v",
+				len(replay.Steps), len(trace.Steps))
+		}
+		if got, want := replay.Steps[i], trace.Steps[i]; !reflect.DeepEqual(got, want) {
+			return nil, fmt.Errorf("mutation diverged at step #%v: %v call #%v %v %v, recorded %v call #%v %v %v",
+				i, got.Op, got.Call, got.Name, got.Path, want.Op, want.Call, want.Name, want.Path)
+		}
+	}
+	if src.pos != len(src.trace.Rand) {
+		return nil, fmt.Errorf("mutation diverged: consumed %v random values, recorded %v",
+			src.pos, len(src.trace.Rand))
+	}
+	if src.choice != len(trace.Choices) {
+		return nil, fmt.Errorf("mutation diverged: chose %v calls, recorded %v", src.choice, len(trace.Choices))
+	}
+	return p, nil
+}
+
+// traceHook is implemented by the mutation trace sources. It intercepts choices that depend
+// on the choice table and the corpus, so that they are recorded in the trace and taken
+// from the trace during replay.
+type traceHook interface {
+	chooseCall(r *rand.Rand, ct *ChoiceTable, bias int) int
+	corpusProg(corpus []*Prog, idx int) *Prog
+	useCorpusProg(idx int, p *Prog)
+}
+
+func (r *randGen) chooseCall(ct *ChoiceTable, bias int) int {
+	if r.trace != nil {
+		return r.trace.chooseCall(r.Rand, ct, bias)
+	}
+	return ct.choose(r.Rand, bias)
+}
+
+// corpusProg returns the corpus program idx, useCorpusProg must be called if the program is used.
+func (r *randGen) corpusProg(corpus []*Prog, idx int) *Prog {
+	if r.trace != nil {
+		return r.trace.corpusProg(corpus, idx)
+	}
+	return corpus[idx]
+}
+
+func (r *randGen) useCorpusProg(idx int, p *Prog) {
+	if r.trace != nil {
+		r.trace.useCorpusProg(idx, p)
+	}
+}
+
+// traceSource records values returned by the underlying source.
+type traceSource struct {
+	src   rand.Source
+	trace *MutationTrace
+}
+
+func (s *traceSource) Int63() int64 {
+	v := s.src.Int63()
+	s.trace.Rand = append(s.trace.Rand, uint64(v))
+	return v
+}
+
+func (s *traceSource) Uint64() uint64 {
+	var v uint64
+	if src64, ok := s.src.(rand.Source64); ok {
+		v = src64.Uint64()
+	} else {
+		// This matches what rand.Rand does for non-Source64 sources.
+		v = uint64(s.src.Int63())>>31 | uint64(s.src.Int63())<<32
+	}
+	s.trace.Rand = append(s.trace.Rand, v)
+	return v
+}
+
+func (s *traceSource) Seed(seed int64) {
+	panic("mutation trace source can't be seeded")
+}
+
+func (s *traceSource) chooseCall(r *rand.Rand, ct *ChoiceTable, bias int) int {
+	start := len(s.trace.Rand)
+	var idx int
+	if hook, ok := s.src.(traceHook); ok {
+		idx = hook.chooseCall(r, ct, bias)
+	} else {
+		idx = ct.choose(r, bias)
+	}
+	s.trace.Choices = append(s.trace.Choices, MutationChoice{
+		Call: ct.target.Syscalls[idx].Name,
+		Rand: len(s.trace.Rand) - start,
+	})
+	return idx
+}
+
+func (s *traceSource) corpusProg(corpus []*Prog, idx int) *Prog {
+	if hook, ok := s.src.(traceHook); ok {
+		return hook.corpusProg(corpus, idx)
+	}
+	return corpus[idx]
+}
+
+func (s *traceSource) useCorpusProg(idx int, p *Prog) {
+	if _, ok := s.trace.Corpus[idx]; ok {
+		return
+	}
+	if s.trace.Corpus == nil {
+		s.trace.Corpus = make(map[int]string)
+	}
+	s.trace.Corpus[idx] = string(p.Serialize())
+}
+
+type replayError string
+
+func (err replayError) Error() string {
+	return string(err)
+}
+
+const errReplayExhausted = replayError("recorded random values are exhausted")
+
+// replaySource returns recorded random values, chosen calls and corpus programs.
+type replaySource struct {
+	target *Target
+	trace  *MutationTrace
+	corpus map[int]*Prog
+	pos    int
+	choice int
+}
+
+func (s *replaySource) Int63() int64 {
+	return int64(s.Uint64() & (1<<63 - 1))
+}
+
+func (s *replaySource) Uint64() uint64 {
+	if s.pos >= len(s.trace.Rand) {
+		panic(errReplayExhausted)
+	}
+	v := s.trace.Rand[s.pos]
+	s.pos++
+	return v
+}
+
+func (s *replaySource) Seed(seed int64) {
+	panic("mutation replay source can't be seeded")
+}
+
+func (s *replaySource) chooseCall(r *rand.Rand, ct *ChoiceTable, bias int) int {
+	if s.choice >= len(s.trace.Choices) {
+		panic(replayError("recorded call choices are exhausted"))
+	}
+	choice := s.trace.Choices[s.choice]
+	s.choice++
+	meta := s.target.SyscallMap[choice.Call]
+	if meta == nil || !ct.Generatable(meta.ID) {
+		panic(replayError(fmt.Sprintf("recorded call %v is not enabled", choice.Call)))
+	}
+	// Consume the same random values as the recorded choice.
+	for i := 0; i < choice.Rand; i++ {
+		r.Uint64()
+	}
+	return meta.ID
+}
+
+func (s *replaySource) corpusProg(corpus []*Prog, idx int) *Prog {
+	if p := s.corpus[idx]; p != nil {
+		return p
+	}
+	// The program was not used by the recorded mutation.
+	return &Prog{Target: s.target}
+}
+
+func (s *replaySource) useCorpusProg(idx int, p *Prog) {
+	if s.corpus[idx] == nil {
+		panic(replayError(fmt.Sprintf("corpus program #%v is not recorded", idx)))
+	}
+}
+
+// traceSnapshot is the state of the program before a mutation step.
+type traceSnapshot struct {
+	calls []*Call
+	flat  *flatProg
+}
+
+func (ctx *mutator) traceBegin() *traceSnapshot {
+	if ctx.trace == nil {
+		return nil
+	}
+	return &traceSnapshot{
+		calls: append([]*Call{}, ctx.p.Calls...),
+		flat:  flattenProg(ctx.p, func(idx int) string { return fmt.Sprint(idx) }),
+	}
+}
+
+// traceEnd records the mutation step that started with traceBegin.
+// Calls are matched by identity since the operators change calls in place.
+func (ctx *mutator) traceEnd(snap *traceSnapshot, op MutationOp, call int, path string) {
+	if snap == nil {
+		return
+	}
+	p := ctx.p
+	toNew, toOld := make([]int, len(snap.calls)), make([]int, len(p.Calls))
+	oldIdx := make(map[*Call]int)
+	for i, c := range snap.calls {
+		oldIdx[c] = i
+		toNew[i] = -1
+	}
+	for j, c := range p.Calls {
+		toOld[j] = -1
+		if i, ok := oldIdx[c]; ok {
+			toNew[i], toOld[j] = j, i
+		}
+	}
+	cur := flattenProg(p, func(idx int) string {
+		if toOld[idx] >= 0 {
+			return fmt.Sprint(toOld[idx])
+		}
+		return fmt.Sprintf("new%v", idx)
+	})
+	step := MutationStep{
+		Op:   op,
+		Call: call,
+		Path: path,
+	}
+	if op == MutationRemove {
+		step.Name = snap.calls[call].Meta.Name
+	} else {
+		step.Name = p.Calls[call].Meta.Name
+	}
+	for _, cd := range diffCalls(snap.calls, p.Calls, toNew, toOld, snap.flat, cur) {
+		switch cd.Kind {
+		case CallSame:
+			continue
+		case CallRemoved:
+			cd.Fields = diffFields(snap.flat.calls[cd.Old], nil)
+		case CallInserted:
+			cd.Fields = diffFields(nil, cur.calls[cd.New])
+		}
+		step.Calls = append(step.Calls, cd)
+	}
+	ctx.trace.Steps = append(ctx.trace.Steps, step)
+}
+
+func (ctx *mutator) traceSquash(snap *traceSnapshot, ptr complexPtr) {
+	if snap == nil {
+		return
+	}
+	for idx, c := range ctx.p.Calls {
+		if c == ptr.call {
+			ctx.traceEnd(snap, MutationSquash, idx, argPath(c, ptr.arg))
+		}
+	}
+}
+
+// argPath returns path of the argument arg of the call c (see FieldDiff for the notation).
+func argPath(c *Call, arg Arg) string {
+	for i, a := range c.Args {
+		if path, ok := findArgPath(a, arg, c.Meta.Args[i].Name); ok {
+			return path
+		}
+	}
+	return ""
+}
+
+func findArgPath(arg, target Arg, path string) (string, bool) {
+	if arg == target {
+		return path, true
+	}
+	switch a := arg.(type) {
+	case *PointerArg:
+		if a.Res != nil {
+			return findArgPath(a.Res, target, path)
+		}
+	case *GroupArg:
+		switch typ := a.Type().(type) {
+		case *StructType:
+			for i, inner := range a.Inner {
+				if res, ok := findArgPath(inner, target, path+"."+typ.Fields[i].Name); ok {
+					return res, true
+				}
+			}
+		case *ArrayType:
+			for i, inner := range a.Inner {
+				if res, ok := findArgPath(inner, target, fmt.Sprintf("%v.%v", path, i)); ok {
+					return res, true
+				}
+			}
+		}
+	case *UnionArg:
+		name := a.Type().(*UnionType).Fields[a.Index].Name
+		return findArgPath(a.Option, target, path+"."+name)
+	}
+	return "", false
+}
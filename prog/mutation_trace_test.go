@@ -0,0 +1,77 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestMutationTraceReplay(t *testing.T) {
+	testEachTargetRandom(t, func(t *testing.T, target *Target, rs rand.Source, iters int) {
+		var corpus []*Prog
+		for i := 0; i < 10; i++ {
+			corpus = append(corpus, target.Generate(rs, 10, target.DefaultChoiceTable()))
+		}
+		// The replay must not depend on the corpus and priorities of the choice table.
+		ct := target.BuildChoiceTable(corpus, nil)
+		replayCt := target.DefaultChoiceTable()
+		noMutate := map[int]bool{target.Syscalls[0].ID: true}
+		for i := 0; i < iters; i++ {
+			p0 := target.Generate(rs, 10, ct)
+			p1 := p0.Clone()
+			opts := DefaultMutateOpts
+			opts.Trace = new(MutationTrace)
+			p1.MutateWithOpts(rs, 20, ct, noMutate, corpus, opts)
+			if len(opts.Trace.Steps) == 0 || len(opts.Trace.Rand) == 0 {
+				t.Fatalf("empty mutation trace:\n%s", opts.Trace.Serialize())
+			}
+			trace, err := DeserializeMutationTrace(opts.Trace.Serialize())
+			if err != nil {
+				t.Fatal(err)
+			}
+			p2, err := target.ReplayMutation(trace, replayCt)
+			if err != nil {
+				t.Fatalf("replay failed: %v\nprogram:\n%s\ntrace:\n%v", err, p0.Serialize(), trace)
+			}
+			if data1, data2 := p1.Serialize(), p2.Serialize(); !bytes.Equal(data1, data2) {
+				t.Fatalf("replayed program differs:\n%s\nwant:\n%s\ntrace:\n%v", data2, data1, trace)
+			}
+			// Replay with missing random values must detect divergence.
+			trace.Rand = trace.Rand[:len(trace.Rand)/2]
+			if _, err := target.ReplayMutation(trace, replayCt); err == nil {
+				t.Fatalf("replay with truncated random values succeeded")
+			}
+		}
+	})
+}
+
+func TestMutationTraceSteps(t *testing.T) {
+	target, rs, iters := initRandomTargetTest(t, "test", "64")
+	ct := target.DefaultChoiceTable()
+	ops := make(map[MutationOp]bool)
+	for i := 0; i < iters; i++ {
+		p := target.Generate(rs, 10, ct)
+		opts := DefaultMutateOpts
+		opts.Trace = new(MutationTrace)
+		p.MutateWithOpts(rs, 20, ct, nil, []*Prog{target.Generate(rs, 5, ct)}, opts)
+		for _, step := range opts.Trace.Steps {
+			ops[step.Op] = true
+			if step.Op == MutationMutateArg && step.Path == "" {
+				t.Fatalf("no argument path in mutation step:\n%v", opts.Trace)
+			}
+			if !strings.Contains(opts.Trace.String(), string(step.Op)) {
+				t.Fatalf("step %v is not printed:\n%v", step.Op, opts.Trace)
+			}
+		}
+	}
+	for _, op := range []MutationOp{MutationSquash, MutationSplice, MutationInsert,
+		MutationMutateArg, MutationRemove} {
+		if !ops[op] {
+			t.Errorf("mutation operator %v was never traced", op)
+		}
+	}
+}
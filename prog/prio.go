@@ -4,6 +4,7 @@
 package prog
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"math/rand"
@@ -26,14 +27,39 @@ import (
 // Note: the current implementation is very basic, there is no theory behind any
 // constants.
 
+// The dynamic component can also be imported from another corpus (e.g. a corpus of a manager
+// that fuzzes a different kernel), in such case it's used as an additional prior.
+// The weight of the prior decreases as the own corpus grows: it has full weight for an empty corpus,
+// half of the weight for a corpus of priorCorpusSize programs, and so on.
+
+const priorCorpusSize = 1000
+
 func (target *Target) CalculatePriorities(corpus []*Prog) [][]int32 {
+	return target.CalculatePrioritiesWithPrior(corpus, nil)
+}
+
+// CalculatePrioritiesWithPrior is like CalculatePriorities, but also adds the prior
+// dynamic priorities (if not nil) scaled down according to the corpus size.
+func (target *Target) CalculatePrioritiesWithPrior(corpus []*Prog, prior *DynamicPrios) [][]int32 {
 	static := target.calcStaticPriorities()
+	var dynamic [][][]int32
 	if len(corpus) != 0 {
-		// Let's just sum the static and dynamic distributions.
-		dynamic := target.calcDynamicPrio(corpus)
-		for i, prios := range dynamic {
+		dynamic = append(dynamic, target.calcDynamicPrio(corpus))
+	}
+	if prior != nil {
+		prios := prior.matrix(target)
+		for _, row := range prios {
+			for j, p := range row {
+				row[j] = int32(int64(p) * priorCorpusSize / int64(priorCorpusSize+len(corpus)))
+			}
+		}
+		dynamic = append(dynamic, prios)
+	}
+	// Let's just sum the static and dynamic distributions.
+	for _, prios := range dynamic {
+		for i, row := range prios {
 			dst := static[i]
-			for j, p := range prios {
+			for j, p := range row {
 				dst[j] += p
 			}
 		}
@@ -41,6 +67,80 @@ func (target *Target) CalculatePriorities(corpus []*Prog) [][]int32 {
 	return static
 }
 
+// DynamicPrios are serializable dynamic (corpus-derived) call-to-call priorities.
+// Calls are identified by names rather than IDs, so the priorities can be exported
+// from one manager and imported into another one with different descriptions.
+type DynamicPrios struct {
+	Target string `json:"target"`
+	// Non-zero priorities: call -> call -> priority.
+	Prios map[string]map[string]int32 `json:"prios"`
+}
+
+func (target *Target) CalculateDynamicPriorities(corpus []*Prog) *DynamicPrios {
+	res := &DynamicPrios{
+		Target: target.OS + "/" + target.Arch,
+		Prios:  make(map[string]map[string]int32),
+	}
+	for i, row := range target.calcDynamicPrio(corpus) {
+		for j, p := range row {
+			if p == 0 {
+				continue
+			}
+			name := target.Syscalls[i].Name
+			if res.Prios[name] == nil {
+				res.Prios[name] = make(map[string]int32)
+			}
+			res.Prios[name][target.Syscalls[j].Name] = p
+		}
+	}
+	return res
+}
+
+func (prios *DynamicPrios) Serialize() []byte {
+	data, err := json.MarshalIndent(prios, "", "\t")
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func DeserializeDynamicPrios(data []byte) (*DynamicPrios, error) {
+	prios := new(DynamicPrios)
+	if err := json.Unmarshal(data, prios); err != nil {
+		return nil, fmt.Errorf("failed to parse priorities: %w", err)
+	}
+	for call, row := range prios.Prios {
+		for call1, p := range row {
+			if p < 0 {
+				return nil, fmt.Errorf("negative priority %v for %v -> %v", p, call, call1)
+			}
+		}
+	}
+	return prios, nil
+}
+
+// matrix returns the priorities for syscalls of the target, unknown syscalls are ignored.
+func (prios *DynamicPrios) matrix(target *Target) [][]int32 {
+	res := make([][]int32, len(target.Syscalls))
+	for i := range res {
+		res[i] = make([]int32, len(target.Syscalls))
+	}
+	for call, row := range prios.Prios {
+		c0 := target.SyscallMap[call]
+		if c0 == nil {
+			continue
+		}
+		for call1, p := range row {
+			if c1 := target.SyscallMap[call1]; c1 != nil {
+				res[c0.ID][c1.ID] = p
+			}
+		}
+	}
+	// Some syscalls may be missing, so normalize again.
+	normalizePrios(res)
+	return res
+}
+
 func (target *Target) calcStaticPriorities() [][]int32 {
 	uses := target.calcResourceUsage()
 	prios := make([][]int32, len(target.Syscalls))
@@ -181,17 +281,18 @@ func (target *Target) calcDynamicPrio(corpus []*Prog) [][]int32 {
 
 // normalizePrio distributes |N| * 10 points proportional to the values in the matrix.
 func normalizePrios(prios [][]int32) {
-	total := 10 * int32(len(prios))
+	total := 10 * int64(len(prios))
 	for _, prio := range prios {
-		sum := int32(0)
+		sum := int64(0)
 		for _, p := range prio {
-			sum += p
+			sum += int64(p)
 		}
 		if sum == 0 {
 			continue
 		}
 		for i, p := range prio {
-			prio[i] = p * total / sum
+			// Use int64 since imported priorities are already normalized and can be large.
+			prio[i] = int32(int64(p) * total / sum)
 		}
 	}
 }
@@ -205,6 +306,13 @@ type ChoiceTable struct {
 }
 
 func (target *Target) BuildChoiceTable(corpus []*Prog, enabled map[*Syscall]bool) *ChoiceTable {
+	return target.BuildChoiceTableWithPrior(corpus, enabled, nil)
+}
+
+// BuildChoiceTableWithPrior is like BuildChoiceTable, but uses prior as additional
+// dynamic priorities (see CalculatePrioritiesWithPrior).
+func (target *Target) BuildChoiceTableWithPrior(corpus []*Prog, enabled map[*Syscall]bool,
+	prior *DynamicPrios) *ChoiceTable {
 	if enabled == nil {
 		enabled = make(map[*Syscall]bool)
 		for _, c := range target.Syscalls {
@@ -241,7 +349,7 @@ func (target *Target) BuildChoiceTable(corpus []*Prog, enabled map[*Syscall]bool
 			}
 		}
 	}
-	prios := target.CalculatePriorities(corpus)
+	prios := target.CalculatePrioritiesWithPrior(corpus, prior)
 	run := make([][]int32, len(target.Syscalls))
 	// ChoiceTable.runs[][] contains cumulated sum of weighted priority numbers.
 	// This helps in quick binary search with biases when generating programs.
@@ -94,3 +94,79 @@ func TestPrioDeterminism(t *testing.T) {
 		}
 	}
 }
+
+func TestDynamicPriosImport(t *testing.T) {
+	target, rs, _ := initRandomTargetTest(t, "test", "64")
+	ct := target.DefaultChoiceTable()
+	var corpus []*Prog
+	for i := 0; i < 100; i++ {
+		corpus = append(corpus, target.Generate(rs, 10, ct))
+	}
+	exported := target.CalculateDynamicPriorities(corpus)
+	prior, err := DeserializeDynamicPrios(exported.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(exported, prior) {
+		t.Fatalf("priorities changed after serialization")
+	}
+	// Imported priorities must be the same as priorities calculated from the corpus
+	// up to rounding errors of the repeated normalization.
+	want := target.CalculatePriorities(corpus)
+	got := target.CalculatePrioritiesWithPrior(nil, prior)
+	for i := range want {
+		for j := range want[i] {
+			if diff := want[i][j] - got[i][j]; diff < -1-want[i][j]/100 || diff > 1+want[i][j]/100 {
+				t.Fatalf("wrong imported priority %v -> %v: %v, want %v",
+					target.Syscalls[i].Name, target.Syscalls[j].Name, got[i][j], want[i][j])
+			}
+		}
+	}
+	// Unknown syscalls are ignored.
+	prior.Prios["foo"] = map[string]int32{"test$res0": 100}
+	for _, row := range prior.Prios {
+		row["bar"] = 100
+	}
+	if !reflect.DeepEqual(got, target.CalculatePrioritiesWithPrior(nil, prior)) {
+		t.Fatalf("unknown syscalls affected priorities")
+	}
+	// The prior is used for syscall choice.
+	call0, call1 := target.SyscallMap["test$res0"], target.SyscallMap["test$res1"]
+	prior = &DynamicPrios{Prios: map[string]map[string]int32{call0.Name: {call1.Name: 1}}}
+	ct0 := target.BuildChoiceTable(nil, nil)
+	ct1 := target.BuildChoiceTableWithPrior(nil, nil, prior)
+	count0, count1 := 0, 0
+	r := rand.New(rs)
+	for i := 0; i < 1000; i++ {
+		if ct0.choose(r, call0.ID) == call1.ID {
+			count0++
+		}
+		if ct1.choose(r, call0.ID) == call1.ID {
+			count1++
+		}
+	}
+	if count1 <= count0 {
+		t.Fatalf("prior is not used: %v vs %v", count1, count0)
+	}
+	// The prior weight decreases as the corpus grows.
+	full := prior.matrix(target)[call0.ID][call1.ID]
+	for _, size := range []int{0, 100} {
+		corpus := corpus[:size]
+		delta := target.CalculatePrioritiesWithPrior(corpus, prior)[call0.ID][call1.ID] -
+			target.CalculatePriorities(corpus)[call0.ID][call1.ID]
+		if want := full * priorCorpusSize / int32(priorCorpusSize+size); delta != want {
+			t.Fatalf("prior weight for corpus of %v programs: %v, want %v", size, delta, want)
+		}
+	}
+}
+
+func TestDeserializeDynamicPrios(t *testing.T) {
+	for _, data := range []string{
+		`{"prios": {"a": {"b": -1}}}`,
+		`{"prios": []}`,
+	} {
+		if _, err := DeserializeDynamicPrios([]byte(data)); err == nil {
+			t.Errorf("parsing %q succeeded", data)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ProtocolDesc describes valid orders of calls on instances of a resource as a state machine
+// (e.g. a socket needs to be bound before listen, and listen needs to happen before accept).
+// Every new instance of the resource starts in ProtocolInitState.
+type ProtocolDesc struct {
+	Name        string
+	Resource    string
+	Transitions []ProtocolTransition
+}
+
+type ProtocolTransition struct {
+	Call string
+	From string
+	To   string
+}
+
+// ProtocolInitState is the state of newly created resources.
+// Transitions from this state applied to calls that create the resource.
+const ProtocolInitState = "init"
+
+type protocolTransition struct {
+	proto *ProtocolDesc
+	res   *ResourceDesc
+	from  string
+	to    string
+}
+
+type protocolInstance struct {
+	res   *ResultArg
+	proto *ProtocolDesc
+}
+
+func (target *Target) initProtocols() {
+	target.protocolCalls = make(map[int][]*protocolTransition)
+	for i := range target.Protocols {
+		proto := &target.Protocols[i]
+		res := target.resourceMap[proto.Resource]
+		if res == nil {
+			panic(fmt.Sprintf("protocol %v: unknown resource %v", proto.Name, proto.Resource))
+		}
+		for _, t := range proto.Transitions {
+			meta := target.SyscallMap[t.Call]
+			if meta == nil {
+				panic(fmt.Sprintf("protocol %v: unknown call %v", proto.Name, t.Call))
+			}
+			target.protocolCalls[meta.ID] = append(target.protocolCalls[meta.ID], &protocolTransition{
+				proto: proto,
+				res:   res,
+				from:  t.From,
+				to:    t.To,
+			})
+		}
+	}
+}
+
+// isProtocolResource returns true if res is an instance of the protocol resource (or a more specialized one).
+func (t *protocolTransition) isProtocolResource(res *ResourceType) bool {
+	return isCompatibleResourceImpl(t.res.Kind, res.Desc.Kind, true)
+}
+
+// analyzeProtocols updates protocol states of resources after the call c.
+func (s *state) analyzeProtocols(c *Call) {
+	transitions := s.target.protocolCalls[c.Meta.ID]
+	if len(transitions) == 0 {
+		return
+	}
+	done := make(map[*ProtocolDesc]bool)
+	for _, t := range transitions {
+		if done[t.proto] {
+			continue
+		}
+		if t.from == ProtocolInitState {
+			for _, res := range protocolResources(c, t, false) {
+				s.protoStates[protocolInstance{res, t.proto}] = t.to
+				done[t.proto] = true
+			}
+			continue
+		}
+		for _, res := range protocolResources(c, t, true) {
+			inst := protocolInstance{res.Res, t.proto}
+			if s.protocolState(inst) == t.from {
+				s.protoStates[inst] = t.to
+				done[t.proto] = true
+				break
+			}
+		}
+	}
+}
+
+// protocolResources returns the protocol resources used (if input is set) or produced by the call.
+func protocolResources(c *Call, t *protocolTransition, input bool) []*ResultArg {
+	var res []*ResultArg
+	ForeachArg(c, func(arg Arg, _ *ArgCtx) {
+		typ, ok := arg.Type().(*ResourceType)
+		if !ok {
+			return
+		}
+		a := arg.(*ResultArg)
+		if input {
+			if a.Dir() != DirOut && a.Res != nil && t.isProtocolResource(a.Res.Type().(*ResourceType)) {
+				res = append(res, a)
+			}
+		} else if a.Dir() != DirIn && t.isProtocolResource(typ) {
+			res = append(res, a)
+		}
+	})
+	return res
+}
+
+func (s *state) protocolState(inst protocolInstance) string {
+	if state, ok := s.protoStates[inst]; ok {
+		return state
+	}
+	return ProtocolInitState
+}
+
+// protocolCandidates returns existing resources that are in the state expected by a transition of the call.
+// The order is deterministic, each resource is returned once even if it matches several transitions.
+func (s *state) protocolCandidates(meta *Syscall) []*ResultArg {
+	transitions := s.target.protocolCalls[meta.ID]
+	if len(transitions) == 0 {
+		return nil
+	}
+	var kinds []string
+	for kind := range s.resources {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	var candidates []*ResultArg
+	dedup := make(map[*ResultArg]bool)
+	for _, t := range transitions {
+		if t.from == ProtocolInitState {
+			continue
+		}
+		for _, kind := range kinds {
+			for _, res := range s.resources[kind] {
+				if !dedup[res] && t.isProtocolResource(res.Type().(*ResourceType)) &&
+					s.protocolState(protocolInstance{res, t.proto}) == t.from {
+					dedup[res] = true
+					candidates = append(candidates, res)
+				}
+			}
+		}
+	}
+	return candidates
+}
+
+// protocolValid returns false if the call does not have a resource in the state required by its protocols.
+func (s *state) protocolValid(meta *Syscall) bool {
+	transitions := s.target.protocolCalls[meta.ID]
+	if len(transitions) == 0 {
+		return true
+	}
+	for _, t := range transitions {
+		if t.from == ProtocolInitState {
+			return true
+		}
+	}
+	return len(s.protocolCandidates(meta)) != 0
+}
+
+// protocolNextCall returns a call that moves one of the existing resources to the next protocol state,
+// and the resource it should be applied to.
+func (s *state) protocolNextCall(r *randGen) (*Syscall, *ResultArg) {
+	var calls []*Syscall
+	for id := range s.target.protocolCalls {
+		meta := s.target.Syscalls[id]
+		if !s.ct.Generatable(id) || meta.Attrs.NoGenerate {
+			continue
+		}
+		if len(s.protocolCandidates(meta)) != 0 {
+			calls = append(calls, meta)
+		}
+	}
+	if len(calls) == 0 {
+		return nil, nil
+	}
+	sort.Slice(calls, func(i, j int) bool {
+		return calls[i].ID < calls[j].ID
+	})
+	meta := calls[r.Intn(len(calls))]
+	candidates := s.protocolCandidates(meta)
+	return meta, candidates[r.Intn(len(candidates))]
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocolStates(t *testing.T) {
+	target, _, _ := initRandomTargetTest(t, "test", "64")
+	p, err := target.Deserialize([]byte(`
+r0 = test$proto_create()
+r1 = test$proto_create()
+test$proto_bind(r0)
+test$proto_listen(r1)
+test$proto_listen(r0)
+r2 = test$proto_accept(r0)
+test$proto_bind(r0)
+`), Strict)
+	require.NoError(t, err)
+	s := analyze(nil, nil, p, nil)
+	proto := &target.Protocols[0]
+	state := func(call int) string {
+		return s.protocolState(protocolInstance{p.Calls[call].Ret, proto})
+	}
+	assert.Equal(t, "listening", state(0))
+	assert.Equal(t, "created", state(1))
+	assert.Equal(t, ProtocolInitState, state(5))
+}
+
+func TestProtocolCandidates(t *testing.T) {
+	target, _, _ := initRandomTargetTest(t, "test", "64")
+	p, err := target.Deserialize([]byte(`
+r0 = test$proto_create()
+r1 = test$proto_create()
+r2 = test$proto_create()
+test$proto_bind(r1)
+`), Strict)
+	require.NoError(t, err)
+	s := analyze(nil, nil, p, nil)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, []*ResultArg{p.Calls[0].Ret, p.Calls[2].Ret},
+			s.protocolCandidates(target.SyscallMap["test$proto_bind"]))
+		assert.Equal(t, []*ResultArg{p.Calls[1].Ret},
+			s.protocolCandidates(target.SyscallMap["test$proto_listen"]))
+	}
+	assert.Empty(t, s.protocolCandidates(target.SyscallMap["test$proto_create"]))
+}
+
+func TestProtocolGeneration(t *testing.T) {
+	target, rs, _ := initRandomTargetTest(t, "test", "64")
+	enabled := make(map[*Syscall]bool)
+	for _, call := range target.Protocols[0].Transitions {
+		enabled[target.SyscallMap[call.Call]] = true
+	}
+	ct := target.BuildChoiceTable(nil, enabled)
+	valid, invalid := 0, 0
+	for i := 0; i < 300; i++ {
+		p := target.Generate(rs, 10, ct)
+		s := newState(target, ct, nil)
+		for _, c := range p.Calls {
+			if c.Meta.Name != "test$proto_create" {
+				res := c.Args[0].(*ResultArg).Res
+				from := target.protocolCalls[c.Meta.ID][0].from
+				if res != nil && s.protocolState(protocolInstance{res, &target.Protocols[0]}) == from {
+					valid++
+				} else {
+					invalid++
+				}
+			}
+			s.analyze(c)
+		}
+	}
+	t.Logf("valid calls: %v, invalid calls: %v", valid, invalid)
+	// Without protocols roughly half of the calls are invalid.
+	assert.Greater(t, valid, 2*invalid)
+	// But we still want to test invalid orders.
+	assert.Greater(t, invalid, 0)
+}
@@ -29,6 +29,7 @@ type randGen struct {
 	inGenerateResource    bool
 	patchConditionalDepth int
 	recDepth              map[string]int
+	trace                 traceHook // Set if the mutation is traced or replayed.
 }
 
 func newRand(target *Target, rs rand.Source) *randGen {
@@ -596,8 +597,30 @@ func (r *randGen) generateCall(s *state, p *Prog, insertionPoint int) []*Call {
 			biasCall = insertionCall.ID
 		}
 	}
-	idx := s.ct.choose(r.Rand, biasCall)
+	if len(r.target.protocolCalls) != 0 && r.oneOf(3) {
+		// Move one of the existing resources to the next state of its protocol.
+		if meta, subject := s.protocolNextCall(r); meta != nil {
+			return r.generateProtocolCall(s, meta, subject)
+		}
+	}
+	idx := r.chooseCall(s.ct, biasCall)
 	meta := r.target.Syscalls[idx]
+	// Calls that violate protocols (e.g. accept on a socket that is not listening) are mostly useless,
+	// but we still want to generate them sometimes.
+	for retry := 0; retry < 3 && !s.protocolValid(meta) && r.nOutOf(9, 10); retry++ {
+		idx = r.chooseCall(s.ct, biasCall)
+		meta = r.target.Syscalls[idx]
+	}
+	if candidates := s.protocolCandidates(meta); len(candidates) != 0 && r.nOutOf(9, 10) {
+		return r.generateProtocolCall(s, meta, candidates[r.Intn(len(candidates))])
+	}
+	return r.generateParticularCall(s, meta)
+}
+
+// generateProtocolCall generates the call meta that uses the resource subject as one of its arguments.
+func (r *randGen) generateProtocolCall(s *state, meta *Syscall, subject *ResultArg) []*Call {
+	s.protoSubject = subject
+	defer func() { s.protoSubject = nil }()
 	return r.generateParticularCall(s, meta)
 }
 
@@ -742,6 +765,11 @@ func (a *ResourceType) generate(r *randGen, s *state, dir Dir) (arg Arg, calls [
 		defer func() { r.inGenerateResource = false }()
 		canRecurse = true
 	}
+	if subject := s.protoSubject; subject != nil &&
+		r.target.isCompatibleResource(a.Desc.Name, subject.Type().Name()) {
+		s.protoSubject = nil
+		return MakeResultArg(a, dir, subject, 0), nil
+	}
 	if canRecurse && r.nOutOf(8, 10) ||
 		!canRecurse && r.nOutOf(19, 20) {
 		arg = r.existingResource(s, a, dir)
@@ -957,11 +985,12 @@ func (r *randGen) resourceCentric(s *state, t *ResourceType, dir Dir) (arg Arg,
 	var p *Prog
 	var resource *ResultArg
 	for _, idx := range r.Perm(len(s.corpus)) {
-		corpusProg := s.corpus[idx]
+		corpusProg := r.corpusProg(s.corpus, idx)
 		resources := getCompatibleResources(corpusProg, t.TypeName, r)
 		if len(resources) == 0 {
 			continue
 		}
+		r.useCorpusProg(idx, corpusProg)
 		argMap := make(map[*ResultArg]*ResultArg)
 		p = corpusProg.cloneWithMap(argMap)
 		resource = argMap[resources[r.Intn(len(resources))]]
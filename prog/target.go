@@ -31,6 +31,7 @@ type Target struct {
 	Consts    []ConstValue
 	Flags     []FlagDesc
 	Types     []Type
+	Protocols []ProtocolDesc
 
 	// MakeDataMmap creates calls that mmaps target data memory range.
 	MakeDataMmap func() []*Call
@@ -77,6 +78,8 @@ type Target struct {
 	// Maps resource name to a list of calls that can create the resource.
 	resourceCtors map[string][]ResourceCtor
 	any           anyTypes
+	// Maps syscall ID to protocol transitions of the call.
+	protocolCalls map[int][]*protocolTransition
 
 	// The default ChoiceTable is used only by tests and utilities, so we initialize it lazily.
 	defaultOnce        sync.Once
@@ -183,6 +186,7 @@ func (target *Target) initTarget() {
 	for _, res := range target.Resources {
 		target.resourceCtors[res.Name] = target.calcResourceCtors(res, false)
 	}
+	target.initProtocols()
 }
 
 func (target *Target) initUselessHints() {
@@ -484,6 +488,10 @@ type Builder struct {
 	target *Target
 	ma     *memAlloc
 	p      *Prog
+	// State of the high-level interface (see builder.go).
+	cur  *Call
+	vars map[string]*ResultArg
+	err  error
 }
 
 func MakeProgGen(target *Target) *Builder {
@@ -493,10 +501,12 @@ func MakeProgGen(target *Target) *Builder {
 		p: &Prog{
 			Target: target,
 		},
+		vars: make(map[string]*ResultArg),
 	}
 }
 
 func (pg *Builder) Append(c *Call) error {
+	pg.flush()
 	pg.target.assignSizesCall(c)
 	pg.target.sanitize(c, true)
 	pg.p.Calls = append(pg.p.Calls, c)
@@ -512,6 +522,10 @@ func (pg *Builder) AllocateVMA(npages uint64) uint64 {
 }
 
 func (pg *Builder) Finalize() (*Prog, error) {
+	pg.flush()
+	if pg.err != nil {
+		return nil, pg.err
+	}
 	if err := pg.p.validate(); err != nil {
 		return nil, err
 	}
@@ -584,13 +584,23 @@ type CsumKind int
 const (
 	CsumInet CsumKind = iota
 	CsumPseudo
+	CsumCRC32
+	CsumCRC32C
+	CsumAdler32
+	CsumFletcher16
+	CsumFletcher32
+	// Ones-complement sum of the data as for CsumInet, but without the final complement.
+	CsumInetSum
+	// Ones-complement sum of only the IP pseudo header as for CsumPseudo, without the final complement.
+	// This is what the checksum field must contain for checksum offloading (CHECKSUM_PARTIAL).
+	CsumPseudoSum
 )
 
 type CsumType struct {
 	IntTypeCommon
 	Kind     CsumKind
 	Buf      string
-	Protocol uint64 // for CsumPseudo
+	Protocol uint64 // for CsumPseudo and CsumPseudoSum
 }
 
 func (t *CsumType) String() string {
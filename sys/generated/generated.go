@@ -21,6 +21,7 @@ type Desc struct {
 	Consts    []prog.ConstValue
 	Flags     []prog.FlagDesc
 	Types     []prog.Type
+	Protocols []prog.ProtocolDesc
 }
 
 func Register(os, arch, revision string, init func(*prog.Target), files embed.FS) {
@@ -55,6 +56,7 @@ func fill(target *prog.Target, files embed.FS) {
 	target.Consts = desc.Consts
 	target.Flags = desc.Flags
 	target.Types = desc.Types
+	target.Protocols = desc.Protocols
 }
 
 func Serialize(desc *Desc) ([]byte, error) {
@@ -0,0 +1,144 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/ast"
+	"github.com/google/syzkaller/pkg/btf"
+	"github.com/google/syzkaller/pkg/compiler"
+)
+
+// btfExtractor takes const values from the kernel BTF instead of compiling programs against kernel headers,
+// so it does not need a configured kernel source tree and cross-compilers.
+// BTF contains only enums, values of other consts (defines, syscall numbers) are preserved
+// from the existing .const files.
+type btfExtractor struct {
+	files    []string
+	spec     *btf.Spec
+	enums    map[string]uint64
+	existing *compiler.ConstFile
+}
+
+func newBTFExtractor(files string) *btfExtractor {
+	return &btfExtractor{files: strings.Split(files, ",")}
+}
+
+func (ex *btfExtractor) prepare(sourcedir string, build bool, arches []*Arch) error {
+	if len(arches) != 1 {
+		return fmt.Errorf("BTF describes a single arch, specify it with -arch")
+	}
+	// The first file is vmlinux, the rest are modules with split BTF.
+	// Modules may define the same enumerators as vmlinux or other modules with different values,
+	// such values are ambiguous.
+	ex.enums = make(map[string]uint64)
+	conflicts := make(map[string]bool)
+	for i, file := range ex.files {
+		spec, err := btf.Load(file, ex.spec)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			ex.spec = spec
+		}
+		enums, ambiguous := spec.Enums()
+		for name := range ambiguous {
+			conflicts[name] = true
+		}
+		for name, val := range enums {
+			if old, ok := ex.enums[name]; ok && old != val {
+				conflicts[name] = true
+			}
+			ex.enums[name] = val
+		}
+	}
+	for name := range conflicts {
+		delete(ex.enums, name)
+	}
+	errBuf := new(bytes.Buffer)
+	ex.existing = compiler.DeserializeConstFile(filepath.Join("sys", arches[0].target.OS, "*.const"),
+		func(pos ast.Pos, msg string) {
+			fmt.Fprintf(errBuf, "%v: %v\n", pos, msg)
+		})
+	if ex.existing == nil {
+		return fmt.Errorf("failed to load existing const files:\n%s", errBuf.Bytes())
+	}
+	return nil
+}
+
+func (ex *btfExtractor) prepareArch(arch *Arch) error {
+	return nil
+}
+
+func (ex *btfExtractor) processFile(arch *Arch, info *compiler.ConstInfo) (map[string]uint64, map[string]bool, error) {
+	existing := ex.existing.Arch(arch.target.Arch)
+	existingUndeclared := ex.existing.Undeclared(arch.target.Arch)
+	res := make(map[string]uint64)
+	undeclared := make(map[string]bool)
+	var missing []string
+	for _, c := range info.Consts {
+		if val, ok := ex.enums[c.Name]; ok {
+			res[c.Name] = val
+		} else if val, ok := existing[c.Name]; ok {
+			res[c.Name] = val
+		} else if existingUndeclared[c.Name] {
+			undeclared[c.Name] = true
+		} else {
+			missing = append(missing, c.Name)
+		}
+	}
+	if len(missing) != 0 {
+		sort.Strings(missing)
+		return nil, nil, fmt.Errorf("consts are not present in BTF nor in the .const files,"+
+			" run syz-extract without -btf: %v", strings.Join(missing, ", "))
+	}
+	return res, undeclared, nil
+}
+
+// checkLayouts compiles descriptions with the new consts and compares struct layouts with the kernel.
+func (ex *btfExtractor) checkLayouts(arch *Arch) error {
+	errBuf := new(bytes.Buffer)
+	eh := func(pos ast.Pos, msg string) {
+		fmt.Fprintf(errBuf, "%v: %v\n", pos, msg)
+	}
+	top := ast.ParseGlob(filepath.Join("sys", arch.target.OS, "*.txt"), eh)
+	if top == nil {
+		return fmt.Errorf("%s", errBuf.Bytes())
+	}
+	cf := compiler.DeserializeConstFile(filepath.Join("sys", arch.target.OS, "*.const"), eh)
+	if cf == nil {
+		return fmt.Errorf("%s", errBuf.Bytes())
+	}
+	prog := compiler.Compile(top, cf.Arch(arch.target.Arch), arch.target, eh)
+	if prog == nil {
+		return fmt.Errorf("failed to compile descriptions:\n%s", errBuf.Bytes())
+	}
+	mismatches := ex.spec.CheckLayouts(prog.Types)
+	for _, mismatch := range mismatches {
+		fmt.Printf("layout mismatch: %v\n", mismatch)
+	}
+	if len(mismatches) != 0 {
+		return fmt.Errorf("%v struct layout mismatches with the kernel BTF", len(mismatches))
+	}
+	return nil
+}
+
+// preserveArches adds consts of the arches that are not extracted from the existing const file.
+func preserveArches(cf *compiler.ConstFile, file string, extracted *Arch) error {
+	old := compiler.DeserializeConstFile(file, func(pos ast.Pos, msg string) {})
+	for _, arch := range old.Arches() {
+		if arch == extracted.target.Arch {
+			continue
+		}
+		if err := cf.AddArch(arch, old.Arch(arch), old.Undeclared(arch)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
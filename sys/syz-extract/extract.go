@@ -28,6 +28,8 @@ var (
 	flagBuildDir  = flag.String("builddir", "", "path to kernel build dir")
 	flagArch      = flag.String("arch", "", "comma-separated list of arches to generate (all by default)")
 	flagConfig    = flag.String("config", "", "base kernel config file instead of defconfig")
+	flagBTF       = flag.String("btf", "", "comma-separated list of vmlinux and kernel module files (or raw BTF"+
+		" files from /sys/kernel/btf) to take consts from instead of compiling against kernel headers")
 )
 
 type Arch struct {
@@ -80,11 +82,16 @@ func main() {
 	if extractor == nil {
 		tool.Failf("unknown os: %v", OS)
 	}
+	var btfEx *btfExtractor
+	if *flagBTF != "" {
+		btfEx = newBTFExtractor(*flagBTF)
+		extractor = btfEx
+	}
 	arches, nfiles, err := createArches(OS, archList(OS, *flagArch), flag.Args())
 	if err != nil {
 		tool.Fail(err)
 	}
-	if *flagSourceDir == "" {
+	if *flagSourceDir == "" && btfEx == nil {
 		tool.Failf("provide path to kernel checkout via -sourcedir " +
 			"flag (or make extract SOURCEDIR)")
 	}
@@ -120,6 +127,12 @@ func main() {
 			}
 			if constFiles[f.name] == nil {
 				constFiles[f.name] = compiler.NewConstFile()
+				if btfEx != nil {
+					outname := filepath.Join("sys", OS, f.name+".const")
+					if err := preserveArches(constFiles[f.name], outname, f.arch); err != nil {
+						tool.Failf("%v: %v", outname, err)
+					}
+				}
 			}
 			constFiles[f.name].AddArch(f.arch.target.Arch, f.consts, f.undeclared)
 		}
@@ -139,6 +152,12 @@ func main() {
 	if !failed && *flagArch == "" {
 		failed = checkUnsupportedCalls(arches)
 	}
+	if !failed && btfEx != nil {
+		if err := btfEx.checkLayouts(arches[0]); err != nil {
+			fmt.Printf("%v\n", err)
+			failed = true
+		}
+	}
 	for _, arch := range arches {
 		if arch.build {
 			os.RemoveAll(arch.buildDir)
@@ -245,6 +245,7 @@ func processJob(job *Job, descriptions *ast.Description, constFile *compiler.Con
 		Types:     prg.Types,
 		Consts:    constArr,
 		Flags:     flags,
+		Protocols: prg.Protocols,
 	}
 	data, err := generated.Serialize(desc)
 	if err != nil {
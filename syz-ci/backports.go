@@ -0,0 +1,60 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/vcs"
+)
+
+// Older fixes are either already backported or are not going to be.
+const backportsMaxAge = 365 * 24 * time.Hour
+
+func (jp *JobProcessor) pollBackports() {
+	for _, mgr := range jp.managers {
+		if !mgr.mgrcfg.Jobs.PollBackports {
+			continue
+		}
+		for _, tree := range mgr.mgrcfg.BackportTrees {
+			if err := jp.pollTreeBackports(mgr, tree); err != nil {
+				jp.Errorf("failed to poll backports of %v/%v for %v: %v",
+					tree.Repo, tree.Branch, mgr.name, err)
+			}
+		}
+	}
+}
+
+func (jp *JobProcessor) pollTreeBackports(mgr *Manager, tree *BackportTree) error {
+	resp, err := mgr.dash.FixCommits(&dashapi.FixCommitsReq{
+		Since: time.Now().Add(-backportsMaxAge),
+	})
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(jp.baseDir, mgr.managercfg.TargetOS, "kernel")
+	repo, err := vcs.NewRepo(mgr.managercfg.TargetOS, mgr.managercfg.Type, dir)
+	if err != nil {
+		return fmt.Errorf("failed to create kernel repo: %w", err)
+	}
+	commit, err := repo.CheckoutBranch(tree.Repo, tree.Branch)
+	if err != nil {
+		return fmt.Errorf("failed to checkout kernel repo %v/%v: %w", tree.Repo, tree.Branch, err)
+	}
+	missing, err := vcs.MissingFixCommits(repo, resp.List)
+	if err != nil {
+		return err
+	}
+	jp.Logf(0, "%v/%v: %v out of %v fix commits are missing", tree.Repo, tree.Branch,
+		len(missing), len(resp.List))
+	return mgr.dash.UploadMissingBackports(&dashapi.UploadMissingBackportsReq{
+		Repo:    tree.Repo,
+		Branch:  tree.Branch,
+		Commit:  commit.Hash,
+		Missing: missing,
+	})
+}
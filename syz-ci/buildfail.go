@@ -0,0 +1,307 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/bisect"
+	"github.com/google/syzkaller/pkg/build"
+	"github.com/google/syzkaller/pkg/debugtracer"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// BuildFailure is a kernel build failure deduplicated across managers and kernel commits
+// by build.Failure.Signature.
+type BuildFailure struct {
+	build.Failure
+	Signature   string
+	FirstSeen   time.Time
+	LastSeen    time.Time
+	Count       int
+	Managers    []string
+	FirstCommit string
+	LastCommit  string
+	// Dashboard title of the first report of the failure.
+	// Reports of the same failure from other managers refer to it,
+	// so that the dashboard merges them into the same bug.
+	ReportTitle string
+	Bisection   *BuildBisection
+}
+
+// BuildBisection is the state of the bisection of the commit that broke the build.
+type BuildBisection struct {
+	Manager  string
+	Started  time.Time
+	Finished time.Time
+	// Culprit commits ("hash title"), more than one if the bisection is inconclusive.
+	Commits []string
+	Error   string
+}
+
+// BuildFailures is the registry of build failures shared by all managers.
+type BuildFailures struct {
+	mu       sync.Mutex
+	failures map[string]*BuildFailure
+}
+
+// How many distinct build failures we keep in memory.
+const buildFailuresSize = 100
+
+var buildFailures = newBuildFailures()
+
+func newBuildFailures() *BuildFailures {
+	return &BuildFailures{
+		failures: make(map[string]*BuildFailure),
+	}
+}
+
+// record registers an occurrence of the failure on the manager and kernel commit.
+// Returns the signature of the failure and whether it was seen for the first time.
+func (bf *BuildFailures) record(manager, commit string, failure *build.Failure) (string, bool) {
+	bf.mu.Lock()
+	defer bf.mu.Unlock()
+	sig := failure.Signature()
+	now := time.Now()
+	f := bf.failures[sig]
+	isNew := f == nil
+	if isNew {
+		bf.evictLocked()
+		f = &BuildFailure{
+			Failure:     *failure,
+			Signature:   sig,
+			FirstSeen:   now,
+			FirstCommit: commit,
+		}
+		bf.failures[sig] = f
+	}
+	// Line numbers may change over time, so keep the latest location.
+	f.Failure = *failure
+	f.LastSeen = now
+	f.LastCommit = commit
+	f.Count++
+	found := false
+	for _, name := range f.Managers {
+		found = found || name == manager
+	}
+	if !found {
+		f.Managers = append(f.Managers, manager)
+		sort.Strings(f.Managers)
+	}
+	return sig, isNew
+}
+
+func (bf *BuildFailures) evictLocked() {
+	if len(bf.failures) < buildFailuresSize {
+		return
+	}
+	var oldest *BuildFailure
+	for _, f := range bf.failures {
+		if oldest == nil || f.LastSeen.Before(oldest.LastSeen) {
+			oldest = f
+		}
+	}
+	delete(bf.failures, oldest.Signature)
+}
+
+// startBisection marks the failure as being bisected by the manager.
+// Returns false if the failure is unknown or it's already bisected.
+func (bf *BuildFailures) startBisection(sig, manager string) bool {
+	bf.mu.Lock()
+	defer bf.mu.Unlock()
+	f := bf.failures[sig]
+	if f == nil || f.Bisection != nil {
+		return false
+	}
+	f.Bisection = &BuildBisection{
+		Manager: manager,
+		Started: time.Now(),
+	}
+	return true
+}
+
+// cancelBisection resets the bisection state if the bisection could not be started.
+func (bf *BuildFailures) cancelBisection(sig string) {
+	bf.mu.Lock()
+	defer bf.mu.Unlock()
+	if f := bf.failures[sig]; f != nil {
+		f.Bisection = nil
+	}
+}
+
+// reportTitles returns alternative titles for the dashboard report of the failure:
+// if the failure was already reported with a different title (e.g. by a manager
+// for another repo), the report refers to the first one.
+func (bf *BuildFailures) reportTitles(sig, title string) []string {
+	bf.mu.Lock()
+	defer bf.mu.Unlock()
+	f := bf.failures[sig]
+	if f == nil {
+		return nil
+	}
+	if f.ReportTitle == "" {
+		f.ReportTitle = title
+	}
+	if f.ReportTitle == title {
+		return nil
+	}
+	return []string{f.ReportTitle}
+}
+
+func (bf *BuildFailures) finishBisection(sig string, res *bisect.Result, err error) {
+	bf.mu.Lock()
+	defer bf.mu.Unlock()
+	f := bf.failures[sig]
+	if f == nil || f.Bisection == nil {
+		return
+	}
+	f.Bisection.Finished = time.Now()
+	if err != nil {
+		f.Bisection.Error = err.Error()
+		return
+	}
+	for _, com := range res.Commits {
+		f.Bisection.Commits = append(f.Bisection.Commits, fmt.Sprintf("%v %v", com.Hash, com.Title))
+	}
+}
+
+// snapshot returns copies of all failures, most recent first.
+func (bf *BuildFailures) snapshot() []*BuildFailure {
+	bf.mu.Lock()
+	defer bf.mu.Unlock()
+	var ret []*BuildFailure
+	for _, f := range bf.failures {
+		cp := *f
+		cp.Managers = append([]string{}, f.Managers...)
+		if f.Bisection != nil {
+			bisection := *f.Bisection
+			cp.Bisection = &bisection
+		}
+		ret = append(ret, &cp)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].LastSeen.After(ret[j].LastSeen)
+	})
+	return ret
+}
+
+// buildBisectionReq is a queued bisection of a build failure.
+type buildBisectionReq struct {
+	sig    string
+	bad    string
+	good   string
+	config []byte
+}
+
+// How many bisections may be waiting for the running one to finish.
+const buildBisectionQueueSize = 10
+
+// recordBuildFailure registers the build failure and, if it's a new one and the manager
+// is configured to do so, queues bisection of the commit that broke the build.
+// Returns the signature of the failure.
+func (mgr *Manager) recordBuildFailure(commit string, config []byte, buildErr error) string {
+	failure := build.ParseFailure(buildErr)
+	sig, isNew := buildFailures.record(mgr.name, commit, failure)
+	log.Logf(0, "%v: build failure: %v", mgr.name, failure.Title())
+	if !isNew || !mgr.mgrcfg.BisectBuildFailures {
+		return sig
+	}
+	switch failure.Kind {
+	case build.FailureCompiler, build.FailureLinker, build.FailureKconfig:
+	default:
+		return sig
+	}
+	info, err := loadBuildInfo(mgr.currentDir)
+	if err != nil {
+		// We don't know the last good commit.
+		return sig
+	}
+	if !buildFailures.startBisection(sig, mgr.name) {
+		return sig
+	}
+	req := &buildBisectionReq{
+		sig:    sig,
+		bad:    commit,
+		good:   info.KernelCommit,
+		config: config,
+	}
+	select {
+	case mgr.bisections <- req:
+	default:
+		log.Logf(0, "%v: too many pending build failure bisections, skipping", mgr.name)
+		buildFailures.cancelBisection(sig)
+	}
+	return sig
+}
+
+// bisectLoop runs the queued build failure bisections one at a time,
+// since they share the bisection dir of the manager.
+func (mgr *Manager) bisectLoop() {
+	for {
+		select {
+		case req := <-mgr.bisections:
+			res, err := mgr.bisectBuildFailure(req.sig, req.bad, req.good, req.config)
+			if err != nil {
+				log.Logf(0, "%v: build failure bisection failed: %v", mgr.name, err)
+			}
+			buildFailures.finishBisection(req.sig, res, err)
+		case <-mgr.stop:
+			return
+		}
+	}
+}
+
+func (mgr *Manager) bisectBuildFailure(sig, bad, good string, config []byte) (*bisect.Result, error) {
+	dir := filepath.Join(filepath.Dir(mgr.workDir), "bisect")
+	mgrcfg := new(mgrconfig.Config)
+	*mgrcfg = *mgr.managercfg
+	mgrcfg.Name += "-bisect"
+	mgrcfg.Workdir = filepath.Join(dir, "workdir")
+	mgrcfg.KernelSrc = filepath.Join(dir, "kernel", mgr.mgrcfg.KernelSrcSuffix)
+	mgrcfg.Syzkaller = osutil.Abs(mgrcfg.Syzkaller)
+	os.RemoveAll(mgrcfg.Workdir)
+	defer os.RemoveAll(mgrcfg.Workdir)
+	trace := new(bytes.Buffer)
+	cfg := &bisect.Config{
+		Trace: &debugtracer.GenericTracer{
+			TraceWriter: io.MultiWriter(trace, log.VerboseWriter(3)),
+			OutDir:      osutil.Abs(filepath.Join(dir, "debug", sig)),
+		},
+		Timeout:         12 * time.Hour,
+		DefaultCompiler: mgr.mgrcfg.Compiler,
+		CompilerType:    mgr.mgrcfg.CompilerType,
+		Make:            mgr.mgrcfg.Make,
+		Linker:          mgr.mgrcfg.Linker,
+		BinDir:          mgr.cfg.BisectBinDir,
+		Ccache:          mgr.cfg.Ccache,
+		BuildCPUs:       mgr.cfg.BuildCPUs,
+		Kernel: bisect.KernelConfig{
+			Repo:      mgr.mgrcfg.Repo,
+			Branch:    mgr.mgrcfg.Branch,
+			Commit:    bad,
+			Config:    config,
+			Userspace: mgr.mgrcfg.Userspace,
+			Cmdline:   mgr.mgrcfg.KernelCmdline,
+			Sysctl:    mgr.mgrcfg.KernelSysctl,
+			Backports: mgr.backportCommits(),
+		},
+		Manager:        mgrcfg,
+		BuildSemaphore: buildSem,
+		TestSemaphore:  testSem,
+	}
+	res, err := bisect.RunBuildFailure(cfg, good)
+	if err := osutil.WriteFile(filepath.Join(dir, sig+".log"), trace.Bytes()); err != nil {
+		log.Logf(0, "failed to save bisection log: %v", err)
+	}
+	return res, err
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/bisect"
+	"github.com/google/syzkaller/pkg/build"
+	"github.com/google/syzkaller/pkg/vcs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildFailuresDedup(t *testing.T) {
+	bf := newBuildFailures()
+	f1 := &build.Failure{Kind: build.FailureCompiler, File: "net/core/dev.c", Line: 10, Message: "'foo' undeclared"}
+	f2 := &build.Failure{Kind: build.FailureCompiler, File: "net/core/dev.c", Line: 12, Message: "'foo' undeclared"}
+	f3 := &build.Failure{Kind: build.FailureLinker, File: "fs/inode.c", Message: "undefined reference to `bar'"}
+
+	sig1, isNew := bf.record("ci-upstream", "commit1", f1)
+	assert.True(t, isNew)
+	// The same failure on another manager/commit/line is deduplicated.
+	sig2, isNew := bf.record("ci-upstream-kmsan", "commit2", f2)
+	assert.False(t, isNew)
+	assert.Equal(t, sig1, sig2)
+	_, isNew = bf.record("ci-upstream", "commit2", f3)
+	assert.True(t, isNew)
+
+	list := bf.snapshot()
+	assert.Len(t, list, 2)
+	assert.Equal(t, build.FailureLinker, list[0].Kind)
+	failure := list[1]
+	assert.Equal(t, 2, failure.Count)
+	assert.Equal(t, 12, failure.Line)
+	assert.Equal(t, []string{"ci-upstream", "ci-upstream-kmsan"}, failure.Managers)
+	assert.Equal(t, "commit1", failure.FirstCommit)
+	assert.Equal(t, "commit2", failure.LastCommit)
+
+	// Reports from managers for other repos refer to the first report.
+	assert.Empty(t, bf.reportTitles(sig1, "upstream build error"))
+	assert.Empty(t, bf.reportTitles(sig1, "upstream build error"))
+	assert.Equal(t, []string{"upstream build error"}, bf.reportTitles(sig1, "linux-next build error"))
+
+	assert.True(t, bf.startBisection(sig1, "ci-upstream"))
+	bf.cancelBisection(sig1)
+	assert.True(t, bf.startBisection(sig1, "ci-upstream"))
+	assert.False(t, bf.startBisection(sig1, "ci-upstream-kmsan"))
+	assert.False(t, bf.startBisection("unknown", "ci-upstream"))
+	bf.finishBisection(sig1, &bisect.Result{
+		Commits: []*vcs.Commit{{Hash: "abcdef", Title: "net: break the build"}},
+	}, nil)
+	for _, f := range bf.snapshot() {
+		if f.Signature == sig1 {
+			assert.Equal(t, []string{"abcdef net: break the build"}, f.Bisection.Commits)
+		}
+	}
+}
+
+func TestBuildFailuresEviction(t *testing.T) {
+	bf := newBuildFailures()
+	for i := 0; i < buildFailuresSize+10; i++ {
+		bf.record("ci-upstream", "commit", &build.Failure{
+			Kind:    build.FailureCompiler,
+			File:    fmt.Sprintf("file%v.c", i),
+			Message: "error",
+		})
+	}
+	assert.Len(t, bf.snapshot(), buildFailuresSize)
+}
+
+func TestStatusServerBuildFailures(t *testing.T) {
+	buildFailures = newBuildFailures()
+	defer func() { buildFailures = newBuildFailures() }()
+	mgr := testStatusManager("ci-upstream")
+	mgr.recordBuildFailure("0123456789abcdef", nil, &build.KernelError{
+		Report: []byte("net/core/dev.c:123:5: error: 'foo' undeclared"),
+	})
+	mgr.recordBuildFailure("fedcba9876543210", nil, errors.New("something went wrong"))
+	mux := http.NewServeMux()
+	NewStatusServer(&Config{Name: "ci"}, []*Manager{mgr}, nil).RegisterHandlers(mux)
+	body := httpGet(t, mux, "/")
+	assert.Contains(t, body, "Build failures (2)")
+	assert.Contains(t, body, "net/core/dev.c:123")
+	assert.Contains(t, body, "something went wrong")
+}
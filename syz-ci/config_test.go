@@ -17,6 +17,44 @@ func TestLoadConfig(t *testing.T) {
 	}
 }
 
+func TestConfigVariants(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, "ci.cfg")
+	osutil.WriteFile(cfgFile, []byte(`{
+	"name": "ci",
+	"http": ":80",
+	"managers": [
+		{
+			"name": "upstream",
+			"repo": "git://git.kernel.org/pub/scm/linux/kernel/git/torvalds/linux.git",
+			"kernel_config": "/syzkaller/kasan.config",
+			"config_variants": {"count": 2, "keep": ["KASAN"]},
+			"manager_config": {
+				"target": "linux/amd64",
+				"http": ":20000",
+				"type": "qemu",
+				"vm": {"count": 1}
+			}
+		}
+	]
+}`))
+	cfg, err := loadConfig(cfgFile)
+	assert.NoError(t, err)
+	var names, https []string
+	for _, mgr := range cfg.Managers {
+		names = append(names, mgr.managercfg.Name)
+		https = append(https, mgr.managercfg.HTTP)
+	}
+	assert.Equal(t, []string{"ci-upstream", "ci-upstream-v1", "ci-upstream-v2"}, names)
+	assert.Equal(t, []string{":20000", ":10000", ":10001"}, https)
+	assert.Equal(t, 0, cfg.Managers[0].variant)
+	assert.Equal(t, 2, cfg.Managers[2].variant)
+	assert.Equal(t, 10, cfg.Managers[2].ConfigVariants.Toggles)
+	assert.Equal(t, 5, cfg.Managers[2].ConfigVariants.CoverBoost)
+	assert.Equal(t, cfg.Managers[0], cfg.Managers[2].variantBase)
+	assert.NotEqual(t, cfg.Managers[1].variantTag(), cfg.Managers[2].variantTag())
+}
+
 func TestBaselineCanInference(t *testing.T) {
 	dir := t.TempDir()
 	kernelConfig := filepath.Join(dir, "kernel.config")
@@ -0,0 +1,213 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/google/syzkaller/pkg/html/pages"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/prog"
+)
+
+// StatusServer serves the syz-ci web UI: the list of managers with their builds,
+// image tests, artifact uploads and dashboard jobs, and manager lifecycle controls.
+type StatusServer struct {
+	cfg      *Config
+	managers []*Manager
+	jobs     *JobStatus // may be nil if there is no job manager
+	start    time.Time
+}
+
+func NewStatusServer(cfg *Config, managers []*Manager, jobs *JobStatus) *StatusServer {
+	return &StatusServer{
+		cfg:      cfg,
+		managers: managers,
+		jobs:     jobs,
+		start:    time.Now(),
+	}
+}
+
+func (serv *StatusServer) RegisterHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("/", serv.httpMain)
+	mux.HandleFunc("/manager", serv.httpManager)
+	mux.HandleFunc("/action", serv.httpAction)
+	// Browsers like to request this, without special handler this goes to / handler.
+	mux.HandleFunc("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {})
+}
+
+type uiMainPage struct {
+	Name     string
+	Revision string
+	Uptime   time.Duration
+	Managers []*uiManager
+	Jobs     *JobSnapshot
+	// Build failures deduplicated across all managers.
+	BuildFailures []*BuildFailure
+}
+
+type uiManager struct {
+	Name    string
+	Repo    string
+	Branch  string
+	HTTP    string
+	Jobs    ManagerJobs
+	Status  *ManagerSnapshot
+	Builds  []*BuildRecord
+	Uploads []*uiUpload
+}
+
+type uiUpload struct {
+	Name string
+	*UploadRecord
+}
+
+func (serv *StatusServer) httpMain(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	data := &uiMainPage{
+		Name:     serv.cfg.Name,
+		Revision: prog.GitRevisionBase,
+		Uptime:   time.Since(serv.start),
+	}
+	for _, mgr := range serv.managers {
+		data.Managers = append(data.Managers, serv.uiManager(mgr, 1))
+	}
+	if serv.jobs != nil {
+		data.Jobs = serv.jobs.snapshot()
+	}
+	data.BuildFailures = buildFailures.snapshot()
+	executeTemplate(w, "main.html", data)
+}
+
+func (serv *StatusServer) httpManager(w http.ResponseWriter, r *http.Request) {
+	mgr := serv.findManager(r.FormValue("name"))
+	if mgr == nil {
+		http.Error(w, "unknown manager", http.StatusNotFound)
+		return
+	}
+	data := &uiMainPage{
+		Name:     serv.cfg.Name,
+		Revision: prog.GitRevisionBase,
+		Uptime:   time.Since(serv.start),
+		Managers: []*uiManager{serv.uiManager(mgr, statusHistorySize)},
+	}
+	if serv.jobs != nil {
+		jobs := serv.jobs.snapshot()
+		data.Jobs = &JobSnapshot{
+			Running: filterJobs(jobs.Running, mgr.name),
+			Done:    filterJobs(jobs.Done, mgr.name),
+		}
+	}
+	executeTemplate(w, "manager.html", data)
+}
+
+func (serv *StatusServer) httpAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin requests are not allowed", http.StatusForbidden)
+		return
+	}
+	mgr := serv.findManager(r.FormValue("name"))
+	if mgr == nil {
+		http.Error(w, "unknown manager", http.StatusNotFound)
+		return
+	}
+	act, err := parseManagerAction(r.FormValue("action"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := mgr.requestAction(act); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	http.Redirect(w, r, "/manager?name="+mgr.name, http.StatusFound)
+}
+
+// sameOrigin protects the state-changing handlers from cross-site request forgery.
+// Browsers attach Origin (or at least Referer) to form submissions, so a request
+// coming from a page on another host is rejected. Requests without both headers
+// come from non-browser clients and are let through.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		origin = r.Header.Get("Referer")
+	}
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return u.Host == r.Host
+}
+
+func (serv *StatusServer) findManager(name string) *Manager {
+	for _, mgr := range serv.managers {
+		if mgr.name == name {
+			return mgr
+		}
+	}
+	return nil
+}
+
+func (serv *StatusServer) uiManager(mgr *Manager, builds int) *uiManager {
+	status := mgr.status.snapshot()
+	ret := &uiManager{
+		Name:   mgr.name,
+		Repo:   mgr.mgrcfg.Repo,
+		Branch: mgr.mgrcfg.Branch,
+		HTTP:   mgr.managercfg.HTTP,
+		Jobs:   mgr.mgrcfg.Jobs,
+		Status: status,
+		Builds: status.Builds,
+	}
+	if len(ret.Builds) > builds {
+		ret.Builds = ret.Builds[:builds]
+	}
+	for name, rec := range status.Uploads {
+		ret.Uploads = append(ret.Uploads, &uiUpload{name, rec})
+	}
+	sort.Slice(ret.Uploads, func(i, j int) bool {
+		return ret.Uploads[i].Name < ret.Uploads[j].Name
+	})
+	return ret
+}
+
+func filterJobs(list []*JobRecord, manager string) []*JobRecord {
+	var ret []*JobRecord
+	for _, rec := range list {
+		if rec.Manager == manager {
+			ret = append(ret, rec)
+		}
+	}
+	return ret
+}
+
+func executeTemplate(w http.ResponseWriter, name string, data interface{}) {
+	buf := new(bytes.Buffer)
+	if err := statusTemplates.ExecuteTemplate(buf, name, data); err != nil {
+		log.Logf(0, "failed to execute template: %v", err)
+		http.Error(w, fmt.Sprintf("failed to execute template: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Write(buf.Bytes())
+}
+
+//go:embed templates
+var templatesFS embed.FS
+var statusTemplates = pages.CreateFromFS(templatesFS, "templates/*.html")
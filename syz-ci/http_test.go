@@ -0,0 +1,153 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func testStatusManager(name string) *Manager {
+	return &Manager{
+		name: name,
+		mgrcfg: &ManagerConfig{
+			Repo:   "git://repo",
+			Branch: "master",
+		},
+		managercfg: &mgrconfig.Config{HTTP: ":10000"},
+		status:     newManagerStatus(),
+		actions:    make(chan ManagerAction, 1),
+	}
+}
+
+func TestStatusServer(t *testing.T) {
+	mgr := testStatusManager("ci-upstream")
+	info := &BuildInfo{
+		KernelCommit:      "0123456789abcdef",
+		KernelCommitTitle: "some commit title",
+	}
+	mgr.status.recordBuild(StageBuild, info, nil)
+	mgr.status.recordBuild(StageTest, info, errors.New("upstream test error: BUG in foo"))
+	mgr.status.setBuildInfo(info)
+	mgr.status.recordUpload("corpus", errors.New("permission denied"))
+
+	jobs := newJobStatus()
+	jobs.start("ci-job", &dashapi.JobPollResp{
+		ID:      "job-1",
+		Type:    dashapi.JobTestPatch,
+		Manager: mgr.name,
+	})
+	jobs.start("ci-job", &dashapi.JobPollResp{
+		ID:      "job-2",
+		Type:    dashapi.JobBisectCause,
+		Manager: mgr.name,
+	})
+	jobs.finish(&dashapi.JobDoneReq{
+		ID:         "job-1",
+		CrashTitle: "KASAN: use-after-free in bar",
+	})
+
+	mux := http.NewServeMux()
+	NewStatusServer(&Config{Name: "ci"}, []*Manager{mgr}, jobs).RegisterHandlers(mux)
+
+	body := httpGet(t, mux, "/")
+	assert.Contains(t, body, "ci-upstream")
+	assert.Contains(t, body, "01234567")
+	assert.Contains(t, body, "job-2")
+
+	body = httpGet(t, mux, "/manager?name=ci-upstream")
+	assert.Contains(t, body, "upstream test error: BUG in foo")
+	assert.Contains(t, body, "permission denied")
+	assert.Contains(t, body, "KASAN: use-after-free in bar")
+	assert.Contains(t, body, `value="pause"`)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/manager?name=unknown", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestStatusServerAction(t *testing.T) {
+	mgr := testStatusManager("ci-upstream")
+	mux := http.NewServeMux()
+	NewStatusServer(&Config{Name: "ci"}, []*Manager{mgr}, nil).RegisterHandlers(mux)
+
+	post := func(action string) int {
+		form := url.Values{"name": {mgr.name}, "action": {action}}
+		req := httptest.NewRequest(http.MethodPost, "/action", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusBadRequest, post("explode"))
+	assert.Equal(t, http.StatusFound, post("pause"))
+	// The previous action is not consumed yet.
+	assert.Equal(t, http.StatusConflict, post("rebuild"))
+
+	mgr.handleAction(<-mgr.actions)
+	assert.True(t, mgr.paused)
+	assert.True(t, mgr.status.snapshot().Paused)
+
+	assert.Equal(t, http.StatusFound, post("rebuild"))
+	mgr.handleAction(<-mgr.actions)
+	assert.True(t, mgr.forceRebuild)
+
+	assert.Equal(t, http.StatusFound, post("resume"))
+	mgr.handleAction(<-mgr.actions)
+	assert.False(t, mgr.paused)
+	assert.False(t, mgr.status.snapshot().Paused)
+}
+
+func TestStatusServerActionOrigin(t *testing.T) {
+	mgr := testStatusManager("ci-upstream")
+	mux := http.NewServeMux()
+	NewStatusServer(&Config{Name: "ci"}, []*Manager{mgr}, nil).RegisterHandlers(mux)
+
+	post := func(header, value string) int {
+		form := url.Values{"name": {mgr.name}, "action": {"pause"}}
+		req := httptest.NewRequest(http.MethodPost, "http://ci.example.com/action", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set(header, value)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusForbidden, post("Origin", "http://evil.example.com"))
+	assert.Equal(t, http.StatusForbidden, post("Referer", "http://evil.example.com/page"))
+	assert.Equal(t, http.StatusForbidden, post("Origin", "null"))
+	assert.Len(t, mgr.actions, 0)
+
+	assert.Equal(t, http.StatusFound, post("Origin", "http://ci.example.com"))
+	mgr.handleAction(<-mgr.actions)
+	assert.True(t, mgr.paused)
+	assert.Equal(t, http.StatusFound, post("Referer", "http://ci.example.com/manager?name=ci-upstream"))
+}
+
+func TestStatusHistoryLimit(t *testing.T) {
+	st := newManagerStatus()
+	for i := 0; i < statusHistorySize+10; i++ {
+		st.recordBuild(StageBuild, &BuildInfo{KernelCommit: "commit"}, nil)
+	}
+	st.recordBuild(StageBuild, &BuildInfo{KernelCommit: "last"}, nil)
+	builds := st.snapshot().Builds
+	assert.Len(t, builds, statusHistorySize)
+	assert.Equal(t, "last", builds[0].Commit)
+}
+
+func httpGet(t *testing.T, handler http.Handler, url string) string {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %v failed: %v\n%s", url, rec.Code, rec.Body.String())
+	}
+	return rec.Body.String()
+}
@@ -33,6 +33,7 @@ type JobManager struct {
 	managers          []*Manager
 	parallelJobFilter *ManagerJobs
 	shutdownPending   <-chan struct{}
+	status            *JobStatus
 }
 
 type JobProcessor struct {
@@ -44,6 +45,7 @@ type JobProcessor struct {
 	jobFilter      *ManagerJobs
 	jobTicker      <-chan time.Time
 	commitTicker   <-chan time.Time
+	backportTicker <-chan time.Time
 }
 
 func newJobManager(cfg *Config, managers []*Manager, shutdownPending chan struct{}) (*JobManager, error) {
@@ -56,6 +58,7 @@ func newJobManager(cfg *Config, managers []*Manager, shutdownPending chan struct
 		dash:            dash,
 		managers:        managers,
 		shutdownPending: shutdownPending,
+		status:          newJobStatus(),
 		// For now let's only parallelize patch testing requests.
 		parallelJobFilter: &ManagerJobs{TestPatches: true},
 	}, nil
@@ -87,6 +90,8 @@ func (jm *JobManager) loop(stop chan struct{}) {
 	}
 	commitTicker := time.NewTicker(time.Duration(jm.cfg.CommitPollPeriod) * time.Second)
 	defer commitTicker.Stop()
+	backportTicker := time.NewTicker(time.Duration(jm.cfg.BackportPollPeriod) * time.Second)
+	defer backportTicker.Stop()
 	jobTicker := time.NewTicker(time.Duration(jm.cfg.JobPollPeriod) * time.Second)
 	defer jobTicker.Stop()
 	var wg sync.WaitGroup
@@ -99,6 +104,7 @@ func (jm *JobManager) loop(stop chan struct{}) {
 			jp.instanceSuffix = "-job"
 			jp.baseDir = osutil.Abs("jobs")
 			jp.commitTicker = commitTicker.C
+			jp.backportTicker = backportTicker.C
 			jp.knownCommits = make(map[string]bool)
 		} else {
 			jp.instanceSuffix = "-job-parallel"
@@ -166,6 +172,8 @@ loop:
 			jp.pollJobs()
 		case <-jp.commitTicker:
 			jp.pollCommits()
+		case <-jp.backportTicker:
+			jp.pollBackports()
 		case <-stop:
 			break loop
 		}
@@ -334,7 +342,9 @@ func (jp *JobProcessor) processJob(job *Job) {
 	req := job.req
 	jp.Logf(0, "starting job %v type %v for manager %v on %v/%v",
 		req.ID, req.Type, req.Manager, req.KernelRepo, req.KernelBranch)
+	jp.status.start(jp.name, req)
 	resp := jp.process(job)
+	jp.status.finish(resp)
 	jp.Logf(0, "done job %v: commit %v, crash %q, error: %s",
 		resp.ID, resp.Build.KernelCommit, resp.CrashTitle, resp.Error)
 	select {
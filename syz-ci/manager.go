@@ -4,7 +4,6 @@
 package main
 
 import (
-	"context"
 	"crypto/sha256"
 	"encoding/json"
 	"errors"
@@ -12,7 +11,6 @@ import (
 	"io"
 	"math/rand"
 	"net/http"
-	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -25,9 +23,9 @@ import (
 	"github.com/google/syzkaller/pkg/build"
 	"github.com/google/syzkaller/pkg/config"
 	"github.com/google/syzkaller/pkg/cover"
-	"github.com/google/syzkaller/pkg/gcs"
 	"github.com/google/syzkaller/pkg/hash"
 	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/kconfig"
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/mgrconfig"
 	"github.com/google/syzkaller/pkg/osutil"
@@ -92,6 +90,11 @@ type Manager struct {
 	lastBuild      *dashapi.Build
 	buildFailed    bool
 	lastRestarted  time.Time
+	status         *ManagerStatus
+	actions        chan ManagerAction
+	forceRebuild   bool
+	paused         bool
+	bisections     chan *buildBisectionReq
 }
 
 type ManagerDashapi interface {
@@ -101,6 +104,8 @@ type ManagerDashapi interface {
 	LogError(name, msg string, args ...interface{})
 	CommitPoll() (*dashapi.CommitPollResp, error)
 	UploadCommits(commits []dashapi.Commit) error
+	FixCommits(req *dashapi.FixCommitsReq) (*dashapi.FixCommitsResp, error)
+	UploadMissingBackports(req *dashapi.UploadMissingBackportsReq) error
 }
 
 func createManager(cfg *Config, mgrcfg *ManagerConfig, stop chan struct{},
@@ -147,7 +152,7 @@ func createManager(cfg *Config, mgrcfg *ManagerConfig, stop chan struct{},
 		kernelBuildDir: kernelDir,
 		currentDir:     filepath.Join(dir, "current"),
 		latestDir:      filepath.Join(dir, "latest"),
-		configTag:      hash.String(configData),
+		configTag:      hash.String(configData, []byte(mgrcfg.variantTag())),
 		configData:     configData,
 		cfg:            cfg,
 		repo:           repo,
@@ -157,6 +162,9 @@ func createManager(cfg *Config, mgrcfg *ManagerConfig, stop chan struct{},
 		debugStorage:   !cfg.AssetStorage.IsEmpty() && cfg.AssetStorage.Debug,
 		stop:           stop,
 		debug:          debug,
+		status:         newManagerStatus(),
+		actions:        make(chan ManagerAction, 1),
+		bisections:     make(chan *buildBisectionReq, buildBisectionQueueSize),
 	}
 	// Leave the dashboard interface value as nil if it does not wrap a valid dashboard pointer.
 	if dash != nil {
@@ -181,6 +189,11 @@ const fuzzingMinutesBeforeCover = 360
 const benchUploadPeriod = 30 * time.Minute
 
 func (mgr *Manager) loop() {
+	bisectDone := make(chan struct{})
+	go func() {
+		defer close(bisectDone)
+		mgr.bisectLoop()
+	}()
 	lastCommit := ""
 	nextBuildTime := time.Now()
 	var managerRestartTime, artifactUploadTime, benchUploadTime time.Time
@@ -206,33 +219,46 @@ func (mgr *Manager) loop() {
 
 loop:
 	for {
-		if time.Since(nextBuildTime) >= 0 {
+		if mgr.forceRebuild || !mgr.paused && time.Since(nextBuildTime) >= 0 {
 			var rebuildAfter time.Duration
 			lastCommit, latestInfo, rebuildAfter = mgr.pollAndBuild(lastCommit, latestInfo)
 			nextBuildTime = time.Now().Add(rebuildAfter)
 		}
 		if !artifactUploadTime.IsZero() && time.Now().After(artifactUploadTime) {
 			artifactUploadTime = time.Time{}
-			if err := mgr.uploadCoverReport(); err != nil {
+			err := mgr.uploadCoverReport()
+			if err != nil {
 				mgr.Errorf("failed to upload cover report: %v", err)
 			}
-			if err := mgr.uploadProgramsWithCoverage(); err != nil {
+			mgr.status.recordUpload("cover report", err)
+			err = mgr.uploadProgramsWithCoverage()
+			if err != nil {
 				mgr.Errorf("failed to upload programs with coverage: %v", err)
 			}
+			mgr.status.recordUpload("programs with coverage", err)
+			if err := mgr.saveFileCover(); err != nil {
+				mgr.Errorf("failed to save file coverage: %v", err)
+			}
 			// Function uploadCoverStat also forces manager to drop the coverage structures to reduce memory usage.
 			// Should be the last request touching the coverage data.
-			if err := mgr.uploadCoverStat(fuzzingMinutesBeforeCover); err != nil {
+			err = mgr.uploadCoverStat(fuzzingMinutesBeforeCover)
+			if err != nil {
 				mgr.Errorf("failed to upload coverage stat: %v", err)
 			}
-			if err := mgr.uploadCorpus(); err != nil {
+			mgr.status.recordUpload("coverage stat", err)
+			err = mgr.uploadCorpus()
+			if err != nil {
 				mgr.Errorf("failed to upload corpus: %v", err)
 			}
+			mgr.status.recordUpload("corpus", err)
 		}
 		if mgr.cfg.BenchUploadPath != "" && time.Now().After(benchUploadTime) {
 			benchUploadTime = time.Now().Add(benchUploadPeriod)
-			if err := mgr.uploadBenchData(); err != nil {
+			err := mgr.uploadBenchData()
+			if err != nil {
 				mgr.Errorf("failed to upload bench: %v", err)
 			}
+			mgr.status.recordUpload("bench", err)
 		}
 
 		select {
@@ -241,7 +267,7 @@ loop:
 		default:
 		}
 
-		if latestInfo != nil && (latestInfo.Time != managerRestartTime || mgr.cmd == nil) {
+		if !mgr.paused && latestInfo != nil && (latestInfo.Time != managerRestartTime || mgr.cmd == nil) {
 			managerRestartTime = latestInfo.Time
 			mgr.restartManager()
 			if mgr.cmd != nil {
@@ -251,16 +277,54 @@ loop:
 
 		select {
 		case <-ticker.C:
+		case act := <-mgr.actions:
+			mgr.handleAction(act)
 		case <-mgr.stop:
 			break loop
 		}
 	}
 
+	mgr.stopManager()
+	<-bisectDone
+	log.Logf(0, "%v: stopped", mgr.name)
+}
+
+// handleAction applies a lifecycle action requested via the web UI.
+// It's executed on the manager loop goroutine.
+func (mgr *Manager) handleAction(act ManagerAction) {
+	log.Logf(0, "%v: got %v request", mgr.name, act)
+	switch act {
+	case ActionRebuild:
+		mgr.forceRebuild = true
+	case ActionRestart:
+		mgr.stopManager()
+		mgr.paused = false
+	case ActionPause:
+		mgr.stopManager()
+		mgr.paused = true
+	case ActionResume:
+		mgr.paused = false
+	}
+	mgr.status.setPaused(mgr.paused)
+}
+
+// requestAction queues a lifecycle action for the manager loop.
+// Returns an error if there is already a pending action.
+func (mgr *Manager) requestAction(act ManagerAction) error {
+	select {
+	case mgr.actions <- act:
+		return nil
+	default:
+		return fmt.Errorf("%v: another action is already pending", mgr.name)
+	}
+}
+
+func (mgr *Manager) stopManager() {
 	if mgr.cmd != nil {
 		mgr.cmd.Close()
 		mgr.cmd = nil
 	}
-	log.Logf(0, "%v: stopped", mgr.name)
+	mgr.status.setRunning(false)
 }
 
 func (mgr *Manager) archiveCommit(commit string) {
@@ -278,6 +342,7 @@ func (mgr *Manager) pollAndBuild(lastCommit string, latestInfo *BuildInfo) (
 	rebuildAfter := buildRetryPeriod
 	commit, err := mgr.repo.Poll(mgr.mgrcfg.Repo, mgr.mgrcfg.Branch)
 	if err != nil {
+		mgr.forceRebuild = false
 		mgr.buildFailed = true
 		mgr.Errorf("failed to poll: %v", err)
 	} else {
@@ -286,6 +351,11 @@ func (mgr *Manager) pollAndBuild(lastCommit string, latestInfo *BuildInfo) (
 			commit.Hash != latestInfo.KernelCommit ||
 			mgr.configTag != latestInfo.KernelConfigTag)
 		mgr.buildFailed = needsUpdate
+		if mgr.forceRebuild {
+			// The rebuild was explicitly requested via the web UI.
+			mgr.forceRebuild = false
+			needsUpdate, lastCommit = true, ""
+		}
 		if commit.Hash != lastCommit && needsUpdate {
 			lastCommit = commit.Hash
 			select {
@@ -371,6 +441,15 @@ func (mgr *Manager) build(kernelCommit *vcs.Commit) error {
 	if err := osutil.MkdirAll(tmpDir); err != nil {
 		return fmt.Errorf("failed to create tmp dir: %w", err)
 	}
+	configData := mgr.configData
+	if mgr.mgrcfg.variant != 0 {
+		var err error
+		if configData, err = mgr.variantConfig(); err != nil {
+			err = fmt.Errorf("failed to create config variant: %w", err)
+			mgr.status.recordBuild(StageBuild, mgr.createBuildInfo(kernelCommit, ""), err)
+			return err
+		}
+	}
 	params := build.Params{
 		TargetOS:     mgr.managercfg.TargetOS,
 		TargetArch:   mgr.managercfg.TargetVMArch,
@@ -384,13 +463,15 @@ func (mgr *Manager) build(kernelCommit *vcs.Commit) error {
 		UserspaceDir: mgr.mgrcfg.Userspace,
 		CmdlineFile:  mgr.mgrcfg.KernelCmdline,
 		SysctlFile:   mgr.mgrcfg.KernelSysctl,
-		Config:       mgr.configData,
+		Config:       configData,
 		Build:        mgr.mgrcfg.Build,
 		BuildCPUs:    mgr.cfg.BuildCPUs,
 	}
 	details, err := build.Image(params)
 	info := mgr.createBuildInfo(kernelCommit, details.CompilerID)
 	if err != nil {
+		mgr.status.recordBuild(StageBuild, info, err)
+		sig := mgr.recordBuildFailure(kernelCommit.Hash, configData, err)
 		rep := &report.Report{
 			Title: fmt.Sprintf("%v build error", mgr.mgrcfg.RepoAlias),
 		}
@@ -409,17 +490,22 @@ func (mgr *Manager) build(kernelCommit *vcs.Commit) error {
 		default:
 			rep.Report = []byte(err.Error())
 		}
+		// The same failure may have already been reported by managers for other repos.
+		rep.AltTitles = buildFailures.reportTitles(sig, rep.Title)
 		if err := mgr.reportBuildError(rep, info, tmpDir); err != nil {
 			mgr.Errorf("failed to report image error: %v", err)
 		}
 		return fmt.Errorf("kernel build failed: %w", err)
 	}
+	mgr.status.recordBuild(StageBuild, info, nil)
 
 	if err := config.SaveFile(filepath.Join(tmpDir, "tag"), info); err != nil {
 		return fmt.Errorf("failed to write tag file: %w", err)
 	}
 
-	if err := mgr.testImage(tmpDir, info); err != nil {
+	err = mgr.testImage(tmpDir, info)
+	mgr.status.recordBuild(StageTest, info, err)
+	if err != nil {
 		return err
 	}
 
@@ -437,10 +523,7 @@ func (mgr *Manager) restartManager() {
 		mgr.Errorf("can't start manager, image files missing")
 		return
 	}
-	if mgr.cmd != nil {
-		mgr.cmd.Close()
-		mgr.cmd = nil
-	}
+	mgr.stopManager()
 	if err := osutil.LinkFiles(mgr.latestDir, mgr.currentDir, imageFiles); err != nil {
 		mgr.Errorf("failed to create current image dir: %v", err)
 		return
@@ -450,6 +533,7 @@ func (mgr *Manager) restartManager() {
 		mgr.Errorf("failed to load build info: %v", err)
 		return
 	}
+	mgr.status.setBuildInfo(info)
 	// HEAD might be pointing to a different commit now e.g. due to a recent failed kernel
 	// build attempt, so let's always reset it to the commit the current kernel was built at.
 	_, err = mgr.repo.CheckoutCommit(mgr.mgrcfg.Repo, info.KernelCommit)
@@ -483,6 +567,7 @@ func (mgr *Manager) restartManager() {
 	}
 	mgr.cmd = NewManagerCmd(mgr.name, logFile, benchFile, mgr.Errorf, bin, args...)
 	mgr.lastRestarted = time.Now()
+	mgr.status.setRunning(true)
 }
 
 func (mgr *Manager) testImage(imageDir string, info *BuildInfo) error {
@@ -865,7 +950,7 @@ func (mgr *Manager) uploadCoverReport() error {
 	}
 	defer resp.Body.Close()
 	if directUpload {
-		return uploadFile(mgr.cfg.CoverUploadPath, mgr.name+".html", resp.Body, mgr.cfg.PublishGCS)
+		return asset.UploadFile(mgr.cfg.CoverUploadPath, mgr.name+".html", resp.Body, mgr.cfg.PublishGCS)
 	}
 	// Upload via the asset storage.
 	newAsset, err := mgr.storage.UploadBuildAsset(resp.Body, mgr.name+".html",
@@ -923,8 +1008,8 @@ func (mgr *Manager) uploadCoverJSONLToGCS(mgrSrc, gcsDest string, curTime time.T
 		mgr.mgrcfg.DashboardClient,
 		mgr.name, curTime.Format(time.DateOnly),
 		curTime.Hour(), curTime.Minute())
-	if err := uploadFile(gcsDest, fileName, pr, publish); err != nil {
-		return fmt.Errorf("failed to uploadFileGCS(): %w", err)
+	if err := asset.UploadFile(gcsDest, fileName, pr, publish); err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
 	}
 	return nil
 }
@@ -994,7 +1079,7 @@ func (mgr *Manager) uploadCorpus() error {
 		return err
 	}
 	defer f.Close()
-	return uploadFile(mgr.cfg.CorpusUploadPath, mgr.name+"-corpus.db", f, mgr.cfg.PublishGCS)
+	return asset.UploadFile(mgr.cfg.CorpusUploadPath, mgr.name+"-corpus.db", f, mgr.cfg.PublishGCS)
 }
 
 func (mgr *Manager) uploadBenchData() error {
@@ -1012,7 +1097,7 @@ func (mgr *Manager) uploadBenchData() error {
 		return fmt.Errorf("failed to open bench file: %w", err)
 	}
 	defer f.Close()
-	err = uploadFile(mgr.cfg.BenchUploadPath+"/"+mgr.name,
+	err = asset.UploadFile(mgr.cfg.BenchUploadPath+"/"+mgr.name,
 		mgr.lastRestarted.Format("2006-01-02_15h.json"), f, false)
 	if err != nil {
 		return fmt.Errorf("failed to upload the bench file: %w", err)
@@ -1020,44 +1105,120 @@ func (mgr *Manager) uploadBenchData() error {
 	return nil
 }
 
-func uploadFile(dstPath, name string, file io.Reader, publish bool) error {
-	URL, err := url.Parse(dstPath)
+// Errorf logs non-fatal error and sends it to dashboard.
+func (mgr *Manager) Errorf(msg string, args ...interface{}) {
+	log.Errorf(mgr.name+": "+msg, args...)
+	if mgr.dash != nil {
+		mgr.dash.LogError(mgr.name, msg, args...)
+	}
+}
+
+// variantConfig derives the config variant from the base kernel config using Kconfig
+// of the checked out kernel. The variant is deterministic for the manager name,
+// but may change when Kconfig files change.
+func (mgr *Manager) variantConfig() ([]byte, error) {
+	target := targets.Get(mgr.managercfg.TargetOS, mgr.managercfg.TargetVMArch)
+	if target == nil {
+		return nil, fmt.Errorf("unknown target %v/%v", mgr.managercfg.TargetOS, mgr.managercfg.TargetVMArch)
+	}
+	kconf, err := kconfig.Parse(target, filepath.Join(mgr.kernelBuildDir, "Kconfig"))
+	if err != nil {
+		return nil, err
+	}
+	base, err := kconfig.ParseConfigData(mgr.configData, "config")
 	if err != nil {
-		return fmt.Errorf("failed to parse upload path: %w", err)
+		return nil, err
+	}
+	variants := mgr.mgrcfg.ConfigVariants
+	sig := hash.Hash([]byte(mgr.name))
+	rnd := rand.New(rand.NewSource(sig.Truncate64()))
+	params := kconfig.VariantParams{
+		Toggles: variants.Toggles,
+		Keep:    variants.Keep,
+	}
+	poorFiles, err := mgr.poorlyCoveredFiles()
+	if err != nil {
+		log.Logf(0, "%v: failed to read file coverage of the base manager: %v", mgr.name, err)
 	}
-	URL.Path = path.Join(URL.Path, name)
-	URLStr := URL.String()
-	log.Logf(0, "uploading %v to %v", name, URLStr)
-	if strings.HasPrefix(URLStr, "http://") ||
-		strings.HasPrefix(URLStr, "https://") {
-		return uploadFileHTTPPut(URLStr, file)
+	for i := 0; i < variants.CoverBoost && len(poorFiles) != 0; i++ {
+		file := poorFiles[rnd.Intn(len(poorFiles))]
+		params.Enable = append(params.Enable, kconfig.FileConfigs(mgr.kernelSrcDir, file)...)
 	}
-	return gcs.UploadFile(context.Background(), file, URLStr, publish)
+	variant, changed := kconf.Variant(base, params, rnd)
+	log.Logf(0, "%v: config variant changes %v", mgr.name, strings.Join(changed, " "))
+	return variant.Serialize(), nil
 }
 
-func uploadFileHTTPPut(URL string, file io.Reader) error {
-	req, err := http.NewRequest(http.MethodPut, URL, file)
+// poorlyCoveredFiles returns files that are poorly covered by the base manager of the config variant
+// according to the file coverage report saved by the base manager (see saveFileCover).
+func (mgr *Manager) poorlyCoveredFiles() ([]string, error) {
+	base := mgr.mgrcfg.variantBase
+	if base == nil || mgr.mgrcfg.ConfigVariants.CoverBoost <= 0 {
+		return nil, nil
+	}
+	file := fileCoverFile(base.Name)
+	if !osutil.IsExist(file) {
+		// The base manager hasn't fuzzed long enough yet.
+		return nil, nil
+	}
+	f, err := os.Open(file)
 	if err != nil {
-		return fmt.Errorf("failed to create HTTP PUT request: %w", err)
+		return nil, err
 	}
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	defer f.Close()
+	files, err := cover.ReadFileCover(f)
 	if err != nil {
-		return fmt.Errorf("failed to perform HTTP PUT request: %w", err)
+		return nil, err
+	}
+	return cover.PoorlyCoveredFiles(files, mgr.mgrcfg.ConfigVariants.CoverThreshold), nil
+}
+
+// saveFileCover saves the file coverage report of the base manager,
+// config variants use it to enable configs related to poorly covered files.
+func (mgr *Manager) saveFileCover() error {
+	variants := mgr.mgrcfg.ConfigVariants
+	if mgr.mgrcfg.variant != 0 || variants == nil || variants.Count == 0 || variants.CoverBoost <= 0 ||
+		!mgr.managercfg.Cover {
+		return nil
+	}
+	// Report generation can consume lots of memory. Generate one at a time.
+	select {
+	case <-buildSem.WaitC():
+	case <-mgr.stop:
+		return nil
+	}
+	defer buildSem.Signal()
+
+	resp, err := mgr.httpGET("/filecover")
+	if err != nil {
+		return err
 	}
 	defer resp.Body.Close()
-	if !(resp.StatusCode >= 200 && resp.StatusCode <= 299) {
-		return fmt.Errorf("HTTP PUT failed with status code: %v", resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get file coverage: %v", resp.Status)
 	}
-	return nil
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	file := fileCoverFile(mgr.mgrcfg.Name)
+	if err := osutil.WriteFile(file+".tmp", data); err != nil {
+		return err
+	}
+	return osutil.Rename(file+".tmp", file)
 }
 
-// Errorf logs non-fatal error and sends it to dashboard.
-func (mgr *Manager) Errorf(msg string, args ...interface{}) {
-	log.Errorf(mgr.name+": "+msg, args...)
-	if mgr.dash != nil {
-		mgr.dash.LogError(mgr.name, msg, args...)
+func fileCoverFile(manager string) string {
+	return osutil.Abs(filepath.Join("managers", manager, "filecover.csv"))
+}
+
+// variantTag returns a string that identifies the config variant parameters.
+func (mgr *ManagerConfig) variantTag() string {
+	if mgr.variant == 0 {
+		return ""
 	}
+	return fmt.Sprintf("variant-%v-%v-%v", mgr.variant, mgr.ConfigVariants.Toggles,
+		strings.Join(mgr.ConfigVariants.Keep, ","))
 }
 
 func (mgr *ManagerConfig) validate(cfg *Config) error {
@@ -1072,8 +1233,17 @@ func (mgr *ManagerConfig) validate(cfg *Config) error {
 	if mgr.Jobs.PollCommits && (cfg.DashboardAddr == "" || mgr.DashboardClient == "") {
 		return fmt.Errorf("manager %v: commit_poll is set but no dashboard info", mgr.Name)
 	}
-	if (mgr.Jobs.BisectCause || mgr.Jobs.BisectFix) && cfg.BisectBinDir == "" {
+	if mgr.Jobs.PollBackports && (cfg.DashboardAddr == "" || mgr.DashboardClient == "") {
+		return fmt.Errorf("manager %v: poll_backports is set but no dashboard info", mgr.Name)
+	}
+	if mgr.Jobs.PollBackports != (len(mgr.BackportTrees) != 0) {
+		return fmt.Errorf("manager %v: poll_backports and backport_trees must be set together", mgr.Name)
+	}
+	if (mgr.Jobs.BisectCause || mgr.Jobs.BisectFix || mgr.BisectBuildFailures) && cfg.BisectBinDir == "" {
 		return fmt.Errorf("manager %v: enabled bisection but no bisect_bin_dir", mgr.Name)
 	}
+	if mgr.ConfigVariants != nil && mgr.ConfigVariants.Count != 0 && mgr.managercfg.TargetOS != targets.Linux {
+		return fmt.Errorf("manager %v: config_variants are supported only for linux", mgr.Name)
+	}
 	return nil
 }
@@ -29,6 +29,12 @@ func (dm *dashapiMock) UploadBuild(build *dashapi.Build) error            { retu
 func (dm *dashapiMock) LogError(name, msg string, args ...interface{})    {}
 func (dm *dashapiMock) CommitPoll() (*dashapi.CommitPollResp, error)      { return nil, nil }
 func (dm *dashapiMock) UploadCommits(commits []dashapi.Commit) error      { return nil }
+func (dm *dashapiMock) FixCommits(req *dashapi.FixCommitsReq) (*dashapi.FixCommitsResp, error) {
+	return nil, nil
+}
+func (dm *dashapiMock) UploadMissingBackports(req *dashapi.UploadMissingBackportsReq) error {
+	return nil
+}
 
 func TestManagerPollCommits(t *testing.T) {
 	// Mock a repository.
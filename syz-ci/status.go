@@ -0,0 +1,228 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+)
+
+// How many build/test/job records we keep in memory for the web UI.
+const statusHistorySize = 50
+
+// ManagerStatus keeps the state of a Manager that is exported via the syz-ci web UI.
+// It's updated by the manager loop and read concurrently by the HTTP handlers.
+type ManagerStatus struct {
+	mu      sync.Mutex
+	info    *BuildInfo
+	builds  []*BuildRecord
+	uploads map[string]*UploadRecord
+	paused  bool
+	running bool
+}
+
+type BuildStage string
+
+const (
+	StageBuild BuildStage = "build"
+	StageTest  BuildStage = "test"
+)
+
+// BuildRecord describes a single kernel build or image test attempt.
+type BuildRecord struct {
+	Time   time.Time
+	Stage  BuildStage
+	Commit string
+	Title  string
+	Error  string
+}
+
+// UploadRecord describes the last attempt to upload a manager artifact (cover report, corpus, etc).
+type UploadRecord struct {
+	Time  time.Time
+	Error string
+}
+
+func newManagerStatus() *ManagerStatus {
+	return &ManagerStatus{
+		uploads: make(map[string]*UploadRecord),
+	}
+}
+
+func (st *ManagerStatus) setBuildInfo(info *BuildInfo) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.info = info
+}
+
+func (st *ManagerStatus) setRunning(running bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.running = running
+}
+
+func (st *ManagerStatus) setPaused(paused bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.paused = paused
+}
+
+func (st *ManagerStatus) recordBuild(stage BuildStage, info *BuildInfo, err error) {
+	rec := &BuildRecord{
+		Time:  time.Now(),
+		Stage: stage,
+	}
+	if info != nil {
+		rec.Commit = info.KernelCommit
+		rec.Title = info.KernelCommitTitle
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.builds = appendHistory(st.builds, rec)
+}
+
+func (st *ManagerStatus) recordUpload(what string, err error) {
+	rec := &UploadRecord{Time: time.Now()}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.uploads[what] = rec
+}
+
+// ManagerSnapshot is a consistent copy of ManagerStatus.
+type ManagerSnapshot struct {
+	Info    *BuildInfo
+	Builds  []*BuildRecord // most recent first
+	Uploads map[string]*UploadRecord
+	Paused  bool
+	Running bool
+}
+
+func (st *ManagerStatus) snapshot() *ManagerSnapshot {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	ret := &ManagerSnapshot{
+		Info:    st.info,
+		Uploads: make(map[string]*UploadRecord),
+		Paused:  st.paused,
+		Running: st.running,
+	}
+	for i := len(st.builds) - 1; i >= 0; i-- {
+		ret.Builds = append(ret.Builds, st.builds[i])
+	}
+	for what, rec := range st.uploads {
+		ret.Uploads[what] = rec
+	}
+	return ret
+}
+
+// ManagerAction is a request to change the manager lifecycle that comes from the web UI.
+type ManagerAction string
+
+const (
+	ActionRebuild ManagerAction = "rebuild"
+	ActionRestart ManagerAction = "restart"
+	ActionPause   ManagerAction = "pause"
+	ActionResume  ManagerAction = "resume"
+)
+
+func parseManagerAction(s string) (ManagerAction, error) {
+	switch act := ManagerAction(s); act {
+	case ActionRebuild, ActionRestart, ActionPause, ActionResume:
+		return act, nil
+	}
+	return "", fmt.Errorf("unknown action %q", s)
+}
+
+// JobStatus keeps the recent history of dashboard jobs processed by JobManager.
+type JobStatus struct {
+	mu      sync.Mutex
+	running map[string]*JobRecord
+	done    []*JobRecord
+}
+
+// JobRecord describes a single job received from the dashboard.
+type JobRecord struct {
+	ID        string
+	Type      dashapi.JobType
+	Manager   string
+	Processor string
+	Started   time.Time
+	Finished  time.Time
+	Commit    string
+	Crash     string
+	Error     string
+}
+
+func newJobStatus() *JobStatus {
+	return &JobStatus{
+		running: make(map[string]*JobRecord),
+	}
+}
+
+func (st *JobStatus) start(processor string, req *dashapi.JobPollResp) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.running[req.ID] = &JobRecord{
+		ID:        req.ID,
+		Type:      req.Type,
+		Manager:   req.Manager,
+		Processor: processor,
+		Started:   time.Now(),
+	}
+}
+
+func (st *JobStatus) finish(resp *dashapi.JobDoneReq) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	rec := st.running[resp.ID]
+	if rec == nil {
+		return
+	}
+	delete(st.running, resp.ID)
+	rec.Finished = time.Now()
+	rec.Commit = resp.Build.KernelCommit
+	rec.Crash = resp.CrashTitle
+	rec.Error = string(resp.Error)
+	st.done = appendHistory(st.done, rec)
+}
+
+// JobSnapshot is a consistent copy of JobStatus.
+type JobSnapshot struct {
+	Running []*JobRecord
+	Done    []*JobRecord // most recent first
+}
+
+func (st *JobStatus) snapshot() *JobSnapshot {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	ret := new(JobSnapshot)
+	for _, rec := range st.running {
+		ret.Running = append(ret.Running, rec)
+	}
+	sort.Slice(ret.Running, func(i, j int) bool {
+		return ret.Running[i].Started.Before(ret.Running[j].Started)
+	})
+	for i := len(st.done) - 1; i >= 0; i-- {
+		ret.Done = append(ret.Done, st.done[i])
+	}
+	return ret
+}
+
+func appendHistory[T any](list []T, elem T) []T {
+	list = append(list, elem)
+	if len(list) > statusHistorySize {
+		list = list[len(list)-statusHistorySize:]
+	}
+	return list
+}
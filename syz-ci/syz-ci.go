@@ -105,19 +105,22 @@ type Config struct {
 	// - *.h files are copied to syzkaller/executor/
 	SyzkallerDescriptions string `json:"syzkaller_descriptions"`
 	// Path to upload coverage reports from managers (optional).
-	// Supported protocols: GCS (gs://) and HTTP PUT (http:// or https://).
+	// Supported protocols: GCS (gs://), S3 (s3://), local dir (file://)
+	// and HTTP PUT (http:// or https://). See pkg/asset.UploadFile.
 	CoverUploadPath string `json:"cover_upload_path"`
 	// Path to upload managers syz programs and their coverage in jsonl (optional).
 	CoverProgramsPath string `json:"cover_programs_path"`
 	// Path to upload json coverage reports from managers (optional).
 	CoverPipelinePath string `json:"cover_pipeline_path"`
 	// Path to upload corpus.db from managers (optional).
-	// Supported protocols: GCS (gs://) and HTTP PUT (http:// or https://).
+	// Supported protocols: GCS (gs://), S3 (s3://), local dir (file://)
+	// and HTTP PUT (http:// or https://). See pkg/asset.UploadFile.
 	CorpusUploadPath string `json:"corpus_upload_path"`
 	// Make files uploaded via CoverUploadPath, CorpusUploadPath and CoverProgramsPath public.
 	PublishGCS bool `json:"publish_gcs"`
 	// Path to upload bench data from instances (optional).
-	// Supported protocols: GCS (gs://) and HTTP PUT (http:// or https://).
+	// Supported protocols: GCS (gs://), S3 (s3://), local dir (file://)
+	// and HTTP PUT (http:// or https://). See pkg/asset.UploadFile.
 	BenchUploadPath string `json:"bench_upload_path"`
 	// BinDir must point to a dir that contains compilers required to build
 	// older versions of the kernel. For linux, it needs to include several
@@ -141,6 +144,8 @@ type Config struct {
 	ParallelJobs bool `json:"parallel_jobs"`
 	// Poll period for commits in seconds (optional, defaults to 3600 seconds)
 	CommitPollPeriod int `json:"commit_poll_period"`
+	// Period of the missing backports checks in seconds (optional, defaults to 86400 seconds).
+	BackportPollPeriod int `json:"backport_poll_period"`
 	// Asset Storage config.
 	AssetStorage *asset.Config `json:"asset_storage"`
 	// Per-vm type JSON diffs that will be applied to every instace of the
@@ -215,6 +220,9 @@ type ManagerConfig struct {
 	Jobs         ManagerJobs `json:"jobs"`
 	// Extra commits to cherry pick to older kernel revisions.
 	BisectBackports []vcs.BackportCommit `json:"bisect_backports"`
+	// The (stable) trees that are checked for the missing backports of the fixes
+	// of the manager's namespace bugs. Requires jobs.poll_backports.
+	BackportTrees []*BackportTree `json:"backport_trees"`
 	// Base syz-manager config for the instance.
 	ManagerConfig json.RawMessage `json:"manager_config"`
 	// By default we want to archive git commits.
@@ -224,29 +232,63 @@ type ManagerConfig struct {
 	// fuzzing won't be started on this instance.
 	// By default it's 30 days.
 	MaxKernelLagDays int `json:"max_kernel_lag_days"`
-	managercfg       *mgrconfig.Config
+	// If set, syz-ci bisects the commit that broke the kernel build for new (by signature) build failures.
+	// Requires bisect_bin_dir.
+	BisectBuildFailures bool `json:"bisect_build_failures"`
+	// If set, syz-ci additionally builds and fuzzes kernels with randomly varied configs
+	// derived from kernel_config (see ConfigVariants).
+	ConfigVariants *ConfigVariants `json:"config_variants"`
+	managercfg     *mgrconfig.Config
+	// Index of the config variant (starting from 1), 0 for the base manager.
+	variant int
+	// The manager the config variant is derived from.
+	variantBase *ManagerConfig
 
 	// Auto-assigned ports used by test instances.
 	testRPCPort int
 }
 
+type BackportTree struct {
+	Repo   string `json:"repo"`
+	Branch string `json:"branch"`
+}
+
+// ConfigVariants describes config-variant managers derived from a base manager.
+// Each variant is a separate manager named NAME-vN that builds the same kernel tree
+// with kernel_config with some user-visible options toggled (see kconfig.KConfig.Variant).
+// Variants don't run any jobs and use auto-assigned http/rpc ports.
+type ConfigVariants struct {
+	Count int `json:"count"`
+	// Number of options to toggle in each variant (10 by default).
+	Toggles int `json:"toggles"`
+	// Configs that must not be changed (e.g. CONFIG_KASAN).
+	Keep []string `json:"keep"`
+	// Each variant also enables configs related to CoverBoost random files whose line coverage
+	// in the base manager is below CoverThreshold (5 files and 0.1 by default, see syz-kconfvariant).
+	// Requires coverage to be enabled for the base manager. Negative CoverBoost disables this.
+	CoverBoost     int     `json:"cover_boost"`
+	CoverThreshold float64 `json:"cover_threshold"`
+}
+
 type ManagerJobs struct {
-	TestPatches bool `json:"test_patches"` // enable patch testing jobs
-	PollCommits bool `json:"poll_commits"` // poll info about fix commits
-	BisectCause bool `json:"bisect_cause"` // do cause bisection
-	BisectFix   bool `json:"bisect_fix"`   // do fix bisection
+	TestPatches   bool `json:"test_patches"`   // enable patch testing jobs
+	PollCommits   bool `json:"poll_commits"`   // poll info about fix commits
+	BisectCause   bool `json:"bisect_cause"`   // do cause bisection
+	BisectFix     bool `json:"bisect_fix"`     // do fix bisection
+	PollBackports bool `json:"poll_backports"` // look for fix commits missing from backport_trees
 }
 
 func (m *ManagerJobs) AnyEnabled() bool {
-	return m.TestPatches || m.PollCommits || m.BisectCause || m.BisectFix
+	return m.TestPatches || m.PollCommits || m.BisectCause || m.BisectFix || m.PollBackports
 }
 
 func (m *ManagerJobs) Filter(filter *ManagerJobs) *ManagerJobs {
 	return &ManagerJobs{
-		TestPatches: m.TestPatches && filter.TestPatches,
-		PollCommits: m.PollCommits && filter.PollCommits,
-		BisectCause: m.BisectCause && filter.BisectCause,
-		BisectFix:   m.BisectFix && filter.BisectFix,
+		TestPatches:   m.TestPatches && filter.TestPatches,
+		PollCommits:   m.PollCommits && filter.PollCommits,
+		BisectCause:   m.BisectCause && filter.BisectCause,
+		BisectFix:     m.BisectFix && filter.BisectFix,
+		PollBackports: m.PollBackports && filter.PollBackports,
 	}
 }
 
@@ -309,6 +351,7 @@ func main() {
 		log.Fatalf("failed to create dashapi connection %v", err)
 	}
 	stopJobs := jp.startLoop(&wg)
+	NewStatusServer(cfg, managers, jp.status).RegisterHandlers(http.DefaultServeMux)
 
 	// For testing. Racy. Use with care.
 	http.HandleFunc("/upload_cover", func(w http.ResponseWriter, r *http.Request) {
@@ -387,13 +430,14 @@ func serveHTTP(cfg *Config) {
 
 func loadConfig(filename string) (*Config, error) {
 	cfg := &Config{
-		SyzkallerRepo:    "https://github.com/google/syzkaller.git",
-		SyzkallerBranch:  "master",
-		ManagerPort:      10000,
-		RPCPort:          30000,
-		Goroot:           os.Getenv("GOROOT"),
-		JobPollPeriod:    10,
-		CommitPollPeriod: 3600,
+		SyzkallerRepo:      "https://github.com/google/syzkaller.git",
+		SyzkallerBranch:    "master",
+		ManagerPort:        10000,
+		RPCPort:            30000,
+		Goroot:             os.Getenv("GOROOT"),
+		JobPollPeriod:      10,
+		CommitPollPeriod:   3600,
+		BackportPollPeriod: 86400,
 	}
 	if err := config.LoadFile(filename, cfg); err != nil {
 		return nil, err
@@ -410,12 +454,18 @@ func loadConfig(filename string) (*Config, error) {
 	cfg.Ccache = osutil.Abs(cfg.Ccache)
 	var managers []*ManagerConfig
 	for _, mgr := range cfg.Managers {
-		if mgr.Disabled == "" {
-			managers = append(managers, mgr)
-		}
-		if err := loadManagerConfig(cfg, mgr); err != nil {
+		variants, err := configVariants(mgr)
+		if err != nil {
 			return nil, err
 		}
+		for _, mgr := range append([]*ManagerConfig{mgr}, variants...) {
+			if mgr.Disabled == "" {
+				managers = append(managers, mgr)
+			}
+			if err := loadManagerConfig(cfg, mgr); err != nil {
+				return nil, err
+			}
+		}
 	}
 	cfg.Managers = managers
 	if len(cfg.Managers) == 0 {
@@ -489,6 +539,52 @@ func loadManagerConfig(cfg *Config, mgr *ManagerConfig) error {
 	return nil
 }
 
+// configVariants creates config-variant managers for mgr according to mgr.ConfigVariants.
+func configVariants(mgr *ManagerConfig) ([]*ManagerConfig, error) {
+	if mgr.ConfigVariants == nil || mgr.ConfigVariants.Count == 0 {
+		return nil, nil
+	}
+	if mgr.KernelConfig == "" {
+		return nil, fmt.Errorf("manager %v: config_variants require kernel_config", mgr.Name)
+	}
+	if mgr.ConfigVariants.Toggles == 0 {
+		mgr.ConfigVariants.Toggles = 10
+	}
+	if mgr.ConfigVariants.CoverBoost == 0 {
+		mgr.ConfigVariants.CoverBoost = 5
+	}
+	if mgr.ConfigVariants.CoverThreshold == 0 {
+		mgr.ConfigVariants.CoverThreshold = 0.1
+	}
+	managercfg, err := mgrconfig.LoadPartialData(mgr.ManagerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("manager config: %w", err)
+	}
+	var variants []*ManagerConfig
+	for i := 1; i <= mgr.ConfigVariants.Count; i++ {
+		variant := new(ManagerConfig)
+		*variant = *mgr
+		variant.variant = i
+		variant.variantBase = mgr
+		variant.Jobs = ManagerJobs{}
+		patch := map[string]interface{}{
+			"http": "",
+			"rpc":  ":0",
+		}
+		if managercfg.Name != "" {
+			patch["name"] = fmt.Sprintf("%v-v%v", managercfg.Name, i)
+		} else {
+			variant.Name = fmt.Sprintf("%v-v%v", mgr.Name, i)
+		}
+		variant.ManagerConfig, err = config.PatchJSON(mgr.ManagerConfig, patch)
+		if err != nil {
+			return nil, fmt.Errorf("manager %v: %w", mgr.Name, err)
+		}
+		variants = append(variants, variant)
+	}
+	return variants, nil
+}
+
 func inferBaselineConfig(kernelConfig string) string {
 	suffixPos := strings.LastIndex(kernelConfig, ".config")
 	if suffixPos < 0 {
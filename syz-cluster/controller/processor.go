@@ -13,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/syzkaller/pkg/config"
 	"github.com/google/syzkaller/syz-cluster/pkg/app"
 	"github.com/google/syzkaller/syz-cluster/pkg/blob"
 	"github.com/google/syzkaller/syz-cluster/pkg/db"
@@ -22,15 +23,15 @@ import (
 
 type SeriesProcessor struct {
 	blobStorage     blob.Storage
-	seriesRepo      *db.SeriesRepository
-	sessionRepo     *db.SessionRepository
+	seriesRepo      db.SeriesRepository
+	sessionRepo     db.SessionRepository
 	workflows       workflow.Service
 	dbPollInterval  time.Duration
 	parallelWorkers int
 }
 
 func NewSeriesProcessor(env *app.AppEnvironment) *SeriesProcessor {
-	workflows, err := workflow.NewArgoService()
+	workflows, err := newWorkflowService()
 	if err != nil {
 		app.Fatalf("failed to initialize workflows: %v", err)
 	}
@@ -44,14 +45,28 @@ func NewSeriesProcessor(env *app.AppEnvironment) *SeriesProcessor {
 	}
 	return &SeriesProcessor{
 		blobStorage:     env.BlobStorage,
-		seriesRepo:      db.NewSeriesRepository(env.Spanner),
-		sessionRepo:     db.NewSessionRepository(env.Spanner),
+		seriesRepo:      db.NewSeriesRepository(env.DB),
+		sessionRepo:     db.NewSessionRepository(env.DB),
 		dbPollInterval:  time.Minute,
 		workflows:       workflows,
 		parallelWorkers: parallelWorkers,
 	}
 }
 
+// newWorkflowService returns the Argo service unless LOCAL_WORKFLOW_CONFIG points to
+// a workflow.LocalConfig file, in which case the steps are run on the same machine.
+func newWorkflowService() (workflow.Service, error) {
+	cfgFile := os.Getenv("LOCAL_WORKFLOW_CONFIG")
+	if cfgFile == "" {
+		return workflow.NewArgoService()
+	}
+	cfg := new(workflow.LocalConfig)
+	if err := config.LoadFile(cfgFile, cfg); err != nil {
+		return nil, err
+	}
+	return workflow.NewLocalService(cfg)
+}
+
 func (sp *SeriesProcessor) Loop(ctx context.Context) error {
 	var wg sync.WaitGroup
 	defer wg.Wait()
@@ -78,7 +78,7 @@ func TestProcessor(t *testing.T) {
 	cancel()
 }
 
-func awaitFinishedSessions(t *testing.T, seriesRepo *db.SeriesRepository, wantFinished int) {
+func awaitFinishedSessions(t *testing.T, seriesRepo db.SeriesRepository, wantFinished int) {
 	t.Logf("awaiting %d finished sessions", wantFinished)
 	deadline := time.Second * 2
 	interval := time.Second / 10
@@ -145,8 +145,8 @@ func prepareProcessorTest(t *testing.T, workflows workflow.Service) (*SeriesProc
 	env, ctx := app.TestEnvironment(t)
 	client := controller.TestServer(t, env)
 	return &SeriesProcessor{
-		seriesRepo:      db.NewSeriesRepository(env.Spanner),
-		sessionRepo:     db.NewSessionRepository(env.Spanner),
+		seriesRepo:      db.NewSeriesRepository(env.DB),
+		sessionRepo:     db.NewSessionRepository(env.DB),
 		workflows:       workflows,
 		dbPollInterval:  time.Second / 10,
 		parallelWorkers: 2,
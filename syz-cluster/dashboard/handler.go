@@ -19,10 +19,10 @@ import (
 )
 
 type dashboardHandler struct {
-	seriesRepo      *db.SeriesRepository
-	sessionRepo     *db.SessionRepository
-	sessionTestRepo *db.SessionTestRepository
-	findingRepo     *db.FindingRepository
+	seriesRepo      db.SeriesRepository
+	sessionRepo     db.SessionRepository
+	sessionTestRepo db.SessionTestRepository
+	findingRepo     db.FindingRepository
 	blobStorage     blob.Storage
 	templates       map[string]*template.Template
 }
@@ -42,10 +42,10 @@ func newHandler(env *app.AppEnvironment) (*dashboardHandler, error) {
 	return &dashboardHandler{
 		templates:       perFile,
 		blobStorage:     env.BlobStorage,
-		seriesRepo:      db.NewSeriesRepository(env.Spanner),
-		sessionRepo:     db.NewSessionRepository(env.Spanner),
-		sessionTestRepo: db.NewSessionTestRepository(env.Spanner),
-		findingRepo:     db.NewFindingRepository(env.Spanner),
+		seriesRepo:      db.NewSeriesRepository(env.DB),
+		sessionRepo:     db.NewSessionRepository(env.DB),
+		sessionTestRepo: db.NewSessionTestRepository(env.DB),
+		findingRepo:     db.NewFindingRepository(env.DB),
 	}, nil
 }
 
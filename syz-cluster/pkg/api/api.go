@@ -8,8 +8,8 @@ import "time"
 type TriageResult struct {
 	// If set, ignore the patch series completely.
 	Skip *SkipRequest `json:"skip"`
-	// Fuzzing configuration to try (NULL if nothing).
-	Fuzz *FuzzConfig `json:"fuzz"`
+	// Fuzzing configurations to try (e.g. for different archs), all of them are run in the same session.
+	Fuzz []*FuzzConfig `json:"fuzz"`
 }
 
 type SkipRequest struct {
@@ -22,6 +22,9 @@ type FuzzConfig struct {
 	Patched   BuildRequest `json:"patched"`
 	Config    string       `json:"config"` // Refers to workflow/configs/{}.
 	CorpusURL string       `json:"corpus_url"`
+	// Tells apart the names of the tests of different fuzz configs, e.g. " [arm64]".
+	// Empty for the first config.
+	TestSuffix string `json:"test_suffix"`
 }
 
 // The triage step of the workflow will request these from controller.
@@ -84,10 +87,26 @@ type NewFinding struct {
 	SessionID string `json:"session_id"`
 	TestName  string `json:"test_name"`
 	Title     string `json:"title"`
+	Type      string `json:"type,omitempty"`
 	Report    []byte `json:"report"`
 	Log       []byte `json:"log"`
 }
 
+const (
+	// FindingNew is a new kernel crash, build or boot error.
+	FindingNew = ""
+	// FindingRegression is a known bug whose reproducer crashes the patched kernel, but not the base one.
+	FindingRegression = "regression"
+)
+
+// The finding status is set by the commands in the replies to the report.
+const (
+	FindingOpen    = ""
+	FindingInvalid = "invalid"
+	// The finding is a duplicate of the bug referenced by Finding.DupOf.
+	FindingDup = "dup"
+)
+
 type Series struct {
 	ID          string        `json:"id"` // Only included in the reply.
 	ExtID       string        `json:"ext_id"`
@@ -133,6 +152,9 @@ type SessionReport struct {
 
 type Finding struct {
 	Title        string    `json:"title"`
+	Type         string    `json:"type,omitempty"`
+	Status       string    `json:"status,omitempty"`
+	DupOf        string    `json:"dup_of,omitempty"`
 	Report       string    `json:"report"`
 	LogURL       string    `json:"log_url"`
 	Build        BuildInfo `json:"build"`
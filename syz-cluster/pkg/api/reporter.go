@@ -49,3 +49,33 @@ func (client ReporterClient) UpstreamReport(ctx context.Context, id string, req
 	_, err := postJSON[UpstreamReportReq, any](ctx, client.baseURL+"/reports/"+id+"/upstream", req)
 	return err
 }
+
+// InvalidateReport marks all findings of the report as invalid.
+func (client ReporterClient) InvalidateReport(ctx context.Context, id string) error {
+	_, err := postJSON[any, any](ctx, client.baseURL+"/reports/"+id+"/invalidate", nil)
+	return err
+}
+
+type DupReportReq struct {
+	DupOf string `json:"dup_of"` // The title of the original bug.
+}
+
+// DupReport marks all findings of the report as duplicates of another bug.
+func (client ReporterClient) DupReport(ctx context.Context, id string, req *DupReportReq) error {
+	_, err := postJSON[DupReportReq, any](ctx, client.baseURL+"/reports/"+id+"/dup", req)
+	return err
+}
+
+type IncomingEmailReq struct {
+	Raw []byte `json:"raw"` // The whole RFC 822 message.
+}
+
+type IncomingEmailResp struct {
+	// If not empty, it should be sent back as a reply to the email.
+	Reply string `json:"reply"`
+}
+
+// IncomingEmail passes a reply to a report to the reporter, which executes the #syz commands in it.
+func (client ReporterClient) IncomingEmail(ctx context.Context, req *IncomingEmailReq) (*IncomingEmailResp, error) {
+	return postJSON[IncomingEmailReq, IncomingEmailResp](ctx, client.baseURL+"/emails/incoming", req)
+}
@@ -16,21 +16,21 @@ import (
 )
 
 type AppEnvironment struct {
-	Spanner     *spanner.Client
+	DB          db.Client
 	BlobStorage blob.Storage
 }
 
 func Environment(ctx context.Context) (*AppEnvironment, error) {
-	spanner, err := DefaultSpanner(ctx)
+	database, err := DefaultDB(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to set up a Spanner client: %w", err)
+		return nil, fmt.Errorf("failed to set up the database: %w", err)
 	}
 	storage, err := DefaultStorage(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to set up the blob storage: %w", err)
 	}
 	return &AppEnvironment{
-		Spanner:     spanner,
+		DB:          database,
 		BlobStorage: storage,
 	}, nil
 }
@@ -38,7 +38,7 @@ func Environment(ctx context.Context) (*AppEnvironment, error) {
 func TestEnvironment(t *testing.T) (*AppEnvironment, context.Context) {
 	client, ctx := db.NewTransientDB(t)
 	return &AppEnvironment{
-		Spanner:     client,
+		DB:          client,
 		BlobStorage: blob.NewLocalStorage(t.TempDir()),
 	}, ctx
 }
@@ -51,6 +51,18 @@ func DefaultSpannerURI() (db.ParsedURI, error) {
 	return db.ParseURI(rawURI)
 }
 
+// DefaultDB returns the SQLite database if SQLITE_DATABASE_PATH is set, and Spanner otherwise.
+func DefaultDB(ctx context.Context) (db.Client, error) {
+	if file := os.Getenv("SQLITE_DATABASE_PATH"); file != "" {
+		return db.OpenSQLite(ctx, file)
+	}
+	client, err := DefaultSpanner(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up a Spanner client: %w", err)
+	}
+	return db.NewSpannerClient(client), nil
+}
+
 func DefaultSpanner(ctx context.Context) (*spanner.Client, error) {
 	uri, err := DefaultSpannerURI()
 	if err != nil {
@@ -70,6 +82,9 @@ func DefaultStorage(ctx context.Context) (blob.Storage, error) {
 }
 
 func DefaultClient() *api.Client {
-	// TODO: take it from some env variable.
-	return api.NewClient(`http://controller-service:8080`)
+	url := os.Getenv("CONTROLLER_URL")
+	if url == "" {
+		url = `http://controller-service:8080`
+	}
+	return api.NewClient(url)
 }
@@ -10,13 +10,33 @@ import (
 	"github.com/google/uuid"
 )
 
-type BuildRepository struct {
+type BuildRepository interface {
+	EntityRepository[Build]
+	Insert(ctx context.Context, build *Build) error
+	// LastBuiltTree returns the build with the latest CommitDate among those that match params.
+	LastBuiltTree(ctx context.Context, params *LastBuildParams) (*Build, error)
+}
+
+func NewBuildRepository(client Client) BuildRepository {
+	return client.newBuildRepository()
+}
+
+type LastBuildParams struct {
+	Arch       string
+	TreeName   string
+	ConfigName string
+	Status     string
+	Commit     string
+}
+
+type spannerBuildRepository struct {
 	client *spanner.Client
 	*genericEntityOps[Build, string]
 }
 
-func NewBuildRepository(client *spanner.Client) *BuildRepository {
-	return &BuildRepository{
+func (c *spannerClient) newBuildRepository() BuildRepository {
+	client := c.client
+	return &spannerBuildRepository{
 		client: client,
 		genericEntityOps: &genericEntityOps[Build, string]{
 			client:   client,
@@ -26,22 +46,14 @@ func NewBuildRepository(client *spanner.Client) *BuildRepository {
 	}
 }
 
-func (repo *BuildRepository) Insert(ctx context.Context, build *Build) error {
+func (repo *spannerBuildRepository) Insert(ctx context.Context, build *Build) error {
 	if build.ID == "" {
 		build.ID = uuid.NewString()
 	}
 	return repo.genericEntityOps.Insert(ctx, build)
 }
 
-type LastBuildParams struct {
-	Arch       string
-	TreeName   string
-	ConfigName string
-	Status     string
-	Commit     string
-}
-
-func (repo *BuildRepository) LastBuiltTree(ctx context.Context, params *LastBuildParams) (*Build, error) {
+func (repo *spannerBuildRepository) LastBuiltTree(ctx context.Context, params *LastBuildParams) (*Build, error) {
 	stmt := spanner.Statement{
 		SQL:    "SELECT * FROM `Builds` WHERE 1=1",
 		Params: map[string]interface{}{},
@@ -4,6 +4,7 @@
 package db
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -11,7 +12,10 @@ import (
 )
 
 func TestLastSuccessfulBuild(t *testing.T) {
-	client, ctx := NewTransientDB(t)
+	forEachBackend(t, testLastSuccessfulBuild)
+}
+
+func testLastSuccessfulBuild(t *testing.T, ctx context.Context, client Client) {
 	repo := NewBuildRepository(client)
 
 	params := &LastBuildParams{
@@ -0,0 +1,43 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+)
+
+// Client is the database the repositories operate on.
+// It's either Cloud Spanner (NewSpannerClient) or SQLite (OpenSQLite).
+type Client interface {
+	newSeriesRepository() SeriesRepository
+	newSessionRepository() SessionRepository
+	newSessionTestRepository() SessionTestRepository
+	newBuildRepository() BuildRepository
+	newFindingRepository() FindingRepository
+	newReportRepository() ReportRepository
+}
+
+// EntityRepository lists the operations available for all entities that are identified by an ID.
+type EntityRepository[EntityType any] interface {
+	// GetByID returns nil if there's no such entity.
+	GetByID(ctx context.Context, id string) (*EntityType, error)
+	// Update atomically applies cb to the entity. It returns ErrEntityNotFound if there's no such entity.
+	Update(ctx context.Context, id string, cb func(*EntityType) error) error
+}
+
+var ErrEntityNotFound = errors.New("entity not found")
+
+// NewTransientDB creates an empty database that only exists for the duration of the test.
+// Spanner is used if the emulator is configured, otherwise the database is a temporary SQLite file.
+func NewTransientDB(t *testing.T) (Client, context.Context) {
+	if os.Getenv("SPANNER_EMULATOR_BIN") != "" || os.Getenv("SPANNER_EMULATOR_HOST") != "" ||
+		os.Getenv("CI") != "" {
+		client, ctx := NewTransientSpanner(t)
+		return NewSpannerClient(client), ctx
+	}
+	return NewTransientSQLite(t)
+}
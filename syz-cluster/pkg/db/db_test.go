@@ -0,0 +1,49 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// forEachBackend runs the test against all supported databases.
+func forEachBackend(t *testing.T, test func(*testing.T, context.Context, Client)) {
+	t.Run("spanner", func(t *testing.T) {
+		client, ctx := NewTransientSpanner(t)
+		test(t, ctx, NewSpannerClient(client))
+	})
+	t.Run("sqlite", func(t *testing.T) {
+		client, ctx := NewTransientSQLite(t)
+		test(t, ctx, client)
+	})
+}
+
+func TestSpannerToSQLite(t *testing.T) {
+	assert.Equal(t, `CREATE TABLE Series (
+    ID TEXT NOT NULL,
+    Version INTEGER NOT NULL,
+    PublishedAt TEXT NOT NULL,
+    Moderation BOOLEAN,
+    Cc TEXT,
+    PRIMARY KEY (ID)
+);
+
+CREATE INDEX SeriesByPublishedAt ON Series (PublishedAt);
+`, spannerToSQLite(`CREATE TABLE Series (
+    ID STRING(36) NOT NULL,
+    Version INT64 NOT NULL,
+    PublishedAt TIMESTAMP NOT NULL,
+    Moderation BOOL,
+    Cc ARRAY<STRING(256)>,
+) PRIMARY KEY (ID);
+
+ALTER TABLE Series ADD CONSTRAINT FK_SeriesLatestSession FOREIGN KEY (LatestSessionID) REFERENCES Sessions (ID);
+CREATE INDEX SeriesByPublishedAt ON Series (PublishedAt);
+`))
+	assert.Equal(t, `ALTER TABLE Findings ADD COLUMN Type TEXT NOT NULL DEFAULT '';`,
+		spannerToSQLite(`ALTER TABLE Findings ADD COLUMN Type STRING(32) NOT NULL DEFAULT ('');`))
+}
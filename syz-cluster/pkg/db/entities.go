@@ -99,8 +99,11 @@ type Finding struct {
 	SessionID string `spanner:"SessionID"`
 	TestName  string `spanner:"TestName"`
 	Title     string `spanner:"Title"`
+	Type      string `spanner:"Type"`
 	ReportURI string `spanner:"ReportURI"`
 	LogURI    string `spanner:"LogURI"`
+	Status    string `spanner:"Status"`
+	DupOf     string `spanner:"DupOf"`
 }
 
 type SessionReport struct {
@@ -12,13 +12,27 @@ import (
 	"google.golang.org/api/iterator"
 )
 
-type FindingRepository struct {
+type FindingRepository interface {
+	EntityRepository[Finding]
+	// Save either adds the finding to the database or returns ErrFindingExists.
+	Save(ctx context.Context, finding *Finding) error
+	ListForSession(ctx context.Context, sessionID string) ([]*Finding, error)
+}
+
+func NewFindingRepository(client Client) FindingRepository {
+	return client.newFindingRepository()
+}
+
+var ErrFindingExists = errors.New("the finding already exists")
+
+type spannerFindingRepository struct {
 	client *spanner.Client
 	*genericEntityOps[Finding, string]
 }
 
-func NewFindingRepository(client *spanner.Client) *FindingRepository {
-	return &FindingRepository{
+func (c *spannerClient) newFindingRepository() FindingRepository {
+	client := c.client
+	return &spannerFindingRepository{
 		client: client,
 		genericEntityOps: &genericEntityOps[Finding, string]{
 			client:   client,
@@ -28,10 +42,7 @@ func NewFindingRepository(client *spanner.Client) *FindingRepository {
 	}
 }
 
-var ErrFindingExists = errors.New("the finding already exists")
-
-// Save either adds the finding to the database or returns ErrFindingExists.
-func (repo *FindingRepository) Save(ctx context.Context, finding *Finding) error {
+func (repo *spannerFindingRepository) Save(ctx context.Context, finding *Finding) error {
 	if finding.ID == "" {
 		finding.ID = uuid.NewString()
 	}
@@ -65,7 +76,7 @@ func (repo *FindingRepository) Save(ctx context.Context, finding *Finding) error
 }
 
 // nolint: dupl
-func (repo *FindingRepository) ListForSession(ctx context.Context, sessionID string) ([]*Finding, error) {
+func (repo *spannerFindingRepository) ListForSession(ctx context.Context, sessionID string) ([]*Finding, error) {
 	stmt := spanner.Statement{
 		SQL:    "SELECT * FROM `Findings` WHERE `SessionID` = @session ORDER BY `TestName`, `Title`",
 		Params: map[string]interface{}{"session": sessionID},
@@ -4,6 +4,7 @@
 package db
 
 import (
+	"context"
 	"testing"
 
 	"github.com/google/syzkaller/syz-cluster/pkg/api"
@@ -11,7 +12,10 @@ import (
 )
 
 func TestFindingRepo(t *testing.T) {
-	client, ctx := NewTransientDB(t)
+	forEachBackend(t, testFindingRepo)
+}
+
+func testFindingRepo(t *testing.T, ctx context.Context, client Client) {
 	sessionRepo := NewSessionRepository(client)
 	seriesRepo := NewSeriesRepository(client)
 	findingRepo := NewFindingRepository(client)
@@ -50,6 +54,7 @@ func TestFindingRepo(t *testing.T) {
 		{
 			TestName:  "second",
 			Title:     "A",
+			Type:      api.FindingRegression,
 			SessionID: session.ID,
 		},
 	}
@@ -67,4 +72,16 @@ func TestFindingRepo(t *testing.T) {
 	list, err := findingRepo.ListForSession(ctx, session.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, toInsert, list)
+
+	// Mark one as a duplicate.
+	err = findingRepo.Update(ctx, toInsert[1].ID, func(finding *Finding) error {
+		finding.Status = api.FindingDup
+		finding.DupOf = "C"
+		return nil
+	})
+	assert.NoError(t, err)
+	updated, err := findingRepo.GetByID(ctx, toInsert[1].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, api.FindingDup, updated.Status)
+	assert.Equal(t, "C", updated.DupOf)
 }
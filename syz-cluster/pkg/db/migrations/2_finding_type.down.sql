@@ -0,0 +1 @@
+ALTER TABLE Findings DROP COLUMN Type;
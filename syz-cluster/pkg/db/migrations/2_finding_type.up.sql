@@ -0,0 +1,2 @@
+-- Findings are not only new bugs, but also e.g. the known bugs that were reintroduced by the series.
+ALTER TABLE Findings ADD COLUMN Type STRING(32) NOT NULL DEFAULT ('');
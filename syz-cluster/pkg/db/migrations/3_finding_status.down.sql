@@ -0,0 +1,2 @@
+ALTER TABLE Findings DROP COLUMN DupOf;
+ALTER TABLE Findings DROP COLUMN Status;
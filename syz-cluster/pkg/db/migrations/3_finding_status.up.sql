@@ -0,0 +1,3 @@
+-- The state of the finding as set by the developers' replies to the report.
+ALTER TABLE Findings ADD COLUMN Status STRING(32) NOT NULL DEFAULT ('');
+ALTER TABLE Findings ADD COLUMN DupOf STRING(512) NOT NULL DEFAULT ('');
@@ -10,13 +10,24 @@ import (
 	"github.com/google/uuid"
 )
 
-type ReportRepository struct {
+type ReportRepository interface {
+	EntityRepository[SessionReport]
+	Insert(ctx context.Context, rep *SessionReport) error
+	ListNotReported(ctx context.Context, limit int) ([]*SessionReport, error)
+}
+
+func NewReportRepository(client Client) ReportRepository {
+	return client.newReportRepository()
+}
+
+type spannerReportRepository struct {
 	client *spanner.Client
 	*genericEntityOps[SessionReport, string]
 }
 
-func NewReportRepository(client *spanner.Client) *ReportRepository {
-	return &ReportRepository{
+func (c *spannerClient) newReportRepository() ReportRepository {
+	client := c.client
+	return &spannerReportRepository{
 		client: client,
 		genericEntityOps: &genericEntityOps[SessionReport, string]{
 			client:   client,
@@ -26,14 +37,14 @@ func NewReportRepository(client *spanner.Client) *ReportRepository {
 	}
 }
 
-func (repo *ReportRepository) Insert(ctx context.Context, rep *SessionReport) error {
+func (repo *spannerReportRepository) Insert(ctx context.Context, rep *SessionReport) error {
 	if rep.ID == "" {
 		rep.ID = uuid.NewString()
 	}
 	return repo.genericEntityOps.Insert(ctx, rep)
 }
 
-func (repo *ReportRepository) ListNotReported(ctx context.Context, limit int) ([]*SessionReport, error) {
+func (repo *spannerReportRepository) ListNotReported(ctx context.Context, limit int) ([]*SessionReport, error) {
 	stmt := spanner.Statement{
 		SQL:    "SELECT * FROM `SessionReports` WHERE `ReportedAt` IS NULL",
 		Params: map[string]interface{}{},
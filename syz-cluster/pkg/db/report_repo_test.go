@@ -4,6 +4,7 @@
 package db
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -13,7 +14,10 @@ import (
 )
 
 func TestReportRepository(t *testing.T) {
-	client, ctx := NewTransientDB(t)
+	forEachBackend(t, testReportRepository)
+}
+
+func testReportRepository(t *testing.T, ctx context.Context, client Client) {
 	sessionRepo := NewSessionRepository(client)
 	seriesRepo := NewSeriesRepository(client)
 	reportRepo := NewReportRepository(client)
@@ -51,7 +55,10 @@ func TestReportRepository(t *testing.T) {
 }
 
 func TestSessionsWithoutReports(t *testing.T) {
-	client, ctx := NewTransientDB(t)
+	forEachBackend(t, testSessionsWithoutReports)
+}
+
+func testSessionsWithoutReports(t *testing.T, ctx context.Context, client Client) {
 	sessionRepo := NewSessionRepository(client)
 	seriesRepo := NewSeriesRepository(client)
 	findingRepo := NewFindingRepository(client)
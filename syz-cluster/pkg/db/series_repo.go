@@ -16,13 +16,44 @@ import (
 	"google.golang.org/api/iterator"
 )
 
-type SeriesRepository struct {
+type SeriesRepository interface {
+	EntityRepository[Series]
+	PatchByID(ctx context.Context, id string) (*Patch, error)
+	GetByExtID(ctx context.Context, extID string) (*Series, error)
+	// Insert() checks whether there already exists a series with the same ExtID.
+	// Since Patch content is stored elsewhere, we do not demand it be filled out before calling Insert().
+	// Instead, Insert() obtains this data via a callback.
+	Insert(ctx context.Context, series *Series, queryPatches func() ([]*Patch, error)) error
+	Count(ctx context.Context) (int, error)
+	// ListLatest() returns the list of series ordered by the decreasing PublishedAt value.
+	ListLatest(ctx context.Context, filter SeriesFilter, maxPublishedAt time.Time,
+		limit int) ([]*SeriesWithSession, error)
+	ListPatches(ctx context.Context, series *Series) ([]*Patch, error)
+}
+
+func NewSeriesRepository(client Client) SeriesRepository {
+	return client.newSeriesRepository()
+}
+
+var ErrSeriesExists = errors.New("the series already exists")
+
+type SeriesWithSession struct {
+	Series  *Series
+	Session *Session
+}
+
+type SeriesFilter struct {
+	Cc string
+}
+
+type spannerSeriesRepository struct {
 	client *spanner.Client
 	*genericEntityOps[Series, string]
 }
 
-func NewSeriesRepository(client *spanner.Client) *SeriesRepository {
-	return &SeriesRepository{
+func (c *spannerClient) newSeriesRepository() SeriesRepository {
+	client := c.client
+	return &spannerSeriesRepository{
 		client: client,
 		genericEntityOps: &genericEntityOps[Series, string]{
 			client:   client,
@@ -34,7 +65,7 @@ func NewSeriesRepository(client *spanner.Client) *SeriesRepository {
 
 // TODO: move to SeriesPatchesRepository?
 // nolint:dupl
-func (repo *SeriesRepository) PatchByID(ctx context.Context, id string) (*Patch, error) {
+func (repo *spannerSeriesRepository) PatchByID(ctx context.Context, id string) (*Patch, error) {
 	stmt := spanner.Statement{
 		SQL:    "SELECT * FROM Patches WHERE ID=@id",
 		Params: map[string]interface{}{"id": id},
@@ -45,7 +76,7 @@ func (repo *SeriesRepository) PatchByID(ctx context.Context, id string) (*Patch,
 }
 
 // nolint:dupl
-func (repo *SeriesRepository) GetByExtID(ctx context.Context, extID string) (*Series, error) {
+func (repo *spannerSeriesRepository) GetByExtID(ctx context.Context, extID string) (*Series, error) {
 	stmt := spanner.Statement{
 		SQL:    "SELECT * FROM Series WHERE ExtID=@extID",
 		Params: map[string]interface{}{"extID": extID},
@@ -55,12 +86,7 @@ func (repo *SeriesRepository) GetByExtID(ctx context.Context, extID string) (*Se
 	return readOne[Series](iter)
 }
 
-var ErrSeriesExists = errors.New("the series already exists")
-
-// Insert() checks whether there already exists a series with the same ExtID.
-// Since Patch content is stored elsewhere, we do not demand it be filled out before calling Insert().
-// Instead, Insert() obtains this data via a callback.
-func (repo *SeriesRepository) Insert(ctx context.Context, series *Series,
+func (repo *spannerSeriesRepository) Insert(ctx context.Context, series *Series,
 	queryPatches func() ([]*Patch, error)) error {
 	var patches []*Patch
 	var patchesErr error
@@ -116,7 +142,7 @@ func (repo *SeriesRepository) Insert(ctx context.Context, series *Series,
 	return err
 }
 
-func (repo *SeriesRepository) Count(ctx context.Context) (int, error) {
+func (repo *spannerSeriesRepository) Count(ctx context.Context) (int, error) {
 	stmt := spanner.Statement{SQL: "SELECT COUNT(*) FROM `Series`"}
 	var count int64
 	err := repo.client.Single().Query(ctx, stmt).Do(func(row *spanner.Row) error {
@@ -125,17 +151,7 @@ func (repo *SeriesRepository) Count(ctx context.Context) (int, error) {
 	return int(count), err
 }
 
-type SeriesWithSession struct {
-	Series  *Series
-	Session *Session
-}
-
-type SeriesFilter struct {
-	Cc string
-}
-
-// ListLatest() returns the list of series ordered by the decreasing PublishedAt value.
-func (repo *SeriesRepository) ListLatest(ctx context.Context, filter SeriesFilter,
+func (repo *spannerSeriesRepository) ListLatest(ctx context.Context, filter SeriesFilter,
 	maxPublishedAt time.Time, limit int) ([]*SeriesWithSession, error) {
 	ro := repo.client.ReadOnlyTransaction()
 	defer ro.Close()
@@ -201,7 +217,7 @@ func (repo *SeriesRepository) ListLatest(ctx context.Context, filter SeriesFilte
 
 // golint sees too much similarity with SessionRepository's ListForSeries, but in reality there's not.
 // nolint:dupl
-func (repo *SeriesRepository) ListPatches(ctx context.Context, series *Series) ([]*Patch, error) {
+func (repo *spannerSeriesRepository) ListPatches(ctx context.Context, series *Series) ([]*Patch, error) {
 	stmt := spanner.Statement{
 		SQL: "SELECT * FROM `Patches` WHERE `SeriesID` = @seriesID ORDER BY `Seq`",
 		Params: map[string]interface{}{
@@ -4,6 +4,7 @@
 package db
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -11,7 +12,10 @@ import (
 )
 
 func TestSeriesRepositoryGet(t *testing.T) {
-	client, ctx := NewTransientDB(t)
+	forEachBackend(t, testSeriesRepositoryGet)
+}
+
+func testSeriesRepositoryGet(t *testing.T, ctx context.Context, client Client) {
 	repo := NewSeriesRepository(client)
 	series := &Series{
 		ExtID:       "ext-id",
@@ -51,7 +55,10 @@ func TestSeriesRepositoryGet(t *testing.T) {
 }
 
 func TestSeriesRepositoryList(t *testing.T) {
-	client, ctx := NewTransientDB(t)
+	forEachBackend(t, testSeriesRepositoryList)
+}
+
+func testSeriesRepositoryList(t *testing.T, ctx context.Context, client Client) {
 	repo := NewSeriesRepository(client)
 	for _, series := range []*Series{
 		{
@@ -115,7 +122,10 @@ func TestSeriesRepositoryList(t *testing.T) {
 }
 
 func TestSeriesRepositoryUpdate(t *testing.T) {
-	client, ctx := NewTransientDB(t)
+	forEachBackend(t, testSeriesRepositoryUpdate)
+}
+
+func testSeriesRepositoryUpdate(t *testing.T, ctx context.Context, client Client) {
 	repo := NewSeriesRepository(client)
 	series := &Series{
 		ExtID:       "ext-id",
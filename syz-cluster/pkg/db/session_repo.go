@@ -12,13 +12,42 @@ import (
 	"github.com/google/uuid"
 )
 
-type SessionRepository struct {
+type SessionRepository interface {
+	EntityRepository[Session]
+	// Start marks the session as started and makes it the latest session of the series.
+	Start(ctx context.Context, sessionID string) error
+	Insert(ctx context.Context, session *Session) error
+	ListRunning(ctx context.Context) ([]*Session, error)
+	// ListWaiting returns the not yet started sessions in the order of creation.
+	// The returned NextSession can be passed to the next call to continue the iteration.
+	ListWaiting(ctx context.Context, from *NextSession, limit int) ([]*Session, *NextSession, error)
+	ListForSeries(ctx context.Context, series *Series) ([]*Session, error)
+	// MissingReportList lists the session objects that are missing any SessionReport objects,
+	// but do have Findings.
+	// Once the conditions for creating a SessionRepor object become more complex, it will
+	// likely be not enough to have this simple method, but for now it should be fine.
+	MissingReportList(ctx context.Context, from time.Time, limit int) ([]*Session, error)
+}
+
+func NewSessionRepository(client Client) SessionRepository {
+	return client.newSessionRepository()
+}
+
+var ErrSessionAlreadyStarted = errors.New("the session already started")
+
+type NextSession struct {
+	id        string
+	createdAt time.Time
+}
+
+type spannerSessionRepository struct {
 	client *spanner.Client
 	*genericEntityOps[Session, string]
 }
 
-func NewSessionRepository(client *spanner.Client) *SessionRepository {
-	return &SessionRepository{
+func (c *spannerClient) newSessionRepository() SessionRepository {
+	client := c.client
+	return &spannerSessionRepository{
 		client: client,
 		genericEntityOps: &genericEntityOps[Session, string]{
 			client:   client,
@@ -28,9 +57,7 @@ func NewSessionRepository(client *spanner.Client) *SessionRepository {
 	}
 }
 
-var ErrSessionAlreadyStarted = errors.New("the session already started")
-
-func (repo *SessionRepository) Start(ctx context.Context, sessionID string) error {
+func (repo *spannerSessionRepository) Start(ctx context.Context, sessionID string) error {
 	_, err := repo.client.ReadWriteTransaction(ctx,
 		func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
 			iter := txn.Query(ctx, spanner.Statement{
@@ -69,14 +96,14 @@ func (repo *SessionRepository) Start(ctx context.Context, sessionID string) erro
 	return err
 }
 
-func (repo *SessionRepository) Insert(ctx context.Context, session *Session) error {
+func (repo *spannerSessionRepository) Insert(ctx context.Context, session *Session) error {
 	if session.ID == "" {
 		session.ID = uuid.NewString()
 	}
 	return repo.genericEntityOps.Insert(ctx, session)
 }
 
-func (repo *SessionRepository) ListRunning(ctx context.Context) ([]*Session, error) {
+func (repo *spannerSessionRepository) ListRunning(ctx context.Context) ([]*Session, error) {
 	stmt := spanner.Statement{SQL: "SELECT * FROM `Sessions` WHERE `StartedAt` IS NOT NULL " +
 		"AND `FinishedAt` IS NULL"}
 	iter := repo.client.Single().Query(ctx, stmt)
@@ -84,12 +111,7 @@ func (repo *SessionRepository) ListRunning(ctx context.Context) ([]*Session, err
 	return readEntities[Session](iter)
 }
 
-type NextSession struct {
-	id        string
-	createdAt time.Time
-}
-
-func (repo *SessionRepository) ListWaiting(ctx context.Context, from *NextSession,
+func (repo *spannerSessionRepository) ListWaiting(ctx context.Context, from *NextSession,
 	limit int) ([]*Session, *NextSession, error) {
 	stmt := spanner.Statement{
 		SQL:    "SELECT * FROM `Sessions` WHERE `StartedAt` IS NULL",
@@ -117,18 +139,14 @@ func (repo *SessionRepository) ListWaiting(ctx context.Context, from *NextSessio
 
 // golint sees too much similarity with SeriesRepository's ListPatches, but in reality there's not.
 // nolint:dupl
-func (repo *SessionRepository) ListForSeries(ctx context.Context, series *Series) ([]*Session, error) {
+func (repo *spannerSessionRepository) ListForSeries(ctx context.Context, series *Series) ([]*Session, error) {
 	return repo.readEntities(ctx, spanner.Statement{
 		SQL:    "SELECT * FROM `Sessions` WHERE `SeriesID` = @series ORDER BY CreatedAt DESC",
 		Params: map[string]interface{}{"series": series.ID},
 	})
 }
 
-// MissingReportList lists the session objects that are missing any SessionReport objects,
-// but do have Findings.
-// Once the conditions for creating a SessionRepor object become more complex, it will
-// likely be not enough to have this simple method, but for now it should be fine.
-func (repo *SessionRepository) MissingReportList(ctx context.Context, from time.Time, limit int) ([]*Session, error) {
+func (repo *spannerSessionRepository) MissingReportList(ctx context.Context, from time.Time, limit int) ([]*Session, error) {
 	stmt := spanner.Statement{
 		SQL: "SELECT * FROM Sessions WHERE FinishedAt IS NOT NULL " +
 			" AND NOT EXISTS (" +
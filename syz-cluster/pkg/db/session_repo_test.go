@@ -4,6 +4,7 @@
 package db
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -11,7 +12,10 @@ import (
 )
 
 func TestSeriesInsertSession(t *testing.T) {
-	client, ctx := NewTransientDB(t)
+	forEachBackend(t, testSeriesInsertSession)
+}
+
+func testSeriesInsertSession(t *testing.T, ctx context.Context, client Client) {
 	sessionRepo := NewSessionRepository(client)
 	seriesRepo := NewSeriesRepository(client)
 
@@ -49,7 +53,10 @@ func TestSeriesInsertSession(t *testing.T) {
 }
 
 func TestQueryWaitingSessions(t *testing.T) {
-	client, ctx := NewTransientDB(t)
+	forEachBackend(t, testQueryWaitingSessions)
+}
+
+func testQueryWaitingSessions(t *testing.T, ctx context.Context, client Client) {
 	sessionRepo := NewSessionRepository(client)
 	seriesRepo := NewSeriesRepository(client)
 
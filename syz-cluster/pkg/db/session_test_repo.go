@@ -10,17 +10,34 @@ import (
 	"google.golang.org/api/iterator"
 )
 
-type SessionTestRepository struct {
+type SessionTestRepository interface {
+	InsertOrUpdate(ctx context.Context, test *SessionTest) error
+	Get(ctx context.Context, sessionID, testName string) (*SessionTest, error)
+	// BySession returns the session tests together with the builds they refer to.
+	BySession(ctx context.Context, sessionID string) ([]*FullSessionTest, error)
+}
+
+func NewSessionTestRepository(client Client) SessionTestRepository {
+	return client.newSessionTestRepository()
+}
+
+type FullSessionTest struct {
+	*SessionTest
+	BaseBuild    *Build
+	PatchedBuild *Build
+}
+
+type spannerSessionTestRepository struct {
 	client *spanner.Client
 }
 
-func NewSessionTestRepository(client *spanner.Client) *SessionTestRepository {
-	return &SessionTestRepository{
-		client: client,
+func (c *spannerClient) newSessionTestRepository() SessionTestRepository {
+	return &spannerSessionTestRepository{
+		client: c.client,
 	}
 }
 
-func (repo *SessionTestRepository) InsertOrUpdate(ctx context.Context, test *SessionTest) error {
+func (repo *spannerSessionTestRepository) InsertOrUpdate(ctx context.Context, test *SessionTest) error {
 	_, err := repo.client.ReadWriteTransaction(ctx,
 		func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
 			// Check if the test already exists.
@@ -57,7 +74,7 @@ func (repo *SessionTestRepository) InsertOrUpdate(ctx context.Context, test *Ses
 	return err
 }
 
-func (repo *SessionTestRepository) Get(ctx context.Context, sessionID, testName string) (*SessionTest, error) {
+func (repo *spannerSessionTestRepository) Get(ctx context.Context, sessionID, testName string) (*SessionTest, error) {
 	stmt := spanner.Statement{
 		SQL: "SELECT * FROM `SessionTests` WHERE `SessionID` = @session AND `TestName` = @name",
 		Params: map[string]interface{}{
@@ -70,13 +87,7 @@ func (repo *SessionTestRepository) Get(ctx context.Context, sessionID, testName
 	return readOne[SessionTest](iter)
 }
 
-type FullSessionTest struct {
-	*SessionTest
-	BaseBuild    *Build
-	PatchedBuild *Build
-}
-
-func (repo *SessionTestRepository) BySession(ctx context.Context, sessionID string) ([]*FullSessionTest, error) {
+func (repo *spannerSessionTestRepository) BySession(ctx context.Context, sessionID string) ([]*FullSessionTest, error) {
 	stmt := spanner.Statement{
 		SQL: "SELECT * FROM `SessionTests` WHERE `SessionID` = @session" +
 			" ORDER BY `UpdatedAt`",
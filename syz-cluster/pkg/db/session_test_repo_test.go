@@ -4,6 +4,7 @@
 package db
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -13,7 +14,10 @@ import (
 )
 
 func TestSessionTestRepository(t *testing.T) {
-	client, ctx := NewTransientDB(t)
+	forEachBackend(t, testSessionTestRepository)
+}
+
+func testSessionTestRepository(t *testing.T, ctx context.Context, client Client) {
 	sessionRepo := NewSessionRepository(client)
 	seriesRepo := NewSeriesRepository(client)
 	testsRepo := NewSessionTestRepository(client)
@@ -7,7 +7,6 @@ import (
 	"bufio"
 	"context"
 	"embed"
-	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -124,7 +123,18 @@ func RunMigrations(ctx context.Context, uri string) error {
 	return m.Up()
 }
 
-func NewTransientDB(t *testing.T) (*spanner.Client, context.Context) {
+// NewSpannerClient returns a Client that stores the data in Cloud Spanner.
+func NewSpannerClient(client *spanner.Client) Client {
+	return &spannerClient{client: client}
+}
+
+type spannerClient struct {
+	client *spanner.Client
+}
+
+// NewTransientSpanner creates a temporary Spanner database in the emulator.
+// The test is skipped if no emulator is available.
+func NewTransientSpanner(t *testing.T) (*spanner.Client, context.Context) {
 	// If the environment contains the emulator binary, start it.
 	if bin := os.Getenv("SPANNER_EMULATOR_BIN"); bin != "" {
 		host := spannerTestWrapper(t, bin)
@@ -283,8 +293,6 @@ func (g *genericEntityOps[EntityType, KeyType]) GetByID(ctx context.Context, key
 	return readOne[EntityType](iter)
 }
 
-var ErrEntityNotFound = errors.New("entity not found")
-
 func (g *genericEntityOps[EntityType, KeyType]) Update(ctx context.Context, key KeyType,
 	cb func(*EntityType) error) error {
 	_, err := g.client.ReadWriteTransaction(ctx,
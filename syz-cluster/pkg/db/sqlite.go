@@ -0,0 +1,387 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	_ "modernc.org/sqlite"
+)
+
+// OpenSQLite opens (and creates, if necessary) an SQLite database and brings its schema up to date.
+// It's meant for small deployments and tests, where running Spanner would be an overkill.
+func OpenSQLite(ctx context.Context, file string) (Client, error) {
+	db, err := sql.Open("sqlite", "file:"+file+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(10000)")
+	if err != nil {
+		return nil, err
+	}
+	// SQLite does not support concurrent writers anyway, and a single connection
+	// saves us from the "database is locked" errors.
+	db.SetMaxOpenConns(1)
+	if err := runSQLiteMigrations(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+	return &sqliteClient{db: db}, nil
+}
+
+type sqliteClient struct {
+	db *sql.DB
+}
+
+// NewTransientSQLite creates an SQLite database in a temporary directory.
+func NewTransientSQLite(t *testing.T) (Client, context.Context) {
+	ctx := context.Background()
+	client, err := OpenSQLite(ctx, filepath.Join(t.TempDir(), "db.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		client.(*sqliteClient).db.Close()
+	})
+	return client, ctx
+}
+
+// The migration scripts are written for Spanner, but the subset of DDL we use is easy
+// to convert to SQLite, so that there's only one source of truth for the schema.
+// The only loss is ALTER TABLE ... ADD CONSTRAINT, which SQLite does not support.
+var sqliteDDLReplacements = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`(?m)^ALTER TABLE [^;]* ADD CONSTRAINT [^;]*;\n?`), ""},
+	// SQLite does not accept expressions as the defaults of the added columns, only literals.
+	{regexp.MustCompile(`DEFAULT \(('[^']*'|\d+)\)`), "DEFAULT $1"},
+	// Arrays are stored as JSON lists.
+	{regexp.MustCompile(`ARRAY<[^>]*>`), "TEXT"},
+	{regexp.MustCompile(`STRING\(\d+\)`), "TEXT"},
+	// Timestamps are stored as sortable strings (see sqliteTimeFormat).
+	{regexp.MustCompile(`\bTIMESTAMP\b`), "TEXT"},
+	{regexp.MustCompile(`\bINT64\b`), "INTEGER"},
+	{regexp.MustCompile(`\bBOOL\b`), "BOOLEAN"},
+	{regexp.MustCompile(`,\s*\)\s*PRIMARY KEY\s*\(([^)]*)\)`), ",\n    PRIMARY KEY ($1)\n)"},
+}
+
+func spannerToSQLite(ddl string) string {
+	for _, r := range sqliteDDLReplacements {
+		ddl = r.re.ReplaceAllString(ddl, r.repl)
+	}
+	return ddl
+}
+
+func runSQLiteMigrations(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS SchemaMigrations (Version INTEGER PRIMARY KEY)")
+	if err != nil {
+		return err
+	}
+	files, err := fs.Glob(migrationsFs, "migrations/*.up.sql")
+	if err != nil {
+		return err
+	}
+	versions := map[int]string{}
+	var sorted []int
+	for _, file := range files {
+		version, err := strconv.Atoi(strings.Split(filepath.Base(file), "_")[0])
+		if err != nil {
+			return fmt.Errorf("invalid migration file name %q", file)
+		}
+		versions[version] = file
+		sorted = append(sorted, version)
+	}
+	sort.Ints(sorted)
+	for _, version := range sorted {
+		data, err := fs.ReadFile(migrationsFs, versions[version])
+		if err != nil {
+			return err
+		}
+		err = sqliteTransaction(ctx, db, func(tx *sql.Tx) error {
+			var applied int
+			err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM SchemaMigrations WHERE Version = @version",
+				sql.Named("version", version)).Scan(&applied)
+			if err != nil || applied != 0 {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, spannerToSQLite(string(data))); err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, "INSERT INTO SchemaMigrations (Version) VALUES (@version)",
+				sql.Named("version", version))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %v: %w", versions[version], err)
+		}
+	}
+	return nil
+}
+
+func sqliteTransaction(ctx context.Context, db *sql.DB, cb func(*sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := cb(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// sqliteQuerier is either *sql.DB or *sql.Tx.
+type sqliteQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// The format is fixed-width, so that the timestamps can be compared as strings.
+const sqliteTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// sqliteArg converts the value to the form it's stored in.
+func sqliteArg(name string, val any) sql.NamedArg {
+	switch v := val.(type) {
+	case time.Time:
+		val = v.UTC().Format(sqliteTimeFormat)
+	case spanner.NullTime:
+		val = nil
+		if v.Valid {
+			val = v.Time.UTC().Format(sqliteTimeFormat)
+		}
+	case spanner.NullString:
+		val = nil
+		if v.Valid {
+			val = v.StringVal
+		}
+	case []string:
+		val = nil
+		if v != nil {
+			data, _ := json.Marshal(v)
+			val = string(data)
+		}
+	}
+	return sql.Named(name, val)
+}
+
+// sqliteColumn scans a column value into the struct field.
+type sqliteColumn struct {
+	field reflect.Value
+}
+
+func (col sqliteColumn) Scan(src any) error {
+	if b, ok := src.([]byte); ok {
+		src = string(b)
+	}
+	switch ptr := col.field.Addr().Interface().(type) {
+	case *time.Time:
+		t, err := parseSQLiteTime(src)
+		*ptr = t
+		return err
+	case *spanner.NullTime:
+		if src == nil {
+			*ptr = spanner.NullTime{}
+			return nil
+		}
+		t, err := parseSQLiteTime(src)
+		*ptr = spanner.NullTime{Time: t, Valid: true}
+		return err
+	case *spanner.NullString:
+		return ptr.Scan(src)
+	case *[]string:
+		*ptr = nil
+		if src == nil {
+			return nil
+		}
+		str, ok := src.(string)
+		if !ok {
+			return fmt.Errorf("unexpected array value %#v", src)
+		}
+		return json.Unmarshal([]byte(str), ptr)
+	}
+	if src == nil {
+		col.field.SetZero()
+		return nil
+	}
+	switch col.field.Kind() {
+	case reflect.Bool:
+		switch v := src.(type) {
+		case bool:
+			col.field.SetBool(v)
+			return nil
+		case int64:
+			col.field.SetBool(v != 0)
+			return nil
+		}
+	case reflect.Int64:
+		if v, ok := src.(int64); ok {
+			col.field.SetInt(v)
+			return nil
+		}
+	case reflect.String:
+		if v, ok := src.(string); ok {
+			col.field.SetString(v)
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot scan %#v into %v", src, col.field.Type())
+}
+
+func parseSQLiteTime(src any) (time.Time, error) {
+	switch v := src.(type) {
+	case time.Time:
+		return v.UTC(), nil
+	case string:
+		return time.Parse(sqliteTimeFormat, v)
+	}
+	return time.Time{}, fmt.Errorf("unexpected timestamp value %#v", src)
+}
+
+type sqliteField struct {
+	name  string
+	value reflect.Value
+}
+
+// sqliteFields returns the struct fields in the order of declaration.
+// The column names are taken from the spanner tags, so the same structures work for both backends.
+func sqliteFields(obj any) []sqliteField {
+	val := reflect.ValueOf(obj).Elem()
+	var ret []sqliteField
+	for i := 0; i < val.NumField(); i++ {
+		name := val.Type().Field(i).Tag.Get("spanner")
+		if name == "" || name == "-" {
+			continue
+		}
+		ret = append(ret, sqliteField{name: name, value: val.Field(i)})
+	}
+	return ret
+}
+
+func sqliteReadEntities[T any](ctx context.Context, q sqliteQuerier, query string, args ...any) ([]*T, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var ret []*T
+	for rows.Next() {
+		obj := new(T)
+		fields := map[string]reflect.Value{}
+		for _, field := range sqliteFields(obj) {
+			fields[field.name] = field.value
+		}
+		dest := make([]any, len(columns))
+		for i, name := range columns {
+			if field, ok := fields[name]; ok {
+				dest[i] = sqliteColumn{field}
+			} else {
+				dest[i] = new(any)
+			}
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		ret = append(ret, obj)
+	}
+	return ret, rows.Err()
+}
+
+func sqliteReadOne[T any](ctx context.Context, q sqliteQuerier, query string, args ...any) (*T, error) {
+	list, err := sqliteReadEntities[T](ctx, q, query+" LIMIT 1", args...)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+func sqliteInsert(ctx context.Context, q sqliteQuerier, table string, obj any) error {
+	var names, params []string
+	var args []any
+	for _, field := range sqliteFields(obj) {
+		names = append(names, "`"+field.name+"`")
+		params = append(params, "@"+field.name)
+		args = append(args, sqliteArg(field.name, field.value.Interface()))
+	}
+	_, err := q.ExecContext(ctx, fmt.Sprintf("INSERT INTO `%s` (%s) VALUES (%s)",
+		table, strings.Join(names, ", "), strings.Join(params, ", ")), args...)
+	return err
+}
+
+// sqliteUpdate overwrites all columns of the row identified by the key columns.
+func sqliteUpdate(ctx context.Context, q sqliteQuerier, table string, obj any, keys ...string) error {
+	var set, where []string
+	var args []any
+	for _, field := range sqliteFields(obj) {
+		cond := "`" + field.name + "` = @" + field.name
+		if slices.Contains(keys, field.name) {
+			where = append(where, cond)
+		} else {
+			set = append(set, cond)
+		}
+		args = append(args, sqliteArg(field.name, field.value.Interface()))
+	}
+	_, err := q.ExecContext(ctx, fmt.Sprintf("UPDATE `%s` SET %s WHERE %s",
+		table, strings.Join(set, ", "), strings.Join(where, " AND ")), args...)
+	return err
+}
+
+func addSQLiteLimit(query string, args []any, limit int) (string, []any) {
+	if limit > 0 {
+		query += " LIMIT @limit"
+		args = append(args, sqliteArg("limit", limit))
+	}
+	return query, args
+}
+
+type sqliteEntityOps[EntityType any] struct {
+	db       *sql.DB
+	keyField string
+	table    string
+}
+
+func (g *sqliteEntityOps[EntityType]) GetByID(ctx context.Context, key string) (*EntityType, error) {
+	return sqliteReadOne[EntityType](ctx, g.db, "SELECT * FROM `"+g.table+"` WHERE `"+g.keyField+"` = @key",
+		sqliteArg("key", key))
+}
+
+func (g *sqliteEntityOps[EntityType]) Update(ctx context.Context, key string, cb func(*EntityType) error) error {
+	return sqliteTransaction(ctx, g.db, func(tx *sql.Tx) error {
+		entity, err := sqliteReadOne[EntityType](ctx, tx,
+			"SELECT * FROM `"+g.table+"` WHERE `"+g.keyField+"` = @key", sqliteArg("key", key))
+		if err != nil {
+			return err
+		}
+		if entity == nil {
+			return ErrEntityNotFound
+		}
+		if err := cb(entity); err != nil {
+			return err
+		}
+		return sqliteUpdate(ctx, tx, g.table, entity, g.keyField)
+	})
+}
+
+func (g *sqliteEntityOps[EntityType]) Insert(ctx context.Context, obj *EntityType) error {
+	return sqliteInsert(ctx, g.db, g.table, obj)
+}
+
+func (g *sqliteEntityOps[EntityType]) readEntities(ctx context.Context, query string, args ...any) (
+	[]*EntityType, error) {
+	return sqliteReadEntities[EntityType](ctx, g.db, query, args...)
+}
@@ -0,0 +1,427 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The repositories below mirror the Spanner ones, see the interface definitions for the comments.
+// The queries are mostly the same, except for the work with arrays, which are stored as JSON.
+
+type sqliteSeriesRepository struct {
+	db *sql.DB
+	*sqliteEntityOps[Series]
+}
+
+func (c *sqliteClient) newSeriesRepository() SeriesRepository {
+	return &sqliteSeriesRepository{
+		db: c.db,
+		sqliteEntityOps: &sqliteEntityOps[Series]{
+			db:       c.db,
+			keyField: "ID",
+			table:    "Series",
+		},
+	}
+}
+
+func (repo *sqliteSeriesRepository) PatchByID(ctx context.Context, id string) (*Patch, error) {
+	return sqliteReadOne[Patch](ctx, repo.db, "SELECT * FROM `Patches` WHERE `ID` = @id",
+		sqliteArg("id", id))
+}
+
+func (repo *sqliteSeriesRepository) GetByExtID(ctx context.Context, extID string) (*Series, error) {
+	return sqliteReadOne[Series](ctx, repo.db, "SELECT * FROM `Series` WHERE `ExtID` = @extID",
+		sqliteArg("extID", extID))
+}
+
+func (repo *sqliteSeriesRepository) Insert(ctx context.Context, series *Series,
+	queryPatches func() ([]*Patch, error)) error {
+	if series.ID == "" {
+		series.ID = uuid.NewString()
+	}
+	return sqliteTransaction(ctx, repo.db, func(tx *sql.Tx) error {
+		existing, err := sqliteReadOne[Series](ctx, tx, "SELECT * FROM `Series` WHERE `ExtID` = @extID",
+			sqliteArg("extID", series.ExtID))
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return ErrSeriesExists
+		}
+		var patches []*Patch
+		if queryPatches != nil {
+			patches, err = queryPatches()
+			if err != nil {
+				return err
+			}
+		}
+		if err := sqliteInsert(ctx, tx, "Series", series); err != nil {
+			return err
+		}
+		for _, patch := range patches {
+			patch.ID = uuid.NewString()
+			patch.SeriesID = series.ID
+			if err := sqliteInsert(ctx, tx, "Patches", patch); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (repo *sqliteSeriesRepository) Count(ctx context.Context) (int, error) {
+	var count int
+	err := repo.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM `Series`").Scan(&count)
+	return count, err
+}
+
+func (repo *sqliteSeriesRepository) ListLatest(ctx context.Context, filter SeriesFilter,
+	maxPublishedAt time.Time, limit int) ([]*SeriesWithSession, error) {
+	query := "SELECT * FROM `Series` WHERE 1=1"
+	var args []any
+	if !maxPublishedAt.IsZero() {
+		query += " AND `PublishedAt` < @toTime"
+		args = append(args, sqliteArg("toTime", maxPublishedAt))
+	}
+	if filter.Cc != "" {
+		query += " AND EXISTS (SELECT 1 FROM json_each(`Series`.`Cc`) WHERE json_each.value = @cc)"
+		args = append(args, sqliteArg("cc", filter.Cc))
+	}
+	query += " ORDER BY `PublishedAt` DESC"
+	query, args = addSQLiteLimit(query, args, limit)
+	var ret []*SeriesWithSession
+	err := sqliteTransaction(ctx, repo.db, func(tx *sql.Tx) error {
+		ret = nil
+		seriesList, err := sqliteReadEntities[Series](ctx, tx, query, args...)
+		if err != nil {
+			return err
+		}
+		var keys []string
+		idToSeries := map[string]*SeriesWithSession{}
+		for _, series := range seriesList {
+			if !series.LatestSessionID.IsNull() {
+				keys = append(keys, series.LatestSessionID.StringVal)
+			}
+			obj := &SeriesWithSession{Series: series}
+			ret = append(ret, obj)
+			idToSeries[series.ID] = obj
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+		sessions, err := sqliteReadEntities[Session](ctx, tx,
+			"SELECT * FROM `Sessions` WHERE `ID` IN (SELECT value FROM json_each(@ids))",
+			sqliteArg("ids", keys))
+		if err != nil {
+			return err
+		}
+		for _, session := range sessions {
+			if obj := idToSeries[session.SeriesID]; obj != nil {
+				obj.Session = session
+			}
+		}
+		return nil
+	})
+	return ret, err
+}
+
+func (repo *sqliteSeriesRepository) ListPatches(ctx context.Context, series *Series) ([]*Patch, error) {
+	return sqliteReadEntities[Patch](ctx, repo.db,
+		"SELECT * FROM `Patches` WHERE `SeriesID` = @seriesID ORDER BY `Seq`",
+		sqliteArg("seriesID", series.ID))
+}
+
+type sqliteSessionRepository struct {
+	db *sql.DB
+	*sqliteEntityOps[Session]
+}
+
+func (c *sqliteClient) newSessionRepository() SessionRepository {
+	return &sqliteSessionRepository{
+		db: c.db,
+		sqliteEntityOps: &sqliteEntityOps[Session]{
+			db:       c.db,
+			keyField: "ID",
+			table:    "Sessions",
+		},
+	}
+}
+
+func (repo *sqliteSessionRepository) Start(ctx context.Context, sessionID string) error {
+	return sqliteTransaction(ctx, repo.db, func(tx *sql.Tx) error {
+		session, err := sqliteReadOne[Session](ctx, tx, "SELECT * FROM `Sessions` WHERE `ID` = @id",
+			sqliteArg("id", sessionID))
+		if err != nil {
+			return err
+		}
+		if session == nil {
+			return ErrEntityNotFound
+		}
+		if !session.StartedAt.IsNull() {
+			return ErrSessionAlreadyStarted
+		}
+		session.SetStartedAt(time.Now())
+		if err := sqliteUpdate(ctx, tx, "Sessions", session, "ID"); err != nil {
+			return err
+		}
+		series, err := sqliteReadOne[Series](ctx, tx, "SELECT * FROM `Series` WHERE `ID` = @id",
+			sqliteArg("id", session.SeriesID))
+		if err != nil {
+			return err
+		}
+		if series == nil {
+			return ErrEntityNotFound
+		}
+		series.SetLatestSession(session)
+		return sqliteUpdate(ctx, tx, "Series", series, "ID")
+	})
+}
+
+func (repo *sqliteSessionRepository) Insert(ctx context.Context, session *Session) error {
+	if session.ID == "" {
+		session.ID = uuid.NewString()
+	}
+	return repo.sqliteEntityOps.Insert(ctx, session)
+}
+
+func (repo *sqliteSessionRepository) ListRunning(ctx context.Context) ([]*Session, error) {
+	return repo.readEntities(ctx, "SELECT * FROM `Sessions` WHERE `StartedAt` IS NOT NULL "+
+		"AND `FinishedAt` IS NULL")
+}
+
+func (repo *sqliteSessionRepository) ListWaiting(ctx context.Context, from *NextSession,
+	limit int) ([]*Session, *NextSession, error) {
+	query := "SELECT * FROM `Sessions` WHERE `StartedAt` IS NULL"
+	var args []any
+	if from != nil {
+		query += " AND ((`CreatedAt` > @from) OR (`CreatedAt` = @from AND `ID` > @id))"
+		args = append(args, sqliteArg("from", from.createdAt), sqliteArg("id", from.id))
+	}
+	query += " ORDER BY `CreatedAt`, `ID`"
+	query, args = addSQLiteLimit(query, args, limit)
+	list, err := repo.readEntities(ctx, query, args...)
+
+	var next *NextSession
+	if err == nil && len(list) > 0 {
+		last := list[len(list)-1]
+		next = &NextSession{
+			id:        last.ID,
+			createdAt: last.CreatedAt,
+		}
+	}
+	return list, next, err
+}
+
+func (repo *sqliteSessionRepository) ListForSeries(ctx context.Context, series *Series) ([]*Session, error) {
+	return repo.readEntities(ctx, "SELECT * FROM `Sessions` WHERE `SeriesID` = @series ORDER BY `CreatedAt` DESC",
+		sqliteArg("series", series.ID))
+}
+
+func (repo *sqliteSessionRepository) MissingReportList(ctx context.Context, from time.Time,
+	limit int) ([]*Session, error) {
+	query := "SELECT * FROM `Sessions` WHERE `FinishedAt` IS NOT NULL" +
+		" AND NOT EXISTS (" +
+		"SELECT 1 FROM `SessionReports` WHERE `SessionReports`.`SessionID` = `Sessions`.`ID`" +
+		") AND EXISTS (" +
+		"SELECT 1 FROM `Findings` WHERE `Findings`.`SessionID` = `Sessions`.`ID`)"
+	var args []any
+	if !from.IsZero() {
+		query += " AND `FinishedAt` > @from"
+		args = append(args, sqliteArg("from", from))
+	}
+	query += " ORDER BY `FinishedAt`"
+	query, args = addSQLiteLimit(query, args, limit)
+	return repo.readEntities(ctx, query, args...)
+}
+
+type sqliteSessionTestRepository struct {
+	db *sql.DB
+}
+
+func (c *sqliteClient) newSessionTestRepository() SessionTestRepository {
+	return &sqliteSessionTestRepository{
+		db: c.db,
+	}
+}
+
+func (repo *sqliteSessionTestRepository) InsertOrUpdate(ctx context.Context, test *SessionTest) error {
+	return sqliteTransaction(ctx, repo.db, func(tx *sql.Tx) error {
+		existing, err := sqliteReadOne[SessionTest](ctx, tx,
+			"SELECT * FROM `SessionTests` WHERE `SessionID` = @sessionID AND `TestName` = @testName",
+			sqliteArg("sessionID", test.SessionID), sqliteArg("testName", test.TestName))
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return sqliteUpdate(ctx, tx, "SessionTests", test, "SessionID", "TestName")
+		}
+		return sqliteInsert(ctx, tx, "SessionTests", test)
+	})
+}
+
+func (repo *sqliteSessionTestRepository) Get(ctx context.Context, sessionID, testName string) (*SessionTest, error) {
+	return sqliteReadOne[SessionTest](ctx, repo.db,
+		"SELECT * FROM `SessionTests` WHERE `SessionID` = @session AND `TestName` = @name",
+		sqliteArg("session", sessionID), sqliteArg("name", testName))
+}
+
+func (repo *sqliteSessionTestRepository) BySession(ctx context.Context, sessionID string) (
+	[]*FullSessionTest, error) {
+	list, err := sqliteReadEntities[SessionTest](ctx, repo.db,
+		"SELECT * FROM `SessionTests` WHERE `SessionID` = @session ORDER BY `UpdatedAt`",
+		sqliteArg("session", sessionID))
+	if err != nil {
+		return nil, err
+	}
+	var ret []*FullSessionTest
+	needBuilds := map[string][]**Build{}
+	for _, obj := range list {
+		full := &FullSessionTest{SessionTest: obj}
+		ret = append(ret, full)
+		if id := obj.BaseBuildID.StringVal; !obj.BaseBuildID.IsNull() {
+			needBuilds[id] = append(needBuilds[id], &full.BaseBuild)
+		}
+		if id := obj.PatchedBuildID.StringVal; !obj.PatchedBuildID.IsNull() {
+			needBuilds[id] = append(needBuilds[id], &full.PatchedBuild)
+		}
+	}
+	if len(needBuilds) > 0 {
+		var keys []string
+		for key := range needBuilds {
+			keys = append(keys, key)
+		}
+		builds, err := sqliteReadEntities[Build](ctx, repo.db,
+			"SELECT * FROM `Builds` WHERE `ID` IN (SELECT value FROM json_each(@ids))",
+			sqliteArg("ids", keys))
+		if err != nil {
+			return nil, err
+		}
+		for _, build := range builds {
+			for _, patch := range needBuilds[build.ID] {
+				*patch = build
+			}
+		}
+	}
+	return ret, nil
+}
+
+type sqliteBuildRepository struct {
+	db *sql.DB
+	*sqliteEntityOps[Build]
+}
+
+func (c *sqliteClient) newBuildRepository() BuildRepository {
+	return &sqliteBuildRepository{
+		db: c.db,
+		sqliteEntityOps: &sqliteEntityOps[Build]{
+			db:       c.db,
+			keyField: "ID",
+			table:    "Builds",
+		},
+	}
+}
+
+func (repo *sqliteBuildRepository) Insert(ctx context.Context, build *Build) error {
+	if build.ID == "" {
+		build.ID = uuid.NewString()
+	}
+	return repo.sqliteEntityOps.Insert(ctx, build)
+}
+
+func (repo *sqliteBuildRepository) LastBuiltTree(ctx context.Context, params *LastBuildParams) (*Build, error) {
+	query := "SELECT * FROM `Builds` WHERE 1=1"
+	var args []any
+	for _, cond := range []struct {
+		column string
+		value  string
+	}{
+		{"Arch", params.Arch},
+		{"TreeName", params.TreeName},
+		{"ConfigName", params.ConfigName},
+		{"Status", params.Status},
+		{"CommitHash", params.Commit},
+	} {
+		if cond.value != "" {
+			query += " AND `" + cond.column + "` = @" + cond.column
+			args = append(args, sqliteArg(cond.column, cond.value))
+		}
+	}
+	query += " ORDER BY `CommitDate` DESC"
+	return sqliteReadOne[Build](ctx, repo.db, query, args...)
+}
+
+type sqliteFindingRepository struct {
+	db *sql.DB
+	*sqliteEntityOps[Finding]
+}
+
+func (c *sqliteClient) newFindingRepository() FindingRepository {
+	return &sqliteFindingRepository{
+		db: c.db,
+		sqliteEntityOps: &sqliteEntityOps[Finding]{
+			db:       c.db,
+			keyField: "ID",
+			table:    "Findings",
+		},
+	}
+}
+
+func (repo *sqliteFindingRepository) Save(ctx context.Context, finding *Finding) error {
+	if finding.ID == "" {
+		finding.ID = uuid.NewString()
+	}
+	return sqliteTransaction(ctx, repo.db, func(tx *sql.Tx) error {
+		existing, err := sqliteReadOne[Finding](ctx, tx, "SELECT * FROM `Findings` WHERE `SessionID` = @sessionID"+
+			" AND `TestName` = @testName AND `Title` = @title",
+			sqliteArg("sessionID", finding.SessionID),
+			sqliteArg("testName", finding.TestName),
+			sqliteArg("title", finding.Title))
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return ErrFindingExists
+		}
+		return sqliteInsert(ctx, tx, "Findings", finding)
+	})
+}
+
+func (repo *sqliteFindingRepository) ListForSession(ctx context.Context, sessionID string) ([]*Finding, error) {
+	return repo.readEntities(ctx,
+		"SELECT * FROM `Findings` WHERE `SessionID` = @session ORDER BY `TestName`, `Title`",
+		sqliteArg("session", sessionID))
+}
+
+type sqliteReportRepository struct {
+	db *sql.DB
+	*sqliteEntityOps[SessionReport]
+}
+
+func (c *sqliteClient) newReportRepository() ReportRepository {
+	return &sqliteReportRepository{
+		db: c.db,
+		sqliteEntityOps: &sqliteEntityOps[SessionReport]{
+			db:       c.db,
+			keyField: "ID",
+			table:    "SessionReports",
+		},
+	}
+}
+
+func (repo *sqliteReportRepository) Insert(ctx context.Context, rep *SessionReport) error {
+	if rep.ID == "" {
+		rep.ID = uuid.NewString()
+	}
+	return repo.sqliteEntityOps.Insert(ctx, rep)
+}
+
+func (repo *sqliteReportRepository) ListNotReported(ctx context.Context, limit int) ([]*SessionReport, error) {
+	query, args := addSQLiteLimit("SELECT * FROM `SessionReports` WHERE `ReportedAt` IS NULL", nil, limit)
+	return repo.readEntities(ctx, query, args...)
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package fuzzconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/google/syzkaller/pkg/config"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// To reduce duplication, patched configs are stored as a delta to their corresponding base.cfg version.
+// Load performs all the necessary merging and parsing and returns two configs.
+// If workdir is not empty, the configs are also completed and are ready to use.
+func Load(configFolder, configName, workdir string) (*mgrconfig.Config, *mgrconfig.Config, error) {
+	var baseRaw, deltaRaw json.RawMessage
+	err := config.LoadFile(filepath.Join(configFolder, configName, "base.cfg"), &baseRaw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the base config: %w", err)
+	}
+	err = config.LoadFile(filepath.Join(configFolder, configName, "patched.cfg"), &deltaRaw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the patched config: %w", err)
+	}
+	patchedRaw, err := config.MergeJSONs(baseRaw, deltaRaw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to merge the configs: %w", err)
+	}
+	base, err := mgrconfig.LoadPartialData(baseRaw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the base config: %w", err)
+	}
+	patched, err := mgrconfig.LoadPartialData(patchedRaw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the patched config: %w", err)
+	}
+	if workdir != "" {
+		base.Workdir = filepath.Join(workdir, "base")
+		osutil.MkdirAll(base.Workdir)
+		patched.Workdir = filepath.Join(workdir, "patched")
+		osutil.MkdirAll(patched.Workdir)
+		err = mgrconfig.Complete(base)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to complete the base config: %w", err)
+		}
+		err = mgrconfig.Complete(patched)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to complete the patched config: %w", err)
+		}
+	}
+	return base, patched, nil
+}
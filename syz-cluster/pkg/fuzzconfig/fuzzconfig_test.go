@@ -1,7 +1,7 @@
 // Copyright 2025 syzkaller project authors. All rights reserved.
 // Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
 
-package main
+package fuzzconfig
 
 import (
 	"io/fs"
@@ -10,7 +10,7 @@ import (
 )
 
 func TestConfigLoad(t *testing.T) {
-	root := filepath.Join("..", "configs")
+	root := filepath.Join("..", "..", "workflow", "configs")
 	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -19,7 +19,7 @@ func TestConfigLoad(t *testing.T) {
 			return nil
 		}
 		t.Logf("checking %v", path)
-		_, _, err = loadConfigs(root, d.Name(), false)
+		_, _, err = Load(root, d.Name(), "")
 		if err != nil {
 			t.Fatalf("error proessing %q: %v", path, err)
 		}
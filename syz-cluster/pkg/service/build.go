@@ -12,12 +12,12 @@ import (
 )
 
 type BuildService struct {
-	buildRepo *db.BuildRepository
+	buildRepo db.BuildRepository
 }
 
 func NewBuildService(env *app.AppEnvironment) *BuildService {
 	return &BuildService{
-		buildRepo: db.NewBuildRepository(env.Spanner),
+		buildRepo: db.NewBuildRepository(env.DB),
 	}
 }
 
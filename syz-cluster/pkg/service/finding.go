@@ -15,13 +15,13 @@ import (
 )
 
 type FindingService struct {
-	findingRepo *db.FindingRepository
+	findingRepo db.FindingRepository
 	blobStorage blob.Storage
 }
 
 func NewFindingService(env *app.AppEnvironment) *FindingService {
 	return &FindingService{
-		findingRepo: db.NewFindingRepository(env.Spanner),
+		findingRepo: db.NewFindingRepository(env.DB),
 		blobStorage: env.BlobStorage,
 	}
 }
@@ -46,6 +46,7 @@ func (s *FindingService) Save(ctx context.Context, req *api.NewFinding) error {
 		SessionID: req.SessionID,
 		TestName:  req.TestName,
 		Title:     req.Title,
+		Type:      req.Type,
 		ReportURI: reportURI,
 		LogURI:    logURI,
 	})
@@ -65,6 +66,9 @@ func (s *FindingService) List(ctx context.Context, sessionID string) ([]*api.Fin
 	for _, item := range list {
 		finding := &api.Finding{
 			Title:  item.Title,
+			Type:   item.Type,
+			Status: item.Status,
+			DupOf:  item.DupOf,
 			LogURL: "TODO", // TODO: where to take it from?
 		}
 		bytes, err := blob.ReadAllBytes(s.blobStorage, item.ReportURI)
@@ -76,3 +80,22 @@ func (s *FindingService) List(ctx context.Context, sessionID string) ([]*api.Fin
 	}
 	return ret, nil
 }
+
+// SetStatus updates the status of all findings of the session.
+func (s *FindingService) SetStatus(ctx context.Context, sessionID, status, dupOf string) error {
+	list, err := s.findingRepo.ListForSession(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to query the list: %w", err)
+	}
+	for _, item := range list {
+		err := s.findingRepo.Update(ctx, item.ID, func(finding *db.Finding) error {
+			finding.Status = status
+			finding.DupOf = dupOf
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update the finding: %w", err)
+		}
+	}
+	return nil
+}
@@ -15,14 +15,14 @@ import (
 )
 
 type ReportService struct {
-	reportRepo     *db.ReportRepository
+	reportRepo     db.ReportRepository
 	seriesService  *SeriesService
 	findingService *FindingService
 }
 
 func NewReportService(env *app.AppEnvironment) *ReportService {
 	return &ReportService{
-		reportRepo:     db.NewReportRepository(env.Spanner),
+		reportRepo:     db.NewReportRepository(env.DB),
 		seriesService:  NewSeriesService(env),
 		findingService: NewFindingService(env),
 	}
@@ -79,6 +79,38 @@ func (rs *ReportService) Upstream(ctx context.Context, id string, req *api.Upstr
 	return nil
 }
 
+// Invalidate marks all findings of the report as invalid.
+func (rs *ReportService) Invalidate(ctx context.Context, id string) error {
+	rep, err := rs.query(ctx, id)
+	if err != nil {
+		return err
+	}
+	return rs.findingService.SetStatus(ctx, rep.SessionID, api.FindingInvalid, "")
+}
+
+// Dup marks all findings of the report as duplicates of the specified bug.
+func (rs *ReportService) Dup(ctx context.Context, id string, req *api.DupReportReq) error {
+	if req.DupOf == "" {
+		return ErrEmptyDupOf
+	}
+	rep, err := rs.query(ctx, id)
+	if err != nil {
+		return err
+	}
+	return rs.findingService.SetStatus(ctx, rep.SessionID, api.FindingDup, req.DupOf)
+}
+
+var ErrEmptyDupOf = errors.New("the duplicated bug is not specified")
+
+// Series returns the patch series the report was generated for.
+func (rs *ReportService) Series(ctx context.Context, id string) (*api.Series, error) {
+	rep, err := rs.query(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return rs.seriesService.GetSessionSeries(ctx, rep.SessionID)
+}
+
 func (rs *ReportService) Next(ctx context.Context) (*api.NextReportResp, error) {
 	list, err := rs.reportRepo.ListNotReported(ctx, 1)
 	if err != nil {
@@ -18,15 +18,15 @@ import (
 // SeriesService is tested in controller/.
 
 type SeriesService struct {
-	sessionRepo *db.SessionRepository
-	seriesRepo  *db.SeriesRepository
+	sessionRepo db.SessionRepository
+	seriesRepo  db.SeriesRepository
 	blobStorage blob.Storage
 }
 
 func NewSeriesService(env *app.AppEnvironment) *SeriesService {
 	return &SeriesService{
-		sessionRepo: db.NewSessionRepository(env.Spanner),
-		seriesRepo:  db.NewSeriesRepository(env.Spanner),
+		sessionRepo: db.NewSessionRepository(env.DB),
+		seriesRepo:  db.NewSeriesRepository(env.DB),
 		blobStorage: env.BlobStorage,
 	}
 }
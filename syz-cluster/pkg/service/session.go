@@ -14,14 +14,14 @@ import (
 )
 
 type SessionService struct {
-	sessionRepo *db.SessionRepository
-	seriesRepo  *db.SeriesRepository
+	sessionRepo db.SessionRepository
+	seriesRepo  db.SeriesRepository
 }
 
 func NewSessionService(env *app.AppEnvironment) *SessionService {
 	return &SessionService{
-		sessionRepo: db.NewSessionRepository(env.Spanner),
-		seriesRepo:  db.NewSeriesRepository(env.Spanner),
+		sessionRepo: db.NewSessionRepository(env.DB),
+		seriesRepo:  db.NewSeriesRepository(env.DB),
 	}
 }
 
@@ -17,13 +17,13 @@ import (
 )
 
 type SessionTestService struct {
-	testRepo    *db.SessionTestRepository
+	testRepo    db.SessionTestRepository
 	blobStorage blob.Storage
 }
 
 func NewSessionTestService(env *app.AppEnvironment) *SessionTestService {
 	return &SessionTestService{
-		testRepo:    db.NewSessionTestRepository(env.Spanner),
+		testRepo:    db.NewSessionTestRepository(env.DB),
 		blobStorage: env.BlobStorage,
 	}
 }
@@ -0,0 +1,72 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package triage
+
+import (
+	"regexp"
+	"slices"
+
+	"github.com/google/syzkaller/pkg/vcs"
+	"github.com/google/syzkaller/syz-cluster/pkg/api"
+)
+
+// DefaultArch is the arch the trees are built and fuzzed on (see api.Tree.KernelConfig and FuzzConfig).
+const DefaultArch = "amd64"
+
+// ArchTarget describes how to build and fuzz the kernel for an additional arch.
+type ArchTarget struct {
+	Arch         string
+	KernelConfig string // Refers to build-step's --kernel_configs.
+	FuzzConfig   string // Refers to workflow/configs/{}.
+	// The files that are only compiled for the arch.
+	Paths *regexp.Regexp
+}
+
+var ArchTargets = []*ArchTarget{
+	{
+		Arch:         "arm64",
+		KernelConfig: "upstream-arm64-kasan.config",
+		FuzzConfig:   "arm64",
+		Paths:        regexp.MustCompile(`^(arch/arm64/|drivers/firmware/|drivers/irqchip/irq-gic|drivers/of/)`),
+	},
+}
+
+// The changes to these files tend to behave differently depending on the arch
+// (word size, endianness, memory ordering, alignment requirements, etc).
+var portabilityPaths = regexp.MustCompile(`^(include/asm-generic/|include/linux/(atomic|bitops|compiler|unaligned)|` +
+	`include/linux/byteorder/|include/uapi/|lib/(bitmap|find_bit|string)|kernel/dma/|mm/(percpu|ioremap|memory\.c))`)
+
+// SelectArchTargets returns the additional (besides DefaultArch) archs to fuzz the series on.
+// An arch is selected if the series touches its specific code or the portability-sensitive code.
+// Only the archs from the enabled list are considered.
+func SelectArchTargets(series *api.Series, enabled []string) []*ArchTarget {
+	var files []string
+	for _, patch := range series.PatchBodies() {
+		files = append(files, vcs.ParseGitDiff(patch)...)
+	}
+	portability := false
+	for _, file := range files {
+		if portabilityPaths.MatchString(file) {
+			portability = true
+			break
+		}
+	}
+	var ret []*ArchTarget
+	for _, target := range ArchTargets {
+		if !slices.Contains(enabled, target.Arch) {
+			continue
+		}
+		selected := portability
+		for _, file := range files {
+			if target.Paths.MatchString(file) {
+				selected = true
+				break
+			}
+		}
+		if selected {
+			ret = append(ret, target)
+		}
+	}
+	return ret
+}
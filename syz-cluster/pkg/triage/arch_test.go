@@ -0,0 +1,61 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package triage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/syzkaller/syz-cluster/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectArchTargets(t *testing.T) {
+	tests := []struct {
+		files   []string
+		enabled []string
+		result  []string
+	}{
+		{
+			files:   []string{"net/ipv4/tcp.c"},
+			enabled: []string{"arm64"},
+			result:  nil,
+		},
+		{
+			files:   []string{"net/ipv4/tcp.c", "arch/arm64/kernel/setup.c"},
+			enabled: []string{"arm64"},
+			result:  []string{"arm64"},
+		},
+		{
+			files:   []string{"include/asm-generic/barrier.h"},
+			enabled: []string{"arm64"},
+			result:  []string{"arm64"},
+		},
+		{
+			files:   []string{"arch/arm64/kernel/setup.c"},
+			enabled: nil,
+			result:  nil,
+		},
+		{
+			files:   []string{"arch/x86/kernel/setup.c"},
+			enabled: []string{"arm64"},
+			result:  nil,
+		},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			series := &api.Series{}
+			for _, file := range test.files {
+				series.Patches = append(series.Patches, api.SeriesPatch{
+					Body: []byte(fmt.Sprintf("diff --git a/%[1]v b/%[1]v\n--- a/%[1]v\n+++ b/%[1]v\n", file)),
+				})
+			}
+			var archs []string
+			for _, target := range SelectArchTargets(series, test.enabled) {
+				archs = append(archs, target.Arch)
+			}
+			assert.Equal(t, test.result, archs)
+		})
+	}
+}
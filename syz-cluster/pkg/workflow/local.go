@@ -0,0 +1,530 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/syz-cluster/pkg/api"
+)
+
+// LocalConfig configures LocalService.
+// Paths that are hardcoded in the container images (/configs, /kernel-configs, etc)
+// must be given explicitly.
+type LocalConfig struct {
+	// Directory with the triage-step, build-step, boot-step, repro-step and fuzz-step binaries.
+	BinDir string `json:"bin_dir"`
+	// Directory for the per-session state, checkouts, builds and logs.
+	Workdir string `json:"workdir"`
+	// Kernel git repository with all the trees (see kernel-disk/fetch-kernels-cron.yaml).
+	KernelRepo string `json:"kernel_repo"`
+	// Directory with the kernel configs (see build-step's --kernel_configs).
+	KernelConfigs string `json:"kernel_configs"`
+	// Directory with the syzkaller configs (syz-cluster/workflow/configs).
+	Configs string `json:"configs"`
+	// Syzkaller checkout with built binaries.
+	Syzkaller string `json:"syzkaller"`
+	// Userspace image for the kernel builds (see build-step's --userspace).
+	Userspace string `json:"userspace,omitempty"`
+	// The archs (besides amd64) that triage may select for the series (see triage-step's --archs).
+	Archs []string `json:"archs,omitempty"`
+	// syz-manager workdir with the known reproducers (see repro-step's --repros).
+	// If empty, the reproducers are not run.
+	Repros string `json:"repros,omitempty"`
+	// How long to fuzz, 3h by default.
+	FuzzTime string `json:"fuzz_time,omitempty"`
+	// Additional environment variables for the steps (e.g. CONTROLLER_URL).
+	Env []string `json:"env,omitempty"`
+}
+
+// LocalService runs the workflow steps as subprocesses on the local machine.
+// It follows the same sequence of steps as the Argo workflow (see template.yaml),
+// but runs them one by one. The state of each session is persisted in the workdir,
+// so unfinished sessions are resumed after a restart from the first unfinished step.
+type LocalService struct {
+	cfg    *LocalConfig
+	mu     sync.Mutex
+	active map[string]bool
+}
+
+func NewLocalService(cfg *LocalConfig) (*LocalService, error) {
+	if cfg.BinDir == "" || cfg.Workdir == "" || cfg.KernelRepo == "" {
+		return nil, fmt.Errorf("bin_dir, workdir and kernel_repo must be set")
+	}
+	if cfg.FuzzTime == "" {
+		cfg.FuzzTime = "3h"
+	}
+	if err := osutil.MkdirAll(cfg.Workdir); err != nil {
+		return nil, err
+	}
+	return &LocalService{
+		cfg:    cfg,
+		active: make(map[string]bool),
+	}, nil
+}
+
+type localState struct {
+	Status Status       `json:"status"`
+	Error  string       `json:"error,omitempty"`
+	Steps  []*localStep `json:"steps"`
+}
+
+type localStep struct {
+	Name     string    `json:"name"`
+	Args     []string  `json:"args"`
+	Status   Status    `json:"status"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Error    string    `json:"error,omitempty"`
+}
+
+func (ls *LocalService) Start(sessionID string) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if _, err := ls.loadState(sessionID); err == nil {
+		return fmt.Errorf("session %q was already started", sessionID)
+	}
+	if err := osutil.MkdirAll(ls.sessionDir(sessionID)); err != nil {
+		return err
+	}
+	if err := ls.saveState(sessionID, &localState{Status: StatusRunning}); err != nil {
+		return err
+	}
+	ls.launch(sessionID)
+	return nil
+}
+
+func (ls *LocalService) Status(sessionID string) (Status, []byte, error) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	state, err := ls.loadState(sessionID)
+	if errors.Is(err, os.ErrNotExist) {
+		return StatusNotFound, nil, nil
+	} else if err != nil {
+		return "", nil, err
+	}
+	if state.Status == StatusRunning && !ls.active[sessionID] {
+		// We were restarted in the middle of the session.
+		ls.launch(sessionID)
+	}
+	return state.Status, ls.generateLog(sessionID, state), nil
+}
+
+func (ls *LocalService) PollPeriod() time.Duration {
+	return 30 * time.Second
+}
+
+func (ls *LocalService) sessionDir(sessionID string) string {
+	return filepath.Join(ls.cfg.Workdir, sessionID)
+}
+
+func (ls *LocalService) loadState(sessionID string) (*localState, error) {
+	data, err := os.ReadFile(filepath.Join(ls.sessionDir(sessionID), "state.json"))
+	if err != nil {
+		return nil, err
+	}
+	state := new(localState)
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse the session state: %w", err)
+	}
+	return state, nil
+}
+
+func (ls *LocalService) saveState(sessionID string, state *localState) error {
+	return osutil.WriteJSON(filepath.Join(ls.sessionDir(sessionID), "state.json"), state)
+}
+
+// updateState applies fn to the persisted session state.
+func (ls *LocalService) updateState(sessionID string, fn func(*localState)) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	state, err := ls.loadState(sessionID)
+	if err != nil {
+		return err
+	}
+	fn(state)
+	return ls.saveState(sessionID, state)
+}
+
+// launch must be called with ls.mu held.
+func (ls *LocalService) launch(sessionID string) {
+	ls.active[sessionID] = true
+	go func() {
+		run := &localRun{
+			ls:        ls,
+			cfg:       ls.cfg,
+			sessionID: sessionID,
+			dir:       ls.sessionDir(sessionID),
+		}
+		err := run.pipeline()
+		ls.updateState(sessionID, func(state *localState) {
+			state.Status = StatusFinished
+			if err != nil {
+				state.Status = StatusFailed
+				state.Error = err.Error()
+			}
+		})
+		ls.mu.Lock()
+		delete(ls.active, sessionID)
+		ls.mu.Unlock()
+	}()
+}
+
+func (ls *LocalService) generateLog(sessionID string, state *localState) []byte {
+	// Logs of long-running steps may be huge, keep only the end.
+	const maxStepLog = 64 << 10
+	var buf bytes.Buffer
+	for i, step := range state.Steps {
+		if i > 0 {
+			buf.WriteString("---------\n")
+		}
+		fmt.Fprintf(&buf, "Name: %s\n", step.Name)
+		fmt.Fprintf(&buf, "Phase: %s\n", step.Status)
+		fmt.Fprintf(&buf, "StartedAt: %s\n", step.Started)
+		fmt.Fprintf(&buf, "FinishedAt: %s\n", step.Finished)
+		fmt.Fprintf(&buf, "Args: %q\n", step.Args)
+		if step.Error != "" {
+			fmt.Fprintf(&buf, "Error: %s\n", step.Error)
+		}
+		output, _ := readTail(filepath.Join(ls.sessionDir(sessionID), "logs", step.Name+".log"), maxStepLog)
+		fmt.Fprintf(&buf, "Output:\n%s\n", output)
+	}
+	if state.Error != "" {
+		fmt.Fprintf(&buf, "=========\nWorkflow error: %s\n", state.Error)
+	}
+	return buf.Bytes()
+}
+
+func readTail(file string, size int64) ([]byte, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if stat.Size() > size {
+		if _, err := f.Seek(stat.Size()-size, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+	return io.ReadAll(f)
+}
+
+type localRun struct {
+	ls        *LocalService
+	cfg       *LocalConfig
+	sessionID string
+	dir       string
+}
+
+func (run *localRun) pipeline() error {
+	kernelDir := filepath.Join(run.dir, "kernel")
+	err := run.do("checkout", nil, func(log io.Writer) error {
+		return checkoutKernel(log, run.cfg.KernelRepo, kernelDir)
+	})
+	if err != nil {
+		return err
+	}
+	verdict := new(api.TriageResult)
+	verdictFile := filepath.Join(run.dir, "triage.json")
+	err = run.step("triage", "triage-step", verdictFile, verdict,
+		"--session", run.sessionID,
+		"--repository", kernelDir,
+		"--verdict", verdictFile,
+		"--archs", strings.Join(run.cfg.Archs, ","),
+	)
+	if err != nil || verdict.Skip != nil {
+		return err
+	}
+	// A failure of one fuzz config must not prevent the others from running (see template.yaml).
+	var errs []error
+	for i, fuzz := range verdict.Fuzz {
+		// The steps of the first fuzz config keep the names they had when there was only one.
+		suffix := ""
+		if i > 0 {
+			suffix = fmt.Sprintf("-%d", i)
+		}
+		if err := run.fuzzConfig(fuzz, kernelDir, suffix); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fuzzConfig performs the steps of Argo's process-fuzz template.
+func (run *localRun) fuzzConfig(fuzz *api.FuzzConfig, kernelDir, suffix string) error {
+	baseDir := filepath.Join(run.dir, "base"+suffix)
+	baseBuild, err := run.build("build-base"+suffix, "Build Base"+fuzz.TestSuffix, &fuzz.Base,
+		kernelDir, baseDir, false)
+	if err != nil || !baseBuild.Success {
+		return err
+	}
+	patchedDir := filepath.Join(run.dir, "patched"+suffix)
+	patchedBuild, err := run.build("build-patched"+suffix, "Build Patched"+fuzz.TestSuffix, &fuzz.Patched,
+		kernelDir, patchedDir, true)
+	if err != nil || !patchedBuild.Success {
+		return err
+	}
+	baseBoot, err := run.boot("boot-base"+suffix, "Boot test: Base"+fuzz.TestSuffix, fuzz.Config, baseDir,
+		"--base_build", baseBuild.BuildID)
+	if err != nil {
+		return err
+	}
+	patchedBoot, err := run.boot("boot-patched"+suffix, "Boot test: Patched"+fuzz.TestSuffix, fuzz.Config,
+		patchedDir, "--patched_build", patchedBuild.BuildID, "-findings=true")
+	if err != nil || !baseBoot.Success || !patchedBoot.Success {
+		return err
+	}
+	configs, err := run.configs("fuzz"+suffix, fuzz.Config, map[string]string{
+		"/base":    baseDir,
+		"/patched": patchedDir,
+	})
+	if err != nil {
+		return err
+	}
+	if run.cfg.Repros != "" {
+		err = run.step("repro"+suffix, "repro-step", "", nil,
+			"--configs", configs,
+			"--config", fuzz.Config,
+			"--session", run.sessionID,
+			"--test_name", "Reproducers"+fuzz.TestSuffix,
+			"--base_build", baseBuild.BuildID,
+			"--patched_build", patchedBuild.BuildID,
+			"--repros", run.cfg.Repros,
+			"--workdir", filepath.Join(run.dir, "repro-workdir"+suffix),
+		)
+		if err != nil {
+			// Reproducer problems must not prevent fuzzing (see template.yaml).
+			log.Printf("session %v: %v", run.sessionID, err)
+		}
+	}
+	return run.step("fuzz"+suffix, "fuzz-step", "", nil,
+		"--configs", configs,
+		"--config", fuzz.Config,
+		"--session", run.sessionID,
+		"--test_name", "Fuzzing"+fuzz.TestSuffix,
+		"--base_build", baseBuild.BuildID,
+		"--patched_build", patchedBuild.BuildID,
+		"--corpus_url", fuzz.CorpusURL,
+		"--time", run.cfg.FuzzTime,
+		"--workdir", filepath.Join(run.dir, "fuzz-workdir"+suffix),
+	)
+}
+
+func (run *localRun) build(name, testName string, req *api.BuildRequest, kernelDir, outDir string,
+	findings bool) (*api.BuildResult, error) {
+	reqFile := filepath.Join(run.dir, name+".json")
+	if err := osutil.WriteJSON(reqFile, req); err != nil {
+		return nil, err
+	}
+	if err := osutil.MkdirAll(outDir); err != nil {
+		return nil, err
+	}
+	args := []string{
+		"--request", reqFile,
+		"--repository", kernelDir,
+		"--output", outDir,
+		"--session", run.sessionID,
+		"--test_name", testName,
+		"-findings=" + strconv.FormatBool(findings),
+	}
+	if run.cfg.KernelConfigs != "" {
+		args = append(args, "--kernel_configs", run.cfg.KernelConfigs)
+	}
+	if run.cfg.Userspace != "" {
+		args = append(args, "--userspace", run.cfg.Userspace)
+	}
+	res := new(api.BuildResult)
+	err := run.step(name, "build-step", filepath.Join(outDir, "result.json"), res, args...)
+	return res, err
+}
+
+func (run *localRun) boot(name, testName, config, kernelDir string, args ...string) (*api.BootResult, error) {
+	// Boot tests always use base.cfg with the kernel at /base.
+	configs, err := run.configs(name, config, map[string]string{
+		"/base": kernelDir,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resFile := filepath.Join(run.dir, name+".json")
+	res := new(api.BootResult)
+	err = run.step(name, "boot-step", resFile, res, append([]string{
+		"--configs", configs,
+		"--config", config,
+		"--output", resFile,
+		"--session", run.sessionID,
+		"--test_name", testName,
+		"--workdir", filepath.Join(run.dir, name+"-workdir"),
+	}, args...)...)
+	return res, err
+}
+
+// configs copies the syzkaller configs of the step and replaces the paths that are
+// mounted into the containers with the local paths.
+func (run *localRun) configs(step, config string, mounts map[string]string) (string, error) {
+	mounts["/syzkaller"] = run.cfg.Syzkaller
+	mounts["/workdir"] = filepath.Join(run.dir, step+"-workdir")
+	dir := filepath.Join(run.dir, "configs", step)
+	for _, name := range []string{"base.cfg", "patched.cfg"} {
+		data, err := os.ReadFile(filepath.Join(run.cfg.Configs, config, name))
+		if err != nil {
+			return "", err
+		}
+		var cfg any
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return "", fmt.Errorf("failed to parse %v: %w", name, err)
+		}
+		if err := osutil.MkdirAll(filepath.Join(dir, config)); err != nil {
+			return "", err
+		}
+		err = osutil.WriteJSON(filepath.Join(dir, config, name), replacePaths(cfg, mounts))
+		if err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+func replacePaths(val any, mounts map[string]string) any {
+	switch v := val.(type) {
+	case map[string]any:
+		for key, elem := range v {
+			v[key] = replacePaths(elem, mounts)
+		}
+	case []any:
+		for i, elem := range v {
+			v[i] = replacePaths(elem, mounts)
+		}
+	case string:
+		for from, to := range mounts {
+			if v == from || strings.HasPrefix(v, from+"/") {
+				return to + v[len(from):]
+			}
+		}
+	}
+	return val
+}
+
+// step runs the step binary and parses the JSON result file (if any) into result.
+func (run *localRun) step(name, bin, resultFile string, result any, args ...string) error {
+	return run.do(name, args, func(log io.Writer) error {
+		cmd := osutil.Command(filepath.Join(run.cfg.BinDir, bin), args...)
+		cmd.Env = append(os.Environ(), run.cfg.Env...)
+		cmd.Stdout = log
+		cmd.Stderr = log
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%v failed: %w", bin, err)
+		}
+		return nil
+	}, func() error {
+		if resultFile == "" {
+			return nil
+		}
+		data, err := os.ReadFile(resultFile)
+		if err != nil {
+			return fmt.Errorf("failed to read the %v result: %w", name, err)
+		}
+		return json.Unmarshal(data, result)
+	})
+}
+
+// do runs fn as the named step unless it has already succeeded before (e.g. before a restart).
+// The optional post functions are run after fn (or instead of it, if the step was already done).
+func (run *localRun) do(name string, args []string, fn func(io.Writer) error, post ...func() error) error {
+	done := false
+	err := run.ls.updateState(run.sessionID, func(state *localState) {
+		for _, step := range state.Steps {
+			if step.Name == name {
+				done = step.Status == StatusFinished
+				if !done {
+					*step = localStep{Name: name, Args: args, Status: StatusRunning, Started: time.Now()}
+				}
+				return
+			}
+		}
+		state.Steps = append(state.Steps, &localStep{
+			Name:    name,
+			Args:    args,
+			Status:  StatusRunning,
+			Started: time.Now(),
+		})
+	})
+	if err != nil || done {
+		return runAll(err, post)
+	}
+	logDir := filepath.Join(run.dir, "logs")
+	if err := osutil.MkdirAll(logDir); err != nil {
+		return err
+	}
+	logFile, err := os.Create(filepath.Join(logDir, name+".log"))
+	if err != nil {
+		return err
+	}
+	err = runAll(fn(logFile), post)
+	logFile.Close()
+	updateErr := run.ls.updateState(run.sessionID, func(state *localState) {
+		for _, step := range state.Steps {
+			if step.Name != name {
+				continue
+			}
+			step.Finished = time.Now()
+			step.Status = StatusFinished
+			if err != nil {
+				step.Status = StatusFailed
+				step.Error = err.Error()
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("step %v: %w", name, err)
+	}
+	return updateErr
+}
+
+func runAll(err error, fns []func() error) error {
+	for _, fn := range fns {
+		if err != nil {
+			break
+		}
+		err = fn()
+	}
+	return err
+}
+
+// checkoutKernel creates a private copy of the kernel repository that shares objects with the original,
+// so that the steps can freely check out and patch it.
+func checkoutKernel(log io.Writer, repo, dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	const timeout = time.Hour
+	for _, args := range [][]string{
+		{"clone", "--shared", "--no-checkout", repo, dir},
+		// The trees are fetched as remotes and tagged as NAME-head, copy all of them.
+		{"-C", dir, "fetch", "--update-head-ok", "origin", "+refs/*:refs/*"},
+	} {
+		output, err := osutil.RunCmd(timeout, "", "git", args...)
+		log.Write(output)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package workflow
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalPipeline(t *testing.T) {
+	env := newLocalTestEnv(t, `{"fuzz": [{"config": "all"}]}`, true)
+	status, log := env.run("session")
+	assert.Equal(t, StatusFinished, status)
+	assert.Equal(t, []string{"triage", "build", "build", "boot", "boot", "repro", "fuzz"}, env.calls())
+	assert.Contains(t, log, "Name: fuzz\nPhase: finished")
+	// The container paths must be replaced by the local ones.
+	sessionDir := filepath.Join(env.cfg.Workdir, "session")
+	assert.Contains(t, log, `"kernel_obj": "`+filepath.Join(sessionDir, "base", "obj")+`"`)
+	assert.Contains(t, log, `"kernel_obj": "`+filepath.Join(sessionDir, "patched", "obj")+`"`)
+	assert.Contains(t, log, `"syzkaller": "`+env.cfg.Syzkaller+`"`)
+	assert.NotContains(t, log, `"/base`)
+
+	// Restart the service in the middle of fuzzing.
+	state, err := env.service.loadState("session")
+	require.NoError(t, err)
+	state.Status = StatusRunning
+	state.Steps[len(state.Steps)-1].Status = StatusRunning
+	require.NoError(t, env.service.saveState("session", state))
+	env.service, err = NewLocalService(env.cfg)
+	require.NoError(t, err)
+	status, _ = env.run("")
+	assert.Equal(t, StatusFinished, status)
+	assert.Equal(t, []string{"triage", "build", "build", "boot", "boot", "repro", "fuzz", "fuzz"}, env.calls())
+}
+
+func TestLocalMultipleConfigs(t *testing.T) {
+	env := newLocalTestEnv(t, `{"fuzz": [{"config": "all"}, {"config": "all", "test_suffix": " [arm64]"}]}`, true)
+	status, log := env.run("session")
+	assert.Equal(t, StatusFinished, status)
+	assert.Equal(t, []string{"triage", "build", "build", "boot", "boot", "repro", "fuzz",
+		"build", "build", "boot", "boot", "repro", "fuzz"}, env.calls())
+	assert.Contains(t, log, "Name: fuzz\nPhase: finished")
+	assert.Contains(t, log, "Name: fuzz-1\nPhase: finished")
+	assert.Contains(t, log, "Fuzzing [arm64]")
+	assert.Contains(t, log, `"kernel_obj": "`+filepath.Join(env.cfg.Workdir, "session", "patched-1", "obj")+`"`)
+}
+
+func TestLocalConfigFailure(t *testing.T) {
+	// The first config fails, but the second one must still be fuzzed.
+	env := newLocalTestEnv(t, `{"fuzz": [{"config": "missing"}, {"config": "all", "test_suffix": " [arm64]"}]}`, true)
+	status, log := env.run("session")
+	assert.Equal(t, StatusFailed, status)
+	assert.NotContains(t, log, "Name: boot-base\n")
+	assert.Contains(t, log, "Name: fuzz-1\nPhase: finished")
+	assert.Regexp(t, "Workflow error: .*/configs/missing/base.cfg", log)
+}
+
+func TestLocalSkip(t *testing.T) {
+	env := newLocalTestEnv(t, `{"skip": {"reason": "no patches"}}`, true)
+	status, _ := env.run("session")
+	assert.Equal(t, StatusFinished, status)
+	assert.Equal(t, []string{"triage"}, env.calls())
+}
+
+func TestLocalBuildFailure(t *testing.T) {
+	env := newLocalTestEnv(t, `{"fuzz": [{"config": "all"}]}`, false)
+	status, log := env.run("session")
+	assert.Equal(t, StatusFinished, status)
+	assert.Equal(t, []string{"triage", "build"}, env.calls())
+	assert.Contains(t, log, "Name: build-base\nPhase: finished")
+	assert.NotContains(t, log, "build-patched")
+}
+
+func TestLocalReproFailure(t *testing.T) {
+	env := newLocalTestEnv(t, `{"fuzz": [{"config": "all"}]}`, true)
+	env.cfg.Repros = filepath.Join(env.cfg.Workdir, "nonexistent")
+	status, log := env.run("session")
+	assert.Equal(t, StatusFinished, status)
+	assert.Equal(t, []string{"triage", "build", "build", "boot", "boot", "repro", "fuzz"}, env.calls())
+	assert.Contains(t, log, "Name: repro\nPhase: failed")
+	assert.Contains(t, log, "Name: fuzz\nPhase: finished")
+}
+
+func TestLocalStepFailure(t *testing.T) {
+	env := newLocalTestEnv(t, `garbage`, true)
+	status, log := env.run("session")
+	assert.Equal(t, StatusFailed, status)
+	assert.Contains(t, log, "Name: triage\nPhase: failed")
+	assert.Contains(t, log, "Workflow error: step triage")
+
+	status, _, err := env.service.Status("unknown")
+	require.NoError(t, err)
+	assert.Equal(t, StatusNotFound, status)
+	assert.Error(t, env.service.Start("session"))
+}
+
+type localTestEnv struct {
+	t       *testing.T
+	cfg     *LocalConfig
+	service *LocalService
+}
+
+// The fake steps record their invocations and produce the results the real ones would.
+var localTestSteps = map[string]string{
+	"triage-step": `echo "$TEST_VERDICT" > $flag_verdict`,
+	"build-step":  `echo "{\"build_id\": \"$flag_test_name\", \"success\": $TEST_BUILD}" > $flag_output/result.json`,
+	"boot-step": `cat $flag_configs/$flag_config/base.cfg
+echo '{"success": true}' > $flag_output`,
+	"repro-step": `test -d $flag_repros`,
+	"fuzz-step":  `cat $flag_configs/$flag_config/base.cfg $flag_configs/$flag_config/patched.cfg`,
+}
+
+func newLocalTestEnv(t *testing.T, verdict string, buildOK bool) *localTestEnv {
+	dir := t.TempDir()
+	cfg := &LocalConfig{
+		BinDir:     filepath.Join(dir, "bin"),
+		Workdir:    filepath.Join(dir, "workdir"),
+		KernelRepo: filepath.Join(dir, "kernel"),
+		Configs:    filepath.Join(dir, "configs"),
+		Syzkaller:  filepath.Join(dir, "syzkaller"),
+		Repros:     dir,
+		Env: []string{
+			"TEST_VERDICT=" + verdict,
+			"TEST_BUILD=" + map[bool]string{true: "true", false: "false"}[buildOK],
+			"TEST_CALLS=" + filepath.Join(dir, "calls"),
+		},
+	}
+	require.NoError(t, osutil.MkdirAll(cfg.BinDir))
+	for name, body := range localTestSteps {
+		script := `#!/usr/bin/env bash
+set -e
+echo ` + strings.TrimSuffix(name, "-step") + ` >> $TEST_CALLS
+while [ $# -gt 0 ]; do
+	case "$1" in
+	*=*) shift;;
+	*) declare "flag_${1#--}=$2"; shift 2;;
+	esac
+done
+` + body + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(cfg.BinDir, name), []byte(script), 0755))
+	}
+	for _, name := range []string{"base", "patched"} {
+		require.NoError(t, osutil.MkdirAll(filepath.Join(cfg.Configs, "all")))
+		require.NoError(t, osutil.WriteJSON(filepath.Join(cfg.Configs, "all", name+".cfg"), map[string]any{
+			"kernel_obj": "/" + name + "/obj",
+			"syzkaller":  "/syzkaller",
+			"workdir":    "/workdir",
+		}))
+	}
+	for _, args := range [][]string{
+		{"init", cfg.KernelRepo},
+		{"-C", cfg.KernelRepo, "-c", "user.name=test", "-c", "user.email=test@test",
+			"commit", "--allow-empty", "-m", "initial"},
+		{"-C", cfg.KernelRepo, "tag", "mainline-head"},
+	} {
+		_, err := osutil.RunCmd(time.Minute, "", "git", args...)
+		require.NoError(t, err)
+	}
+	service, err := NewLocalService(cfg)
+	require.NoError(t, err)
+	return &localTestEnv{
+		t:       t,
+		cfg:     cfg,
+		service: service,
+	}
+}
+
+// run starts the session (unless it's empty) and waits until it's done.
+func (env *localTestEnv) run(sessionID string) (Status, string) {
+	if sessionID != "" {
+		require.NoError(env.t, env.service.Start(sessionID))
+	} else {
+		sessionID = "session"
+	}
+	for start := time.Now(); time.Since(start) < time.Minute; time.Sleep(10 * time.Millisecond) {
+		status, log, err := env.service.Status(sessionID)
+		require.NoError(env.t, err)
+		if status != StatusRunning {
+			return status, string(log)
+		}
+	}
+	env.t.Fatalf("the session did not finish")
+	return "", ""
+}
+
+func (env *localTestEnv) calls() []string {
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(env.cfg.Workdir), "calls"))
+	require.NoError(env.t, err)
+	return strings.Fields(string(data))
+}
+
+func TestReplacePaths(t *testing.T) {
+	var cfg any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"image": "/base/image",
+		"other": "/based",
+		"sandboxes": ["/base", 1],
+		"vm": {"kernel": "/patched/kernel"}
+	}`), &cfg))
+	replacePaths(cfg, map[string]string{"/base": "/tmp/base", "/patched": "/tmp/patched"})
+	assert.Equal(t, map[string]any{
+		"image":     "/tmp/base/image",
+		"other":     "/based",
+		"sandboxes": []any{"/tmp/base", float64(1)},
+		"vm":        map[string]any{"kernel": "/tmp/patched/kernel"},
+	}, cfg)
+}
@@ -14,10 +14,11 @@ import (
 
 type ReporterAPI struct {
 	service *service.ReportService
+	emails  *EmailHandler
 }
 
-func NewReporterAPI(service *service.ReportService) *ReporterAPI {
-	return &ReporterAPI{service: service}
+func NewReporterAPI(service *service.ReportService, emails *EmailHandler) *ReporterAPI {
+	return &ReporterAPI{service: service, emails: emails}
 }
 
 func (ra *ReporterAPI) Mux() *http.ServeMux {
@@ -25,7 +26,10 @@ func (ra *ReporterAPI) Mux() *http.ServeMux {
 	mux.HandleFunc("/reports/{report_id}/update", ra.updateReport)
 	mux.HandleFunc("/reports/{report_id}/upstream", ra.upstreamReport)
 	mux.HandleFunc("/reports/{report_id}/confirm", ra.confirmReport)
+	mux.HandleFunc("/reports/{report_id}/invalidate", ra.invalidateReport)
+	mux.HandleFunc("/reports/{report_id}/dup", ra.dupReport)
 	mux.HandleFunc("/reports", ra.nextReports)
+	mux.HandleFunc("/emails/incoming", ra.incomingEmail)
 	return mux
 }
 
@@ -60,11 +64,35 @@ func (ra *ReporterAPI) confirmReport(w http.ResponseWriter, r *http.Request) {
 	reply[interface{}](w, nil, err)
 }
 
+func (ra *ReporterAPI) invalidateReport(w http.ResponseWriter, r *http.Request) {
+	err := ra.service.Invalidate(r.Context(), r.PathValue("report_id"))
+	reply[interface{}](w, nil, err)
+}
+
+// nolint: dupl
+func (ra *ReporterAPI) dupReport(w http.ResponseWriter, r *http.Request) {
+	req := api.ParseJSON[api.DupReportReq](w, r)
+	if req == nil {
+		return
+	}
+	err := ra.service.Dup(r.Context(), r.PathValue("report_id"), req)
+	reply[interface{}](w, nil, err)
+}
+
+func (ra *ReporterAPI) incomingEmail(w http.ResponseWriter, r *http.Request) {
+	req := api.ParseJSON[api.IncomingEmailReq](w, r)
+	if req == nil {
+		return
+	}
+	text, err := ra.emails.Handle(r.Context(), req.Raw)
+	reply(w, &api.IncomingEmailResp{Reply: text}, err)
+}
+
 func reply[T any](w http.ResponseWriter, obj T, err error) {
 	if errors.Is(err, service.ErrReportNotFound) {
 		http.Error(w, fmt.Sprint(err), http.StatusNotFound)
 		return
-	} else if errors.Is(err, service.ErrNotOnModeration) {
+	} else if errors.Is(err, service.ErrNotOnModeration) || errors.Is(err, service.ErrEmptyDupOf) {
 		http.Error(w, fmt.Sprint(err), http.StatusBadRequest)
 		return
 	} else if err != nil {
@@ -115,14 +115,16 @@ func TestAPIReportFlow(t *testing.T) {
 }
 
 func ReporterServer(t *testing.T, env *app.AppEnvironment) *api.ReporterClient {
-	apiServer := NewReporterAPI(service.NewReportService(env))
+	reportService := service.NewReportService(env)
+	emails := NewEmailHandler(reportService, controller.TestServer(t, env), testOwnEmail)
+	apiServer := NewReporterAPI(reportService, emails)
 	server := httptest.NewServer(apiServer.Mux())
 	t.Cleanup(server.Close)
 	return api.NewReporterClient(server.URL)
 }
 
 func markSessionFinished(t *testing.T, env *app.AppEnvironment, sessionID string) {
-	repo := db.NewSessionRepository(env.Spanner)
+	repo := db.NewSessionRepository(env.DB)
 	err := repo.Update(context.Background(), sessionID, func(session *db.Session) error {
 		session.SetFinishedAt(time.Now())
 		return nil
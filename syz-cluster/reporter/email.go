@@ -0,0 +1,138 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/email"
+	"github.com/google/syzkaller/syz-cluster/pkg/api"
+	"github.com/google/syzkaller/syz-cluster/pkg/service"
+)
+
+// EmailHandler executes the #syz commands from the replies to the reports.
+// The reports are expected to be sent from the ownEmail address with the report ID
+// as its context (see email.AddAddrContext), so that the replies can be matched to the reports.
+type EmailHandler struct {
+	service  *service.ReportService
+	client   *api.Client // The controller API.
+	ownEmail string
+}
+
+func NewEmailHandler(service *service.ReportService, client *api.Client, ownEmail string) *EmailHandler {
+	return &EmailHandler{
+		service:  service,
+		client:   client,
+		ownEmail: ownEmail,
+	}
+}
+
+var errNoOwnEmail = errors.New("the reporter's own email is not configured")
+
+// Handle returns the text that should be sent back as a reply, if any.
+func (eh *EmailHandler) Handle(ctx context.Context, raw []byte) (string, error) {
+	if eh.ownEmail == "" {
+		return "", errNoOwnEmail
+	}
+	msg, err := email.Parse(bytes.NewReader(raw), []string{eh.ownEmail}, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	if msg.OwnEmail || len(msg.Commands) == 0 {
+		return "", nil
+	}
+	reportID := ""
+	for _, id := range msg.BugIDs {
+		if id != "" {
+			reportID = id
+			break
+		}
+	}
+	if reportID == "" {
+		return "I cannot find the report this email refers to.\n" +
+			"Please reply to the original report email and keep its From address in the recipients.", nil
+	}
+	var replies []string
+	for _, cmd := range msg.Commands {
+		reply, err := eh.command(ctx, reportID, msg, cmd)
+		if errors.Is(err, service.ErrReportNotFound) {
+			return fmt.Sprintf("The report %s is not found.", reportID), nil
+		} else if err != nil {
+			return "", fmt.Errorf("failed to execute %q: %w", cmd.Str, err)
+		}
+		replies = append(replies, reply)
+	}
+	return strings.TrimSpace(strings.Join(replies, "\n")), nil
+}
+
+func (eh *EmailHandler) command(ctx context.Context, reportID string, msg *email.Email,
+	cmd *email.SingleCommand) (string, error) {
+	switch cmd.Command {
+	case email.CmdUpstream:
+		err := eh.service.Upstream(ctx, reportID, &api.UpstreamReportReq{User: msg.Author})
+		if errors.Is(err, service.ErrNotOnModeration) {
+			return "The report has already been sent upstream.", nil
+		}
+		return "", err
+	case email.CmdInvalid:
+		return "", eh.service.Invalidate(ctx, reportID)
+	case email.CmdDup:
+		err := eh.service.Dup(ctx, reportID, &api.DupReportReq{DupOf: cmd.Args})
+		if errors.Is(err, service.ErrEmptyDupOf) {
+			return "Please specify the title of the original bug: #syz dup: title", nil
+		}
+		return "", err
+	case email.CmdTest:
+		return eh.test(ctx, reportID, msg)
+	default:
+		return fmt.Sprintf("Command %q is not supported.", cmd.Str), nil
+	}
+}
+
+// test submits the patch from the email as a new series to be fuzzed in place of the original one.
+func (eh *EmailHandler) test(ctx context.Context, reportID string, msg *email.Email) (string, error) {
+	if msg.Patch == "" {
+		return "Please attach the updated patch or put it inline.", nil
+	}
+	series, err := eh.service.Series(ctx, reportID)
+	if err != nil {
+		return "", fmt.Errorf("failed to query the series: %w", err)
+	}
+	resp, err := eh.client.UploadSeries(ctx, &api.Series{
+		ExtID:       msg.MessageID,
+		Title:       series.Title,
+		AuthorEmail: msg.Author,
+		Cc:          series.Cc,
+		Version:     series.Version,
+		Link:        msg.Link,
+		PublishedAt: time.Now(),
+		Patches: []api.SeriesPatch{
+			{
+				Seq:   1,
+				Title: msg.Subject,
+				Link:  msg.Link,
+				Body:  []byte(msg.Patch),
+			},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload the series: %w", err)
+	} else if !resp.Saved {
+		// The email was already processed.
+		return "", nil
+	}
+	_, err = eh.client.UploadSession(ctx, &api.NewSession{
+		ExtID: msg.MessageID,
+		Tags:  []string{"test"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to request a fuzzing session: %w", err)
+	}
+	return "The patch has been submitted for testing, the results will be reported separately.", nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/email"
+	"github.com/google/syzkaller/syz-cluster/pkg/api"
+	"github.com/google/syzkaller/syz-cluster/pkg/app"
+	"github.com/google/syzkaller/syz-cluster/pkg/controller"
+	"github.com/google/syzkaller/syz-cluster/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOwnEmail = "reporter@syzkaller.com"
+
+func TestEmailCommands(t *testing.T) {
+	env, ctx := app.TestEnvironment(t)
+	client := controller.TestServer(t, env)
+	_, sessionID := controller.UploadTestSeries(t, ctx, client, testSeries)
+	err := client.UploadTestResult(ctx, &api.TestResult{
+		SessionID: sessionID,
+		TestName:  "test",
+		Result:    api.TestFailed,
+	})
+	require.NoError(t, err)
+	err = client.UploadFinding(ctx, &api.NewFinding{
+		SessionID: sessionID,
+		Title:     "finding",
+		TestName:  "test",
+		Report:    []byte("report"),
+	})
+	require.NoError(t, err)
+	markSessionFinished(t, env, sessionID)
+	require.NoError(t, newReportGenerator(env).process(ctx, 1))
+
+	reportClient := ReporterServer(t, env)
+	next, err := reportClient.GetNextReport(ctx)
+	require.NoError(t, err)
+	reportID := next.Report.ID
+	replyTo, err := email.AddAddrContext(testOwnEmail, reportID)
+	require.NoError(t, err)
+
+	send := func(messageID, body string) string {
+		resp, err := reportClient.IncomingEmail(ctx, &api.IncomingEmailReq{
+			Raw: []byte(fmt.Sprintf(`Date: Sun, 7 May 2017 19:54:00 -0700
+Message-ID: <%s>
+Subject: Re: test series name
+From: developer@kernel.org
+To: %s
+
+%s`, messageID, replyTo, body)),
+		})
+		require.NoError(t, err)
+		return resp.Reply
+	}
+	findingStatus := func() (string, string) {
+		next, err := reportClient.GetNextReport(ctx)
+		require.NoError(t, err)
+		require.Len(t, next.Report.Findings, 1)
+		return next.Report.Findings[0].Status, next.Report.Findings[0].DupOf
+	}
+
+	assert.Empty(t, send("invalid@kernel.org", "#syz invalid\n"))
+	status, _ := findingStatus()
+	assert.Equal(t, api.FindingInvalid, status)
+
+	assert.Contains(t, send("dup-empty@kernel.org", "#syz dup:\n"), "Please specify")
+	assert.Empty(t, send("dup@kernel.org", "#syz dup: KASAN: use-after-free in foo\n"))
+	status, dupOf := findingStatus()
+	assert.Equal(t, api.FindingDup, status)
+	assert.Equal(t, "KASAN: use-after-free in foo", dupOf)
+
+	assert.Contains(t, send("unknown@kernel.org", "#syz fix: something\n"), "is not supported")
+	assert.Contains(t, send("no-patch@kernel.org", "#syz test\n"), "Please attach")
+
+	reply := send("test@kernel.org", `#syz test
+
+diff --git a/net/ipv4/tcp.c b/net/ipv4/tcp.c
+--- a/net/ipv4/tcp.c
++++ b/net/ipv4/tcp.c
+@@ -1 +1 @@
+-a
++b
+`)
+	assert.Contains(t, reply, "submitted for testing")
+	series, err := db.NewSeriesRepository(env.DB).GetByExtID(ctx, "<test@kernel.org>")
+	require.NoError(t, err)
+	require.NotNil(t, series)
+	assert.Equal(t, testSeries.Title, series.Title)
+	sessions, err := db.NewSessionRepository(env.DB).ListForSeries(ctx, series)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1)
+
+	// Replies that do not refer to any report.
+	resp, err := reportClient.IncomingEmail(ctx, &api.IncomingEmailReq{
+		Raw: []byte(`Message-ID: <lost@kernel.org>
+From: developer@kernel.org
+To: ` + testOwnEmail + `
+
+#syz invalid
+`),
+	})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Reply, "cannot find the report")
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/google/syzkaller/syz-cluster/pkg/app"
@@ -25,20 +26,22 @@ func main() {
 	generator := newReportGenerator(env)
 	go generator.Loop(ctx)
 
-	api := NewReporterAPI(service.NewReportService(env))
+	reportService := service.NewReportService(env)
+	emails := NewEmailHandler(reportService, app.DefaultClient(), os.Getenv("REPORTER_EMAIL"))
+	api := NewReporterAPI(reportService, emails)
 	log.Printf("listening on port 8080")
 	app.Fatalf("listen failed: %v", http.ListenAndServe(":8080", api.Mux()))
 }
 
 type reportGenerator struct {
-	sessionRepo *db.SessionRepository
-	reportRepo  *db.ReportRepository
+	sessionRepo db.SessionRepository
+	reportRepo  db.ReportRepository
 }
 
 func newReportGenerator(env *app.AppEnvironment) *reportGenerator {
 	return &reportGenerator{
-		sessionRepo: db.NewSessionRepository(env.Spanner),
-		reportRepo:  db.NewReportRepository(env.Spanner),
+		sessionRepo: db.NewSessionRepository(env.DB),
+		reportRepo:  db.NewReportRepository(env.DB),
 	}
 }
 
@@ -0,0 +1,98 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/email/lore"
+)
+
+// LocalSource reads the messages from local mbox files, maildirs and git format-patch outputs.
+// It's meant for the mailing lists that have no public-inbox archive.
+type LocalSource struct {
+	mboxes    []string
+	maildirs  []string
+	patchDirs []string
+	// If empty, the series will have no links.
+	linkPrefix string
+}
+
+func (ls *LocalSource) Emails(ctx context.Context, from time.Time) ([]lore.EmailReader, error) {
+	var list []lore.EmailReader
+	for _, file := range ls.mboxes {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		// An mbox is only appended to, so if it was not modified, there's nothing new there.
+		if info.ModTime().Before(from) {
+			continue
+		}
+		mboxList, err := lore.ReadMbox(file)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("read %d emails from %s", len(mboxList), file)
+		list = append(list, mboxList...)
+	}
+	for _, dir := range ls.maildirs {
+		dirList, err := lore.ReadMaildir(dir, from)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("read %d emails from %s", len(dirList), dir)
+		list = append(list, dirList...)
+	}
+	for _, root := range ls.patchDirs {
+		dirs, err := patchDirs(root, from)
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range dirs {
+			dirList, err := lore.ReadPatchDir(dir)
+			if err != nil {
+				return nil, err
+			}
+			log.Printf("read %d patches from %s", len(dirList), dir)
+			list = append(list, dirList...)
+		}
+	}
+	return list, nil
+}
+
+// patchDirs returns the directories under root that have *.patch files modified since the specified time.
+func patchDirs(root string, from time.Time) ([]string, error) {
+	var ret []string
+	seen := map[string]bool{}
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !strings.HasSuffix(path, ".patch") {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		dir := filepath.Dir(path)
+		if !info.ModTime().Before(from) && !seen[dir] {
+			seen[dir] = true
+			ret = append(ret, dir)
+		}
+		return nil
+	})
+	return ret, err
+}
+
+func (ls *LocalSource) Link(messageID string) string {
+	if ls.linkPrefix == "" {
+		return ""
+	}
+	return ls.linkPrefix + strings.Trim(messageID, "<>")
+}
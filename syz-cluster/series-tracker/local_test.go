@@ -0,0 +1,70 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/syz-cluster/pkg/app"
+	"github.com/google/syzkaller/syz-cluster/pkg/controller"
+	"github.com/google/syzkaller/syz-cluster/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalSource(t *testing.T) {
+	env, ctx := app.TestEnvironment(t)
+	dir := t.TempDir()
+	mbox := filepath.Join(dir, "mbox")
+	require.NoError(t, os.WriteFile(mbox, []byte(`From a@user.com Sun May  7 19:54:00 2017
+Date: Sun, 7 May 2017 19:54:00 -0700
+Subject: [PATCH v2] Mbox patch
+Message-ID: <mbox-patch@user.com>
+From: UserA <a@user.com>
+Cc: UserB <b@user.com>
+
+Patch body
+`), 0644))
+	patches := filepath.Join(dir, "patches", "series")
+	require.NoError(t, os.MkdirAll(patches, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(patches, "0001-first.patch"),
+		[]byte(`From 1111111111111111111111111111111111111111 Mon Sep 17 00:00:00 2001
+From: UserC <c@user.com>
+Date: Sun, 7 May 2017 19:55:00 -0700
+Subject: [PATCH] Format-patch patch
+
+Patch body
+`), 0644))
+
+	fetcher := &SeriesFetcher{
+		client: controller.TestServer(t, env),
+		source: &LocalSource{
+			mboxes:     []string{mbox},
+			patchDirs:  []string{filepath.Join(dir, "patches")},
+			linkPrefix: "https://lists.example.com/",
+		},
+	}
+	require.NoError(t, fetcher.Update(ctx, time.Time{}))
+
+	seriesRepo := db.NewSeriesRepository(env.DB)
+	list, err := seriesRepo.ListLatest(ctx, db.SeriesFilter{}, time.Time{}, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	series, err := seriesRepo.GetByExtID(ctx, "<mbox-patch@user.com>")
+	require.NoError(t, err)
+	require.NotNil(t, series)
+	assert.Equal(t, "Mbox patch", series.Title)
+	assert.Equal(t, int64(2), series.Version)
+	assert.Equal(t, "https://lists.example.com/mbox-patch@user.com", series.Link)
+	assert.Equal(t, []string{"a@user.com", "b@user.com"}, series.Cc)
+
+	// Nothing has changed since the last poll.
+	source := fetcher.source.(*LocalSource)
+	emails, err := source.Emails(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, emails)
+}
@@ -28,23 +28,40 @@ import (
 var (
 	flagArchives = flag.String("archives", "",
 		"a comma-separated list of the archives to poll")
+	flagMbox    = flag.String("mbox", "", "a comma-separated list of mbox files to read instead of the archives")
+	flagMaildir = flag.String("maildir", "", "a comma-separated list of maildirs to read instead of the archives")
+	flagPatches = flag.String("patches", "",
+		"a comma-separated list of directories with git format-patch output (one series per directory)")
+	flagLink    = flag.String("link", "", "URL prefix of the web archive for the local messages")
 	flagVerbose = flag.Bool("verbose", false, "enable verbose output")
 )
 
 func main() {
 	flag.Parse()
 	ctx := context.Background()
-	manifest := NewManifestSource(`https://lore.kernel.org`)
 	fetcher := &SeriesFetcher{
-		gitRepoFolder: `/git-repo`, // Set in deployment.yaml.
-		client:        app.DefaultClient(),
-		manifest:      manifest,
-		archives:      archivesToPoll(),
+		client: app.DefaultClient(),
+	}
+	// On start, the local sources are read in full.
+	var nextFrom time.Time
+	if *flagMbox != "" || *flagMaildir != "" || *flagPatches != "" {
+		fetcher.source = &LocalSource{
+			mboxes:     splitList(*flagMbox),
+			maildirs:   splitList(*flagMaildir),
+			patchDirs:  splitList(*flagPatches),
+			linkPrefix: *flagLink,
+		}
+	} else {
+		manifest := NewManifestSource(`https://lore.kernel.org`)
+		fetcher.source = &LoreSource{
+			gitRepoFolder: `/git-repo`, // Set in deployment.yaml.
+			manifest:      manifest,
+			archives:      archivesToPoll(),
+		}
+		go manifest.Loop(ctx)
+		// On start, look at the last week of messages.
+		nextFrom = time.Now().Add(-time.Hour * 24 * 7)
 	}
-	go manifest.Loop(ctx)
-
-	// On start, look at the last week of messages.
-	nextFrom := time.Now().Add(-time.Hour * 24 * 7)
 	for {
 		oldFrom := nextFrom
 		// Then, parse last 30 minutes every 15 minutes.
@@ -57,6 +74,16 @@ func main() {
 	}
 }
 
+func splitList(list string) []string {
+	var ret []string
+	for _, part := range strings.Split(list, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			ret = append(ret, part)
+		}
+	}
+	return ret
+}
+
 func archivesToPoll() []string {
 	var ret []string
 	for _, part := range strings.Split(*flagArchives, ",") {
@@ -72,48 +99,72 @@ func archivesToPoll() []string {
 	return ret
 }
 
-type SeriesFetcher struct {
+// EmailSource provides the messages to extract the patch series from.
+type EmailSource interface {
+	// Emails returns the messages that appeared since the specified time.
+	Emails(ctx context.Context, from time.Time) ([]lore.EmailReader, error)
+	// Link returns the web archive URL of the message.
+	Link(messageID string) string
+}
+
+// LoreSource polls the public-inbox git archives of the mailing lists.
+type LoreSource struct {
 	gitRepoFolder string
-	client        *api.Client
 	manifest      *ManifestSource
 	archives      []string
 }
 
-func (sf *SeriesFetcher) Update(ctx context.Context, from time.Time) error {
-	log.Printf("querying email threads since %v", from)
-
-	manifest := sf.manifest.Get(ctx)
+func (ls *LoreSource) Emails(ctx context.Context, from time.Time) ([]lore.EmailReader, error) {
+	manifest := ls.manifest.Get(ctx)
 	if manifest == nil {
-		return fmt.Errorf("failed to query the manifest data")
+		return nil, fmt.Errorf("failed to query the manifest data")
 	}
 	var list []lore.EmailReader
-	for _, name := range sf.archives {
+	for _, name := range ls.archives {
 		info, ok := manifest[name]
 		if !ok {
-			return fmt.Errorf("manifest has no info for %q", name)
+			return nil, fmt.Errorf("manifest has no info for %q", name)
 		}
 		url := info.LastEpochURL()
 		log.Printf("polling %s", url)
 
 		folderName := sanitizeName(name)
 		if folderName == "" {
-			return fmt.Errorf("invalid archive name: %q", name)
+			return nil, fmt.Errorf("invalid archive name: %q", name)
 		}
-		gitRepo := vcs.NewLKMLRepo(filepath.Join(sf.gitRepoFolder, folderName))
+		gitRepo := vcs.NewLKMLRepo(filepath.Join(ls.gitRepoFolder, folderName))
 		// TODO: by querying only the last archive, we risk losing the series that are split between both.
 		// But for now let's ignore this possibility.
 		_, err := gitRepo.Poll(url, "master")
 		if err != nil {
-			return fmt.Errorf("failed to poll %q: %w", url, err)
+			return nil, fmt.Errorf("failed to poll %q: %w", url, err)
 		}
 		repoList, err := lore.ReadArchive(gitRepo, from)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		log.Printf("queried %d emails", len(repoList))
 		list = append(list, repoList...)
 	}
+	return list, nil
+}
 
+func (ls *LoreSource) Link(messageID string) string {
+	return "https://lore.kernel.org/all/" + messageID
+}
+
+type SeriesFetcher struct {
+	client *api.Client
+	source EmailSource
+}
+
+func (sf *SeriesFetcher) Update(ctx context.Context, from time.Time) error {
+	log.Printf("querying email threads since %v", from)
+
+	list, err := sf.source.Emails(ctx, from)
+	if err != nil {
+		return err
+	}
 	var emails []*email.Email
 	idToReader := map[string]lore.EmailReader{}
 	for _, item := range list {
@@ -162,7 +213,7 @@ func (sf *SeriesFetcher) handleSeries(ctx context.Context, series *lore.Series,
 		// TODO: set Cc.
 		Title:       series.Subject,
 		Version:     series.Version,
-		Link:        "https://lore.kernel.org/all/" + series.MessageID,
+		Link:        sf.source.Link(series.MessageID),
 		PublishedAt: date,
 	}
 	sp := seriesProcessor{}
@@ -180,7 +231,7 @@ func (sf *SeriesFetcher) handleSeries(ctx context.Context, series *lore.Series,
 		apiSeries.Patches = append(apiSeries.Patches, api.SeriesPatch{
 			Seq:   patch.Seq,
 			Title: patch.Subject,
-			Link:  "https://lore.kernel.org/all/" + patch.MessageID,
+			Link:  sf.source.Link(patch.MessageID),
 			Body:  body,
 		})
 	}
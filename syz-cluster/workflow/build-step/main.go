@@ -13,6 +13,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/google/syzkaller/pkg/build"
 	"github.com/google/syzkaller/pkg/debugtracer"
@@ -32,6 +33,9 @@ var (
 	flagSession    = flag.String("session", "", "session ID")
 	flagFindings   = flag.Bool("findings", false, "report build failures as findings")
 	flagSmokeBuild = flag.Bool("smoke_build", false, "build only if new, don't report findings")
+	flagConfigs    = flag.String("kernel_configs", "/kernel-configs", "directory with kernel configs")
+	flagUserspace  = flag.String("userspace", "/disk-images/buildroot_{arch}_2024.09", // See the Dockerfile.
+		"path to the userspace image, {arch} is replaced with the target arch")
 )
 
 func main() {
@@ -201,13 +205,13 @@ func checkoutKernel(tracer debugtracer.DebugTracer, req *api.BuildRequest, serie
 }
 
 func buildKernel(tracer debugtracer.DebugTracer, req *api.BuildRequest) error {
-	kernelConfig, err := os.ReadFile(filepath.Join("/kernel-configs", req.ConfigName))
+	kernelConfig, err := os.ReadFile(filepath.Join(*flagConfigs, req.ConfigName))
 	if err != nil {
 		return fmt.Errorf("failed to read the kernel config: %w", err)
 	}
-	if req.Arch != "amd64" {
+	if req.Arch != "amd64" && req.Arch != "arm64" {
 		// TODO: lift this restriction.
-		return fmt.Errorf("only amd64 builds are supported now")
+		return fmt.Errorf("only amd64 and arm64 builds are supported now")
 	}
 	params := build.Params{
 		TargetOS:     targets.Linux,
@@ -217,7 +221,7 @@ func buildKernel(tracer debugtracer.DebugTracer, req *api.BuildRequest) error {
 		OutputDir:    *flagOutput,
 		Compiler:     "clang",
 		Linker:       "ld.lld",
-		UserspaceDir: "/disk-images/buildroot_amd64_2024.09", // See the Dockerfile.
+		UserspaceDir: strings.ReplaceAll(*flagUserspace, "{arch}", req.Arch),
 		Config:       kernelConfig,
 		Tracer:       tracer,
 	}
@@ -5,7 +5,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
@@ -15,29 +14,27 @@ import (
 	"path/filepath"
 	"time"
 
-	"github.com/google/syzkaller/pkg/config"
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/manager"
-	"github.com/google/syzkaller/pkg/mgrconfig"
-	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/prog"
 	"github.com/google/syzkaller/syz-cluster/pkg/api"
 	"github.com/google/syzkaller/syz-cluster/pkg/app"
+	"github.com/google/syzkaller/syz-cluster/pkg/fuzzconfig"
 	"golang.org/x/sync/errgroup"
 )
 
 var (
 	flagConfig       = flag.String("config", "", "syzkaller config")
 	flagSession      = flag.String("session", "", "session ID")
+	flagTestName     = flag.String("test_name", "Fuzzing", "test name")
 	flagBaseBuild    = flag.String("base_build", "", "base build ID")
 	flagPatchedBuild = flag.String("patched_build", "", "patched build ID")
 	flagTime         = flag.String("time", "1h", "how long to fuzz")
 	flagWorkdir      = flag.String("workdir", "/workdir", "base workdir path")
 	flagCorpusURL    = flag.String("corpus_url", "", "an URL to download corpus from")
+	flagConfigs      = flag.String("configs", "/configs", "directory with syzkaller configs")
 )
 
-const testName = "Fuzzing"
-
 func main() {
 	flag.Parse()
 	if *flagConfig == "" || *flagSession == "" || *flagTime == "" {
@@ -81,7 +78,7 @@ func run(baseCtx context.Context, client *api.Client) error {
 	const MB = 1000000
 	log.EnableLogCaching(10000, 10*MB)
 
-	base, patched, err := loadConfigs("/configs", *flagConfig, true)
+	base, patched, err := fuzzconfig.Load(*flagConfigs, *flagConfig, *flagWorkdir)
 	if err != nil {
 		return fmt.Errorf("failed to load configs: %w", err)
 	}
@@ -161,51 +158,10 @@ func downloadCorpus(ctx context.Context, workdir, url string) error {
 	return err
 }
 
-// To reduce duplication, patched configs are stored as a delta to their corresponding base.cfg version.
-// loadConfigs performs all the necessary merging and parsing and returns two ready to use configs.
-func loadConfigs(configFolder, configName string, complete bool) (*mgrconfig.Config, *mgrconfig.Config, error) {
-	var baseRaw, deltaRaw json.RawMessage
-	err := config.LoadFile(filepath.Join(configFolder, configName, "base.cfg"), &baseRaw)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read the base config: %w", err)
-	}
-	err = config.LoadFile(filepath.Join(configFolder, configName, "patched.cfg"), &deltaRaw)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read the patched config: %w", err)
-	}
-	patchedRaw, err := config.MergeJSONs(baseRaw, deltaRaw)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to merge the configs: %w", err)
-	}
-	base, err := mgrconfig.LoadPartialData(baseRaw)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to parse the base config: %w", err)
-	}
-	patched, err := mgrconfig.LoadPartialData(patchedRaw)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to parse the patched config: %w", err)
-	}
-	if complete {
-		base.Workdir = filepath.Join(*flagWorkdir, "base")
-		osutil.MkdirAll(base.Workdir)
-		patched.Workdir = filepath.Join(*flagWorkdir, "patched")
-		osutil.MkdirAll(patched.Workdir)
-		err = mgrconfig.Complete(base)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to complete the base config: %w", err)
-		}
-		err = mgrconfig.Complete(patched)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to complete the patched config: %w", err)
-		}
-	}
-	return base, patched, nil
-}
-
 func reportStatus(ctx context.Context, client *api.Client, status string) error {
 	testResult := &api.TestResult{
 		SessionID:      *flagSession,
-		TestName:       testName,
+		TestName:       *flagTestName,
 		BaseBuildID:    *flagBaseBuild,
 		PatchedBuildID: *flagPatchedBuild,
 		Result:         status,
@@ -217,7 +173,7 @@ func reportStatus(ctx context.Context, client *api.Client, status string) error
 func reportFinding(ctx context.Context, client *api.Client, bug *manager.UniqueBug) error {
 	finding := &api.NewFinding{
 		SessionID: *flagSession,
-		TestName:  testName,
+		TestName:  *flagTestName,
 		Title:     bug.Report.Title,
 		Report:    bug.Report.Report,
 		Log:       bug.Report.Output,
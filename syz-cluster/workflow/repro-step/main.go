@@ -0,0 +1,303 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/csource"
+	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/manager"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/subsystem"
+	_ "github.com/google/syzkaller/pkg/subsystem/lists"
+	"github.com/google/syzkaller/pkg/vcs"
+	"github.com/google/syzkaller/syz-cluster/pkg/api"
+	"github.com/google/syzkaller/syz-cluster/pkg/app"
+	"github.com/google/syzkaller/syz-cluster/pkg/fuzzconfig"
+)
+
+var (
+	flagConfig       = flag.String("config", "", "syzkaller config")
+	flagSession      = flag.String("session", "", "session ID")
+	flagTestName     = flag.String("test_name", "Reproducers", "test name")
+	flagBaseBuild    = flag.String("base_build", "", "base build ID")
+	flagPatchedBuild = flag.String("patched_build", "", "patched build ID")
+	flagTime         = flag.String("time", "1h", "the time limit for running the reproducers")
+	flagWorkdir      = flag.String("workdir", "/workdir", "base workdir path")
+	flagConfigs      = flag.String("configs", "/configs", "directory with syzkaller configs")
+	flagRepros       = flag.String("repros", "",
+		"syz-manager workdir with the known reproducers or an URL of its .tar.gz archive")
+	flagMaxRepros = flag.Int("max_repros", 20, "the maximum number of reproducers to run")
+)
+
+func main() {
+	flag.Parse()
+	if *flagConfig == "" || *flagSession == "" {
+		app.Fatalf("--config and --session must be set")
+	}
+	d, err := time.ParseDuration(*flagTime)
+	if err != nil {
+		app.Fatalf("invalid --time: %v", err)
+	}
+	client := app.DefaultClient()
+	ctx := context.Background()
+	if err := reportStatus(ctx, client, api.TestRunning); err != nil {
+		app.Fatalf("failed to report the test: %v", err)
+	}
+	const MB = 1000000
+	log.EnableLogCaching(10000, 10*MB)
+
+	runCtx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	found, err := run(runCtx, client)
+	status := api.TestPassed
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		app.Errorf("the step failed: %v", err)
+		status = api.TestError
+	} else if found > 0 {
+		status = api.TestFailed
+	}
+	if err := reportStatus(ctx, client, status); err != nil {
+		app.Fatalf("failed to update the test: %v", err)
+	}
+}
+
+// run executes the known reproducers for the subsystems touched by the series.
+// It returns the number of reproducers that crashed the patched, but not the base kernel.
+func run(ctx context.Context, client *api.Client) (int, error) {
+	if *flagRepros == "" {
+		log.Logf(0, "no reproducers are configured")
+		return 0, nil
+	}
+	series, err := client.GetSessionSeries(ctx, *flagSession)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query the series info: %w", err)
+	}
+	base, patched, err := fuzzconfig.Load(*flagConfigs, *flagConfig, *flagWorkdir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load configs: %w", err)
+	}
+	dir := *flagRepros
+	if strings.HasPrefix(dir, "http://") || strings.HasPrefix(dir, "https://") {
+		dir = filepath.Join(*flagWorkdir, "repros")
+		if err := downloadRepros(ctx, *flagRepros, dir); err != nil {
+			return 0, fmt.Errorf("failed to download the reproducers: %w", err)
+		}
+	}
+	reporter, err := report.NewReporter(patched)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create a reporter: %w", err)
+	}
+	list := subsystem.GetList(patched.TargetOS)
+	repros, err := loadRepros(dir, subsystem.MakeExtractor(list), reporter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load the reproducers: %w", err)
+	}
+	selected := selectRepros(repros, list, series.PatchBodies(), *flagMaxRepros)
+	log.Logf(0, "selected %d out of %d reproducers", len(selected), len(repros))
+	found := 0
+	for _, repro := range selected {
+		if err := ctx.Err(); err != nil {
+			return found, err
+		}
+		log.Logf(0, "testing %q on the patched kernel", repro.title)
+		rep, err := testRepro(patched, repro)
+		if err != nil {
+			return found, err
+		} else if rep == nil {
+			continue
+		}
+		log.Logf(0, "%q: crashed the patched kernel with %q, checking the base kernel", repro.title, rep.Title)
+		baseRep, err := testRepro(base, repro)
+		if err != nil {
+			return found, err
+		} else if baseRep != nil {
+			log.Logf(0, "%q: the base kernel is also affected (%q)", repro.title, baseRep.Title)
+			continue
+		}
+		found++
+		err = client.UploadFinding(ctx, &api.NewFinding{
+			SessionID: *flagSession,
+			TestName:  *flagTestName,
+			Title:     rep.Title,
+			Type:      api.FindingRegression,
+			Report:    rep.Report,
+			Log:       rep.Output,
+		})
+		if err != nil {
+			app.Errorf("failed to report a finding %s: %v", rep.Title, err)
+		}
+	}
+	return found, nil
+}
+
+type reproducer struct {
+	title      string
+	prog       []byte
+	opts       []byte
+	cprog      []byte
+	subsystems []string
+}
+
+// loadRepros reads the reproducers from the syz-manager crash store.
+// The subsystems of each bug may be listed in the "subsystems" file next to the reproducer
+// (e.g. if the reproducers were taken from the dashboard), otherwise they are deduced from the report.
+func loadRepros(dir string, extractor *subsystem.Extractor, reporter *report.Reporter) ([]*reproducer, error) {
+	store := manager.ReadCrashStore(dir)
+	bugs, err := store.BugList()
+	if err != nil {
+		return nil, err
+	}
+	var ret []*reproducer
+	for _, bug := range bugs {
+		if !bug.HasRepro && !bug.HasCRepro {
+			continue
+		}
+		info, err := store.Report(bug.ID)
+		if err != nil {
+			return nil, err
+		}
+		repro := &reproducer{
+			title: info.Title,
+			cprog: info.CProg,
+		}
+		repro.opts, repro.prog = splitReproOpts(info.Prog)
+		list, err := os.ReadFile(filepath.Join(dir, "crashes", bug.ID, "subsystems"))
+		if err == nil {
+			repro.subsystems = strings.Fields(string(list))
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		} else {
+			crash := &subsystem.Crash{SyzRepro: repro.prog}
+			if rep := reporter.Parse(info.Report); rep != nil {
+				crash.GuiltyPath = rep.GuiltyFile
+			}
+			for _, item := range extractor.Extract([]*subsystem.Crash{crash}) {
+				repro.subsystems = append(repro.subsystems, item.Name)
+			}
+		}
+		ret = append(ret, repro)
+	}
+	return ret, nil
+}
+
+// syz-manager saves the reproducer options as the first comment line of repro.prog.
+func splitReproOpts(prog []byte) ([]byte, []byte) {
+	if !bytes.HasPrefix(prog, []byte("# ")) {
+		return nil, prog
+	}
+	opts, rest, _ := bytes.Cut(prog, []byte("\n"))
+	return opts[2:], rest
+}
+
+// selectRepros returns the reproducers for the subsystems affected by the patches.
+func selectRepros(repros []*reproducer, list []*subsystem.Subsystem, patches [][]byte, max int) []*reproducer {
+	matcher := subsystem.MakePathMatcher(list)
+	touched := map[string]bool{}
+	for _, patch := range patches {
+		for _, file := range vcs.ParseGitDiff(patch) {
+			for _, item := range matcher.Match(file) {
+				touched[item.Name] = true
+			}
+		}
+	}
+	var ret []*reproducer
+	for _, repro := range repros {
+		for _, name := range repro.subsystems {
+			if touched[name] {
+				ret = append(ret, repro)
+				break
+			}
+		}
+		if len(ret) == max {
+			break
+		}
+	}
+	return ret
+}
+
+// testRepro returns the crash report if the reproducer crashed the kernel.
+func testRepro(cfg *mgrconfig.Config, repro *reproducer) (*report.Report, error) {
+	env, err := instance.NewEnv(cfg, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	prog, opts, cprog := repro.prog, repro.opts, repro.cprog
+	if len(prog) != 0 {
+		// The syz reproducer is more reliable, don't run both.
+		cprog = nil
+		if len(opts) == 0 {
+			opts = csource.DefaultOpts(cfg).Serialize()
+		}
+	}
+	const reproVMs = 2
+	results, err := env.Test(reproVMs, prog, opts, cprog)
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range results {
+		var crashErr *instance.CrashError
+		if errors.As(res.Error, &crashErr) {
+			return crashErr.Report, nil
+		} else if res.Error != nil {
+			// Boot errors and the like were already checked by the boot tests.
+			log.Logf(0, "%q: the test failed: %v", repro.title, res.Error)
+		}
+	}
+	return nil, nil
+}
+
+func downloadRepros(ctx context.Context, url, dir string) error {
+	if err := osutil.MkdirAll(dir); err != nil {
+		return err
+	}
+	archive := filepath.Join(dir, "repros.tar.gz")
+	out, err := os.Create(archive)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := (&http.Client{}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status is not 200: %s", resp.Status)
+	}
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		return err
+	}
+	_, err = osutil.RunCmd(time.Hour, dir, "tar", "-xzf", archive)
+	return err
+}
+
+func reportStatus(ctx context.Context, client *api.Client, status string) error {
+	return client.UploadTestResult(ctx, &api.TestResult{
+		SessionID:      *flagSession,
+		TestName:       *flagTestName,
+		BaseBuildID:    *flagBaseBuild,
+		PatchedBuildID: *flagPatchedBuild,
+		Result:         status,
+		Log:            []byte(log.CachedLogOutput()),
+	})
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/subsystem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRepros(t *testing.T) {
+	dir := t.TempDir()
+	for id, files := range map[string]map[string]string{
+		"1": {
+			"description": "KASAN: use-after-free in tcp_close\n",
+			"repro.prog":  "# {Threaded:true Repeat:true}\nsocket(0x2, 0x1, 0x0)\n",
+			"subsystems":  "net\n",
+		},
+		"2": {
+			"description": "WARNING in ext4_write\n",
+			"repro.cprog": "int main() {}",
+			"subsystems":  "ext4 fs",
+		},
+		"3": {
+			"description": "no reproducer\n",
+			"log0":        "some log",
+		},
+	} {
+		bugDir := filepath.Join(dir, "crashes", id)
+		require.NoError(t, os.MkdirAll(bugDir, 0755))
+		for name, data := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(bugDir, name), []byte(data), 0644))
+		}
+	}
+	repros, err := loadRepros(dir, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []*reproducer{
+		{
+			title:      "KASAN: use-after-free in tcp_close",
+			prog:       []byte("socket(0x2, 0x1, 0x0)\n"),
+			opts:       []byte("{Threaded:true Repeat:true}"),
+			subsystems: []string{"net"},
+		},
+		{
+			title:      "WARNING in ext4_write",
+			cprog:      []byte("int main() {}"),
+			subsystems: []string{"ext4", "fs"},
+		},
+	}, repros)
+}
+
+func TestSelectRepros(t *testing.T) {
+	list := []*subsystem.Subsystem{
+		{Name: "net", PathRules: []subsystem.PathRule{{IncludeRegexp: `^net/`}}},
+		{Name: "ext4", PathRules: []subsystem.PathRule{{IncludeRegexp: `^fs/ext4/`}}},
+		{Name: "mm", PathRules: []subsystem.PathRule{{IncludeRegexp: `^mm/`}}},
+	}
+	repros := []*reproducer{
+		{title: "net bug 1", subsystems: []string{"net"}},
+		{title: "ext4 bug", subsystems: []string{"ext4"}},
+		{title: "net bug 2", subsystems: []string{"mm", "net"}},
+		{title: "unknown"},
+	}
+	patch := []byte(`diff --git a/net/ipv4/tcp.c b/net/ipv4/tcp.c
+--- a/net/ipv4/tcp.c
++++ b/net/ipv4/tcp.c
+@@ -1 +1 @@
+-a
++b
+`)
+	var titles []string
+	for _, repro := range selectRepros(repros, list, [][]byte{patch}, 10) {
+		titles = append(titles, repro.title)
+	}
+	assert.Equal(t, []string{"net bug 1", "net bug 2"}, titles)
+	assert.Len(t, selectRepros(repros, list, [][]byte{patch}, 1), 1)
+}
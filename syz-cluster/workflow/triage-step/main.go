@@ -7,6 +7,8 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"log"
+	"strings"
 
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/syz-cluster/pkg/api"
@@ -18,6 +20,8 @@ var (
 	flagSession = flag.String("session", "", "session ID")
 	flagRepo    = flag.String("repository", "", "path to a kernel checkout")
 	flagVerdict = flag.String("verdict", "", "where to save the verdict")
+	flagArchs   = flag.String("archs", "", "comma-separated list of the archs (besides amd64) "+
+		"to also fuzz the series on if it touches the arch-specific code")
 )
 
 func main() {
@@ -46,9 +50,7 @@ func main() {
 		osutil.WriteJSON(*flagVerdict, verdict)
 	}
 
-	// TODO:
-	// 1. It might be that the kernel builds/boots for one arch and does not build for another.
-	// 2. What if controller does not reply? Let Argo just restart the step.
+	// TODO: What if controller does not reply? Let Argo just restart the step.
 }
 
 func getVerdict(ctx context.Context, client *api.Client, ops triage.TreeOps) (*api.TriageResult, error) {
@@ -69,10 +71,40 @@ func getVerdict(ctx context.Context, client *api.Client, ops triage.TreeOps) (*a
 			},
 		}, nil
 	}
-	arch := "amd64"
+	targets := append([]*triage.ArchTarget{{
+		Arch:         triage.DefaultArch,
+		KernelConfig: tree.KernelConfig,
+		FuzzConfig:   tree.FuzzConfig,
+	}}, triage.SelectArchTargets(series, splitList(*flagArchs))...)
+	ret := &api.TriageResult{}
+	selector := triage.NewCommitSelector(ops)
+	for i, target := range targets {
+		fuzz, err := selectFuzzConfig(ctx, client, selector, series, tree, target)
+		if err != nil {
+			return nil, err
+		} else if fuzz == nil && i == 0 {
+			return &api.TriageResult{
+				Skip: &api.SkipRequest{
+					Reason: "no suitable commits found",
+				},
+			}, nil
+		} else if fuzz == nil {
+			log.Printf("no suitable commits found for %v", target.Arch)
+			continue
+		}
+		if i > 0 {
+			fuzz.TestSuffix = fmt.Sprintf(" [%v]", target.Arch)
+		}
+		ret.Fuzz = append(ret.Fuzz, fuzz)
+	}
+	return ret, nil
+}
+
+func selectFuzzConfig(ctx context.Context, client *api.Client, selector *triage.CommitSelector,
+	series *api.Series, tree *api.Tree, target *triage.ArchTarget) (*api.FuzzConfig, error) {
 	lastBuild, err := client.LastBuild(ctx, &api.LastBuildReq{
-		Arch:       arch,
-		ConfigName: tree.KernelConfig,
+		Arch:       target.Arch,
+		ConfigName: target.KernelConfig,
 		TreeName:   tree.Name,
 		Status:     api.BuildSuccess,
 	})
@@ -80,32 +112,35 @@ func getVerdict(ctx context.Context, client *api.Client, ops triage.TreeOps) (*a
 		// TODO: the workflow step must be retried.
 		return nil, fmt.Errorf("failed to query the last build: %w", err)
 	}
-	selector := triage.NewCommitSelector(ops)
 	commit, err := selector.Select(series, tree, lastBuild)
 	if err != nil {
 		// TODO: the workflow step must be retried.
 		return nil, fmt.Errorf("failed to run the commit selector: %w", err)
 	} else if commit == "" {
-		return &api.TriageResult{
-			Skip: &api.SkipRequest{
-				Reason: "no suitable commits found",
-			},
-		}, nil
+		return nil, nil
 	}
 	base := api.BuildRequest{
 		TreeName:   tree.Name,
-		ConfigName: tree.KernelConfig,
+		ConfigName: target.KernelConfig,
 		CommitHash: commit,
-		Arch:       arch,
+		Arch:       target.Arch,
 	}
-	ret := &api.TriageResult{
-		Fuzz: &api.FuzzConfig{
-			Base:      base,
-			Patched:   base,
-			Config:    tree.FuzzConfig,
-			CorpusURL: tree.CorpusURL(),
-		},
+	ret := &api.FuzzConfig{
+		Base:      base,
+		Patched:   base,
+		Config:    target.FuzzConfig,
+		CorpusURL: tree.CorpusURL(),
 	}
-	ret.Fuzz.Patched.SeriesID = series.ID
+	ret.Patched.SeriesID = series.ID
 	return ret, nil
 }
+
+func splitList(list string) []string {
+	var ret []string
+	for _, part := range strings.Split(list, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			ret = append(ret, part)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,407 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"compress/gzip"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/syz-dashboard/state"
+)
+
+type apiHandler func(dash *Dashboard, payload io.Reader) (any, error)
+
+var apiHandlers = map[string]apiHandler{
+	"log_error":             apiLogError,
+	"upload_build":          apiUploadBuild,
+	"builder_poll":          apiBuilderPoll,
+	"report_build_error":    apiReportBuildError,
+	"commit_poll":           apiCommitPoll,
+	"upload_commits":        apiUploadCommits,
+	"report_crash":          apiReportCrash,
+	"need_repro":            apiNeedRepro,
+	"report_failed_repro":   apiReportFailedRepro,
+	"log_to_repro":          apiLogToRepro,
+	"job_poll":              apiJobPoll,
+	"job_done":              apiJobDone,
+	"job_reset":             apiJobReset,
+	"new_test_job":          apiNewTestJob,
+	"manager_stats":         apiManagerStats,
+	"add_build_assets":      apiAddBuildAssets,
+	"needed_assets":         apiNeededAssets,
+	"bug_list":              apiBugList,
+	"load_bug":              apiLoadBug,
+	"update_report":         apiUpdateReport,
+	"reporting_poll_bugs":   apiReportingPollBugs,
+	"reporting_poll_notifs": apiReportingPollNotifs,
+	"reporting_poll_closed": apiReportingPollClosed,
+}
+
+var (
+	errBadRequest   = errors.New("bad request")
+	errUnauthorized = errors.New("unauthorized")
+)
+
+func (dash *Dashboard) handleAPI(w http.ResponseWriter, r *http.Request) {
+	reply, err := dash.serveAPI(r)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, errBadRequest):
+			status = http.StatusBadRequest
+		case errors.Is(err, errUnauthorized):
+			status = http.StatusUnauthorized
+		}
+		log.Logf(0, "api %q from %q failed: %v", r.PostFormValue("method"), r.PostFormValue("client"), err)
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(reply); err != nil {
+		log.Logf(0, "failed to encode reply: %v", err)
+	}
+}
+
+func (dash *Dashboard) serveAPI(r *http.Request) (any, error) {
+	client := r.PostFormValue("client")
+	method := r.PostFormValue("method")
+	log.Logf(1, "api %q from %q", method, client)
+	if err := dash.checkClient(client, r.PostFormValue("key")); err != nil {
+		return nil, err
+	}
+	handler := apiHandlers[method]
+	if handler == nil {
+		return nil, fmt.Errorf("%w: unknown api method %q", errBadRequest, method)
+	}
+	var payload io.Reader = strings.NewReader("")
+	if str := r.PostFormValue("payload"); str != "" {
+		gr, err := gzip.NewReader(strings.NewReader(str))
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to ungzip payload: %w", errBadRequest, err)
+		}
+		// Ignore Close() error because we may not read all data.
+		defer gr.Close()
+		payload = gr
+	}
+	dash.mu.Lock()
+	defer dash.mu.Unlock()
+	return handler(dash, payload)
+}
+
+func (dash *Dashboard) checkClient(client, key string) error {
+	if client == "" {
+		return fmt.Errorf("%w: client is empty", errBadRequest)
+	}
+	want, ok := dash.keys[client]
+	if !ok || subtle.ConstantTimeCompare([]byte(key), []byte(want)) != 1 {
+		return fmt.Errorf("%w: client %q", errUnauthorized, client)
+	}
+	return nil
+}
+
+func decode(payload io.Reader, req any) error {
+	if err := json.NewDecoder(payload).Decode(req); err != nil {
+		return fmt.Errorf("%w: failed to unmarshal request: %w", errBadRequest, err)
+	}
+	return nil
+}
+
+func apiLogError(dash *Dashboard, payload io.Reader) (any, error) {
+	req := new(dashapi.LogEntry)
+	if err := decode(payload, req); err != nil {
+		return nil, err
+	}
+	log.Logf(0, "%v: %v", req.Name, req.Text)
+	return nil, nil
+}
+
+func apiUploadBuild(dash *Dashboard, payload io.Reader) (any, error) {
+	req := new(dashapi.Build)
+	if err := decode(payload, req); err != nil {
+		return nil, err
+	}
+	return nil, dash.st.UploadBuild(req)
+}
+
+func apiBuilderPoll(dash *Dashboard, payload io.Reader) (any, error) {
+	req := new(dashapi.BuilderPollReq)
+	if err := decode(payload, req); err != nil {
+		return nil, err
+	}
+	resp := &dashapi.BuilderPollResp{
+		PendingCommits: dash.st.PendingCommits(req.Manager),
+		ReportEmail:    dash.cfg.ReportEmail,
+	}
+	return resp, nil
+}
+
+func apiReportBuildError(dash *Dashboard, payload io.Reader) (any, error) {
+	req := new(dashapi.BuildErrorReq)
+	if err := decode(payload, req); err != nil {
+		return nil, err
+	}
+	if err := dash.st.UploadBuild(&req.Build); err != nil {
+		return nil, err
+	}
+	req.Crash.BuildID = req.Build.ID
+	_, err := dash.st.ReportCrash(&req.Crash)
+	return nil, err
+}
+
+func apiCommitPoll(dash *Dashboard, payload io.Reader) (any, error) {
+	resp := &dashapi.CommitPollResp{
+		ReportEmail: dash.cfg.ReportEmail,
+		Repos:       dash.cfg.Repos,
+		Commits:     dash.st.PendingCommits(""),
+	}
+	return resp, nil
+}
+
+func apiUploadCommits(dash *Dashboard, payload io.Reader) (any, error) {
+	req := new(dashapi.CommitPollResultReq)
+	if err := decode(payload, req); err != nil {
+		return nil, err
+	}
+	return nil, dash.st.UploadCommits(req.Commits)
+}
+
+func apiReportCrash(dash *Dashboard, payload io.Reader) (any, error) {
+	req := new(dashapi.Crash)
+	if err := decode(payload, req); err != nil {
+		return nil, err
+	}
+	bug, err := dash.st.ReportCrash(req)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dashapi.ReportCrashResp{
+		NeedRepro: dash.st.NeedRepro(bug),
+	}
+	return resp, nil
+}
+
+func apiNeedRepro(dash *Dashboard, payload io.Reader) (any, error) {
+	req := new(dashapi.CrashID)
+	if err := decode(payload, req); err != nil {
+		return nil, err
+	}
+	resp := new(dashapi.NeedReproResp)
+	if req.Corrupted {
+		return resp, nil
+	}
+	bug := dash.st.FindBug(state.CanonicalTitle(req.Title, req.Corrupted, req.Suppressed))
+	if bug == nil {
+		// Manager does not send leak reports w/o repro to dashboard, we want to reproduce them.
+		resp.NeedRepro = req.MayBeMissing
+		return resp, nil
+	}
+	resp.NeedRepro = dash.st.NeedRepro(bug)
+	return resp, nil
+}
+
+func apiReportFailedRepro(dash *Dashboard, payload io.Reader) (any, error) {
+	req := new(dashapi.CrashID)
+	if err := decode(payload, req); err != nil {
+		return nil, err
+	}
+	bug := dash.st.FindBug(state.CanonicalTitle(req.Title, req.Corrupted, req.Suppressed))
+	if bug == nil {
+		return nil, fmt.Errorf("%w: can't find bug for crash %q", errBadRequest, req.Title)
+	}
+	return nil, dash.st.ReportFailedRepro(bug)
+}
+
+func apiLogToRepro(dash *Dashboard, payload io.Reader) (any, error) {
+	// There is no way to request reproduction of particular logs yet.
+	return new(dashapi.LogToReproResp), nil
+}
+
+func apiJobPoll(dash *Dashboard, payload io.Reader) (any, error) {
+	req := new(dashapi.JobPollReq)
+	if err := decode(payload, req); err != nil {
+		return nil, err
+	}
+	job, err := dash.st.PollJob(req.Managers)
+	if err != nil || job == nil {
+		return new(dashapi.JobPollResp), err
+	}
+	crash := dash.st.Crashes[job.CrashID]
+	build := dash.st.Builds[crash.BuildID]
+	resp := &dashapi.JobPollResp{
+		ID:                job.ID,
+		Type:              job.Type,
+		Manager:           job.Manager,
+		KernelRepo:        build.KernelRepo,
+		KernelBranch:      build.KernelBranch,
+		KernelCommit:      build.KernelCommit,
+		KernelCommitTitle: build.KernelCommitTitle,
+		KernelConfig:      build.KernelConfig,
+		SyzkallerCommit:   build.SyzkallerCommit,
+		ReproOpts:         crash.ReproOpts,
+		ReproSyz:          crash.ReproSyz,
+		ReproC:            crash.ReproC,
+	}
+	if job.Type == dashapi.JobTestPatch {
+		resp.KernelRepo = job.Repo
+		resp.KernelBranch = job.Branch
+		resp.Patch = job.Patch
+	}
+	return resp, nil
+}
+
+func apiJobDone(dash *Dashboard, payload io.Reader) (any, error) {
+	req := new(dashapi.JobDoneReq)
+	if err := decode(payload, req); err != nil {
+		return nil, err
+	}
+	return nil, dash.st.JobDone(req)
+}
+
+func apiJobReset(dash *Dashboard, payload io.Reader) (any, error) {
+	req := new(dashapi.JobResetReq)
+	if err := decode(payload, req); err != nil {
+		return nil, err
+	}
+	return nil, dash.st.ResetJobs(req.Managers)
+}
+
+func apiNewTestJob(dash *Dashboard, payload io.Reader) (any, error) {
+	req := new(dashapi.TestPatchRequest)
+	if err := decode(payload, req); err != nil {
+		return nil, err
+	}
+	resp := new(dashapi.TestPatchReply)
+	bug := dash.st.Bugs[req.BugID]
+	switch {
+	case bug == nil:
+		resp.ErrorText = fmt.Sprintf("unknown bug %q", req.BugID)
+	case req.Repo == "" || req.Branch == "" || len(req.Patch) == 0:
+		resp.ErrorText = "repo, branch and patch are required"
+	default:
+		if _, err := dash.st.AddTestJob(bug, req); err != nil {
+			resp.ErrorText = err.Error()
+		}
+	}
+	return resp, nil
+}
+
+func apiManagerStats(dash *Dashboard, payload io.Reader) (any, error) {
+	req := new(dashapi.ManagerStatsReq)
+	if err := decode(payload, req); err != nil {
+		return nil, err
+	}
+	return nil, dash.st.UpdateManager(req)
+}
+
+func apiAddBuildAssets(dash *Dashboard, payload io.Reader) (any, error) {
+	req := new(dashapi.AddBuildAssetsReq)
+	if err := decode(payload, req); err != nil {
+		return nil, err
+	}
+	return nil, dash.st.AddBuildAssets(req)
+}
+
+func apiNeededAssets(dash *Dashboard, payload io.Reader) (any, error) {
+	resp := &dashapi.NeededAssetsResp{
+		DownloadURLs: dash.st.NeededAssets(),
+	}
+	return resp, nil
+}
+
+func apiBugList(dash *Dashboard, payload io.Reader) (any, error) {
+	resp := new(dashapi.BugListResp)
+	for _, bug := range dash.st.SortedBugs() {
+		if bug.IsOpen() {
+			resp.List = append(resp.List, bug.ID)
+		}
+	}
+	return resp, nil
+}
+
+func apiLoadBug(dash *Dashboard, payload io.Reader) (any, error) {
+	req := new(dashapi.LoadBugReq)
+	if err := decode(payload, req); err != nil {
+		return nil, err
+	}
+	bug := dash.st.Bugs[req.ID]
+	if bug == nil {
+		return nil, fmt.Errorf("%w: unknown bug %q", errBadRequest, req.ID)
+	}
+	rep := &dashapi.BugReport{
+		BugStatus:  bug.Status,
+		ID:         bug.ID,
+		Title:      bug.DisplayTitle(),
+		NumCrashes: bug.NumCrashes,
+	}
+	crash := dash.st.BestCrash(bug)
+	if crash == nil && len(bug.Crashes) != 0 {
+		crash = dash.st.Crashes[bug.Crashes[len(bug.Crashes)-1]]
+	}
+	if crash == nil {
+		return rep, nil
+	}
+	rep.CrashID = crash.ID
+	rep.CrashTime = crash.Time
+	rep.Manager = crash.Manager
+	rep.Log = crash.Log
+	rep.Report = crash.Report
+	rep.MachineInfo = crash.MachineInfo
+	rep.ReproOpts = crash.ReproOpts
+	rep.ReproSyz = crash.ReproSyz
+	rep.ReproC = crash.ReproC
+	rep.ReportElements = &dashapi.ReportElements{GuiltyFiles: crash.GuiltyFiles}
+	if build := dash.st.Builds[crash.BuildID]; build != nil {
+		rep.OS = build.OS
+		rep.Arch = build.Arch
+		rep.VMArch = build.VMArch
+		rep.BuildID = build.ID
+		rep.BuildTime = build.Time
+		rep.CompilerID = build.CompilerID
+		rep.KernelRepo = build.KernelRepo
+		rep.KernelBranch = build.KernelBranch
+		rep.KernelCommit = build.KernelCommit
+		rep.KernelCommitTitle = build.KernelCommitTitle
+		rep.KernelCommitDate = build.KernelCommitDate
+		rep.KernelConfig = build.KernelConfig
+		rep.SyzkallerCommit = build.SyzkallerCommit
+	}
+	return rep, nil
+}
+
+func apiUpdateReport(dash *Dashboard, payload io.Reader) (any, error) {
+	req := new(dashapi.UpdateReportReq)
+	if err := decode(payload, req); err != nil {
+		return nil, err
+	}
+	crash := dash.st.Crashes[req.CrashID]
+	if crash == nil || crash.BugID != req.BugID {
+		return nil, fmt.Errorf("%w: unknown crash %v of bug %q", errBadRequest, req.CrashID, req.BugID)
+	}
+	if req.GuiltyFiles == nil {
+		return nil, nil
+	}
+	return nil, dash.st.UpdateGuiltyFiles(crash, *req.GuiltyFiles)
+}
+
+// There is no external reporting, so reporting polls always return nothing.
+
+func apiReportingPollBugs(dash *Dashboard, payload io.Reader) (any, error) {
+	return new(dashapi.PollBugsResponse), nil
+}
+
+func apiReportingPollNotifs(dash *Dashboard, payload io.Reader) (any, error) {
+	return new(dashapi.PollNotificationsResponse), nil
+}
+
+func apiReportingPollClosed(dash *Dashboard, payload io.Reader) (any, error) {
+	return new(dashapi.PollClosedResponse), nil
+}
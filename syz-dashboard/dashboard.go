@@ -0,0 +1,94 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-dashboard is a standalone self-hosted implementation of the dashboard API (see dashboard/dashapi)
+// that stores everything in a local database. It can be used instead of the App Engine dashboard
+// (dashboard/app) by syz-ci and syz-manager. It supports bug deduplication by title,
+// crash and reproducer storage, builds, patch testing and bisection jobs, and shows a simple
+// list of bugs on the web page. External reporting (email, etc) is not supported.
+// See docs/dashboard.md for details.
+package main
+
+import (
+	"flag"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/config"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/tool"
+	"github.com/google/syzkaller/syz-dashboard/state"
+)
+
+var (
+	flagConfig = flag.String("config", "", "config file")
+)
+
+type Config struct {
+	// HTTP address to serve the API and the web UI on.
+	HTTP string `json:"http"`
+	// Directory for the database.
+	Workdir string `json:"workdir"`
+	// Email used in Reported-by tags of the fixing commits,
+	// must contain "+" (e.g. "syzbot@example.com" -> "syzbot+BUG_ID@example.com").
+	ReportEmail string `json:"report_email,omitempty"`
+	// Kernel repos polled for fixing commits by syz-ci.
+	Repos []dashapi.Repo `json:"repos,omitempty"`
+	// Clients allowed to use the API.
+	Clients []struct {
+		Name string `json:"name"`
+		Key  string `json:"key"`
+	} `json:"clients"`
+}
+
+type Dashboard struct {
+	mu   sync.Mutex
+	st   *state.State
+	cfg  *Config
+	keys map[string]string
+}
+
+func main() {
+	defer tool.Init()()
+	cfg := new(Config)
+	if err := config.LoadFile(*flagConfig, cfg); err != nil {
+		log.Fatal(err)
+	}
+	dash, err := newDashboard(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", cfg.HTTP)
+	if err != nil {
+		log.Fatalf("failed to listen on %v: %v", cfg.HTTP, err)
+	}
+	log.Logf(0, "serving http on http://%v", ln.Addr())
+	log.Fatal(http.Serve(ln, dash.handler()))
+}
+
+func newDashboard(cfg *Config) (*Dashboard, error) {
+	st, err := state.Make(cfg.Workdir)
+	if err != nil {
+		return nil, err
+	}
+	dash := &Dashboard{
+		st:   st,
+		cfg:  cfg,
+		keys: make(map[string]string),
+	}
+	for _, client := range cfg.Clients {
+		dash.keys[client.Name] = client.Key
+	}
+	return dash, nil
+}
+
+func (dash *Dashboard) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api", dash.handleAPI)
+	mux.HandleFunc("/", dash.httpBugs)
+	mux.HandleFunc("/bug", dash.httpBug)
+	mux.HandleFunc("/text", dash.httpText)
+	return mux
+}
@@ -0,0 +1,291 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDashboard struct {
+	t      *testing.T
+	cfg    *Config
+	server *httptest.Server
+	client *dashapi.Dashboard
+}
+
+func newTestDashboard(t *testing.T, workdir string) *testDashboard {
+	cfg := &Config{
+		Workdir:     workdir,
+		ReportEmail: "bot@example.com",
+	}
+	cfg.Clients = append(cfg.Clients, struct {
+		Name string `json:"name"`
+		Key  string `json:"key"`
+	}{"ci", "secret"})
+	dash, err := newDashboard(cfg)
+	require.NoError(t, err)
+	server := httptest.NewServer(dash.handler())
+	t.Cleanup(server.Close)
+	client, err := dashapi.New("ci", server.URL, "secret")
+	require.NoError(t, err)
+	return &testDashboard{
+		t:      t,
+		cfg:    cfg,
+		server: server,
+		client: client,
+	}
+}
+
+func (td *testDashboard) get(path string) string {
+	resp, err := http.Get(td.server.URL + path)
+	require.NoError(td.t, err)
+	defer resp.Body.Close()
+	require.Equal(td.t, http.StatusOK, resp.StatusCode)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(td.t, err)
+	return string(data)
+}
+
+func testBuild(id, manager string) *dashapi.Build {
+	return &dashapi.Build{
+		Manager:         manager,
+		ID:              id,
+		OS:              "linux",
+		Arch:            "amd64",
+		VMArch:          "amd64",
+		SyzkallerCommit: "syzkaller-commit",
+		KernelRepo:      "git://repo",
+		KernelBranch:    "master",
+		KernelCommit:    "kernel-commit-" + id,
+		KernelConfig:    []byte("CONFIG_KASAN=y"),
+	}
+}
+
+func TestAuth(t *testing.T) {
+	td := newTestDashboard(t, t.TempDir())
+	client, err := dashapi.New("ci", td.server.URL, "wrong")
+	require.NoError(t, err)
+	err = client.UploadBuild(testBuild("b1", "mgr"))
+	assert.ErrorContains(t, err, "401")
+	err = td.client.Query("no_such_method", nil, nil)
+	assert.ErrorContains(t, err, "400")
+}
+
+func TestCrashes(t *testing.T) {
+	td := newTestDashboard(t, t.TempDir())
+	require.NoError(t, td.client.UploadBuild(testBuild("b1", "mgr")))
+
+	crash := &dashapi.Crash{
+		BuildID: "b1",
+		Title:   "KASAN: use-after-free in foo",
+		Log:     []byte("log"),
+		Report:  []byte("report"),
+	}
+	resp, err := td.client.ReportCrash(crash)
+	require.NoError(t, err)
+	assert.True(t, resp.NeedRepro)
+	_, err = td.client.ReportCrash(crash)
+	require.NoError(t, err)
+	need, err := td.client.NeedRepro(&dashapi.CrashID{BuildID: "b1", Title: crash.Title})
+	require.NoError(t, err)
+	assert.True(t, need)
+	need, err = td.client.NeedRepro(&dashapi.CrashID{BuildID: "b1", Title: "unknown", MayBeMissing: true})
+	require.NoError(t, err)
+	assert.True(t, need)
+	need, err = td.client.NeedRepro(&dashapi.CrashID{BuildID: "b1", Title: "garbage", Corrupted: true})
+	require.NoError(t, err)
+	assert.False(t, need)
+
+	bugs, err := td.client.BugList()
+	require.NoError(t, err)
+	require.Len(t, bugs.List, 1)
+	bug, err := td.client.LoadBug(bugs.List[0])
+	require.NoError(t, err)
+	assert.Equal(t, crash.Title, bug.Title)
+	assert.Equal(t, int64(2), bug.NumCrashes)
+	assert.Equal(t, "kernel-commit-b1", bug.KernelCommit)
+	assert.Equal(t, []byte("report"), bug.Report)
+
+	// A crash with a reproducer satisfies the need for a repro and queues cause bisection.
+	crash.ReproSyz = []byte("getpid()")
+	crash.ReproC = []byte("int main() {}")
+	resp, err = td.client.ReportCrash(crash)
+	require.NoError(t, err)
+	assert.False(t, resp.NeedRepro)
+
+	page := td.get("/")
+	assert.Contains(t, page, "KASAN: use-after-free in foo")
+	page = td.get("/bug?id=" + bugs.List[0])
+	assert.Contains(t, page, "cause bisection")
+	bug, err = td.client.LoadBug(bugs.List[0])
+	require.NoError(t, err)
+	assert.Equal(t, "getpid()", td.get(fmt.Sprintf("/text?crash=%v&tag=repro_syz", bug.CrashID)))
+}
+
+func TestJobs(t *testing.T) {
+	td := newTestDashboard(t, t.TempDir())
+	require.NoError(t, td.client.UploadBuild(testBuild("b1", "mgr")))
+	_, err := td.client.ReportCrash(&dashapi.Crash{
+		BuildID:  "b1",
+		Title:    "WARNING in bar",
+		ReproSyz: []byte("getpid()"),
+	})
+	require.NoError(t, err)
+	bugs, err := td.client.BugList()
+	require.NoError(t, err)
+	reply, err := td.client.NewTestJob(&dashapi.TestPatchRequest{
+		BugID:  bugs.List[0],
+		User:   "dev@example.com",
+		Repo:   "git://dev-repo",
+		Branch: "fix",
+		Patch:  []byte("diff"),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, reply.ErrorText)
+
+	// Nothing for managers that don't do jobs.
+	job, err := td.client.JobPoll(&dashapi.JobPollReq{Managers: map[string]dashapi.ManagerJobs{
+		"other": {TestPatches: true, BisectCause: true},
+		"mgr":   {},
+	}})
+	require.NoError(t, err)
+	assert.Empty(t, job.ID)
+
+	// Patch testing goes before the bisection that was queued first.
+	managers := map[string]dashapi.ManagerJobs{"mgr": {TestPatches: true, BisectCause: true}}
+	job, err = td.client.JobPoll(&dashapi.JobPollReq{Managers: managers})
+	require.NoError(t, err)
+	assert.Equal(t, dashapi.JobTestPatch, job.Type)
+	assert.Equal(t, "git://dev-repo", job.KernelRepo)
+	assert.Equal(t, "fix", job.KernelBranch)
+	assert.Equal(t, []byte("diff"), job.Patch)
+	assert.Equal(t, []byte("getpid()"), job.ReproSyz)
+	testJob := job.ID
+
+	job, err = td.client.JobPoll(&dashapi.JobPollReq{Managers: managers})
+	require.NoError(t, err)
+	assert.Equal(t, dashapi.JobBisectCause, job.Type)
+	assert.Equal(t, "kernel-commit-b1", job.KernelCommit)
+	assert.Equal(t, []byte("CONFIG_KASAN=y"), job.KernelConfig)
+
+	// Reset returns the running jobs back to the queue.
+	require.NoError(t, td.client.JobReset(&dashapi.JobResetReq{Managers: []string{"mgr"}}))
+	job, err = td.client.JobPoll(&dashapi.JobPollReq{Managers: managers})
+	require.NoError(t, err)
+	assert.Equal(t, testJob, job.ID)
+	require.NoError(t, td.client.JobDone(&dashapi.JobDoneReq{
+		ID:    job.ID,
+		Build: *testBuild("b2", "mgr"),
+	}))
+	assert.Error(t, td.client.JobDone(&dashapi.JobDoneReq{ID: job.ID}))
+
+	job, err = td.client.JobPoll(&dashapi.JobPollReq{Managers: managers})
+	require.NoError(t, err)
+	assert.Equal(t, dashapi.JobBisectCause, job.Type)
+	require.NoError(t, td.client.JobDone(&dashapi.JobDoneReq{
+		ID:      job.ID,
+		Commits: []dashapi.Commit{{Hash: "1234", Title: "bad commit"}},
+	}))
+	page := td.get("/bug?id=" + bugs.List[0])
+	assert.Contains(t, page, "1234 bad commit")
+	assert.Contains(t, page, "OK")
+}
+
+func TestFixCommits(t *testing.T) {
+	td := newTestDashboard(t, t.TempDir())
+	require.NoError(t, td.client.UploadBuild(testBuild("b1", "mgr1")))
+	require.NoError(t, td.client.UploadBuild(testBuild("b2", "mgr2")))
+	crash := &dashapi.Crash{
+		BuildID: "b1",
+		Title:   "BUG: bad unlock",
+	}
+	_, err := td.client.ReportCrash(crash)
+	require.NoError(t, err)
+	crash.BuildID = "b2"
+	_, err = td.client.ReportCrash(crash)
+	require.NoError(t, err)
+	bugs, err := td.client.BugList()
+	require.NoError(t, err)
+	bugID := bugs.List[0]
+
+	poll, err := td.client.CommitPoll()
+	require.NoError(t, err)
+	assert.Equal(t, "bot@example.com", poll.ReportEmail)
+	assert.Empty(t, poll.Commits)
+	require.NoError(t, td.client.UploadCommits([]dashapi.Commit{{
+		Hash:   "5678",
+		Title:  "fix bad unlock",
+		BugIDs: []string{bugID},
+	}}))
+	builder, err := td.client.BuilderPoll("mgr1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fix bad unlock"}, builder.PendingCommits)
+	need, err := td.client.NeedRepro(&dashapi.CrashID{BuildID: "b1", Title: crash.Title})
+	require.NoError(t, err)
+	assert.False(t, need)
+
+	// The bug is fixed only when the fix reaches all managers where it happened.
+	build := testBuild("b3", "mgr1")
+	build.Commits = []string{"fix bad unlock"}
+	require.NoError(t, td.client.UploadBuild(build))
+	builder, err = td.client.BuilderPoll("mgr1")
+	require.NoError(t, err)
+	assert.Empty(t, builder.PendingCommits)
+	bugs, err = td.client.BugList()
+	require.NoError(t, err)
+	assert.Equal(t, []string{bugID}, bugs.List)
+	build = testBuild("b4", "mgr2")
+	build.Commits = []string{"fix bad unlock"}
+	require.NoError(t, td.client.UploadBuild(build))
+	bugs, err = td.client.BugList()
+	require.NoError(t, err)
+	assert.Empty(t, bugs.List)
+
+	// The same crash after the fix is a new bug.
+	_, err = td.client.ReportCrash(crash)
+	require.NoError(t, err)
+	bugs, err = td.client.BugList()
+	require.NoError(t, err)
+	require.Len(t, bugs.List, 1)
+	assert.NotEqual(t, bugID, bugs.List[0])
+	assert.True(t, strings.Contains(td.get("/"), "BUG: bad unlock (2)"))
+}
+
+func TestPersistence(t *testing.T) {
+	workdir := t.TempDir()
+	td := newTestDashboard(t, workdir)
+	require.NoError(t, td.client.UploadBuild(testBuild("b1", "mgr")))
+	require.NoError(t, td.client.AddBuildAssets(&dashapi.AddBuildAssetsReq{
+		BuildID: "b1",
+		Assets:  []dashapi.NewAsset{{DownloadURL: "http://assets/b1", Type: dashapi.KernelObject}},
+	}))
+	_, err := td.client.ReportCrash(&dashapi.Crash{BuildID: "b1", Title: "title"})
+	require.NoError(t, err)
+
+	td = newTestDashboard(t, workdir)
+	bugs, err := td.client.BugList()
+	require.NoError(t, err)
+	require.Len(t, bugs.List, 1)
+	bug, err := td.client.LoadBug(bugs.List[0])
+	require.NoError(t, err)
+	assert.Equal(t, "title", bug.Title)
+	assets, err := td.client.NeededAssetsList()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://assets/b1"}, assets.DownloadURLs)
+	// The crash goes into the existing bug.
+	_, err = td.client.ReportCrash(&dashapi.Crash{BuildID: "b1", Title: "title"})
+	require.NoError(t, err)
+	bug, err = td.client.LoadBug(bugs.List[0])
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), bug.NumCrashes)
+}
@@ -0,0 +1,439 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/syz-dashboard/state"
+)
+
+type UIBugsPage struct {
+	Groups   []*UIBugGroup
+	Managers []*UIManager
+}
+
+type UIBugGroup struct {
+	Caption string
+	Bugs    []*UIBug
+}
+
+type UIBug struct {
+	ID         string
+	Title      string
+	NumCrashes int64
+	FirstTime  time.Time
+	LastTime   time.Time
+	ReproLevel string
+	Managers   string
+	Commits    string
+}
+
+type UIManager struct {
+	Name       string
+	Addr       string
+	LastActive time.Time
+	Corpus     uint64
+	Cover      uint64
+	Crashes    uint64
+	Execs      uint64
+}
+
+type UIBugPage struct {
+	Bug     *UIBug
+	Crashes []*UICrash
+	Jobs    []*UIJob
+}
+
+type UICrash struct {
+	ID           int64
+	Title        string
+	Time         time.Time
+	Manager      string
+	KernelCommit string
+	HasLog       bool
+	HasReport    bool
+	HasReproSyz  bool
+	HasReproC    bool
+}
+
+type UIJob struct {
+	ID       string
+	Type     string
+	Manager  string
+	User     string
+	Created  time.Time
+	Started  time.Time
+	Finished time.Time
+	Result   string
+	HasLog   bool
+	HasError bool
+}
+
+func (dash *Dashboard) httpBugs(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	dash.mu.Lock()
+	open := &UIBugGroup{Caption: "Open bugs"}
+	fixed := &UIBugGroup{Caption: "Fixed bugs"}
+	invalid := &UIBugGroup{Caption: "Invalid bugs"}
+	data := &UIBugsPage{
+		Groups: []*UIBugGroup{open, fixed, invalid},
+	}
+	for _, bug := range dash.st.SortedBugs() {
+		group := invalid
+		switch bug.Status {
+		case dashapi.BugStatusOpen:
+			group = open
+		case dashapi.BugStatusFixed:
+			group = fixed
+		}
+		group.Bugs = append(group.Bugs, makeUIBug(bug))
+	}
+	for _, mgr := range dash.st.Managers {
+		data.Managers = append(data.Managers, &UIManager{
+			Name:       mgr.Name,
+			Addr:       mgr.Addr,
+			LastActive: mgr.LastActive,
+			Corpus:     mgr.Corpus,
+			Cover:      mgr.PCs,
+			Crashes:    mgr.TotalCrashes,
+			Execs:      mgr.TotalExecs,
+		})
+	}
+	dash.mu.Unlock()
+	sort.Slice(data.Managers, func(i, j int) bool {
+		return data.Managers[i].Name < data.Managers[j].Name
+	})
+	executeTemplate(w, bugsTemplate, data)
+}
+
+func (dash *Dashboard) httpBug(w http.ResponseWriter, r *http.Request) {
+	dash.mu.Lock()
+	defer dash.mu.Unlock()
+	bug := dash.st.Bugs[r.FormValue("id")]
+	if bug == nil {
+		http.NotFound(w, r)
+		return
+	}
+	data := &UIBugPage{
+		Bug: makeUIBug(bug),
+	}
+	for i := len(bug.Crashes) - 1; i >= 0; i-- {
+		crash := dash.st.Crashes[bug.Crashes[i]]
+		ui := &UICrash{
+			ID:          crash.ID,
+			Title:       crash.Title,
+			Time:        crash.Time,
+			Manager:     crash.Manager,
+			HasLog:      len(crash.Log) != 0,
+			HasReport:   len(crash.Report) != 0,
+			HasReproSyz: len(crash.ReproSyz) != 0,
+			HasReproC:   len(crash.ReproC) != 0,
+		}
+		if build := dash.st.Builds[crash.BuildID]; build != nil {
+			ui.KernelCommit = build.KernelCommit
+		}
+		data.Crashes = append(data.Crashes, ui)
+	}
+	for i := len(bug.Jobs) - 1; i >= 0; i-- {
+		job := dash.st.Jobs[bug.Jobs[i]]
+		data.Jobs = append(data.Jobs, &UIJob{
+			ID:       job.ID,
+			Type:     jobTypes[job.Type],
+			Manager:  job.Manager,
+			User:     job.User,
+			Created:  job.Created,
+			Started:  job.Started,
+			Finished: job.Finished,
+			Result:   jobResult(job),
+			HasLog:   len(job.Log) != 0,
+			HasError: len(job.Error) != 0,
+		})
+	}
+	executeTemplate(w, bugTemplate, data)
+}
+
+// httpText serves crash logs, reports, reproducers and job logs.
+func (dash *Dashboard) httpText(w http.ResponseWriter, r *http.Request) {
+	dash.mu.Lock()
+	defer dash.mu.Unlock()
+	var text []byte
+	if id := r.FormValue("job"); id != "" {
+		job := dash.st.Jobs[id]
+		if job == nil {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.FormValue("tag") {
+		case "log":
+			text = job.Log
+		case "error":
+			text = job.Error
+		}
+	} else {
+		id, _ := strconv.ParseInt(r.FormValue("crash"), 10, 64)
+		crash := dash.st.Crashes[id]
+		if crash == nil {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.FormValue("tag") {
+		case "log":
+			text = crash.Log
+		case "report":
+			text = crash.Report
+		case "repro_syz":
+			text = crash.ReproSyz
+		case "repro_c":
+			text = crash.ReproC
+		case "config":
+			if build := dash.st.Builds[crash.BuildID]; build != nil {
+				text = build.KernelConfig
+			}
+		}
+	}
+	if text == nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write(text)
+}
+
+func makeUIBug(bug *state.Bug) *UIBug {
+	return &UIBug{
+		ID:         bug.ID,
+		Title:      bug.DisplayTitle(),
+		NumCrashes: bug.NumCrashes,
+		FirstTime:  bug.FirstTime,
+		LastTime:   bug.LastTime,
+		ReproLevel: reproLevels[bug.ReproLevel],
+		Managers:   strings.Join(bug.Managers, ", "),
+		Commits:    strings.Join(bug.Commits, "\n"),
+	}
+}
+
+var reproLevels = map[dashapi.ReproLevel]string{
+	dashapi.ReproLevelNone: "",
+	dashapi.ReproLevelSyz:  "syz",
+	dashapi.ReproLevelC:    "C",
+}
+
+var jobTypes = map[dashapi.JobType]string{
+	dashapi.JobTestPatch:   "patch test",
+	dashapi.JobBisectCause: "cause bisection",
+	dashapi.JobBisectFix:   "fix bisection",
+}
+
+func jobResult(job *state.Job) string {
+	switch {
+	case job.IsPending():
+		return "pending"
+	case job.IsRunning():
+		return "running"
+	case len(job.Error) != 0:
+		return "error"
+	case job.Type == dashapi.JobTestPatch && job.CrashTitle != "":
+		return "crashed: " + job.CrashTitle
+	case job.Type == dashapi.JobTestPatch:
+		return "OK"
+	case len(job.Commits) == 1:
+		return fmt.Sprintf("%v %v %v", job.Commits[0].Hash, job.Commits[0].Title, job.Flags)
+	case len(job.Commits) > 1:
+		return fmt.Sprintf("inconclusive: %v commits", len(job.Commits))
+	default:
+		return "no commits"
+	}
+}
+
+func executeTemplate(w http.ResponseWriter, templ *template.Template, data any) {
+	if err := templ.Execute(w, data); err != nil {
+		log.Logf(0, "failed to execute template: %v", err)
+		http.Error(w, fmt.Sprintf("failed to execute template: %v", err), http.StatusInternalServerError)
+	}
+}
+
+func compileTemplate(html string) *template.Template {
+	funcs := template.FuncMap{
+		"formatTime": func(t time.Time) string {
+			if t.IsZero() {
+				return ""
+			}
+			return t.Format("2006/01/02 15:04")
+		},
+	}
+	return template.Must(template.New("").Funcs(funcs).Parse(strings.Replace(html, "{{STYLE}}", htmlStyle, -1)))
+}
+
+var bugsTemplate = compileTemplate(`
+<!doctype html>
+<html>
+<head>
+	<title>syz-dashboard</title>
+	{{STYLE}}
+</head>
+<body>
+<b>syz-dashboard</b>
+<br><br>
+
+{{range $g := $.Groups}}
+<table>
+	<caption>{{$g.Caption}} ({{len $g.Bugs}}):</caption>
+	<tr>
+		<th>Title</th>
+		<th>Repro</th>
+		<th>Count</th>
+		<th>First</th>
+		<th>Last</th>
+		<th>Managers</th>
+		<th>Fixing commits</th>
+	</tr>
+	{{range $b := $g.Bugs}}
+	<tr>
+		<td><a href="/bug?id={{$b.ID}}">{{$b.Title}}</a></td>
+		<td>{{$b.ReproLevel}}</td>
+		<td>{{$b.NumCrashes}}</td>
+		<td>{{formatTime $b.FirstTime}}</td>
+		<td>{{formatTime $b.LastTime}}</td>
+		<td>{{$b.Managers}}</td>
+		<td>{{$b.Commits}}</td>
+	</tr>
+	{{end}}
+</table>
+<br><br>
+{{end}}
+
+<table>
+	<caption>Managers:</caption>
+	<tr>
+		<th>Name</th>
+		<th>Last active</th>
+		<th>Corpus</th>
+		<th>Coverage</th>
+		<th>Crashes</th>
+		<th>Execs</th>
+	</tr>
+	{{range $m := $.Managers}}
+	<tr>
+		<td>{{if $m.Addr}}<a href="http://{{$m.Addr}}">{{$m.Name}}</a>{{else}}{{$m.Name}}{{end}}</td>
+		<td>{{formatTime $m.LastActive}}</td>
+		<td>{{$m.Corpus}}</td>
+		<td>{{$m.Cover}}</td>
+		<td>{{$m.Crashes}}</td>
+		<td>{{$m.Execs}}</td>
+	</tr>
+	{{end}}
+</table>
+</body></html>
+`)
+
+var bugTemplate = compileTemplate(`
+<!doctype html>
+<html>
+<head>
+	<title>{{.Bug.Title}}</title>
+	{{STYLE}}
+</head>
+<body>
+<a href="/">syz-dashboard</a>
+<br><br>
+<b>{{.Bug.Title}}</b><br>
+ID: {{.Bug.ID}}<br>
+Crashes: {{.Bug.NumCrashes}}<br>
+First: {{formatTime .Bug.FirstTime}}<br>
+Last: {{formatTime .Bug.LastTime}}<br>
+{{if .Bug.Commits}}Fixing commits: {{.Bug.Commits}}<br>{{end}}
+<br>
+
+<table>
+	<caption>Jobs:</caption>
+	<tr>
+		<th>Type</th>
+		<th>Manager</th>
+		<th>User</th>
+		<th>Created</th>
+		<th>Started</th>
+		<th>Finished</th>
+		<th>Result</th>
+		<th>Log</th>
+	</tr>
+	{{range $j := .Jobs}}
+	<tr>
+		<td>{{$j.Type}}</td>
+		<td>{{$j.Manager}}</td>
+		<td>{{$j.User}}</td>
+		<td>{{formatTime $j.Created}}</td>
+		<td>{{formatTime $j.Started}}</td>
+		<td>{{formatTime $j.Finished}}</td>
+		<td>{{$j.Result}}</td>
+		<td>
+			{{if $j.HasLog}}<a href="/text?job={{$j.ID}}&tag=log">log</a>{{end}}
+			{{if $j.HasError}}<a href="/text?job={{$j.ID}}&tag=error">error</a>{{end}}
+		</td>
+	</tr>
+	{{end}}
+</table>
+<br><br>
+
+<table>
+	<caption>Crashes:</caption>
+	<tr>
+		<th>Time</th>
+		<th>Manager</th>
+		<th>Kernel commit</th>
+		<th>Title</th>
+		<th>Log</th>
+		<th>Report</th>
+		<th>Syz repro</th>
+		<th>C repro</th>
+		<th>Config</th>
+	</tr>
+	{{range $c := .Crashes}}
+	<tr>
+		<td>{{formatTime $c.Time}}</td>
+		<td>{{$c.Manager}}</td>
+		<td>{{$c.KernelCommit}}</td>
+		<td>{{$c.Title}}</td>
+		<td>{{if $c.HasLog}}<a href="/text?crash={{$c.ID}}&tag=log">log</a>{{end}}</td>
+		<td>{{if $c.HasReport}}<a href="/text?crash={{$c.ID}}&tag=report">report</a>{{end}}</td>
+		<td>{{if $c.HasReproSyz}}<a href="/text?crash={{$c.ID}}&tag=repro_syz">syz</a>{{end}}</td>
+		<td>{{if $c.HasReproC}}<a href="/text?crash={{$c.ID}}&tag=repro_c">C</a>{{end}}</td>
+		<td><a href="/text?crash={{$c.ID}}&tag=config">.config</a></td>
+	</tr>
+	{{end}}
+</table>
+</body></html>
+`)
+
+const htmlStyle = `
+	<style type="text/css" media="screen">
+		table {
+			border-collapse:collapse;
+			border:1px solid;
+		}
+		table caption {
+			font-weight: bold;
+			text-align: left;
+		}
+		table td {
+			border:1px solid;
+			padding: 3px;
+		}
+		table th {
+			border:1px solid;
+			padding: 3px;
+		}
+	</style>
+`
@@ -0,0 +1,754 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package state holds the syz-dashboard database: builds, bugs, crashes, jobs and managers.
+// The whole state is cached in memory and mirrored on disk in a pkg/db database,
+// one JSON-encoded record per object.
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/db"
+	"github.com/google/syzkaller/pkg/hash"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// State is not thread-safe, callers need to synchronize access.
+type State struct {
+	db       *db.DB
+	seq      uint64
+	Builds   map[string]*Build
+	Bugs     map[string]*Bug
+	Crashes  map[int64]*Crash
+	Jobs     map[string]*Job
+	Managers map[string]*Manager
+	// Bugs with the same title sorted by Seq.
+	titles map[string][]*Bug
+}
+
+type Build struct {
+	dashapi.Build
+	Time time.Time
+}
+
+type Bug struct {
+	ID     string
+	Title  string
+	Seq    int
+	Status dashapi.BugStatus
+	// Titles of the commits that fix the bug.
+	Commits []string
+	// Managers that have builds with all of Commits.
+	PatchedOn     []string
+	Managers      []string
+	FirstTime     time.Time
+	LastTime      time.Time
+	Closed        time.Time
+	NumCrashes    int64
+	ReproLevel    dashapi.ReproLevel
+	NumRepro      int
+	LastReproTime time.Time
+	// IDs of the stored crashes (at most maxCrashesPerBug).
+	Crashes []int64
+	// IDs of the jobs for the bug.
+	Jobs []string
+}
+
+type Crash struct {
+	dashapi.Crash
+	ID      int64
+	BugID   string
+	Manager string
+	Time    time.Time
+}
+
+type Job struct {
+	ID       string
+	Type     dashapi.JobType
+	BugID    string
+	CrashID  int64
+	Manager  string
+	User     string
+	Link     string
+	Repo     string
+	Branch   string
+	Patch    []byte
+	Created  time.Time
+	Started  time.Time
+	Finished time.Time
+	Attempts int
+	// Results.
+	BuildID     string
+	Error       []byte
+	Log         []byte
+	CrashTitle  string
+	CrashLog    []byte
+	CrashReport []byte
+	Commits     []dashapi.Commit
+	Flags       dashapi.JobDoneFlags
+}
+
+type Manager struct {
+	dashapi.ManagerStatsReq
+	LastActive time.Time
+	// Totals since the manager first connected.
+	TotalCrashes uint64
+	TotalExecs   uint64
+}
+
+const (
+	CorruptedReportTitle  = "corrupted report"
+	SuppressedReportTitle = "suppressed report"
+
+	maxCrashesPerBug = 40
+	maxReproPerBug   = 10
+	reproRetryPeriod = 24 * time.Hour
+	reproStalePeriod = 100 * 24 * time.Hour
+	// Fix bisection is attempted for bugs with a reproducer that did not happen for that long.
+	fixBisectPeriod = 30 * 24 * time.Hour
+)
+
+// Overridable for testing.
+var timeNow = time.Now
+
+// Make creates State and initializes it from dir.
+func Make(dir string) (*State, error) {
+	if err := osutil.MkdirAll(dir); err != nil {
+		return nil, err
+	}
+	st := &State{
+		Builds:   make(map[string]*Build),
+		Bugs:     make(map[string]*Bug),
+		Crashes:  make(map[int64]*Crash),
+		Jobs:     make(map[string]*Job),
+		Managers: make(map[string]*Manager),
+		titles:   make(map[string][]*Bug),
+	}
+	var err error
+	st.db, err = db.Open(filepath.Join(dir, "dashboard.db"), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	for key, rec := range st.db.Records {
+		st.seq = max(st.seq, rec.Seq)
+		if err := st.load(key, rec.Val); err != nil {
+			return nil, fmt.Errorf("failed to load record %v: %w", key, err)
+		}
+	}
+	for _, bugs := range st.titles {
+		sort.Slice(bugs, func(i, j int) bool { return bugs[i].Seq < bugs[j].Seq })
+	}
+	return st, nil
+}
+
+func (st *State) load(key string, val []byte) error {
+	kind, id, _ := strings.Cut(key, "/")
+	switch kind {
+	case "build":
+		build := new(Build)
+		st.Builds[id] = build
+		return json.Unmarshal(val, build)
+	case "bug":
+		bug := new(Bug)
+		if err := json.Unmarshal(val, bug); err != nil {
+			return err
+		}
+		st.Bugs[id] = bug
+		st.titles[bug.Title] = append(st.titles[bug.Title], bug)
+		return nil
+	case "crash":
+		crash := new(Crash)
+		if err := json.Unmarshal(val, crash); err != nil {
+			return err
+		}
+		st.Crashes[crash.ID] = crash
+		return nil
+	case "job":
+		job := new(Job)
+		st.Jobs[id] = job
+		return json.Unmarshal(val, job)
+	case "manager":
+		mgr := new(Manager)
+		st.Managers[id] = mgr
+		return json.Unmarshal(val, mgr)
+	default:
+		return fmt.Errorf("unknown record kind %q", kind)
+	}
+}
+
+func (st *State) save(key string, obj any) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal %v: %v", key, err))
+	}
+	st.seq++
+	st.db.Save(key, data, st.seq)
+}
+
+func (st *State) saveBuild(build *Build) {
+	st.save("build/"+build.ID, build)
+}
+
+func (st *State) saveBug(bug *Bug) {
+	st.save("bug/"+bug.ID, bug)
+}
+
+func (st *State) saveCrash(crash *Crash) {
+	st.save(fmt.Sprintf("crash/%v", crash.ID), crash)
+}
+
+func (st *State) deleteCrash(crash *Crash) {
+	st.db.Delete(fmt.Sprintf("crash/%v", crash.ID))
+}
+
+func (st *State) saveJob(job *Job) {
+	st.save("job/"+job.ID, job)
+}
+
+func (st *State) saveManager(mgr *Manager) {
+	st.save("manager/"+mgr.Name, mgr)
+}
+
+// nextID returns a unique ID for a new object.
+func (st *State) nextID() int64 {
+	return int64(st.seq) + 1
+}
+
+func (st *State) Flush() error {
+	return st.db.Flush()
+}
+
+func (bug *Bug) IsOpen() bool {
+	return bug.Status == dashapi.BugStatusOpen
+}
+
+// DisplayTitle distinguishes bugs with the same title that happened again after being closed.
+func (bug *Bug) DisplayTitle() string {
+	return displayTitle(bug.Title, bug.Seq)
+}
+
+func (crash *Crash) HasRepro() bool {
+	return len(crash.ReproSyz) != 0
+}
+
+func (job *Job) IsPending() bool {
+	return job.Started.IsZero() && job.Finished.IsZero()
+}
+
+func (job *Job) IsRunning() bool {
+	return !job.Started.IsZero() && job.Finished.IsZero()
+}
+
+func displayTitle(title string, seq int) string {
+	if seq == 0 {
+		return title
+	}
+	return fmt.Sprintf("%v (%v)", title, seq+1)
+}
+
+func bugID(title string, seq int) string {
+	return hash.String([]byte(fmt.Sprintf("%v-%v", title, seq)))
+}
+
+// CanonicalTitle returns the title under which the crash is stored.
+func CanonicalTitle(title string, corrupted, suppressed bool) string {
+	if corrupted {
+		// The report is corrupted and the title is most likely invalid.
+		// Collect them into a single bin.
+		return CorruptedReportTitle
+	}
+	if suppressed {
+		return SuppressedReportTitle
+	}
+	return title
+}
+
+// FindBug returns the open bug with one of the titles, or the last closed one
+// if there are no open bugs with these titles. Returns nil if there are no such bugs.
+func (st *State) FindBug(titles ...string) *Bug {
+	var closed *Bug
+	for _, title := range titles {
+		bugs := st.titles[title]
+		if len(bugs) == 0 {
+			continue
+		}
+		bug := bugs[len(bugs)-1]
+		if bug.IsOpen() {
+			return bug
+		}
+		if closed == nil {
+			closed = bug
+		}
+	}
+	return closed
+}
+
+// UploadBuild stores the build and updates bugs fixed by the commits present in the build.
+func (st *State) UploadBuild(req *dashapi.Build) error {
+	build := &Build{
+		Build: *req,
+		Time:  timeNow(),
+	}
+	if old := st.Builds[req.ID]; old != nil {
+		build.Time = old.Time
+	}
+	st.Builds[build.ID] = build
+	st.saveBuild(build)
+	st.addCommits(build.FixCommits)
+	commits := make(map[string]bool)
+	for _, title := range build.Commits {
+		commits[title] = true
+	}
+	for _, bug := range st.Bugs {
+		if !bug.IsOpen() || len(bug.Commits) == 0 || contains(bug.PatchedOn, build.Manager) {
+			continue
+		}
+		patched := true
+		for _, com := range bug.Commits {
+			patched = patched && commits[com]
+		}
+		if !patched {
+			continue
+		}
+		bug.PatchedOn = append(bug.PatchedOn, build.Manager)
+		sort.Strings(bug.PatchedOn)
+		st.updateFixed(bug)
+		st.saveBug(bug)
+	}
+	return st.Flush()
+}
+
+// PendingCommits returns titles of commits that fix open bugs, but are not yet present
+// in builds of the manager (all managers if manager is empty).
+func (st *State) PendingCommits(manager string) []string {
+	m := make(map[string]bool)
+	for _, bug := range st.Bugs {
+		if !bug.IsOpen() || manager != "" && contains(bug.PatchedOn, manager) {
+			continue
+		}
+		for _, com := range bug.Commits {
+			m[com] = true
+		}
+	}
+	var commits []string
+	for com := range m {
+		commits = append(commits, com)
+	}
+	sort.Strings(commits)
+	return commits
+}
+
+// UploadCommits attaches fixing commits to the bugs referenced by the commits.
+func (st *State) UploadCommits(commits []dashapi.Commit) error {
+	st.addCommits(commits)
+	return st.Flush()
+}
+
+func (st *State) addCommits(commits []dashapi.Commit) {
+	for _, com := range commits {
+		for _, id := range com.BugIDs {
+			bug := st.Bugs[id]
+			if bug == nil || !bug.IsOpen() || contains(bug.Commits, com.Title) {
+				continue
+			}
+			bug.Commits = append(bug.Commits, com.Title)
+			sort.Strings(bug.Commits)
+			// The set of commits has changed, so the bug needs to be patched again everywhere.
+			bug.PatchedOn = nil
+			st.saveBug(bug)
+		}
+	}
+}
+
+// updateFixed closes the bug if it's patched on all managers where it happened.
+func (st *State) updateFixed(bug *Bug) {
+	for _, mgr := range bug.Managers {
+		if !contains(bug.PatchedOn, mgr) {
+			return
+		}
+	}
+	bug.Status = dashapi.BugStatusFixed
+	bug.Closed = timeNow()
+}
+
+// ReportCrash saves the crash and returns the bug it was attributed to.
+// A new bug is created if there is no open bug with the same title.
+func (st *State) ReportCrash(req *dashapi.Crash) (*Bug, error) {
+	build := st.Builds[req.BuildID]
+	if build == nil {
+		return nil, fmt.Errorf("unknown build %q", req.BuildID)
+	}
+	req.Title = CanonicalTitle(req.Title, req.Corrupted, req.Suppressed)
+	now := timeNow()
+	bug := st.FindBug(append([]string{req.Title}, req.AltTitles...)...)
+	if bug == nil || !bug.IsOpen() {
+		seq := 0
+		if bugs := st.titles[req.Title]; len(bugs) != 0 {
+			seq = bugs[len(bugs)-1].Seq + 1
+		}
+		bug = &Bug{
+			ID:        bugID(req.Title, seq),
+			Title:     req.Title,
+			Seq:       seq,
+			FirstTime: now,
+		}
+		st.Bugs[bug.ID] = bug
+		st.titles[bug.Title] = append(st.titles[bug.Title], bug)
+	}
+	bug.NumCrashes++
+	bug.LastTime = now
+	if !contains(bug.Managers, build.Manager) {
+		bug.Managers = append(bug.Managers, build.Manager)
+		sort.Strings(bug.Managers)
+	}
+	level := dashapi.ReproLevelNone
+	switch {
+	case len(req.ReproC) != 0:
+		level = dashapi.ReproLevelC
+	case len(req.ReproSyz) != 0:
+		level = dashapi.ReproLevelSyz
+	}
+	if level != dashapi.ReproLevelNone {
+		bug.NumRepro++
+		bug.LastReproTime = now
+	}
+	crash := &Crash{
+		Crash:   *req,
+		ID:      st.nextID(),
+		BugID:   bug.ID,
+		Manager: build.Manager,
+		Time:    now,
+	}
+	st.Crashes[crash.ID] = crash
+	st.saveCrash(crash)
+	bug.Crashes = append(bug.Crashes, crash.ID)
+	st.evictCrashes(bug)
+	if level > bug.ReproLevel {
+		if bug.ReproLevel == dashapi.ReproLevelNone && bug.Title != CorruptedReportTitle {
+			st.addJob(&Job{
+				Type:    dashapi.JobBisectCause,
+				BugID:   bug.ID,
+				CrashID: crash.ID,
+				Manager: build.Manager,
+			})
+		}
+		bug.ReproLevel = level
+	}
+	st.saveBug(bug)
+	return bug, st.Flush()
+}
+
+// evictCrashes removes old crashes of the bug if it has too many.
+// Crashes with reproducers are preferred over crashes without.
+func (st *State) evictCrashes(bug *Bug) {
+	for len(bug.Crashes) > maxCrashesPerBug {
+		victim := 0
+		for i, id := range bug.Crashes {
+			if !st.Crashes[id].HasRepro() {
+				victim = i
+				break
+			}
+		}
+		crash := st.Crashes[bug.Crashes[victim]]
+		if st.crashUsedByJobs(crash.ID) {
+			// Jobs refer to the crash, so we can't delete it, but it's not one of the bug crashes anymore.
+			crash.BugID = ""
+			st.saveCrash(crash)
+		} else {
+			delete(st.Crashes, crash.ID)
+			st.deleteCrash(crash)
+		}
+		bug.Crashes = append(bug.Crashes[:victim], bug.Crashes[victim+1:]...)
+	}
+}
+
+func (st *State) crashUsedByJobs(id int64) bool {
+	for _, job := range st.Jobs {
+		if job.CrashID == id {
+			return true
+		}
+	}
+	return false
+}
+
+var syzErrorTitleRe = regexp.MustCompile(`^SYZFAIL:|^SYZFATAL:`)
+
+// NeedRepro says if a reproducer is still needed for the bug.
+func (st *State) NeedRepro(bug *Bug) bool {
+	if len(bug.Commits) != 0 || !bug.IsOpen() ||
+		bug.Title == CorruptedReportTitle || bug.Title == SuppressedReportTitle {
+		return false
+	}
+	bestReproLevel := dashapi.ReproLevelC
+	// For some bugs there's anyway no chance to find a C repro.
+	if syzErrorTitleRe.MatchString(bug.Title) {
+		bestReproLevel = dashapi.ReproLevelSyz
+	}
+	since := timeNow().Sub(bug.LastReproTime)
+	if bug.ReproLevel < bestReproLevel {
+		return bug.NumRepro < maxReproPerBug || since >= reproRetryPeriod
+	}
+	return since >= reproStalePeriod
+}
+
+// ReportFailedRepro records a failed reproduction attempt for the bug.
+func (st *State) ReportFailedRepro(bug *Bug) error {
+	bug.NumRepro++
+	bug.LastReproTime = timeNow()
+	st.saveBug(bug)
+	return st.Flush()
+}
+
+// UpdateGuiltyFiles updates the guilty files of the crash.
+func (st *State) UpdateGuiltyFiles(crash *Crash, files []string) error {
+	crash.GuiltyFiles = files
+	st.saveCrash(crash)
+	return st.Flush()
+}
+
+// BestCrash returns the bug crash with the best reproducer, or nil if the bug has no reproducers.
+func (st *State) BestCrash(bug *Bug) *Crash {
+	var best *Crash
+	for _, id := range bug.Crashes {
+		crash := st.Crashes[id]
+		if !crash.HasRepro() {
+			continue
+		}
+		if best == nil || len(best.ReproC) == 0 && len(crash.ReproC) != 0 ||
+			(len(best.ReproC) == 0) == (len(crash.ReproC) == 0) && crash.Time.After(best.Time) {
+			best = crash
+		}
+	}
+	return best
+}
+
+// AddTestJob queues a patch testing job for the bug.
+func (st *State) AddTestJob(bug *Bug, req *dashapi.TestPatchRequest) (*Job, error) {
+	crash := st.BestCrash(bug)
+	if crash == nil {
+		return nil, fmt.Errorf("the bug does not have a reproducer")
+	}
+	job := &Job{
+		Type:    dashapi.JobTestPatch,
+		BugID:   bug.ID,
+		CrashID: crash.ID,
+		Manager: crash.Manager,
+		User:    req.User,
+		Link:    req.Link,
+		Repo:    req.Repo,
+		Branch:  req.Branch,
+		Patch:   req.Patch,
+	}
+	st.addJob(job)
+	st.saveBug(bug)
+	return job, st.Flush()
+}
+
+func (st *State) addJob(job *Job) {
+	job.ID = strconv.FormatInt(st.nextID(), 10)
+	job.Created = timeNow()
+	st.Jobs[job.ID] = job
+	st.saveJob(job)
+	if bug := st.Bugs[job.BugID]; bug != nil {
+		bug.Jobs = append(bug.Jobs, job.ID)
+	}
+}
+
+// PollJob returns the oldest pending job that can be executed by one of the managers
+// and marks it as started. Returns nil if there are no such jobs.
+func (st *State) PollJob(managers map[string]dashapi.ManagerJobs) (*Job, error) {
+	st.addFixBisections(managers)
+	var best *Job
+	for _, job := range st.Jobs {
+		caps, ok := managers[job.Manager]
+		if !ok || !job.IsPending() {
+			continue
+		}
+		switch job.Type {
+		case dashapi.JobTestPatch:
+			ok = caps.TestPatches
+		case dashapi.JobBisectCause:
+			ok = caps.BisectCause
+		case dashapi.JobBisectFix:
+			ok = caps.BisectFix
+		}
+		if !ok || st.Crashes[job.CrashID] == nil {
+			continue
+		}
+		// Patch testing is interactive, so it goes first.
+		if best == nil || job.Type == dashapi.JobTestPatch && best.Type != dashapi.JobTestPatch ||
+			(job.Type == dashapi.JobTestPatch) == (best.Type == dashapi.JobTestPatch) &&
+				job.Created.Before(best.Created) {
+			best = job
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+	best.Started = timeNow()
+	best.Attempts++
+	st.saveJob(best)
+	return best, st.Flush()
+}
+
+// addFixBisections queues fix bisection jobs for open bugs with reproducers that did not
+// happen for a while on the managers that can do fix bisection.
+func (st *State) addFixBisections(managers map[string]dashapi.ManagerJobs) {
+	now := timeNow()
+	for _, bug := range st.Bugs {
+		if !bug.IsOpen() || bug.ReproLevel == dashapi.ReproLevelNone ||
+			now.Sub(bug.LastTime) < fixBisectPeriod || st.hasJob(bug, dashapi.JobBisectFix) {
+			continue
+		}
+		crash := st.BestCrash(bug)
+		if crash == nil || !managers[crash.Manager].BisectFix {
+			continue
+		}
+		st.addJob(&Job{
+			Type:    dashapi.JobBisectFix,
+			BugID:   bug.ID,
+			CrashID: crash.ID,
+			Manager: crash.Manager,
+		})
+		st.saveBug(bug)
+	}
+}
+
+func (st *State) hasJob(bug *Bug, typ dashapi.JobType) bool {
+	for _, id := range bug.Jobs {
+		if st.Jobs[id].Type == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// JobDone stores results of the job.
+func (st *State) JobDone(req *dashapi.JobDoneReq) error {
+	job := st.Jobs[req.ID]
+	if job == nil {
+		return fmt.Errorf("unknown job %q", req.ID)
+	}
+	if !job.IsRunning() {
+		return fmt.Errorf("job %v is not running", req.ID)
+	}
+	if req.Build.ID != "" {
+		build := &Build{
+			Build: req.Build,
+			Time:  timeNow(),
+		}
+		st.Builds[build.ID] = build
+		st.saveBuild(build)
+	}
+	job.Finished = timeNow()
+	job.BuildID = req.Build.ID
+	job.Error = req.Error
+	job.Log = req.Log
+	job.CrashTitle = req.CrashTitle
+	job.CrashLog = req.CrashLog
+	job.CrashReport = req.CrashReport
+	job.Commits = req.Commits
+	job.Flags = req.Flags
+	st.saveJob(job)
+	return st.Flush()
+}
+
+// ResetJobs returns jobs running on the managers back to the queue.
+func (st *State) ResetJobs(managers []string) error {
+	for _, job := range st.Jobs {
+		if job.IsRunning() && contains(managers, job.Manager) {
+			job.Started = time.Time{}
+			st.saveJob(job)
+		}
+	}
+	return st.Flush()
+}
+
+// UpdateManager updates manager statistics.
+func (st *State) UpdateManager(req *dashapi.ManagerStatsReq) error {
+	mgr := st.Managers[req.Name]
+	if mgr == nil {
+		mgr = new(Manager)
+		st.Managers[req.Name] = mgr
+	}
+	mgr.ManagerStatsReq = *req
+	mgr.LastActive = timeNow()
+	mgr.TotalCrashes += req.Crashes
+	mgr.TotalExecs += req.Execs
+	st.saveManager(mgr)
+	return st.Flush()
+}
+
+// AddBuildAssets attaches assets to the build.
+func (st *State) AddBuildAssets(req *dashapi.AddBuildAssetsReq) error {
+	build := st.Builds[req.BuildID]
+	if build == nil {
+		return fmt.Errorf("unknown build %q", req.BuildID)
+	}
+	build.Assets = append(build.Assets, req.Assets...)
+	st.saveBuild(build)
+	return st.Flush()
+}
+
+// NeededAssets returns download URLs of assets of the latest builds of all managers
+// and of builds where open bugs happened.
+func (st *State) NeededAssets() []string {
+	latest := make(map[string]*Build)
+	for _, build := range st.Builds {
+		if prev := latest[build.Manager]; prev == nil || build.Time.After(prev.Time) {
+			latest[build.Manager] = build
+		}
+	}
+	needed := make(map[string]bool)
+	for _, build := range latest {
+		needed[build.ID] = true
+	}
+	for _, crash := range st.Crashes {
+		if bug := st.Bugs[crash.BugID]; bug != nil && bug.IsOpen() {
+			needed[crash.BuildID] = true
+		}
+	}
+	var urls []string
+	for id := range needed {
+		if build := st.Builds[id]; build != nil {
+			for _, asset := range build.Assets {
+				urls = append(urls, asset.DownloadURL)
+			}
+		}
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+// SortedBugs returns all bugs sorted by the last crash time, most recent first.
+func (st *State) SortedBugs() []*Bug {
+	var bugs []*Bug
+	for _, bug := range st.Bugs {
+		bugs = append(bugs, bug)
+	}
+	sort.Slice(bugs, func(i, j int) bool {
+		if !bugs[i].LastTime.Equal(bugs[j].LastTime) {
+			return bugs[i].LastTime.After(bugs[j].LastTime)
+		}
+		return bugs[i].ID < bugs[j].ID
+	})
+	return bugs
+}
+
+func contains(list []string, str string) bool {
+	for _, s := range list {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvictCrashes(t *testing.T) {
+	dir := t.TempDir()
+	st, err := Make(dir)
+	require.NoError(t, err)
+	require.NoError(t, st.UploadBuild(&dashapi.Build{ID: "b1", Manager: "mgr"}))
+	var bug *Bug
+	for i := 0; i < 2*maxCrashesPerBug; i++ {
+		crash := &dashapi.Crash{BuildID: "b1", Title: "title"}
+		if i%10 == 0 {
+			crash.ReproSyz = []byte("getpid()")
+		}
+		bug, err = st.ReportCrash(crash)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int64(2*maxCrashesPerBug), bug.NumCrashes)
+	assert.Len(t, bug.Crashes, maxCrashesPerBug)
+	assert.Len(t, st.Crashes, maxCrashesPerBug)
+	repros := 0
+	for _, id := range bug.Crashes {
+		if st.Crashes[id].HasRepro() {
+			repros++
+		}
+	}
+	assert.Equal(t, 2*maxCrashesPerBug/10, repros)
+
+	st, err = Make(dir)
+	require.NoError(t, err)
+	assert.Len(t, st.Crashes, maxCrashesPerBug)
+	assert.Len(t, st.Bugs[bug.ID].Crashes, maxCrashesPerBug)
+}
+
+func TestFixBisection(t *testing.T) {
+	defer func(orig func() time.Time) { timeNow = orig }(timeNow)
+	now := time.Now()
+	timeNow = func() time.Time { return now }
+	st, err := Make(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, st.UploadBuild(&dashapi.Build{ID: "b1", Manager: "mgr"}))
+	_, err = st.ReportCrash(&dashapi.Crash{BuildID: "b1", Title: "title", ReproSyz: []byte("getpid()")})
+	require.NoError(t, err)
+	managers := map[string]dashapi.ManagerJobs{"mgr": {BisectFix: true}}
+	job, err := st.PollJob(managers)
+	require.NoError(t, err)
+	assert.Nil(t, job)
+
+	now = now.Add(fixBisectPeriod)
+	job, err = st.PollJob(managers)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, dashapi.JobBisectFix, job.Type)
+	assert.Equal(t, "mgr", job.Manager)
+	// Only one fix bisection per bug.
+	job, err = st.PollJob(managers)
+	require.NoError(t, err)
+	assert.Nil(t, job)
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/manager"
+)
+
+// The crashes that happen long after the fix are most likely new bugs with the same title.
+const incompleteFixMaxAge = 90 * 24 * time.Hour
+
+// dashboardFixCommits periodically queries the fixing commits that are present in the builds of the manager.
+// If a crash with the title of the fixed bug happens afterwards, the fix is likely incomplete.
+func (mgr *Manager) dashboardFixCommits() {
+	for ; ; time.Sleep(time.Hour) {
+		resp, err := mgr.dash.FixCommits(&dashapi.FixCommitsReq{
+			Since:   time.Now().Add(-incompleteFixMaxAge),
+			Manager: mgr.cfg.Name,
+		})
+		if err != nil {
+			log.Logf(0, "failed to query fix commits: %v", err)
+			continue
+		}
+		fixes := make(map[string][]dashapi.FixCommit)
+		for _, com := range resp.List {
+			fixes[com.CrashTitle] = append(fixes[com.CrashTitle], com)
+		}
+		mgr.mu.Lock()
+		mgr.knownFixes = fixes
+		mgr.mu.Unlock()
+	}
+}
+
+func (mgr *Manager) incompleteFix(title string) []dashapi.FixCommit {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	return mgr.knownFixes[title]
+}
+
+// saveIncompleteFix keeps the crash locally, so that it's visible in the manager UI.
+func (mgr *Manager) saveIncompleteFix(crash *manager.Crash, fixes []dashapi.FixCommit) {
+	log.Logf(0, "crash %q happened on a build with the fix %q", crash.Title, fixes[0].Title)
+	if _, err := mgr.crashStore.SaveCrash(crash); err != nil {
+		log.Logf(0, "failed to save the crash: %v", err)
+		return
+	}
+	var list []*manager.IncompleteFix
+	for _, com := range fixes {
+		list = append(list, &manager.IncompleteFix{
+			BugTitle: com.BugTitle,
+			BugLink:  strings.TrimSuffix(mgr.cfg.DashboardAddr, "/") + "/bug?id=" + com.BugID,
+			Title:    com.Title,
+			Hash:     com.Hash,
+			Tag:      mgr.cfg.Tag,
+		})
+	}
+	if err := mgr.crashStore.SaveIncompleteFix(crash.Title, list); err != nil {
+		log.Logf(0, "failed to save the incomplete fix: %v", err)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/google/syzkaller/dashboard/dashapi"
 	"github.com/google/syzkaller/pkg/asset"
 	"github.com/google/syzkaller/pkg/corpus"
+	"github.com/google/syzkaller/pkg/cover"
 	"github.com/google/syzkaller/pkg/csource"
 	"github.com/google/syzkaller/pkg/db"
 	"github.com/google/syzkaller/pkg/flatrpc"
@@ -43,6 +44,8 @@ import (
 	"github.com/google/syzkaller/pkg/runtest"
 	"github.com/google/syzkaller/pkg/signal"
 	"github.com/google/syzkaller/pkg/stat"
+	"github.com/google/syzkaller/pkg/subsystem"
+	_ "github.com/google/syzkaller/pkg/subsystem/lists"
 	"github.com/google/syzkaller/pkg/vminfo"
 	"github.com/google/syzkaller/prog"
 	"github.com/google/syzkaller/sys/targets"
@@ -86,6 +89,9 @@ type Manager struct {
 	// This is specifically separated from dash, so that we can keep dash = nil when
 	// cfg.DashboardOnlyRepro is set, so that we don't accidentially use dash for anything.
 	dashRepro *dashapi.Dashboard
+	// Attributes coverage and crashes to kernel subsystems for the dashboard stats.
+	// Nil if there's no subsystem list for the target OS.
+	subsystems *subsystem.PathMatcher
 
 	mu             sync.Mutex
 	fuzzer         atomic.Pointer[fuzzer.Fuzzer]
@@ -98,6 +104,8 @@ type Manager struct {
 	memoryLeakFrames map[string]bool
 	dataRaceFrames   map[string]bool
 	saturatedCalls   map[string]bool
+	subsystemCrashes map[string]uint64
+	knownFixes       map[string][]dashapi.FixCommit // crash title -> fixes present in the build
 
 	externalReproQueue chan *manager.Crash
 	crashes            chan *manager.Crash
@@ -332,6 +340,10 @@ func RunManager(mode *Mode, cfg *mgrconfig.Config) {
 		mgr.dashRepro = dash
 		if !cfg.DashboardOnlyRepro {
 			mgr.dash = dash
+			if list := subsystem.GetList(cfg.TargetOS); list != nil {
+				mgr.subsystems = subsystem.MakePathMatcher(list)
+				mgr.subsystemCrashes = make(map[string]uint64)
+			}
 		}
 	}
 
@@ -726,6 +738,11 @@ func (mgr *Manager) saveCrash(crash *manager.Crash) bool {
 		mgr.crashTypes[crash.Title] = true
 		mgr.statCrashTypes.Add(1)
 	}
+	if mgr.subsystems != nil && !crash.Suppressed && crash.GuiltyFile != "" {
+		for _, item := range mgr.subsystems.Match(crash.GuiltyFile) {
+			mgr.subsystemCrashes[item.Name]++
+		}
+	}
 	mgr.mu.Unlock()
 
 	if mgr.dash != nil {
@@ -744,6 +761,10 @@ func (mgr *Manager) saveCrash(crash *manager.Crash) bool {
 			MachineInfo: crash.MachineInfo,
 		}
 		setGuiltyFiles(dc, crash.Report)
+		if fixes := mgr.incompleteFix(crash.Title); len(fixes) != 0 && !crash.Suppressed {
+			dc.IncompleteFix = fixes
+			mgr.saveIncompleteFix(crash, fixes)
+		}
 		resp, err := mgr.dash.ReportCrash(dc)
 		if err != nil {
 			log.Logf(0, "failed to report crash to dashboard: %v", err)
@@ -904,11 +925,12 @@ func (mgr *Manager) saveRepro(res *manager.ReproResult) {
 			ReproLog:      truncateReproLog(res.Stats.FullLog()),
 			Assets:        mgr.uploadReproAssets(repro),
 			OriginalTitle: res.Crash.Title,
+			IncompleteFix: mgr.incompleteFix(report.Title),
 		}
 		setGuiltyFiles(dc, report)
 		if _, err := mgr.dash.ReportCrash(dc); err != nil {
 			log.Logf(0, "failed to report repro to dashboard: %v", err)
-		} else {
+		} else if len(dc.IncompleteFix) == 0 {
 			// Don't store the crash locally, if we've successfully
 			// uploaded it to the dashboard. These will just eat disk space.
 			return
@@ -961,6 +983,12 @@ func (mgr *Manager) uploadReproAssets(repro *repro.Result) []dashapi.NewAsset {
 }
 
 func (mgr *Manager) corpusInputHandler(updates <-chan corpus.NewItemEvent) {
+	tracesDir := filepath.Join(mgr.cfg.Workdir, "traces")
+	if mgr.cfg.Experimental.TraceMutations {
+		if err := osutil.MkdirAll(tracesDir); err != nil {
+			log.Errorf("failed to create traces dir: %v", err)
+		}
+	}
 	for update := range updates {
 		if len(update.NewCover) != 0 && mgr.coverFilters.ExecutorFilter != nil {
 			filtered := 0
@@ -981,6 +1009,12 @@ func (mgr *Manager) corpusInputHandler(updates <-chan corpus.NewItemEvent) {
 			log.Errorf("failed to save corpus database: %v", err)
 		}
 		mgr.corpusDBMu.Unlock()
+		if update.MutationTrace != nil {
+			err := osutil.WriteFile(filepath.Join(tracesDir, update.Sig), update.MutationTrace.Serialize())
+			if err != nil {
+				log.Errorf("failed to save mutation trace: %v", err)
+			}
+		}
 	}
 }
 
@@ -1096,6 +1130,25 @@ func (mgr *Manager) BugFrames() (leaks, races []string) {
 	return
 }
 
+// loadPrioPrior loads call-to-call priorities exported from another manager (if configured).
+func (mgr *Manager) loadPrioPrior() (*prog.DynamicPrios, error) {
+	if mgr.cfg.Experimental.PrioPrior == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(mgr.cfg.Experimental.PrioPrior)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prio_prior: %w", err)
+	}
+	prios, err := prog.DeserializeDynamicPrios(data)
+	if err != nil {
+		return nil, err
+	}
+	if target := mgr.cfg.TargetOS + "/" + mgr.cfg.TargetArch; prios.Target != target {
+		log.Logf(0, "using priorities of %v for %v", prios.Target, target)
+	}
+	return prios, nil
+}
+
 func (mgr *Manager) MachineChecked(features flatrpc.Feature,
 	enabledSyscalls map[*prog.Syscall]bool) (queue.Source, error) {
 	if len(enabledSyscalls) == 0 {
@@ -1124,6 +1177,10 @@ func (mgr *Manager) MachineChecked(features flatrpc.Feature,
 	opts := fuzzer.DefaultExecOpts(mgr.cfg, features, *flagDebug)
 
 	if mgr.mode == ModeFuzzing || mgr.mode == ModeCorpusTriage {
+		prioPrior, err := mgr.loadPrioPrior()
+		if err != nil {
+			return nil, err
+		}
 		corpusUpdates := make(chan corpus.NewItemEvent, 128)
 		mgr.corpus = corpus.NewFocusedCorpus(context.Background(),
 			corpusUpdates, mgr.coverFilters.Areas)
@@ -1140,6 +1197,8 @@ func (mgr *Manager) MachineChecked(features flatrpc.Feature,
 			EnabledCalls:   enabledSyscalls,
 			NoMutateCalls:  mgr.cfg.NoMutateCalls,
 			FetchRawCover:  mgr.cfg.RawCover,
+			PrioPrior:      prioPrior,
+			TraceMutations: mgr.cfg.Experimental.TraceMutations,
 			Logf: func(level int, msg string, args ...interface{}) {
 				if level != 0 {
 					return
@@ -1161,6 +1220,7 @@ func (mgr *Manager) MachineChecked(features flatrpc.Feature,
 		go mgr.fuzzerLoop(fuzzerObj)
 		if mgr.dash != nil {
 			go mgr.dashboardReporter()
+			go mgr.dashboardFixCommits()
 			if mgr.cfg.Reproduce {
 				go mgr.dashboardReproTasks()
 			}
@@ -1182,6 +1242,12 @@ func (mgr *Manager) MachineChecked(features flatrpc.Feature,
 			candidates: candidates,
 			rnd:        rand.New(rand.NewSource(time.Now().UnixNano())),
 		}
+		if mgr.cfg.Cover {
+			// Collect coverage of the corpus programs, so that the coverage pages (e.g. /filecover)
+			// can be used to compare coverage of the same corpus on different kernels/configs.
+			ctx.corpus = corpus.NewCorpus(context.Background())
+			mgr.http.Corpus.Store(ctx.corpus)
+		}
 		return queue.DefaultOpts(ctx, opts), nil
 	} else if mgr.mode == ModeRunTests {
 		ctx := &runtest.Context{
@@ -1225,6 +1291,7 @@ func (mgr *Manager) MachineChecked(features flatrpc.Feature,
 
 type corpusRunner struct {
 	candidates []fuzzer.Candidate
+	corpus     *corpus.Corpus // if set, coverage of the executed programs is saved here
 	mu         sync.Mutex
 	rnd        *rand.Rand
 	seq        int
@@ -1243,10 +1310,32 @@ func (cr *corpusRunner) Next() *queue.Request {
 		// Then pick random progs.
 		p = cr.candidates[cr.rnd.Intn(len(cr.candidates))].Prog
 	}
-	return &queue.Request{
+	req := &queue.Request{
 		Prog:      p,
 		Important: true,
 	}
+	if cr.corpus != nil {
+		req.ExecOpts.ExecFlags |= flatrpc.ExecFlagCollectCover
+		req.OnDone(cr.saveCover)
+	}
+	return req
+}
+
+func (cr *corpusRunner) saveCover(req *queue.Request, res *queue.Result) bool {
+	if res.Info == nil {
+		return true
+	}
+	for call, info := range res.Info.Calls {
+		if info == nil || len(info.Cover) == 0 {
+			continue
+		}
+		cr.corpus.Save(corpus.NewInput{
+			Prog:  req.Prog,
+			Call:  call,
+			Cover: info.Cover,
+		})
+	}
+	return true
 }
 
 func (mgr *Manager) corpusMinimization() {
@@ -1376,6 +1465,8 @@ func (mgr *Manager) dashboardReporter() {
 	triageInfoSent := false
 	var lastFuzzingTime time.Duration
 	var lastCrashes, lastSuppressedCrashes, lastExecs uint64
+	lastSubsystemCrashes := make(map[string]uint64)
+	var lastSubsystemCover time.Time
 	for range time.NewTicker(time.Minute).C {
 		mgr.mu.Lock()
 		corpus := mgr.corpus
@@ -1402,8 +1493,26 @@ func (mgr *Manager) dashboardReporter() {
 			req.TriagedCoverage = uint64(corpus.StatSignal.Val())
 			req.TriagedPCs = uint64(corpus.StatCover.Val())
 		}
+		for name, crashes := range mgr.subsystemCrashes {
+			if delta := crashes - lastSubsystemCrashes[name]; delta != 0 {
+				if req.SubsystemCrashes == nil {
+					req.SubsystemCrashes = make(map[string]uint64)
+				}
+				req.SubsystemCrashes[name] = delta
+			}
+		}
+		coverDue := mgr.subsystems != nil && mgr.phase >= phaseTriagedCorpus &&
+			time.Since(lastSubsystemCover) > subsystemCoverPeriod
 		mgr.mu.Unlock()
 
+		if coverDue {
+			lastSubsystemCover = time.Now()
+			var err error
+			req.SubsystemCover, err = mgr.subsystemCoverage(corpus)
+			if err != nil {
+				log.Logf(0, "failed to calculate subsystem coverage: %v", err)
+			}
+		}
 		if err := mgr.dash.UploadManagerStats(req); err != nil {
 			log.Logf(0, "failed to upload dashboard stats: %v", err)
 			continue
@@ -1413,10 +1522,48 @@ func (mgr *Manager) dashboardReporter() {
 		lastCrashes += req.Crashes
 		lastSuppressedCrashes += req.SuppressedCrashes
 		lastExecs += req.Execs
+		for name, delta := range req.SubsystemCrashes {
+			lastSubsystemCrashes[name] += delta
+		}
 		mgr.mu.Unlock()
 	}
 }
 
+// Symbolizing the whole corpus coverage is expensive, so it's not done on every stats upload.
+const subsystemCoverPeriod = 6 * time.Hour
+
+func (mgr *Manager) subsystemCoverage(corpus *corpus.Corpus) ([]dashapi.SubsystemCover, error) {
+	coverInfo := mgr.http.Cover.Load()
+	if coverInfo == nil {
+		return nil, fmt.Errorf("coverage is not ready")
+	}
+	rg, err := coverInfo.ReportGenerator.Get()
+	if err != nil {
+		return nil, err
+	}
+	var progs []cover.Prog
+	for _, inp := range corpus.Items() {
+		progs = append(progs, cover.Prog{
+			Sig:  inp.Sig,
+			Data: string(inp.Prog.Serialize()),
+			PCs:  manager.CoverToPCs(mgr.cfg, inp.Cover),
+		})
+	}
+	stats, err := rg.SubsystemCoverage(progs, mgr.subsystems)
+	if err != nil {
+		return nil, err
+	}
+	var ret []dashapi.SubsystemCover
+	for _, item := range stats {
+		ret = append(ret, dashapi.SubsystemCover{
+			Name:     item.Name,
+			PCs:      uint64(item.CoveredPCs),
+			TotalPCs: uint64(item.TotalPCs),
+		})
+	}
+	return ret, nil
+}
+
 func (mgr *Manager) dashboardReproTasks() {
 	for range time.NewTicker(20 * time.Minute).C {
 		if !mgr.reproLoop.CanReproMore() {
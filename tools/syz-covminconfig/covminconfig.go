@@ -0,0 +1,277 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-covminconfig minimizes a kernel config while preserving coverage achieved by a corpus.
+// It uses the same minimization procedure as crash-guided config minimization (KConfig.Minimize),
+// but the predicate is: the kernel builds, boots and running the corpus (syz-manager -mode corpus-run)
+// for the given time achieves at least -threshold of the line coverage achieved with the full config
+// in files matching -paths (all files if -paths is empty).
+// The manager config must have coverage enabled and its workdir must contain corpus.db.
+// Example use:
+//
+//	$ syz-covminconfig -sourcedir /src/linux -manager qemu.cfg -userspace /images/bullseye \
+//		-base dashboard/config/linux/upstream-kasan-base.config \
+//		-full dashboard/config/linux/upstream-kasan.config \
+//		-paths net/ipv4/,net/ipv6/ -threshold 0.95 -duration 20m
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/build"
+	"github.com/google/syzkaller/pkg/config"
+	"github.com/google/syzkaller/pkg/cover"
+	"github.com/google/syzkaller/pkg/debugtracer"
+	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/kconfig"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/tool"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+func main() {
+	var (
+		flagSourceDir = flag.String("sourcedir", "", "kernel sources dir")
+		flagArch      = flag.String("arch", runtime.GOARCH, "kernel arch")
+		flagBase      = flag.String("base", "", "baseline config")
+		flagFull      = flag.String("full", "", "full config")
+		flagManager   = flag.String("manager", "", "syz-manager config (workdir must contain corpus.db)")
+		flagCompiler  = flag.String("compiler", "gcc", "kernel compiler")
+		flagMake      = flag.String("make", "make", "make binary")
+		flagUserspace = flag.String("userspace", "", "userspace dir for image build")
+		flagWorkdir   = flag.String("workdir", "covminconfig", "dir for kernel builds and manager workdirs")
+		flagDuration  = flag.Duration("duration", 20*time.Minute, "how long to run the corpus for each config")
+		flagPaths     = flag.String("paths", "", "comma-separated list of source path prefixes to preserve coverage for")
+		flagThreshold = flag.Float64("threshold", 0.95, "fraction of the full config coverage to preserve")
+		flagMaxSteps  = flag.Int("max-steps", 0, "max number of minimization steps (0 - unlimited)")
+		flagOut       = flag.String("out", "min.config", "output file for the minimized config")
+	)
+	defer tool.Init()()
+	if *flagSourceDir == "" || *flagBase == "" || *flagFull == "" || *flagManager == "" {
+		tool.Failf("-sourcedir, -base, -full and -manager are mandatory")
+	}
+	kconf, err := kconfig.Parse(targets.Get(targets.Linux, *flagArch), filepath.Join(*flagSourceDir, "Kconfig"))
+	if err != nil {
+		tool.Fail(err)
+	}
+	base, err := kconfig.ParseConfig(*flagBase)
+	if err != nil {
+		tool.Fail(err)
+	}
+	full, err := kconfig.ParseConfig(*flagFull)
+	if err != nil {
+		tool.Fail(err)
+	}
+	mgrcfg, err := mgrconfig.LoadPartialFile(*flagManager)
+	if err != nil {
+		tool.Fail(err)
+	}
+	if !mgrcfg.Cover || mgrcfg.HTTP == "" {
+		tool.Failf("coverage and http must be enabled in the manager config")
+	}
+	r := &runner{
+		cfg:      mgrcfg,
+		workdir:  osutil.Abs(*flagWorkdir),
+		duration: *flagDuration,
+		buildParams: build.Params{
+			TargetOS:     targets.Linux,
+			TargetArch:   *flagArch,
+			VMType:       mgrcfg.Type,
+			KernelDir:    *flagSourceDir,
+			Compiler:     *flagCompiler,
+			Make:         *flagMake,
+			UserspaceDir: osutil.Abs(*flagUserspace),
+		},
+	}
+	if *flagPaths != "" {
+		r.paths = strings.Split(*flagPaths, ",")
+	}
+	reference, err := r.coverage("full", full)
+	if err != nil {
+		tool.Fail(err)
+	}
+	need, err := requiredCoverage(reference, *flagThreshold)
+	if err != nil {
+		tool.Fail(err)
+	}
+	log.Logf(0, "full config coverage: %v lines, need %v lines", reference, need)
+	pred := coveragePredicate(r.coverage, need)
+	dt := &debugtracer.GenericTracer{
+		TraceWriter: os.Stdout,
+		OutDir:      r.workdir,
+	}
+	res, err := kconf.Minimize(base, full, pred, *flagMaxSteps, dt)
+	if err != nil {
+		tool.Fail(err)
+	}
+	if err := osutil.WriteFile(*flagOut, res.Serialize()); err != nil {
+		tool.Fail(err)
+	}
+	log.Logf(0, "minimized config: %v -> %v configs", len(full.Configs), len(res.Configs))
+}
+
+// requiredCoverage returns the number of lines a minimized config must cover
+// given the coverage of the full config.
+func requiredCoverage(reference int, threshold float64) (int, error) {
+	if threshold <= 0 || threshold > 1 {
+		return 0, fmt.Errorf("threshold must be in (0, 1], got %v", threshold)
+	}
+	if reference == 0 {
+		return 0, fmt.Errorf("the full config does not achieve any coverage in the selected paths")
+	}
+	return int(float64(reference) * threshold), nil
+}
+
+// coveragePredicate returns the KConfig.Minimize predicate that accepts configs
+// that cover at least need lines according to the measure function.
+func coveragePredicate(measure func(name string, cf *kconfig.ConfigFile) (int, error),
+	need int) func(*kconfig.ConfigFile) (bool, error) {
+	step := 0
+	return func(candidate *kconfig.ConfigFile) (bool, error) {
+		step++
+		covered, err := measure(fmt.Sprintf("step%v", step), candidate)
+		if err != nil {
+			return false, err
+		}
+		log.Logf(0, "step %v: %v covered lines (need %v)", step, covered, need)
+		return covered >= need, nil
+	}
+}
+
+type runner struct {
+	cfg         *mgrconfig.Config
+	workdir     string
+	duration    time.Duration
+	paths       []string
+	buildParams build.Params
+}
+
+// coverage builds the kernel with the config, runs the corpus on it and returns the number
+// of covered lines in the selected paths. Build failures and boot failures result in 0 coverage.
+func (r *runner) coverage(name string, cf *kconfig.ConfigFile) (int, error) {
+	dir := filepath.Join(r.workdir, name)
+	if err := os.RemoveAll(dir); err != nil {
+		return 0, err
+	}
+	imageDir := filepath.Join(dir, "image")
+	if err := osutil.MkdirAll(imageDir); err != nil {
+		return 0, err
+	}
+	params := r.buildParams
+	params.OutputDir = imageDir
+	params.Config = cf.Serialize()
+	if _, err := build.Image(params); err != nil {
+		log.Logf(0, "%v: kernel build failed: %v", name, err)
+		return 0, nil
+	}
+	files, err := r.runCorpus(dir, imageDir)
+	if err != nil {
+		return 0, err
+	}
+	return r.coveredLines(files), nil
+}
+
+func (r *runner) coveredLines(files []cover.FileCover) int {
+	covered := 0
+	for _, file := range files {
+		if r.selected(file.Filename) {
+			covered += file.CoveredLines
+		}
+	}
+	return covered
+}
+
+func (r *runner) selected(file string) bool {
+	if len(r.paths) == 0 {
+		return true
+	}
+	for _, path := range r.paths {
+		if strings.HasPrefix(file, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// runCorpus runs syz-manager in the corpus-run mode on the kernel image for the configured
+// duration and returns the resulting file coverage.
+func (r *runner) runCorpus(dir, imageDir string) ([]cover.FileCover, error) {
+	cfg := *r.cfg
+	cfg.Workdir = filepath.Join(dir, "workdir")
+	if err := instance.SetConfigImage(&cfg, imageDir, true); err != nil {
+		return nil, err
+	}
+	if err := osutil.MkdirAll(cfg.Workdir); err != nil {
+		return nil, err
+	}
+	if err := osutil.CopyFile(filepath.Join(r.cfg.Workdir, "corpus.db"),
+		filepath.Join(cfg.Workdir, "corpus.db")); err != nil {
+		return nil, err
+	}
+	cfgFile := filepath.Join(dir, "manager.cfg")
+	if err := config.SaveFile(cfgFile, &cfg); err != nil {
+		return nil, err
+	}
+	logFile, err := os.Create(filepath.Join(dir, "manager.log"))
+	if err != nil {
+		return nil, err
+	}
+	defer logFile.Close()
+	bin := filepath.Join(cfg.Syzkaller, "bin", "syz-manager")
+	cmd := osutil.GraciousCommand(bin, "-config", cfgFile, "-mode", "corpus-run")
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start syz-manager: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	select {
+	case err := <-exited:
+		return nil, fmt.Errorf("syz-manager exited prematurely (%w), see %v", err, logFile.Name())
+	case <-time.After(r.duration):
+	}
+	files, err := fetchFileCover(cfg.HTTP)
+	cmd.Process.Signal(os.Interrupt)
+	select {
+	case <-exited:
+	case <-time.After(time.Minute):
+		cmd.Process.Kill()
+		<-exited
+	}
+	if err != nil {
+		// Most likely the kernel does not boot, so there is no coverage.
+		log.Logf(0, "failed to fetch coverage: %v", err)
+		return nil, nil
+	}
+	return files, nil
+}
+
+func fetchFileCover(addr string) ([]cover.FileCover, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	resp, err := http.Get(fmt.Sprintf("http://%v/filecover", net.JoinHostPort(host, port)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("/filecover returned %v", resp.Status)
+	}
+	return cover.ReadFileCover(resp.Body)
+}
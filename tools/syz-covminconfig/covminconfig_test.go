@@ -0,0 +1,88 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/cover"
+	"github.com/google/syzkaller/pkg/kconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoveredLines(t *testing.T) {
+	files := []cover.FileCover{
+		{Filename: "net/ipv4/tcp.c", CoveredLines: 10},
+		{Filename: "net/ipv6/tcp_ipv6.c", CoveredLines: 20},
+		{Filename: "net/core/sock.c", CoveredLines: 40},
+		{Filename: "fs/namei.c", CoveredLines: 80},
+	}
+	assert.Equal(t, 150, (&runner{}).coveredLines(files))
+	assert.Equal(t, 30, (&runner{paths: []string{"net/ipv4/", "net/ipv6/"}}).coveredLines(files))
+	assert.Equal(t, 0, (&runner{paths: []string{"mm/"}}).coveredLines(files))
+}
+
+func TestRequiredCoverage(t *testing.T) {
+	need, err := requiredCoverage(1000, 0.95)
+	assert.NoError(t, err)
+	assert.Equal(t, 950, need)
+	need, err = requiredCoverage(1000, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, need)
+	_, err = requiredCoverage(0, 0.95)
+	assert.Error(t, err)
+	_, err = requiredCoverage(1000, 0)
+	assert.Error(t, err)
+	_, err = requiredCoverage(1000, 1.5)
+	assert.Error(t, err)
+}
+
+func TestCoveragePredicate(t *testing.T) {
+	var names []string
+	results := []int{100, 99, 0}
+	measure := func(name string, cf *kconfig.ConfigFile) (int, error) {
+		names = append(names, name)
+		if len(results) == 0 {
+			return 0, errors.New("build machine is on fire")
+		}
+		covered := results[0]
+		results = results[1:]
+		return covered, nil
+	}
+	pred := coveragePredicate(measure, 100)
+	for _, want := range []bool{true, false, false} {
+		ok, err := pred(&kconfig.ConfigFile{})
+		assert.NoError(t, err)
+		assert.Equal(t, want, ok)
+	}
+	_, err := pred(&kconfig.ConfigFile{})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"step1", "step2", "step3", "step4"}, names)
+}
+
+func TestFetchFileCover(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/filecover" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "Module,Filename,CoveredLines,TotalLines\n,net/ipv4/tcp.c,10,100\n")
+	}))
+	defer srv.Close()
+	// The manager HTTP address is usually specified without the host.
+	_, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	assert.NoError(t, err)
+	files, err := fetchFileCover(":" + port)
+	assert.NoError(t, err)
+	assert.Equal(t, []cover.FileCover{{Filename: "net/ipv4/tcp.c", CoveredLines: 10, TotalLines: 100}}, files)
+
+	srv.Config.Handler = http.NotFoundHandler()
+	_, err = fetchFileCover(srv.Listener.Addr().String())
+	assert.ErrorContains(t, err, "404")
+}
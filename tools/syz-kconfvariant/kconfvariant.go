@@ -0,0 +1,111 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-kconfvariant derives kernel config variants from a base config to reach code
+// that is not compiled in with the base config.
+// Each variant randomly toggles some user-visible options (with dependency resolution)
+// and, if a file coverage report is provided, enables disabled options that affect
+// poorly covered files. The report is the CSV produced by syz-manager's /filecover page.
+// Example use:
+//
+//	$ syz-kconfvariant -sourcedir /src/linux -base upstream-kasan.config \
+//		-filecover filecover.csv -count 3 -toggles 20 -out variants/
+//
+// The resulting configs need to be passed through make olddefconfig.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/cover"
+	"github.com/google/syzkaller/pkg/kconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/tool"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+func main() {
+	var (
+		flagSourceDir = flag.String("sourcedir", "", "kernel sources dir")
+		flagArch      = flag.String("arch", runtime.GOARCH, "kernel arch")
+		flagBase      = flag.String("base", "", "base config")
+		flagOut       = flag.String("out", ".", "output dir for variant-N.config files")
+		flagCount     = flag.Int("count", 1, "number of variants to generate")
+		flagToggles   = flag.Int("toggles", 10, "number of random options to toggle in each variant")
+		flagSeed      = flag.Int64("seed", 0, "random seed (current time if 0)")
+		flagKeep      = flag.String("keep", "", "comma-separated list of configs that must not be changed")
+		flagFileCover = flag.String("filecover", "", "file coverage CSV report (syz-manager /filecover)")
+		flagThreshold = flag.Float64("threshold", 0.1, "files with line coverage below this are poorly covered")
+		flagBoost     = flag.Int("boost", 5, "number of poorly covered files to boost in each variant")
+	)
+	defer tool.Init()()
+	if *flagSourceDir == "" || *flagBase == "" {
+		tool.Failf("-sourcedir and -base are mandatory")
+	}
+	kconf, err := kconfig.Parse(targets.Get(targets.Linux, *flagArch), filepath.Join(*flagSourceDir, "Kconfig"))
+	if err != nil {
+		tool.Fail(err)
+	}
+	base, err := kconfig.ParseConfig(*flagBase)
+	if err != nil {
+		tool.Fail(err)
+	}
+	var poorFiles []string
+	if *flagFileCover != "" {
+		poorFiles, err = readPoorlyCoveredFiles(*flagFileCover, *flagThreshold)
+		if err != nil {
+			tool.Fail(err)
+		}
+		fmt.Printf("%v poorly covered files\n", len(poorFiles))
+	}
+	seed := *flagSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	var keep []string
+	if *flagKeep != "" {
+		keep = strings.Split(*flagKeep, ",")
+	}
+	if err := osutil.MkdirAll(*flagOut); err != nil {
+		tool.Fail(err)
+	}
+	for i := 0; i < *flagCount; i++ {
+		params := kconfig.VariantParams{
+			Toggles: *flagToggles,
+			Keep:    keep,
+		}
+		for j := 0; j < *flagBoost && len(poorFiles) != 0; j++ {
+			file := poorFiles[rnd.Intn(len(poorFiles))]
+			params.Enable = append(params.Enable, kconfig.FileConfigs(*flagSourceDir, file)...)
+		}
+		variant, changed := kconf.Variant(base, params, rnd)
+		file := filepath.Join(*flagOut, fmt.Sprintf("variant-%v.config", i))
+		if err := osutil.WriteFile(file, variant.Serialize()); err != nil {
+			tool.Fail(err)
+		}
+		fmt.Printf("%v: %v changed configs: %v\n", file, len(changed), strings.Join(changed, " "))
+	}
+}
+
+// readPoorlyCoveredFiles returns the files from the syz-manager /filecover CSV report
+// whose line coverage is below threshold.
+func readPoorlyCoveredFiles(file string, threshold float64) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	files, err := cover.ReadFileCover(f)
+	if err != nil {
+		return nil, err
+	}
+	return cover.PoorlyCoveredFiles(files, threshold), nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/ast"
+	"github.com/google/syzkaller/pkg/compiler"
+)
+
+// There are tens of thousands of consts for linux, don't flood the editor.
+const maxConstCompletions = 200
+
+// complete returns the type names and consts that start with the identifier before the cursor.
+func (ws *workspace) complete(an *analysis, file string, line, col int) []CompletionItem {
+	prefix := identPrefix(ws.file(file), line, col)
+	var res []CompletionItem
+	for _, name := range compiler.BuiltinTypeNames() {
+		if strings.HasPrefix(name, prefix) {
+			res = append(res, CompletionItem{Label: name, Kind: completionKindKeyword, Detail: "builtin"})
+		}
+	}
+	var defs []CompletionItem
+	for name, node := range an.index.defs {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		_, typ, _ := node.Info()
+		defs = append(defs, CompletionItem{Label: name, Kind: completionKind(node), Detail: typ})
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Label < defs[j].Label
+	})
+	res = append(res, defs...)
+	if prefix == "" {
+		return res
+	}
+	var consts []string
+	for name := range ws.consts {
+		if strings.HasPrefix(name, prefix) && an.index.defs[name] == nil {
+			consts = append(consts, name)
+		}
+	}
+	sort.Strings(consts)
+	if len(consts) > maxConstCompletions {
+		consts = consts[:maxConstCompletions]
+	}
+	for _, name := range consts {
+		res = append(res, CompletionItem{Label: name, Kind: completionKindConstant, Detail: "const"})
+	}
+	return res
+}
+
+func completionKind(node ast.Node) int {
+	switch node.(type) {
+	case *ast.Call:
+		return completionKindFunction
+	case *ast.Struct:
+		return completionKindStruct
+	case *ast.IntFlags, *ast.StrFlags:
+		return completionKindEnum
+	case *ast.Resource:
+		return completionKindClass
+	case *ast.Define:
+		return completionKindConstant
+	}
+	return completionKindStruct
+}
+
+// identPrefix returns the part of the identifier that ends right before the 1-based line/column.
+func identPrefix(data []byte, line, col int) string {
+	lines := strings.Split(string(data), "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	text := lines[line-1]
+	end := min(col-1, len(text))
+	start := end
+	for start > 0 && isIdentChar(text[start-1]) {
+		start--
+	}
+	return text[start:end]
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
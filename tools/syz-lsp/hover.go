@@ -0,0 +1,126 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/ast"
+	"github.com/google/syzkaller/pkg/compiler"
+	"github.com/google/syzkaller/prog"
+)
+
+// hover describes the identifier: the resolved layout for structs and unions,
+// the base type for resources, values for flags and consts.
+func (ws *workspace) hover(an *analysis, name string) string {
+	buf := new(bytes.Buffer)
+	if node := an.index.defs[name]; node != nil {
+		_, typ, _ := node.Info()
+		fmt.Fprintf(buf, "%v %v\n", typ, name)
+		switch n := node.(type) {
+		case *ast.Resource:
+			fmt.Fprintf(buf, "\nbase: %v\n", ast.SerializeNode(n.Base))
+			if len(n.Values) != 0 {
+				fmt.Fprintf(buf, "values: %v\n", ws.intValues(n.Values))
+			}
+		case *ast.IntFlags:
+			fmt.Fprintf(buf, "\nvalues: %v\n", ws.intValues(n.Values))
+		case *ast.StrFlags:
+			var values []string
+			for _, v := range n.Values {
+				values = append(values, fmt.Sprintf("%q", v.Value))
+			}
+			fmt.Fprintf(buf, "\nvalues: %v\n", strings.Join(values, ", "))
+		case *ast.Struct, *ast.TypeDef:
+			if an.prog != nil {
+				layouts(buf, an.prog, name)
+			}
+		}
+	} else if val, ok := ws.consts[name]; ok {
+		fmt.Fprintf(buf, "const %v = %v (0x%x)\n", name, val, val)
+	} else if slices.Contains(compiler.BuiltinTypeNames(), name) {
+		fmt.Fprintf(buf, "builtin type %v\n", name)
+	}
+	if buf.Len() == 0 {
+		return ""
+	}
+	return "```\n" + buf.String() + "```"
+}
+
+func (ws *workspace) intValues(values []*ast.Int) string {
+	var res []string
+	for _, v := range values {
+		if v.Ident == "" {
+			res = append(res, fmt.Sprint(v.Value))
+		} else if val, ok := ws.consts[v.Ident]; ok {
+			res = append(res, fmt.Sprintf("%v=%v", v.Ident, val))
+		} else {
+			res = append(res, v.Ident+"=?")
+		}
+	}
+	return strings.Join(res, ", ")
+}
+
+// layouts prints layouts of all compiled structs and unions with the name,
+// there are several of them for templates instantiated with different arguments.
+func layouts(buf *bytes.Buffer, p *compiler.Prog, name string) {
+	for _, typ := range p.Types {
+		if typ.TemplateName() != name {
+			continue
+		}
+		switch t := typ.(type) {
+		case *prog.StructType:
+			buf.WriteString("\n")
+			layout(buf, p.Types, t, t.Fields, true)
+		case *prog.UnionType:
+			buf.WriteString("\n")
+			layout(buf, p.Types, t, t.Fields, false)
+		}
+	}
+}
+
+func layout(buf *bytes.Buffer, types []prog.Type, typ prog.Type, fields []prog.Field, isStruct bool) {
+	fmt.Fprintf(buf, "%v: %v, align %v\n", typ.Name(), typeSize(typ), typ.Alignment())
+	offset, varlen := uint64(0), false
+	for _, f := range fields {
+		ft := resolve(types, f.Type)
+		name := f.Name
+		if prog.IsPad(ft) {
+			name = "<pad>"
+		}
+		off := "?"
+		if !varlen {
+			off = fmt.Sprint(offset)
+		}
+		if !isStruct {
+			off = "0"
+		}
+		bits := ""
+		if ft.IsBitfield() {
+			bits = fmt.Sprintf(":%v@%v", ft.BitfieldLength(), ft.BitfieldOffset())
+		}
+		fmt.Fprintf(buf, "  [%v] %v%v %v: %v\n", off, name, bits, ft.Name(), typeSize(ft))
+		if ft.Varlen() {
+			varlen = true
+		}
+		offset += ft.Size()
+	}
+}
+
+func resolve(types []prog.Type, typ prog.Type) prog.Type {
+	if ref, ok := typ.(prog.Ref); ok {
+		return types[int(ref)]
+	}
+	return typ
+}
+
+func typeSize(typ prog.Type) string {
+	if typ.Varlen() {
+		return "varlen"
+	}
+	return fmt.Sprintf("size %v", typ.Size())
+}
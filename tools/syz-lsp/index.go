@@ -0,0 +1,85 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"sort"
+
+	"github.com/google/syzkaller/pkg/ast"
+)
+
+// index maps identifiers to their definitions and uses in the descriptions.
+type index struct {
+	defs map[string]ast.Node
+	// All occurrences of the names (including the definitions) sorted by position.
+	refs map[string][]ast.Pos
+	// Identifiers by file for the cursor lookup.
+	idents map[string][]*ast.Ident
+}
+
+func buildIndex(desc *ast.Description) *index {
+	idx := &index{
+		defs:   make(map[string]ast.Node),
+		refs:   make(map[string][]ast.Pos),
+		idents: make(map[string][]*ast.Ident),
+	}
+	add := func(pos ast.Pos, name string) {
+		if name == "" {
+			return
+		}
+		id := &ast.Ident{Pos: pos, Name: name}
+		idx.idents[pos.File] = append(idx.idents[pos.File], id)
+		idx.refs[name] = append(idx.refs[name], pos)
+	}
+	for _, node := range desc.Nodes {
+		var name *ast.Ident
+		switch n := node.(type) {
+		case *ast.Resource:
+			name = n.Name
+		case *ast.Struct:
+			name = n.Name
+		case *ast.TypeDef:
+			name = n.Name
+		case *ast.IntFlags:
+			name = n.Name
+		case *ast.StrFlags:
+			name = n.Name
+		case *ast.Call:
+			name = n.Name
+		case *ast.Define:
+			name = n.Name
+		}
+		if name != nil {
+			if _, ok := idx.defs[name.Name]; !ok {
+				idx.defs[name.Name] = node
+			}
+			add(name.Pos, name.Name)
+		}
+		ast.Recursive(func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.Type:
+				add(n.Pos, n.Ident)
+			case *ast.Int:
+				add(n.Pos, n.Ident)
+			}
+			return true
+		})(node)
+	}
+	for _, refs := range idx.refs {
+		sort.Slice(refs, func(i, j int) bool {
+			return refs[i].File < refs[j].File || refs[i].File == refs[j].File && refs[i].Off < refs[j].Off
+		})
+	}
+	return idx
+}
+
+// lookup returns the identifier at the given 1-based line and column.
+func (idx *index) lookup(file string, line, col int) *ast.Ident {
+	for _, id := range idx.idents[file] {
+		if id.Pos.Line == line && col >= id.Pos.Col && col <= id.Pos.Col+len(id.Name) {
+			return id
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"sync"
+)
+
+// message is a JSON-RPC 2.0 request, notification or response.
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  any              `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// conn implements the LSP base protocol: messages with the Content-Length header.
+type conn struct {
+	r  *textproto.Reader
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{
+		r: textproto.NewReader(bufio.NewReader(r)),
+		w: w,
+	}
+}
+
+func (c *conn) read() (*message, error) {
+	hdr, err := c.r.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	size, err := strconv.Atoi(hdr.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("bad Content-Length: %w", err)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(c.r.R, data); err != nil {
+		return nil, err
+	}
+	msg := new(message)
+	if err := json.Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("failed to parse message: %w", err)
+	}
+	return msg, nil
+}
+
+func (c *conn) write(msg *message) error {
+	msg.JSONRPC = "2.0"
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %v\r\n\r\n", len(data)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(data)
+	return err
+}
+
+func (c *conn) reply(id *json.RawMessage, result any, err error) error {
+	msg := &message{ID: id}
+	if err != nil {
+		msg.Error = &responseError{Code: codeInternalError, Message: err.Error()}
+		if rpcErr, ok := err.(*responseError); ok {
+			msg.Error = rpcErr
+		}
+	} else {
+		if result == nil {
+			// The result must be present in successful responses.
+			result = json.RawMessage("null")
+		}
+		msg.Result = result
+	}
+	return c.write(msg)
+}
+
+func (c *conn) notify(method string, params any) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return c.write(&message{Method: method, Params: data})
+}
+
+func (err *responseError) Error() string {
+	return err.Message
+}
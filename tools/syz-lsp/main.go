@@ -0,0 +1,34 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-lsp is a Language Server Protocol server for syzlang descriptions.
+// It provides live compiler diagnostics, go-to-definition and find-references for types, resources and flags,
+// hover with the resolved struct layouts, completion of type names and consts, and formatting.
+//
+// The server talks the protocol over stdin/stdout. All descriptions in the directory of an opened file
+// are compiled together; for sys/OS directories the OS is taken from the directory name,
+// otherwise from the -os flag. Consts are taken from the *.const files in the same directory.
+//
+// Editors that allow to pass initialization options may override the flags with {"os": "...", "arch": "..."}.
+package main
+
+import (
+	"flag"
+	"os"
+	"runtime"
+
+	"github.com/google/syzkaller/pkg/tool"
+)
+
+var (
+	flagOS   = flag.String("os", runtime.GOOS, "target OS for descriptions outside of sys/OS dirs")
+	flagArch = flag.String("arch", runtime.GOARCH, "target arch (consts and type layouts are arch-specific)")
+)
+
+func main() {
+	defer tool.Init()()
+	srv := newServer(os.Stdin, os.Stdout, *flagOS, *flagArch)
+	if err := srv.serve(); err != nil {
+		tool.Fail(err)
+	}
+}
@@ -0,0 +1,416 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/ast"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+// Re-analysis is delayed while the user is typing.
+const analysisDelay = 300 * time.Millisecond
+
+type server struct {
+	conn *conn
+	os   string
+	arch string
+
+	mu         sync.Mutex
+	workspaces map[string]*workspace // dir -> workspace
+	pending    map[*workspace]*time.Timer
+	published  map[string]bool // URIs with non-empty diagnostics
+	shutdown   bool
+}
+
+func newServer(r io.Reader, w io.Writer, os, arch string) *server {
+	return &server{
+		conn:       newConn(r, w),
+		os:         os,
+		arch:       arch,
+		workspaces: make(map[string]*workspace),
+		pending:    make(map[*workspace]*time.Timer),
+		published:  make(map[string]bool),
+	}
+}
+
+// serve handles requests until the exit notification or EOF.
+func (srv *server) serve() error {
+	for {
+		msg, err := srv.conn.read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+		res, err := srv.handle(msg)
+		if msg.ID == nil {
+			if err != nil {
+				log.Logf(0, "%v: %v", msg.Method, err)
+			}
+			continue
+		}
+		if err := srv.conn.reply(msg.ID, res, err); err != nil {
+			return err
+		}
+	}
+}
+
+func (srv *server) handle(msg *message) (any, error) {
+	switch msg.Method {
+	case "initialize":
+		var params InitializeParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return srv.initialize(&params), nil
+	case "initialized", "textDocument/didSave", "$/cancelRequest", "$/setTrace":
+		return nil, nil
+	case "shutdown":
+		srv.mu.Lock()
+		srv.shutdown = true
+		srv.mu.Unlock()
+		return nil, nil
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return nil, srv.update(params.TextDocument.URI, params.TextDocument.Text)
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		text := params.ContentChanges[len(params.ContentChanges)-1].Text
+		return nil, srv.update(params.TextDocument.URI, text)
+	case "textDocument/didClose":
+		return nil, nil
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return srv.definition(&params)
+	case "textDocument/references":
+		var params ReferenceParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return srv.references(&params)
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return srv.hover(&params)
+	case "textDocument/completion":
+		var params TextDocumentPositionParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return srv.completion(&params)
+	case "textDocument/formatting":
+		var params DocumentFormattingParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return srv.formatting(&params)
+	}
+	return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("unknown method %q", msg.Method)}
+}
+
+func unmarshalParams(msg *message, params any) error {
+	if err := json.Unmarshal(msg.Params, params); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func (srv *server) initialize(params *InitializeParams) *InitializeResult {
+	if opts := params.InitializationOptions; opts != nil {
+		if opts.OS != "" {
+			srv.os = opts.OS
+		}
+		if opts.Arch != "" {
+			srv.arch = opts.Arch
+		}
+	}
+	return &InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync: TextDocumentSyncOptions{
+				OpenClose: true,
+				Change:    textDocumentSyncFull,
+				Save:      true,
+			},
+			DefinitionProvider:         true,
+			ReferencesProvider:         true,
+			HoverProvider:              true,
+			CompletionProvider:         CompletionOptions{TriggerCharacters: []string{"[", ","}},
+			DocumentFormattingProvider: true,
+		},
+		ServerInfo: ServerInfo{Name: "syz-lsp"},
+	}
+}
+
+// workspace returns the workspace for the document and the base name of the document.
+func (srv *server) workspace(uri string) (*workspace, string, error) {
+	path, err := uriToPath(uri)
+	if err != nil {
+		return nil, "", err
+	}
+	dir, file := filepath.Split(path)
+	dir = filepath.Clean(dir)
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	ws := srv.workspaces[dir]
+	if ws == nil {
+		target, err := srv.target(dir)
+		if err != nil {
+			return nil, "", err
+		}
+		ws, err = newWorkspace(dir, target)
+		if err != nil {
+			return nil, "", err
+		}
+		srv.workspaces[dir] = ws
+	}
+	return ws, file, nil
+}
+
+// target selects the target for the descriptions dir: sys/OS dirs are compiled for the OS,
+// for other dirs the OS is taken from the flags.
+func (srv *server) target(dir string) (*targets.Target, error) {
+	os := srv.os
+	if targets.List[filepath.Base(dir)] != nil {
+		os = filepath.Base(dir)
+	}
+	arches := targets.List[os]
+	if arches == nil {
+		return nil, fmt.Errorf("unknown OS %q", os)
+	}
+	arch := srv.arch
+	if arches[arch] == nil {
+		if arches[targets.AMD64] != nil {
+			arch = targets.AMD64
+		} else {
+			var names []string
+			for name := range arches {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			arch = names[0]
+		}
+	}
+	return arches[arch], nil
+}
+
+func (srv *server) update(uri, text string) error {
+	ws, file, err := srv.workspace(uri)
+	if err != nil {
+		return err
+	}
+	ws.setFile(file, []byte(text))
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if timer := srv.pending[ws]; timer != nil {
+		timer.Stop()
+	}
+	srv.pending[ws] = time.AfterFunc(analysisDelay, func() {
+		srv.publish(ws, ws.analyze())
+	})
+	return nil
+}
+
+// analysis returns the latest analysis of the workspace, or analyzes it now if there is none.
+func (srv *server) analysis(ws *workspace) *analysis {
+	if an := ws.lastAnalysis(); an != nil {
+		return an
+	}
+	an := ws.analyze()
+	srv.publish(ws, an)
+	return an
+}
+
+func (srv *server) publish(ws *workspace, an *analysis) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.shutdown {
+		return
+	}
+	current := make(map[string]bool)
+	for file, diags := range an.diags {
+		uri := pathToURI(ws.path(file))
+		current[uri] = true
+		params := &PublishDiagnosticsParams{URI: uri}
+		for _, diag := range diags {
+			params.Diagnostics = append(params.Diagnostics, Diagnostic{
+				Range:    posRange(diag.pos, 0),
+				Severity: diagnosticSeverityError,
+				Source:   "syz-lsp",
+				Message:  diag.msg,
+			})
+		}
+		srv.notify("textDocument/publishDiagnostics", params)
+	}
+	// Clear the diagnostics for the files that are fixed now.
+	for uri := range srv.published {
+		if !current[uri] && strings.HasPrefix(uri, pathToURI(ws.dir)+"/") {
+			srv.notify("textDocument/publishDiagnostics", &PublishDiagnosticsParams{
+				URI:         uri,
+				Diagnostics: []Diagnostic{},
+			})
+			delete(srv.published, uri)
+		}
+	}
+	for uri := range current {
+		srv.published[uri] = true
+	}
+}
+
+func (srv *server) notify(method string, params any) {
+	if err := srv.conn.notify(method, params); err != nil {
+		log.Logf(0, "failed to send %v: %v", method, err)
+	}
+}
+
+// ident returns the identifier under the cursor.
+func (srv *server) ident(params *TextDocumentPositionParams) (*workspace, *analysis, *ast.Ident, error) {
+	ws, file, err := srv.workspace(params.TextDocument.URI)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	an := srv.analysis(ws)
+	id := an.index.lookup(file, params.Position.Line+1, params.Position.Character+1)
+	return ws, an, id, nil
+}
+
+func (srv *server) definition(params *TextDocumentPositionParams) ([]Location, error) {
+	ws, an, id, err := srv.ident(params)
+	if id == nil {
+		return nil, err
+	}
+	if node := an.index.defs[id.Name]; node != nil {
+		pos, _, _ := node.Info()
+		return []Location{ws.location(pos, len(id.Name))}, nil
+	}
+	if pos, ok := ws.constPos[id.Name]; ok {
+		return []Location{{URI: pathToURI(pos.File), Range: posRange(pos, len(id.Name))}}, nil
+	}
+	return nil, nil
+}
+
+func (srv *server) references(params *ReferenceParams) ([]Location, error) {
+	ws, an, id, err := srv.ident(&params.TextDocumentPositionParams)
+	if id == nil {
+		return nil, err
+	}
+	var defPos ast.Pos
+	if node := an.index.defs[id.Name]; node != nil {
+		defPos, _, _ = node.Info()
+	}
+	var res []Location
+	for _, pos := range an.index.refs[id.Name] {
+		if !params.Context.IncludeDeclaration && pos.File == defPos.File && pos.Line == defPos.Line {
+			continue
+		}
+		res = append(res, ws.location(pos, len(id.Name)))
+	}
+	return res, nil
+}
+
+func (srv *server) hover(params *TextDocumentPositionParams) (*Hover, error) {
+	ws, an, id, err := srv.ident(params)
+	if id == nil {
+		return nil, err
+	}
+	text := ws.hover(an, id.Name)
+	if text == "" {
+		return nil, nil
+	}
+	rng := posRange(id.Pos, len(id.Name))
+	return &Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: text},
+		Range:    &rng,
+	}, nil
+}
+
+func (srv *server) completion(params *TextDocumentPositionParams) ([]CompletionItem, error) {
+	ws, file, err := srv.workspace(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	an := srv.analysis(ws)
+	return ws.complete(an, file, params.Position.Line+1, params.Position.Character+1), nil
+}
+
+func (srv *server) formatting(params *DocumentFormattingParams) ([]TextEdit, error) {
+	ws, file, err := srv.workspace(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	formatted, err := ws.format(file)
+	if err != nil {
+		return nil, err
+	}
+	old := ws.file(file)
+	if string(formatted) == string(old) {
+		return []TextEdit{}, nil
+	}
+	lines := strings.Count(string(old), "\n")
+	return []TextEdit{{
+		Range: Range{
+			Start: Position{0, 0},
+			End:   Position{lines + 1, 0},
+		},
+		NewText: string(formatted),
+	}}, nil
+}
+
+func (ws *workspace) location(pos ast.Pos, size int) Location {
+	return Location{
+		URI:   pathToURI(ws.path(pos.File)),
+		Range: posRange(pos, size),
+	}
+}
+
+func posRange(pos ast.Pos, size int) Range {
+	start := Position{Line: max(pos.Line-1, 0), Character: max(pos.Col-1, 0)}
+	end := start
+	end.Character += size
+	return Range{Start: start, End: end}
+}
+
+func uriToPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("unsupported URI %q", uri)
+	}
+	return filepath.FromSlash(u.Path), nil
+}
+
+func pathToURI(path string) string {
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	return u.String()
+}
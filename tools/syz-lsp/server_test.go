@@ -0,0 +1,163 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testWorkspace(t *testing.T) (*workspace, *analysis) {
+	dir, err := filepath.Abs(filepath.Join("testdata", targets.TestOS))
+	require.NoError(t, err)
+	ws, err := newWorkspace(dir, targets.List[targets.TestOS][targets.TestArch64])
+	require.NoError(t, err)
+	an := ws.analyze()
+	require.Empty(t, an.diags)
+	require.NotNil(t, an.prog)
+	return ws, an
+}
+
+func TestDiagnostics(t *testing.T) {
+	ws, _ := testWorkspace(t)
+	ws.setFile("calls.txt", []byte("lsp_open() fd_lsp\nlsp_ioctl(fd fd_lsp, arg ptr[in, lsp_foo])\n"))
+	an := ws.analyze()
+	require.Len(t, an.diags["calls.txt"], 1)
+	diag := an.diags["calls.txt"][0]
+	assert.Equal(t, 2, diag.pos.Line)
+	assert.Contains(t, diag.msg, "lsp_foo")
+
+	ws.setFile("calls.txt", []byte("lsp_open( fd_lsp\n"))
+	an = ws.analyze()
+	require.NotEmpty(t, an.diags["calls.txt"])
+	assert.Nil(t, an.prog)
+}
+
+func TestReferences(t *testing.T) {
+	_, an := testWorkspace(t)
+	// The "fd_lsp" in "lsp_ioctl(fd fd_lsp, ...".
+	id := an.index.lookup("calls.txt", 5, 15)
+	require.NotNil(t, id)
+	assert.Equal(t, "fd_lsp", id.Name)
+	pos, typ, _ := an.index.defs["fd_lsp"].Info()
+	assert.Equal(t, "resource", typ)
+	assert.Equal(t, "types.txt", pos.File)
+	assert.Equal(t, 4, pos.Line)
+	var refs []string
+	for _, ref := range an.index.refs["fd_lsp"] {
+		refs = append(refs, fmt.Sprintf("%v:%v", ref.File, ref.Line))
+	}
+	assert.Equal(t, []string{"calls.txt:4", "calls.txt:5", "types.txt:4", "types.txt:12"}, refs)
+
+	// Field names are not references.
+	assert.Nil(t, an.index.lookup("types.txt", 9, 2))
+}
+
+func TestHover(t *testing.T) {
+	ws, an := testWorkspace(t)
+	assert.Equal(t, "```\nstruct lsp_struct\n\n"+
+		"lsp_struct: size 16, align 4\n"+
+		"  [0] a int8: size 1\n"+
+		"  [1] <pad> pad: size 3\n"+
+		"  [4] b int32: size 4\n"+
+		"  [8] c lsp_flags: size 2\n"+
+		"  [10] <pad> pad: size 2\n"+
+		"  [12] d fd_lsp: size 4\n"+
+		"```", ws.hover(an, "lsp_struct"))
+	assert.Equal(t, "```\nflags lsp_flags\n\nvalues: LSP_FLAG_A=1, LSP_FLAG_B=2\n```", ws.hover(an, "lsp_flags"))
+	assert.Equal(t, "```\nconst LSP_FLAG_B = 2 (0x2)\n```", ws.hover(an, "LSP_FLAG_B"))
+	assert.Equal(t, "```\nbuiltin type int32\n```", ws.hover(an, "int32"))
+	assert.Equal(t, "", ws.hover(an, "lsp_unknown"))
+}
+
+func TestCompletion(t *testing.T) {
+	ws, an := testWorkspace(t)
+	ws.setFile("calls.txt", []byte("lsp_open() fd_lsp\nlsp_ioctl(fd fd_lsp, arg ptr[in, lsp_s])\nfoo(a LSP_F)\n"))
+	var labels []string
+	for _, item := range ws.complete(an, "calls.txt", 2, 39) {
+		labels = append(labels, item.Label)
+	}
+	assert.Equal(t, []string{"lsp_struct"}, labels)
+	labels = nil
+	for _, item := range ws.complete(an, "calls.txt", 3, 12) {
+		labels = append(labels, item.Label)
+	}
+	assert.Equal(t, []string{"LSP_FLAG_A", "LSP_FLAG_B"}, labels)
+	labels = nil
+	for _, item := range ws.complete(an, "calls.txt", 2, 33) {
+		labels = append(labels, item.Label)
+	}
+	assert.Contains(t, labels, "int32")
+	assert.Contains(t, labels, "fd_lsp")
+	assert.NotContains(t, labels, "LSP_FLAG_A")
+}
+
+func TestFormatting(t *testing.T) {
+	ws, _ := testWorkspace(t)
+	ws.setFile("calls.txt", []byte("lsp_open(  ) fd_lsp\n"))
+	res, err := ws.format("calls.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "lsp_open() fd_lsp\n", string(res))
+	ws.setFile("calls.txt", []byte("lsp_open(\n"))
+	_, err = ws.format("calls.txt")
+	assert.Error(t, err)
+}
+
+func TestServer(t *testing.T) {
+	dir, err := filepath.Abs(filepath.Join("testdata", targets.TestOS))
+	require.NoError(t, err)
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+	srv := newServer(serverR, serverW, targets.Linux, targets.TestArch64)
+	done := make(chan error)
+	go func() {
+		done <- srv.serve()
+	}()
+	client := newConn(clientR, clientW)
+	call := func(id int, method string, params any) *message {
+		data, err := json.Marshal(params)
+		require.NoError(t, err)
+		rawID := json.RawMessage(fmt.Sprint(id))
+		require.NoError(t, client.write(&message{ID: &rawID, Method: method, Params: data}))
+		for {
+			msg, err := client.read()
+			require.NoError(t, err)
+			if msg.ID != nil && string(*msg.ID) == fmt.Sprint(id) {
+				return msg
+			}
+		}
+	}
+	resp := call(1, "initialize", &InitializeParams{})
+	require.Nil(t, resp.Error)
+	uri := pathToURI(filepath.Join(dir, "calls.txt"))
+	require.NoError(t, client.notify("textDocument/didOpen", &DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: uri, Text: "lsp_open() fd_lsp\nlsp_ioctl(fd fd_lsp, arg ptr[in, lsp_struct])\n"},
+	}))
+	resp = call(2, "textDocument/definition", &TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     Position{Line: 1, Character: 35},
+	})
+	require.Nil(t, resp.Error)
+	data, err := json.Marshal(resp.Result)
+	require.NoError(t, err)
+	var locs []Location
+	require.NoError(t, json.Unmarshal(data, &locs))
+	assert.Equal(t, []Location{{
+		URI:   pathToURI(filepath.Join(dir, "types.txt")),
+		Range: Range{Start: Position{7, 0}, End: Position{7, 10}},
+	}}, locs)
+	resp = call(3, "foo/bar", nil)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, codeMethodNotFound, resp.Error.Code)
+	call(4, "shutdown", nil)
+	require.NoError(t, client.notify("exit", nil))
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/ast"
+	"github.com/google/syzkaller/pkg/compiler"
+	"github.com/google/syzkaller/sys/targets"
+)
+
+// workspace holds descriptions of a single OS: a directory with *.txt and *.const files (e.g. sys/linux).
+// All descriptions of the directory are compiled together, the open documents override the files on disk.
+type workspace struct {
+	dir    string
+	target *targets.Target
+
+	mu       sync.Mutex
+	files    map[string][]byte // base file name -> contents
+	consts   map[string]uint64 // consts for the target arch
+	constPos map[string]ast.Pos
+	analysis *analysis
+}
+
+// analysis is the result of parsing and compiling all descriptions of the workspace.
+type analysis struct {
+	diags map[string][]*diagnostic // base file name -> errors
+	index *index
+	prog  *compiler.Prog // nil if compilation has failed
+}
+
+type diagnostic struct {
+	pos ast.Pos
+	msg string
+}
+
+func newWorkspace(dir string, target *targets.Target) (*workspace, error) {
+	ws := &workspace{
+		dir:      dir,
+		target:   target,
+		files:    make(map[string][]byte),
+		constPos: make(map[string]ast.Pos),
+	}
+	txtFiles, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range txtFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		ws.files[filepath.Base(file)] = data
+	}
+	constGlob := filepath.Join(dir, "*.const")
+	constFiles, err := filepath.Glob(constGlob)
+	if err != nil {
+		return nil, err
+	}
+	if len(constFiles) != 0 {
+		cf := compiler.DeserializeConstFile(constGlob, func(pos ast.Pos, msg string) {})
+		ws.consts = cf.Arch(target.Arch)
+	}
+	for _, file := range constFiles {
+		if err := ws.indexConstFile(file); err != nil {
+			return nil, err
+		}
+	}
+	return ws, nil
+}
+
+// indexConstFile remembers where the consts are defined for go-to-definition.
+func (ws *workspace) indexConstFile(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; s.Scan(); line++ {
+		name, _, ok := strings.Cut(s.Text(), " = ")
+		if !ok || strings.HasPrefix(name, "#") || name == "arches" {
+			continue
+		}
+		if _, ok := ws.constPos[name]; !ok {
+			ws.constPos[name] = ast.Pos{File: file, Line: line, Col: 1}
+		}
+	}
+	return nil
+}
+
+func (ws *workspace) setFile(file string, data []byte) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.files[file] = data
+}
+
+func (ws *workspace) file(file string) []byte {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return ws.files[file]
+}
+
+func (ws *workspace) lastAnalysis() *analysis {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return ws.analysis
+}
+
+// analyze parses and compiles all descriptions and returns the diagnostics.
+func (ws *workspace) analyze() *analysis {
+	ws.mu.Lock()
+	files := make(map[string][]byte, len(ws.files))
+	for name, data := range ws.files {
+		files[name] = data
+	}
+	ws.mu.Unlock()
+
+	res := &analysis{
+		diags: make(map[string][]*diagnostic),
+	}
+	eh := func(pos ast.Pos, msg string) {
+		if pos.File == ast.BuiltinFile {
+			return
+		}
+		res.diags[pos.File] = append(res.diags[pos.File], &diagnostic{pos, msg})
+	}
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	all := &ast.Description{}
+	parsed := true
+	for _, name := range names {
+		desc := ast.Parse(files[name], name, eh)
+		if desc == nil {
+			parsed = false
+			continue
+		}
+		all.Nodes = append(all.Nodes, desc.Nodes...)
+	}
+	res.index = buildIndex(all)
+	// The compiler errors are confusing if some of the files are missing.
+	if parsed {
+		consts := ws.consts
+		if consts == nil {
+			consts = make(map[string]uint64)
+		}
+		res.prog = compiler.Compile(all, consts, ws.target, eh)
+	}
+	ws.mu.Lock()
+	ws.analysis = res
+	ws.mu.Unlock()
+	return res
+}
+
+func (ws *workspace) path(file string) string {
+	return filepath.Join(ws.dir, file)
+}
+
+func (ws *workspace) format(file string) ([]byte, error) {
+	var errors []string
+	desc := ast.Parse(ws.file(file), file, func(pos ast.Pos, msg string) {
+		errors = append(errors, fmt.Sprintf("%v: %v", pos, msg))
+	})
+	if desc == nil {
+		return nil, fmt.Errorf("failed to parse: %v", strings.Join(errors, "\n"))
+	}
+	return ast.Format(desc), nil
+}
@@ -30,6 +30,8 @@ var (
 	flagHintSrc  = flag.Uint64("hint-src", 0, "compared value in the program")
 	flagHintCmp  = flag.Uint64("hint-cmp", 0, "compare operand in the kernel")
 	flagStrict   = flag.Bool("strict", true, "parse input program in strict mode")
+	flagTrace    = flag.String("trace", "", "write mutation trace to the file")
+	flagReplay   = flag.String("replay", "", "replay mutation trace from the file (with the same enabled syscalls)")
 )
 
 func main() {
@@ -68,6 +70,10 @@ func main() {
 	}
 	rs := rand.NewSource(seed)
 	ct := target.BuildChoiceTable(corpus, syscalls)
+	if *flagReplay != "" {
+		replay(target, ct)
+		return
+	}
 	var p *prog.Prog
 	if flag.NArg() == 0 {
 		p = target.Generate(rs, *flagLen, ct)
@@ -95,8 +101,39 @@ func main() {
 			})
 			return
 		} else {
-			p.Mutate(rs, *flagLen, ct, nil, corpus)
+			opts := prog.DefaultMutateOpts
+			if *flagTrace != "" {
+				opts.Trace = new(prog.MutationTrace)
+			}
+			p.MutateWithOpts(rs, *flagLen, ct, nil, corpus, opts)
+			if *flagTrace != "" {
+				if err := os.WriteFile(*flagTrace, opts.Trace.Serialize(), 0644); err != nil {
+					fmt.Fprintf(os.Stderr, "failed to write trace: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Fprintf(os.Stderr, "%v", opts.Trace)
+			}
 		}
 	}
 	fmt.Printf("%s\n", p.Serialize())
 }
+
+func replay(target *prog.Target, ct *prog.ChoiceTable) {
+	data, err := os.ReadFile(*flagReplay)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read trace file: %v\n", err)
+		os.Exit(1)
+	}
+	trace, err := prog.DeserializeMutationTrace(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	p, err := target.ReplayMutation(trace, ct)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "%v", trace)
+	fmt.Printf("%s\n", p.Serialize())
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-priodiff compares dynamic call-to-call priorities of two managers
+// (downloaded from the /prio?json=1 manager page). Absolute priority values depend on the corpus,
+// so each row is normalized to fractions of the row sum before comparison:
+//
+//	syz-priodiff [-top N] [-call name] old.json new.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/google/syzkaller/prog"
+)
+
+var (
+	flagTop  = flag.Int("top", 50, "number of the most different pairs to print")
+	flagCall = flag.String("call", "", "compare priorities only for this call")
+)
+
+func main() {
+	flag.Parse()
+	if flag.NArg() != 2 {
+		fmt.Fprintf(os.Stderr, "usage: syz-priodiff [flags] old.json new.json\n")
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	prios0, prios1 := load(flag.Arg(0)), load(flag.Arg(1))
+	fmt.Printf("targets: %v vs %v\n", prios0.Target, prios1.Target)
+	fmt.Printf("calls: %v vs %v, only in old: %v, only in new: %v\n",
+		len(prios0.Prios), len(prios1.Prios), missing(prios0, prios1), missing(prios1, prios0))
+	type Diff struct {
+		call0, call1 string
+		prio0, prio1 float64
+	}
+	var diffs []Diff
+	pairs := make(map[[2]string]bool)
+	norm0, norm1 := normalize(prios0), normalize(prios1)
+	for _, prios := range []map[string]map[string]float64{norm0, norm1} {
+		for call0, row := range prios {
+			if *flagCall != "" && call0 != *flagCall {
+				continue
+			}
+			for call1 := range row {
+				pair := [2]string{call0, call1}
+				if pairs[pair] {
+					continue
+				}
+				pairs[pair] = true
+				prio0, prio1 := norm0[call0][call1], norm1[call0][call1]
+				if prio0 != prio1 {
+					diffs = append(diffs, Diff{call0, call1, prio0, prio1})
+				}
+			}
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		di, dj := math.Abs(diffs[i].prio1-diffs[i].prio0), math.Abs(diffs[j].prio1-diffs[j].prio0)
+		if di != dj {
+			return di > dj
+		}
+		if diffs[i].call0 != diffs[j].call0 {
+			return diffs[i].call0 < diffs[j].call0
+		}
+		return diffs[i].call1 < diffs[j].call1
+	})
+	fmt.Printf("pairs: %v, changed: %v\n", len(pairs), len(diffs))
+	for i, diff := range diffs {
+		if i == *flagTop {
+			break
+		}
+		fmt.Printf("%v -> %v: %.2f%% -> %.2f%%\n", diff.call0, diff.call1, diff.prio0*100, diff.prio1*100)
+	}
+}
+
+func load(file string) *prog.DynamicPrios {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read priorities: %v\n", err)
+		os.Exit(1)
+	}
+	prios, err := prog.DeserializeDynamicPrios(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v: %v\n", file, err)
+		os.Exit(1)
+	}
+	return prios
+}
+
+// missing returns the number of calls that have priorities in prios0, but not in prios1.
+func missing(prios0, prios1 *prog.DynamicPrios) int {
+	count := 0
+	for call := range prios0.Prios {
+		if prios1.Prios[call] == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// normalize returns priorities as fractions of the sum of the corresponding row.
+func normalize(prios *prog.DynamicPrios) map[string]map[string]float64 {
+	res := make(map[string]map[string]float64)
+	for call0, row := range prios.Prios {
+		var sum float64
+		for _, prio := range row {
+			sum += float64(prio)
+		}
+		if sum == 0 {
+			continue
+		}
+		res[call0] = make(map[string]float64)
+		for call1, prio := range row {
+			res[call0][call1] = float64(prio) / sum
+		}
+	}
+	return res
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Prints structural difference between two programs
+// (exits with status 1 if the programs differ, similar to diff):
+//
+//	syz-progdiff old.prog new.prog
+//
+// or merges changes of two programs derived from the same base program:
+//
+//	syz-progdiff -merge base.prog a.prog b.prog
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/google/syzkaller/prog"
+	_ "github.com/google/syzkaller/sys"
+)
+
+var (
+	flagOS     = flag.String("os", runtime.GOOS, "target os")
+	flagArch   = flag.String("arch", runtime.GOARCH, "target arch")
+	flagMerge  = flag.Bool("merge", false, "merge 2 programs derived from the base program")
+	flagStrict = flag.Bool("strict", false, "parse input programs in strict mode")
+)
+
+func main() {
+	flag.Parse()
+	if !*flagMerge && flag.NArg() != 2 || *flagMerge && flag.NArg() != 3 {
+		fmt.Fprintf(os.Stderr, "usage: syz-progdiff [flags] old.prog new.prog\n")
+		fmt.Fprintf(os.Stderr, "       syz-progdiff [flags] -merge base.prog a.prog b.prog\n")
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	target, err := prog.GetTarget(*flagOS, *flagArch)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	mode := prog.NonStrict
+	if *flagStrict {
+		mode = prog.Strict
+	}
+	var progs []*prog.Prog
+	for _, file := range flag.Args() {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read prog file: %v\n", err)
+			os.Exit(1)
+		}
+		p, err := target.Deserialize(data, mode)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to deserialize %v: %v\n", file, err)
+			os.Exit(1)
+		}
+		progs = append(progs, p)
+	}
+	if !*flagMerge {
+		diff := prog.DiffProgs(progs[0], progs[1])
+		fmt.Printf("%v", diff)
+		if !diff.Empty() {
+			os.Exit(1)
+		}
+		return
+	}
+	p, err := prog.MergeProgs(progs[0], progs[1], progs[2])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s", p.Serialize())
+}